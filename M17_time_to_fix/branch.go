@@ -0,0 +1,75 @@
+package main
+
+import (
+	"sort"
+
+	"golang.org/x/mod/semver"
+)
+
+/* ---------- End of support per major (unfixed-on-branch) ---------- */
+
+// unfixedBranches returns the majors ("v1", …) that an advisory affects but
+// on which no fixed version was ever released, e.g. introduced 1.4.0 with
+// the only fix in 2.0.1. Ranges with an unspecified intro ("0") or with
+// versions that are not semver-like (GIT hashes, PEP 440 pre-releases) are
+// not judged.
+func unfixedBranches(v osvVuln) []string {
+	affected := map[string]bool{}
+	fixed := map[string]bool{}
+	for _, aff := range v.Affected {
+		for _, rg := range aff.Ranges {
+			if rg.Type != "SEMVER" && rg.Type != "ECOSYSTEM" {
+				continue
+			}
+			for _, ev := range rg.Events {
+				if m := major(ev.Introduced); m != "" && ev.Introduced != "0" {
+					affected[m] = true
+				}
+				if m := major(ev.Fixed); m != "" {
+					fixed[m] = true
+				}
+			}
+		}
+	}
+	var out []string
+	for m := range affected {
+		if !fixed[m] {
+			out = append(out, m)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return semver.Compare(out[i], out[j]) < 0 })
+	return out
+}
+
+// crossMajor reports whether the fix of a row lies on a newer major than
+// its intro, i.e. the row's ΔFix would measure an upgrade, not a patch.
+func crossMajor(intro, fix string) bool {
+	mi, mf := major(intro), major(fix)
+	return intro != "" && mi != "" && mf != "" && semver.Compare(mi, mf) < 0
+}
+
+// unfixedOnBranch reports whether a row is counted as unfixed-on-branch
+// instead of getting a ΔFix: its fix is on a newer major and at least one
+// affected major never got a fix.
+func unfixedOnBranch(r *row) bool {
+	return crossMajor(r.introTag, r.fixTag) && len(r.unfixed) > 0
+}
+
+// fixDelta is a row's ΔFix in days from the intro to the fix release, nil if
+// a date is missing or the row is unfixed-on-branch.
+func fixDelta(r *row) *float64 {
+	if unfixedOnBranch(r) || r.introDate == nil || r.fixDate == nil {
+		return nil
+	}
+	d := r.fixDate.Sub(*r.introDate).Hours() / 24
+	return &d
+}
+
+// major returns the semver major of an OSV version ("v1"), or "" if the
+// version is not semver-like.
+func major(ver string) string {
+	if ver == "" {
+		return ""
+	}
+	return semver.Major("v" + ver)
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+/* ---------- Chart (-chart) ---------- */
+
+// sevClasses fixes order and colour of the severity classes in the chart.
+var sevClasses = []struct{ name, color string }{
+	{"CRITICAL", "#b2182b"},
+	{"HIGH", "#ef8a62"},
+	{"MODERATE", "#67a9cf"},
+	{"LOW", "#2166ac"},
+	{"UNKNOWN", "#999999"},
+}
+
+func sevClass(sev string) string {
+	for _, c := range sevClasses {
+		if c.name == sev {
+			return sev
+		}
+	}
+	return "UNKNOWN"
+}
+
+// fixCurve holds, per severity class, the days from publication to fix
+// (fixed advisories) and how many advisories are in the class at all.
+type fixCurve struct {
+	days  []float64
+	total int
+}
+
+// fixCurves collects the curve data. Fixes released before publication count
+// as day 0; open advisories only enlarge the total, so a curve stays below
+// 100 % while advisories are unfixed.
+func fixCurves(rows []row, open []openOut) map[string]*fixCurve {
+	curves := map[string]*fixCurve{}
+	get := func(sev string) *fixCurve {
+		c := curves[sevClass(sev)]
+		if c == nil {
+			c = &fixCurve{}
+			curves[sevClass(sev)] = c
+		}
+		return c
+	}
+	for _, r := range rows {
+		c := get(r.severity)
+		c.total++
+		if r.publishedDate != nil && r.fixDate != nil {
+			c.days = append(c.days, math.Max(0, r.fixDate.Sub(*r.publishedDate).Hours()/24))
+		}
+	}
+	for _, o := range open {
+		get(o.Severity).total++
+	}
+	for _, c := range curves {
+		sort.Float64s(c.days)
+	}
+	return curves
+}
+
+// writeChart writes the severity distribution and the cumulative fraction
+// of fixed advisories over days since publication as SVG, or as an HTML page
+// embedding the SVG (by file extension).
+func writeChart(path, title string, rows []row, open []openOut) error {
+	svg := chartSVG(title, fixCurves(rows, open))
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".svg":
+		return os.WriteFile(path, []byte(svg), 0o644)
+	case ".html", ".htm":
+		page := "<!DOCTYPE html>\n<html><head><meta charset=\"utf-8\"><title>" + html.EscapeString(title) +
+			"</title></head>\n<body>\n" + svg + "</body></html>\n"
+		return os.WriteFile(path, []byte(page), 0o644)
+	}
+	return fmt.Errorf("unsupported chart format %q (use .svg or .html)", filepath.Ext(path))
+}
+
+func chartSVG(title string, curves map[string]*fixCurve) string {
+	const (
+		w, h             = 800, 560
+		left, right, top = 60, 150, 40
+		plotH            = 320 // survival panel
+		barTop, barH     = 420, 100
+	)
+	plotW := float64(w - left - right)
+
+	maxDays := 10.0
+	maxCount := 1
+	for _, c := range curves {
+		if n := len(c.days); n > 0 && c.days[n-1] > maxDays {
+			maxDays = c.days[n-1]
+		}
+		if c.total > maxCount {
+			maxCount = c.total
+		}
+	}
+	x := func(d float64) float64 { return left + d/maxDays*plotW }
+	y := func(f float64) float64 { return top + (1-f)*plotH }
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" font-family=\"sans-serif\" font-size=\"12\">\n", w, h)
+	fmt.Fprintf(&b, "<text x=\"%d\" y=\"20\" font-size=\"15\">%s</text>\n", left, html.EscapeString(title))
+
+	// axes and grid of the fix curve
+	for _, f := range []float64{0, 0.25, 0.5, 0.75, 1} {
+		fmt.Fprintf(&b, "<line x1=\"%d\" x2=\"%.1f\" y1=\"%.1f\" y2=\"%.1f\" stroke=\"#ddd\"/>\n", left, left+plotW, y(f), y(f))
+		fmt.Fprintf(&b, "<text x=\"%d\" y=\"%.1f\" text-anchor=\"end\">%.0f%%</text>\n", left-6, y(f)+4, f*100)
+	}
+	for i := 0; i <= 5; i++ {
+		d := maxDays * float64(i) / 5
+		fmt.Fprintf(&b, "<text x=\"%.1f\" y=\"%d\" text-anchor=\"middle\">%.0f</text>\n", x(d), top+plotH+16, d)
+	}
+	fmt.Fprintf(&b, "<text x=\"%.1f\" y=\"%d\" text-anchor=\"middle\">days since publication</text>\n", left+plotW/2, top+plotH+34)
+	fmt.Fprintf(&b, "<text x=\"%d\" y=\"%d\" transform=\"rotate(-90 %d %d)\" text-anchor=\"middle\">fixed</text>\n", 16, top+plotH/2, 16, top+plotH/2)
+
+	// step curves, one per class
+	legend := 0
+	for _, sc := range sevClasses {
+		c := curves[sc.name]
+		if c == nil || c.total == 0 {
+			continue
+		}
+		pts := []string{fmt.Sprintf("%.1f,%.1f", x(0), y(0))}
+		for i, d := range c.days {
+			prev := float64(i) / float64(c.total)
+			cur := float64(i+1) / float64(c.total)
+			pts = append(pts, fmt.Sprintf("%.1f,%.1f", x(d), y(prev)), fmt.Sprintf("%.1f,%.1f", x(d), y(cur)))
+		}
+		pts = append(pts, fmt.Sprintf("%.1f,%.1f", x(maxDays), y(float64(len(c.days))/float64(c.total))))
+		fmt.Fprintf(&b, "<polyline fill=\"none\" stroke=\"%s\" stroke-width=\"2\" points=\"%s\"/>\n", sc.color, strings.Join(pts, " "))
+
+		ly := top + 10 + legend*18
+		fmt.Fprintf(&b, "<rect x=\"%d\" y=\"%d\" width=\"12\" height=\"12\" fill=\"%s\"/>\n", w-right+15, ly, sc.color)
+		fmt.Fprintf(&b, "<text x=\"%d\" y=\"%d\">%s (%d/%d)</text>\n", w-right+32, ly+10, sc.name, len(c.days), c.total)
+		legend++
+	}
+
+	// severity distribution
+	fmt.Fprintf(&b, "<text x=\"%d\" y=\"%d\">severity distribution</text>\n", left, barTop-10)
+	n := len(sevClasses)
+	slot := plotW / float64(n)
+	for i, sc := range sevClasses {
+		cnt := 0
+		if c := curves[sc.name]; c != nil {
+			cnt = c.total
+		}
+		bh := float64(barH) * float64(cnt) / float64(maxCount)
+		bx := left + float64(i)*slot + slot*0.2
+		fmt.Fprintf(&b, "<rect x=\"%.1f\" y=\"%.1f\" width=\"%.1f\" height=\"%.1f\" fill=\"%s\"/>\n", bx, barTop+barH-bh, slot*0.6, bh, sc.color)
+		fmt.Fprintf(&b, "<text x=\"%.1f\" y=\"%.1f\" text-anchor=\"middle\">%d</text>\n", bx+slot*0.3, barTop+barH-bh-4, cnt)
+		fmt.Fprintf(&b, "<text x=\"%.1f\" y=\"%d\" text-anchor=\"middle\">%s</text>\n", bx+slot*0.3, barTop+barH+16, sc.name)
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+/* ---------- Checkpoint (-checkpoint) ---------- */
+
+// checkpoint keeps the resolved dates per advisory. It is rewritten after
+// every advisory, so an interrupted run (rate limit, network) continues with
+// the first unresolved one. Without -checkpoint nothing is persisted.
+type checkpoint struct {
+	path    string
+	Subject string                   `json:"subject"` // -repo or -pkg the dates belong to
+	Rows    map[string]checkpointRow `json:"rows"`    // advisory ID -> dates
+}
+
+type checkpointRow struct {
+	IntroDate *time.Time `json:"intro_date,omitempty"`
+	FixDate   *time.Time `json:"fix_date,omitempty"`
+}
+
+// loadCheckpoint reads path if it exists and belongs to subject.
+func loadCheckpoint(path, subject string) *checkpoint {
+	cp := &checkpoint{path: path, Subject: subject, Rows: map[string]checkpointRow{}}
+	if path == "" {
+		return cp
+	}
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cp
+	}
+	var old checkpoint
+	if err == nil {
+		err = json.Unmarshal(b, &old)
+	}
+	switch {
+	case err != nil:
+		slog.Warn("checkpoint unreadable, starting over", "file", path, "err", err)
+	case old.Subject != subject:
+		slog.Warn("checkpoint belongs to another subject, starting over", "file", path, "subject", old.Subject)
+	default:
+		cp.Rows = old.Rows
+		slog.Info("resuming from checkpoint", "file", path, "resolved", len(cp.Rows))
+	}
+	return cp
+}
+
+// put records an advisory and saves the file.
+func (cp *checkpoint) put(id string, r checkpointRow) {
+	cp.Rows[id] = r
+	if cp.path == "" {
+		return
+	}
+	if err := cp.save(); err != nil {
+		slog.Warn("checkpoint not written", "file", cp.path, "err", err)
+	}
+}
+
+// save writes via a temporary file, so an interruption never leaves a
+// truncated checkpoint behind.
+func (cp *checkpoint) save() error {
+	b, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(cp.path), ".ttf-checkpoint-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), cp.path)
+}
+
+// finish removes the checkpoint once every advisory is resolved; otherwise
+// it stays for the next run.
+func (cp *checkpoint) finish(failed int) {
+	if cp.path == "" {
+		return
+	}
+	if failed > 0 {
+		slog.Warn("lookups failed, rerun to resume", "checkpoint", cp.path, "failed", failed, "resolved", len(cp.Rows))
+		return
+	}
+	if err := os.Remove(cp.path); err != nil && !os.IsNotExist(err) {
+		slog.Warn("checkpoint not removed", "file", cp.path, "err", err)
+	}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"baa_fs25/shared/i18n"
+)
+
+/* ---------- CVSS (-cvss) ---------- */
+
+// cvssBands are the qualitative ranges of CVSS v3 (none is folded into low).
+var cvssBands = []struct {
+	name     string
+	min, max float64
+}{
+	{"0.0-3.9", 0, 3.9},
+	{"4.0-6.9", 4, 6.9},
+	{"7.0-8.9", 7, 8.9},
+	{"9.0-10", 9, 10},
+}
+
+// cvssBand is one line of the breakdown in the JSON summary.
+type cvssBand struct {
+	Band        string   `json:"band"`
+	Count       int      `json:"count"`
+	MeanFixDays *float64 `json:"mean_fix_days"`
+}
+
+// cvssScore returns the base score of the first usable entry of an OSV
+// severity list: CVSS v3.x or v2 vectors are scored, plain numbers are taken
+// as is. CVSS v4 vectors are skipped.
+func cvssScore(sev []osvSeverity) *float64 {
+	for _, s := range sev {
+		var score float64
+		var err error
+		switch {
+		case strings.HasPrefix(s.Score, "CVSS:3."):
+			score, err = cvss3(s.Score)
+		case s.Type == "CVSS_V2":
+			score, err = cvss2(s.Score)
+		default:
+			score, err = strconv.ParseFloat(s.Score, 64)
+		}
+		if err == nil {
+			return &score
+		}
+	}
+	return nil
+}
+
+func cvssMetrics(vector string) map[string]string {
+	m := map[string]string{}
+	for _, part := range strings.Split(vector, "/") {
+		if k, v, ok := strings.Cut(part, ":"); ok {
+			m[k] = v
+		}
+	}
+	return m
+}
+
+// cvss3 computes the CVSS v3.0/v3.1 base score of a vector string.
+func cvss3(vector string) (float64, error) {
+	m := cvssMetrics(vector)
+	weights := map[string]map[string]float64{
+		"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+		"AC": {"L": 0.77, "H": 0.44},
+		"UI": {"N": 0.85, "R": 0.62},
+		"C":  {"H": 0.56, "L": 0.22, "N": 0},
+		"I":  {"H": 0.56, "L": 0.22, "N": 0},
+		"A":  {"H": 0.56, "L": 0.22, "N": 0},
+	}
+	w := map[string]float64{}
+	for k, vals := range weights {
+		v, ok := vals[m[k]]
+		if !ok {
+			return 0, fmt.Errorf("CVSS vector %q: missing or invalid %s", vector, k)
+		}
+		w[k] = v
+	}
+	changed := m["S"] == "C"
+	if m["S"] != "C" && m["S"] != "U" {
+		return 0, fmt.Errorf("CVSS vector %q: missing or invalid S", vector)
+	}
+	pr := map[string]float64{"N": 0.85, "L": 0.62, "H": 0.27}
+	if changed {
+		pr = map[string]float64{"N": 0.85, "L": 0.68, "H": 0.5}
+	}
+	prW, ok := pr[m["PR"]]
+	if !ok {
+		return 0, fmt.Errorf("CVSS vector %q: missing or invalid PR", vector)
+	}
+
+	iss := 1 - (1-w["C"])*(1-w["I"])*(1-w["A"])
+	impact := 6.42 * iss
+	if changed {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	exploit := 8.22 * w["AV"] * w["AC"] * prW * w["UI"]
+	if impact <= 0 {
+		return 0, nil
+	}
+	if changed {
+		return roundUp(math.Min(1.08*(impact+exploit), 10)), nil
+	}
+	return roundUp(math.Min(impact+exploit, 10)), nil
+}
+
+// roundUp is the "Roundup" of the CVSS v3.1 specification.
+func roundUp(x float64) float64 {
+	i := int(math.Round(x * 100000))
+	if i%10000 == 0 {
+		return float64(i) / 100000
+	}
+	return (math.Floor(float64(i)/10000) + 1) / 10
+}
+
+// cvss2 computes the CVSS v2 base score of a vector string.
+func cvss2(vector string) (float64, error) {
+	m := cvssMetrics(vector)
+	weights := map[string]map[string]float64{
+		"AV": {"L": 0.395, "A": 0.646, "N": 1},
+		"AC": {"H": 0.35, "M": 0.61, "L": 0.71},
+		"Au": {"M": 0.45, "S": 0.56, "N": 0.704},
+		"C":  {"N": 0, "P": 0.275, "C": 0.66},
+		"I":  {"N": 0, "P": 0.275, "C": 0.66},
+		"A":  {"N": 0, "P": 0.275, "C": 0.66},
+	}
+	w := map[string]float64{}
+	for k, vals := range weights {
+		v, ok := vals[m[k]]
+		if !ok {
+			return 0, fmt.Errorf("CVSS v2 vector %q: missing or invalid %s", vector, k)
+		}
+		w[k] = v
+	}
+	impact := 10.41 * (1 - (1-w["C"])*(1-w["I"])*(1-w["A"]))
+	exploit := 20 * w["AV"] * w["AC"] * w["Au"]
+	f := 1.176
+	if impact == 0 {
+		f = 0
+	}
+	return math.Round(((0.6*impact)+(0.4*exploit)-1.5)*f*10) / 10, nil
+}
+
+// printCVSS prints the CVSS-weighted mean ΔFix and the breakdown by band and
+// returns both for the JSON summary.
+func printCVSS(rows []row) (*float64, []cvssBand) {
+	var wsum, wdays float64
+	sums := make([]float64, len(cvssBands))
+	counts := make([]int, len(cvssBands))
+	for _, r := range rows {
+		if r.cvss == nil || r.dFix == nil {
+			continue
+		}
+		wsum += *r.cvss
+		wdays += *r.cvss * *r.dFix
+		for i, b := range cvssBands {
+			if *r.cvss >= b.min && *r.cvss <= b.max+0.05 {
+				sums[i] += *r.dFix
+				counts[i]++
+				break
+			}
+		}
+	}
+	var weighted *float64
+	if wsum > 0 {
+		v := wdays / wsum
+		weighted = &v
+		i18n.Printf("Ø Time-to-Fix, CVSS-gewichtet: %.1f Tage\n", v)
+	} else {
+		i18n.Printf("Ø Time-to-Fix, CVSS-gewichtet: n/a (keine CVSS-Scores)\n")
+	}
+	i18n.Printf("\n%-8s | %5s | %10s\n", "CVSS", "n", i18n.T("Ø ΔFix"))
+	fmt.Println(strings.Repeat("-", 30))
+	var bands []cvssBand
+	for i, b := range cvssBands {
+		mean := avg(sums[i], counts[i])
+		bands = append(bands, cvssBand{Band: b.name, Count: counts[i], MeanFixDays: mean})
+		m := "   n/a"
+		if mean != nil {
+			m = fmt.Sprintf("%6.1f", *mean)
+		}
+		i18n.Printf("%-8s | %5d | %10s\n", b.name, counts[i], m)
+	}
+	return weighted, bands
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"baa_fs25/shared/i18n"
+)
+
+/* ---------- CWE groups (-cwe) ---------- */
+
+// cweNames labels the weakness classes that show up most in advisories;
+// other IDs are printed without a name.
+var cweNames = map[string]string{
+	"CWE-20":   "Improper Input Validation",
+	"CWE-22":   "Path Traversal",
+	"CWE-59":   "Link Following",
+	"CWE-78":   "OS Command Injection",
+	"CWE-79":   "Cross-site Scripting",
+	"CWE-89":   "SQL Injection",
+	"CWE-94":   "Code Injection",
+	"CWE-200":  "Information Exposure",
+	"CWE-284":  "Improper Access Control",
+	"CWE-287":  "Improper Authentication",
+	"CWE-352":  "Cross-Site Request Forgery",
+	"CWE-400":  "Uncontrolled Resource Consumption",
+	"CWE-502":  "Deserialization of Untrusted Data",
+	"CWE-532":  "Sensitive Information in Logs",
+	"CWE-601":  "Open Redirect",
+	"CWE-611":  "XML External Entity",
+	"CWE-770":  "Allocation without Limits",
+	"CWE-862":  "Missing Authorization",
+	"CWE-918":  "Server-Side Request Forgery",
+	"CWE-1321": "Prototype Pollution",
+	"CWE-1333": "Inefficient Regular Expression (ReDoS)",
+}
+
+// noCWE collects advisories without any CWE ID.
+const noCWE = "none"
+
+// cweGroup is one line of the breakdown in the JSON summary.
+type cweGroup struct {
+	CWE           string   `json:"cwe"`
+	Name          string   `json:"name,omitempty"`
+	Count         int      `json:"count"`
+	MedianFixDays *float64 `json:"median_fix_days"`
+	MeanFixDays   *float64 `json:"mean_fix_days"`
+}
+
+// cweIDs returns the normalized CWE IDs of an advisory ("CWE-79"), without
+// duplicates and in their original order.
+func cweIDs(v osvVuln) []string {
+	var ids []string
+	seen := map[string]bool{}
+	for _, id := range v.DatabaseSpecific.CWEIDs {
+		id = strings.ToUpper(strings.TrimSpace(id))
+		if id == "" {
+			continue
+		}
+		if !strings.HasPrefix(id, "CWE-") {
+			id = "CWE-" + id
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// printCWE prints the median and mean ΔFix per CWE and returns the groups for
+// the JSON summary. An advisory with several CWEs counts in each of them.
+func printCWE(rows []row) []cweGroup {
+	days := map[string][]float64{}
+	for _, r := range rows {
+		if r.dFix == nil {
+			continue
+		}
+		ids := r.cwes
+		if len(ids) == 0 {
+			ids = []string{noCWE}
+		}
+		for _, id := range ids {
+			days[id] = append(days[id], *r.dFix)
+		}
+	}
+	var groups []cweGroup
+	for id, ds := range days {
+		var sum float64
+		for _, d := range ds {
+			sum += d
+		}
+		groups = append(groups, cweGroup{
+			CWE: id, Name: cweNames[id], Count: len(ds),
+			MedianFixDays: medianOf(ds), MeanFixDays: avg(sum, len(ds)),
+		})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return groups[i].CWE < groups[j].CWE
+	})
+
+	i18n.Printf("\n%-9s | %-30s | %5s | %11s | %8s\n", "CWE", i18n.T("Klasse"), "n", "Median ΔFix", i18n.T("Ø ΔFix"))
+	fmt.Println(strings.Repeat("-", 76))
+	if len(groups) == 0 {
+		i18n.Println("keine CVEs mit ΔFix")
+	}
+	for _, g := range groups {
+		name := g.Name
+		if len(name) > 30 {
+			name = name[:29] + "…"
+		}
+		i18n.Printf("%-9s | %-30s | %5d | %11.1f | %8.1f\n", g.CWE, name, g.Count, *g.MedianFixDays, *g.MeanFixDays)
+	}
+	return groups
+}
+
+// medianOf returns nil for an empty slice; xs is sorted in place.
+func medianOf(xs []float64) *float64 {
+	if len(xs) == 0 {
+		return nil
+	}
+	sort.Float64s(xs)
+	m := xs[len(xs)/2]
+	if len(xs)%2 == 0 {
+		m = (xs[len(xs)/2-1] + m) / 2
+	}
+	return &m
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"baa_fs25/shared/clones"
+	"baa_fs25/shared/gitwalk"
+	"baa_fs25/shared/i18n"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"golang.org/x/mod/semver"
+)
+
+/* ---------- Downstream adoption (-downstream-repo) ---------- */
+
+// adoption is the first downstream commit that depends on a fixed version.
+type adoption struct {
+	commit string
+	date   time.Time
+}
+
+// downstreamManifests lists the files read per ecosystem; lockfiles come
+// last so they win over the declared range.
+var downstreamManifests = map[string][]string{
+	"npm":  {"package.json", "package-lock.json"},
+	"go":   {"go.mod"},
+	"pypi": {"requirements.txt"},
+}
+
+// downstreamEco maps -plat / -source onto a key of downstreamManifests.
+func downstreamEco() string {
+	switch {
+	case *source == "govulndb" || *plat == "go":
+		return "go"
+	case *source == "pypi" || strings.EqualFold(*plat, "pypi"):
+		return "pypi"
+	case *plat == "" || *plat == "npm":
+		return "npm"
+	}
+	return ""
+}
+
+// openDownstream opens a local checkout, or clones a URL into a temp dir
+// (into the shared mirror with -mirror-dir).
+func openDownstream(loc string) (string, *git.Repository, error) {
+	if fi, err := os.Stat(loc); err == nil && fi.IsDir() {
+		r, err := git.PlainOpen(loc)
+		return loc, r, err
+	}
+	if clones.Enabled() {
+		// shared bare mirror (-mirror-dir), pinned until the process exits
+		dir, _, err := clones.Mirror(loc)
+		if err != nil {
+			return "", nil, err
+		}
+		r, err := git.PlainOpen(dir)
+		return dir, r, err
+	}
+	dir, err := os.MkdirTemp("", "ttf-downstream-")
+	if err != nil {
+		return "", nil, err
+	}
+	r, err := git.PlainClone(dir, false, &git.CloneOptions{URL: loc})
+	return dir, r, err
+}
+
+// adoptions walks the downstream history (oldest first, same first-parent
+// walker as mttu) and returns, per fixed version, the first commit whose
+// manifest pins dep at that version or later.
+func adoptions(loc, eco, dep string, fixes []string) (map[string]adoption, error) {
+	paths, ok := downstreamManifests[eco]
+	if !ok {
+		return nil, fmt.Errorf("no downstream manifest support for %q", eco)
+	}
+	_, r, err := openDownstream(loc)
+	if err != nil {
+		return nil, err
+	}
+
+	out := map[string]adoption{}
+	err = gitwalk.FirstParent{Repo: r}.ForEach(paths, nil, nil, func(c *object.Commit) error {
+		ver := downstreamVersion(c, paths, dep)
+		if ver == "" {
+			return nil
+		}
+		for _, fix := range fixes {
+			if _, done := out[fix]; done {
+				continue
+			}
+			if semver.Compare(semverOf(ver), semverOf(fix)) >= 0 {
+				out[fix] = adoption{commit: c.Hash.String()[:7], date: c.Author.When}
+			}
+		}
+		if len(out) == len(fixes) {
+			return storer.ErrStop
+		}
+		return nil
+	})
+	return out, err
+}
+
+func semverOf(v string) string {
+	if !strings.HasPrefix(v, "v") {
+		v = "v" + v
+	}
+	return v
+}
+
+var (
+	goRequireRx = regexp.MustCompile(`^\s*(?:require\s+)?(\S+)\s+(v\S+)`)
+	pyPinRx     = regexp.MustCompile(`^\s*([A-Za-z0-9._-]+)\s*==\s*([A-Za-z0-9._+-]+)`)
+)
+
+// downstreamVersion returns the version of dep in commit c, or "".
+func downstreamVersion(c *object.Commit, paths []string, dep string) string {
+	var ver string
+	for _, p := range paths {
+		f, err := c.File(p)
+		if err != nil {
+			continue
+		}
+		txt, err := f.Contents()
+		if err != nil {
+			continue
+		}
+		switch p {
+		case "package.json":
+			var pj struct {
+				Dependencies    map[string]string `json:"dependencies"`
+				DevDependencies map[string]string `json:"devDependencies"`
+			}
+			if json.Unmarshal([]byte(txt), &pj) == nil {
+				v := pj.Dependencies[dep]
+				if v == "" {
+					v = pj.DevDependencies[dep]
+				}
+				if v = strings.TrimLeft(v, "^~>=< "); v != "" {
+					ver = v
+				}
+			}
+		case "package-lock.json":
+			var lock struct {
+				Packages map[string]struct {
+					Version string `json:"version"`
+				} `json:"packages"`
+				Dependencies map[string]struct {
+					Version string `json:"version"`
+				} `json:"dependencies"`
+			}
+			if json.Unmarshal([]byte(txt), &lock) == nil {
+				if v := lock.Packages["node_modules/"+dep].Version; v != "" {
+					ver = v
+				} else if v := lock.Dependencies[dep].Version; v != "" {
+					ver = v
+				}
+			}
+		case "go.mod":
+			sc := bufio.NewScanner(strings.NewReader(txt))
+			for sc.Scan() {
+				if m := goRequireRx.FindStringSubmatch(sc.Text()); m != nil && m[1] == dep {
+					ver = m[2]
+				}
+			}
+		case "requirements.txt":
+			sc := bufio.NewScanner(strings.NewReader(txt))
+			for sc.Scan() {
+				if m := pyPinRx.FindStringSubmatch(sc.Text()); m != nil && strings.EqualFold(m[1], dep) {
+					ver = m[2]
+				}
+			}
+		}
+	}
+	return ver
+}
+
+// printAdoption fills rows[i].adopt / dAdopt from -downstream-repo and prints
+// the "time to adopt fix" table. It returns sum and count of ΔAdopt.
+func printAdoption(rows []row) (float64, int) {
+	dep := *pkg
+	if dep == "" {
+		dep = (*repoSlug)[strings.LastIndex(*repoSlug, "/")+1:]
+	}
+	var fixes []string
+	for _, r := range rows {
+		fixes = append(fixes, r.fixTag)
+	}
+	found, err := adoptions(*downRepo, downstreamEco(), dep, fixes)
+	if err != nil {
+		slog.Error("downstream repo not analysed", "repo", *downRepo, "err", err)
+		return 0, 0
+	}
+
+	i18n.Printf("\n=== Time to adopt fix: %s in %s ===\n", dep, *downRepo)
+	i18n.Printf("%-20s | %-12s | %-9s | %-16s | %-10s\n", "CVE-ID", "Fix-Tag", "Commit", "Adopt-Date", "ΔAdopt")
+	fmt.Println(strings.Repeat("-", 80))
+	var sum float64
+	var cnt int
+	for i := range rows {
+		r := &rows[i]
+		commit, aDate, diff := "-", "not adopted", "   n/a"
+		if a, ok := found[r.fixTag]; ok {
+			r.adopt = &a
+			commit, aDate = a.commit, a.date.Format(dateFmt)
+			if r.fixDate != nil {
+				d := a.date.Sub(*r.fixDate).Hours() / 24
+				r.dAdopt = &d
+				diff = fmt.Sprintf("%6.1f", d)
+				sum += d
+				cnt++
+			}
+		}
+		i18n.Printf("%-20s | %-12s | %-9s | %-16s | %6s\n", r.id, r.fixTag, commit, aDate, diff)
+	}
+	fmt.Println(strings.Repeat("-", 80))
+	if cnt == 0 {
+		i18n.Printf("Ø Time-to-Adopt (ΔAdopt): n/a (0 CVEs)\n")
+	} else {
+		i18n.Printf("Ø Time-to-Adopt (ΔAdopt): %.1f Tage (%d CVEs)\n", sum/float64(cnt), cnt)
+	}
+	return sum, cnt
+}
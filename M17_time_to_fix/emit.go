@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+
+	"baa_fs25/shared/provenance"
+	"baa_fs25/shared/report"
+	"baa_fs25/shared/schema"
+)
+
+/* ---------- Enriched OSV dataset (-emit-osv) ---------- */
+
+// Schema of the -emit-osv file:
+//
+//	{
+//	  "schema": "ttf-osv-enriched/1",
+//	  "repo":   "<owner/repo or package>",
+//	  "source": "<where the advisories came from>",
+//	  "vulns":  [ <OSV record>, ... ],
+//	  "provenance": { <tool version, flags, endpoints, ...> }
+//	}
+//
+// Every OSV record is written back unchanged except for
+// database_specific.ttf, which holds the fields of advisoryOut (intro_tag,
+// fix_tag, published, intro_date, fix_date, delta_fix_days,
+// delta_exposure_days, delta_disclosure_days, severity). Records without a fixed version carry no
+// ttf block. Since the top-level key is "vulns", the file can be fed back
+// into ttf with -json. The version follows -schema-version (shared/schema).
+
+type enrichedOut struct {
+	Schema string            `json:"schema"`
+	Repo   string            `json:"repo"`
+	Source string            `json:"source"`
+	Vulns  []json.RawMessage `json:"vulns"`
+
+	Provenance *provenance.Info `json:"provenance,omitempty"`
+}
+
+// UnmarshalJSON keeps the original record next to the decoded fields so that
+// -emit-osv can write it back without losing anything ttf does not model.
+func (v *osvVuln) UnmarshalJSON(b []byte) error {
+	type plain osvVuln
+	if err := json.Unmarshal(b, (*plain)(v)); err != nil {
+		return err
+	}
+	v.raw = append(json.RawMessage(nil), b...)
+	return nil
+}
+
+func emitOSV(path, repo, src string, vulns []osvVuln, advs []advisoryOut) error {
+	byID := map[string]advisoryOut{}
+	for _, a := range advs {
+		byID[a.ID] = a
+	}
+	out := enrichedOut{Schema: schema.ID("ttf-osv-enriched"), Repo: repo, Source: src, Provenance: provenance.Get()}
+	for _, v := range vulns {
+		var rec map[string]any
+		if err := json.Unmarshal(v.raw, &rec); err != nil {
+			return err
+		}
+		if len(v.Aliases) > 0 {
+			rec["aliases"] = v.Aliases // merged across -json inputs
+		}
+		if a, ok := byID[v.ID]; ok {
+			ds, _ := rec["database_specific"].(map[string]any)
+			if ds == nil {
+				ds = map[string]any{}
+			}
+			ds["ttf"] = a
+			rec["database_specific"] = ds
+		}
+		b, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+		out.Vulns = append(out.Vulns, b)
+	}
+	return report.WriteJSON(path, out)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+/* ---------- Go vulnerability database ---------- */
+
+const govulndbBase = "https://vuln.go.dev"
+
+// govulndbVulns fetches every advisory for module from vuln.go.dev. The
+// records are already OSV; affected entries for other modules are dropped so
+// that fixed-in versions only refer to the requested module.
+func govulndbVulns(module string) ([]osvVuln, error) {
+	var index []struct {
+		Path  string `json:"path"`
+		Vulns []struct {
+			ID string `json:"id"`
+		} `json:"vulns"`
+	}
+	if err := getJSON(govulndbBase+"/index/modules.json", &index); err != nil {
+		return nil, fmt.Errorf("govulndb index: %w", err)
+	}
+
+	var ids []string
+	for _, m := range index {
+		if m.Path == module {
+			for _, v := range m.Vulns {
+				ids = append(ids, v.ID)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		slog.Warn("no advisories in govulndb", "module", module)
+	}
+
+	var vulns []osvVuln
+	for _, id := range ids {
+		var v osvVuln
+		if err := getJSON(govulndbBase+"/ID/"+id+".json", &v); err != nil {
+			return nil, fmt.Errorf("govulndb %s: %w", id, err)
+		}
+		aff := v.Affected[:0]
+		for _, a := range v.Affected {
+			if a.Package.Name == module {
+				aff = append(aff, a)
+			}
+		}
+		v.Affected = aff
+		if v.DatabaseSpecific.Severity == "" {
+			v.DatabaseSpecific.Severity = aliasSeverity(v)
+		}
+		vulns = append(vulns, v)
+	}
+	return vulns, nil
+}
+
+// aliasSeverity borrows the severity of the first GHSA alias from osv.dev,
+// since Go vulndb records do not carry one themselves.
+func aliasSeverity(v osvVuln) string {
+	for _, a := range v.Aliases {
+		if !strings.HasPrefix(a, "GHSA-") {
+			continue
+		}
+		var ghsa osvVuln
+		if err := getJSON("https://api.osv.dev/v1/vulns/"+a, &ghsa); err != nil {
+			slog.Warn("osv.dev lookup failed", "id", v.ID, "alias", a, "err", err)
+			continue
+		}
+		if ghsa.DatabaseSpecific.Severity != "" {
+			return ghsa.DatabaseSpecific.Severity
+		}
+	}
+	slog.Debug("no severity for advisory", "id", v.ID)
+	return ""
+}
+
+func getJSON(url string, v any) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
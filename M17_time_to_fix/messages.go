@@ -0,0 +1,66 @@
+package main
+
+import "baa_fs25/shared/i18n"
+
+/* ---------- Report texts (-lang) ---------- */
+
+// The summaries are written in German, the tables and the open-advisory
+// section in English; the catalogs translate each into the other language.
+func init() {
+	i18n.Add(i18n.EN, map[string]string{
+		"Ø Time-to-Fix (ΔFix): n/a (0 CVEs)\n":                             "Mean time-to-fix (ΔFix): n/a (0 CVEs)\n",
+		"Ø Time-to-Fix (ΔFix): %.1f Tage (%d CVEs)\n":                      "Mean time-to-fix (ΔFix): %.1f days (%d CVEs)\n",
+		"Ø Exposure Window (ΔExposure): n/a (0 CVEs)\n":                    "Mean exposure window (ΔExposure): n/a (0 CVEs)\n",
+		"Ø Exposure Window (ΔExposure): %.1f Tage (%d CVEs)\n":             "Mean exposure window (ΔExposure): %.1f days (%d CVEs)\n",
+		"Ø Time-to-Disclosure (ΔDisclosure): n/a (0 CVEs)\n":               "Mean time-to-disclosure (ΔDisclosure): n/a (0 CVEs)\n",
+		"Ø Time-to-Disclosure (ΔDisclosure): %.1f Tage (%d CVEs)\n":        "Mean time-to-disclosure (ΔDisclosure): %.1f days (%d CVEs)\n",
+		"%d CVEs mit Veröffentlichung vor dem Intro-Release ignoriert\n":   "%d CVEs published before the intro release ignored\n",
+		"Ø betroffene Versionen: %.1f (%d CVEs)\n":                         "Mean affected versions: %.1f (%d CVEs)\n",
+		"%d CVEs nicht berücksichtigt (LOW oder keine Severity)\n":         "%d CVEs not counted (LOW or no severity)\n",
+		"%d advisories still unfixed, Ø %.1f Tage seit Veröffentlichung\n": "%d advisories still unfixed, mean %.1f days since publication\n",
+		"Ø Time-to-Fix, CVSS-gewichtet: %.1f Tage\n":                       "CVSS-weighted mean time-to-fix: %.1f days\n",
+		"Ø Time-to-Fix, CVSS-gewichtet: n/a (keine CVSS-Scores)\n":         "CVSS-weighted mean time-to-fix: n/a (no CVSS scores)\n",
+		"Ø Time-to-Adopt (ΔAdopt): n/a (0 CVEs)\n":                         "Mean time-to-adopt (ΔAdopt): n/a (0 CVEs)\n",
+		"Ø Time-to-Adopt (ΔAdopt): %.1f Tage (%d CVEs)\n":                  "Mean time-to-adopt (ΔAdopt): %.1f days (%d CVEs)\n",
+		"Ø ΔFix":              "Mean ΔFix",
+		"Klasse":              "Class",
+		"keine CVEs mit ΔFix": "no CVEs with ΔFix",
+		"%d CVEs auf mindestens einem Major nie gefixt (unfixed-on-branch, Cross-Major-Fix nicht in ΔFix)\n":                           "%d CVEs never fixed on at least one major (unfixed-on-branch, cross-major fix not in ΔFix)\n",
+		"%d CVEs still vor der Veröffentlichung gefixt (Median %.1f Tage Vorlauf, nicht in ΔExposure), %d nach der Veröffentlichung\n": "%d CVEs silently fixed before publication (median lead %.1f days, not in ΔExposure), %d after publication\n",
+		"Ø Exposure Window gewichtet nach Downloads: n/a (%d CVEs mit Download-Zahlen)\n":                                              "Download-weighted mean exposure window: n/a (%d CVEs with download counts)\n",
+		"Ø Exposure Window gewichtet nach Downloads: %.1f Tage (%d CVEs, Downloads/Woche der betroffenen Versionen)\n":                 "Download-weighted mean exposure window: %.1f days (%d CVEs, weekly downloads of the affected versions)\n",
+	})
+	i18n.Add(i18n.DE, map[string]string{
+		"\n=== Open advisories (no fixed version) ===\n":                   "\n=== Offene Advisories (keine Fix-Version) ===\n",
+		"%d advisories still unfixed\n":                                    "%d Advisories noch ohne Fix\n",
+		"%d advisories still unfixed, Ø %.1f Tage seit Veröffentlichung\n": "%d Advisories noch ohne Fix, Ø %.1f Tage seit Veröffentlichung\n",
+		"\n=== Time to adopt fix: %s in %s ===\n":                          "\n=== Übernahme der Fixes: %s in %s ===\n",
+		"Advisories/KLOC: %.3f (%d advisories, %.1f KLOC, %s)\n":           "Advisories/KLOC: %.3f (%d Advisories, %.1f KLOC, %s)\n",
+		"Advisories/Dependency: %.3f (%d advisories, %d deps, %s)\n":       "Advisories/Dependency: %.3f (%d Advisories, %d Dependencies, %s)\n",
+		"not found":      "nicht gefunden",
+		"Published":      "Veröffentlicht",
+		"Intro-Date":     "Intro-Datum",
+		"Fix-Date":       "Fix-Datum",
+		"Unfixed-Branch": "Branch ohne Fix",
+		"Silent-Lead":    "Stiller Vorlauf",
+		"Last-Affected":  "Zuletzt betroffen",
+		"Age":            "Alter",
+		"ttf – %s: %d of %d advisories, sort %s %s": "ttf – %s: %d von %d Advisories, sortiert nach %s %s",
+		", filter %q":                    ", Filter %q",
+		"filter: %s":                     "Filter: %s",
+		"(open)":                         "(offen)",
+		"no advisories match the filter": "keine Advisories passen zum Filter",
+		"Package: %s":                    "Paket: %s",
+		"Severity: %s":                   "Severity: %s",
+		"Severity: %s   no fixed version, last affected: %s":             "Severity: %s   keine Fix-Version, zuletzt betroffen: %s",
+		"Published: %s   open for %s days":                               "Veröffentlicht: %s   seit %s Tagen offen",
+		"Introduced: %s (%s)   fixed: %s (%s)   published: %s":           "Eingeführt: %s (%s)   gefixt: %s (%s)   veröffentlicht: %s",
+		"ΔFix %s   ΔExposure %s   ΔDisclosure %s   affected versions %s": "ΔFix %s   ΔExposure %s   ΔDisclosure %s   betroffene Versionen %s",
+		"Disclosure: %s   lead %s days":                                  "Offenlegung: %s   Vorlauf %s Tage",
+		"Majors without fix: %s":                                         "Majors ohne Fix: %s",
+		"Adopted downstream: %s (%s), ΔAdopt %s days":                    "Downstream übernommen: %s (%s), ΔAdopt %s Tage",
+		"Ranges":     "Bereiche",
+		"References": "Referenzen",
+		"↑/↓ move  s sort  r reverse  / filter (sev:high dfix>30 after:2023-01-01 text)  esc clear  q quit": "↑/↓ bewegen  s sortieren  r umkehren  / filtern (sev:high dfix>30 after:2023-01-01 Text)  esc Filter löschen  q beenden",
+	})
+}
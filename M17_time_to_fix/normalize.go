@@ -0,0 +1,254 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"baa_fs25/shared/i18n"
+)
+
+/* ---------- Normalization (-normalize) ---------- */
+
+// bytesPerLine converts GitHub's per-language byte counts into an estimated
+// line count when no checkout is available.
+const bytesPerLine = 40
+
+// normOut puts the advisory count in relation to project size, so that
+// projects of different size can be compared.
+type normOut struct {
+	Advisories int      `json:"advisories"`
+	KLOC       *float64 `json:"kloc,omitempty"`
+	LOCSource  string   `json:"loc_source,omitempty"`
+	Deps       int      `json:"deps,omitempty"`
+	DepsSource string   `json:"deps_source,omitempty"`
+	PerKLOC    *float64 `json:"advisories_per_kloc,omitempty"`
+	PerDep     *float64 `json:"advisories_per_dep,omitempty"`
+}
+
+// normalize measures the project (from -size-dir if given, otherwise via the
+// GitHub API) and divides the advisory count by KLOC and dependency count.
+func normalize(advisories int) *normOut {
+	n := &normOut{Advisories: advisories}
+	var loc int
+	var err error
+	if *sizeDir != "" {
+		loc, n.LOCSource, err = locFromDir(*sizeDir)
+		if err == nil {
+			n.Deps, err = depsFromDir(*sizeDir)
+			n.DepsSource = "manifests in " + *sizeDir
+		}
+	} else if *repoSlug != "" {
+		loc, err = locFromGitHub(*repoSlug)
+		n.LOCSource = "github languages (estimated)"
+		if err == nil {
+			n.Deps, err = depsFromGitHub(*repoSlug)
+			n.DepsSource = "github dependency graph"
+		}
+	} else {
+		err = fmt.Errorf("need -size-dir or -repo")
+	}
+	if err != nil {
+		slog.Warn("normalization incomplete", "err", err)
+	}
+	if loc > 0 {
+		k := float64(loc) / 1000
+		n.KLOC = &k
+		v := float64(advisories) / k
+		n.PerKLOC = &v
+	}
+	if n.Deps > 0 {
+		n.PerDep = avg(float64(advisories), n.Deps)
+	}
+	return n
+}
+
+func (n *normOut) print() {
+	if n.PerKLOC != nil {
+		i18n.Printf("Advisories/KLOC: %.3f (%d advisories, %.1f KLOC, %s)\n", *n.PerKLOC, n.Advisories, *n.KLOC, n.LOCSource)
+	} else {
+		i18n.Printf("Advisories/KLOC: n/a\n")
+	}
+	if n.PerDep != nil {
+		i18n.Printf("Advisories/Dependency: %.3f (%d advisories, %d deps, %s)\n", *n.PerDep, n.Advisories, n.Deps, n.DepsSource)
+	} else {
+		i18n.Printf("Advisories/Dependency: n/a\n")
+	}
+}
+
+// sourceExts are the file types counted when scc is not installed.
+var sourceExts = map[string]bool{
+	".go": true, ".js": true, ".mjs": true, ".cjs": true, ".ts": true, ".tsx": true, ".jsx": true,
+	".py": true, ".java": true, ".kt": true, ".rs": true, ".c": true, ".h": true, ".cc": true,
+	".cpp": true, ".hpp": true, ".cs": true, ".rb": true, ".php": true, ".swift": true, ".scala": true,
+}
+
+// skipDirs are never counted: VCS data, vendored and generated code.
+var skipDirs = map[string]bool{".git": true, "vendor": true, "node_modules": true, "third_party": true, "dist": true}
+
+// pyNameRx matches the project name at the start of a requirement line;
+// comments and options ("-r", "--hash") do not match.
+var pyNameRx = regexp.MustCompile(`^\s*([A-Za-z0-9][A-Za-z0-9._-]*)`)
+
+// locFromDir counts code lines with scc if available, otherwise counts the
+// non-blank lines of known source files.
+func locFromDir(dir string) (int, string, error) {
+	if _, err := exec.LookPath("scc"); err == nil {
+		out, err := exec.Command("scc", "--format", "json", dir).Output()
+		if err == nil {
+			var langs []struct {
+				Code int `json:"Code"`
+			}
+			if err := json.Unmarshal(out, &langs); err == nil {
+				loc := 0
+				for _, l := range langs {
+					loc += l.Code
+				}
+				return loc, "scc", nil
+			}
+		}
+		slog.Warn("scc failed, counting lines myself", "err", err)
+	}
+	loc := 0
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != dir && skipDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !sourceExts[strings.ToLower(filepath.Ext(p))] {
+			return nil
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		sc := bufio.NewScanner(f)
+		sc.Buffer(make([]byte, 64*1024), 1024*1024)
+		for sc.Scan() {
+			if strings.TrimSpace(sc.Text()) != "" {
+				loc++
+			}
+		}
+		return nil
+	})
+	return loc, "non-blank lines", err
+}
+
+// depsFromDir counts the direct dependencies declared in the manifests at
+// the root of dir.
+func depsFromDir(dir string) (int, error) {
+	seen := map[string]bool{}
+	if b, err := os.ReadFile(filepath.Join(dir, "package.json")); err == nil {
+		var pj struct {
+			Dependencies    map[string]string `json:"dependencies"`
+			DevDependencies map[string]string `json:"devDependencies"`
+		}
+		if err := json.Unmarshal(b, &pj); err != nil {
+			return 0, fmt.Errorf("package.json: %w", err)
+		}
+		for d := range pj.Dependencies {
+			seen["npm:"+d] = true
+		}
+		for d := range pj.DevDependencies {
+			seen["npm:"+d] = true
+		}
+	}
+	if b, err := os.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+		sc := bufio.NewScanner(strings.NewReader(string(b)))
+		inBlock := false
+		for sc.Scan() {
+			line := strings.TrimSpace(sc.Text())
+			switch {
+			case strings.HasPrefix(line, "require ("):
+				inBlock = true
+				continue
+			case inBlock && line == ")":
+				inBlock = false
+				continue
+			}
+			if m := goRequireRx.FindStringSubmatch(line); m != nil && (inBlock || strings.HasPrefix(line, "require ")) {
+				seen["go:"+m[1]] = true
+			}
+		}
+	}
+	if b, err := os.ReadFile(filepath.Join(dir, "requirements.txt")); err == nil {
+		sc := bufio.NewScanner(strings.NewReader(string(b)))
+		for sc.Scan() {
+			if m := pyNameRx.FindStringSubmatch(sc.Text()); m != nil {
+				seen["pypi:"+strings.ToLower(m[1])] = true
+			}
+		}
+	}
+	if len(seen) == 0 {
+		return 0, fmt.Errorf("no dependencies found in %s", dir)
+	}
+	return len(seen), nil
+}
+
+// locFromGitHub estimates the line count from the languages API.
+func locFromGitHub(slug string) (int, error) {
+	var langs map[string]int
+	if err := ghJSON("https://api.github.com/repos/"+slug+"/languages", &langs); err != nil {
+		return 0, err
+	}
+	bytes := 0
+	for _, b := range langs {
+		bytes += b
+	}
+	return bytes / bytesPerLine, nil
+}
+
+// depsFromGitHub counts the packages in the repository's SBOM (dependency
+// graph), minus the repository itself.
+func depsFromGitHub(slug string) (int, error) {
+	var sbom struct {
+		SBOM struct {
+			Packages []struct {
+				Name string `json:"name"`
+			} `json:"packages"`
+		} `json:"sbom"`
+	}
+	if err := ghJSON("https://api.github.com/repos/"+slug+"/dependency-graph/sbom", &sbom); err != nil {
+		return 0, err
+	}
+	n := len(sbom.SBOM.Packages)
+	if n > 0 {
+		n-- // the repository itself
+	}
+	return n, nil
+}
+
+// ghJSON is getJSON with the GH_PAT token, if set.
+func ghJSON(url string, v any) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	if tok := os.Getenv("GH_PAT"); tok != "" {
+		req.Header.Set("Authorization", "Bearer "+tok)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+/* ---------- OSV files (-json) ---------- */
+
+// jsonFiles collects every -json value; the flag may be repeated and each
+// value may be a file or a directory of *.json files.
+type jsonFiles []string
+
+func init() {
+	flag.Var(&jsonIn, "json", "OSV JSON file or directory of them; repeat to merge several (aliases are deduplicated)")
+}
+
+func (j *jsonFiles) String() string { return strings.Join(*j, ",") }
+
+func (j *jsonFiles) Set(v string) error {
+	*j = append(*j, v)
+	return nil
+}
+
+// osvPaths expands directories to the *.json files below them, sorted so
+// that the first record of a duplicate group is stable between runs.
+func osvPaths(args []string) ([]string, error) {
+	var paths []string
+	for _, a := range args {
+		fi, err := os.Stat(a)
+		if err != nil {
+			return nil, err
+		}
+		if !fi.IsDir() {
+			paths = append(paths, a)
+			continue
+		}
+		var found []string
+		err = filepath.WalkDir(a, func(p string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && strings.EqualFold(filepath.Ext(p), ".json") {
+				found = append(found, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(found) == 0 {
+			return nil, fmt.Errorf("%s: no .json files", a)
+		}
+		slices.Sort(found)
+		paths = append(paths, found...)
+	}
+	return paths, nil
+}
+
+// readOSVFile accepts both a {"vulns": [...]} export and a single OSV
+// record as published by osv.dev and the GHSA/PyPA databases.
+func readOSVFile(path string) ([]osvVuln, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var probe struct {
+		ID    string          `json:"id"`
+		Vulns json.RawMessage `json:"vulns"`
+	}
+	if err := json.Unmarshal(b, &probe); err != nil {
+		return nil, err
+	}
+	if probe.Vulns == nil && probe.ID != "" {
+		var v osvVuln
+		if err := json.Unmarshal(b, &v); err != nil {
+			return nil, err
+		}
+		return []osvVuln{v}, nil
+	}
+	var osv osvFile
+	if err := json.Unmarshal(b, &osv); err != nil {
+		return nil, err
+	}
+	return osv.Vulns, nil
+}
+
+// mergeVulns drops records that describe the same vulnerability under
+// another ID (GHSA-… vs. PYSEC-… vs. CVE-…): two records belong together when
+// one's ID or aliases intersect the other's. The first record of a group is
+// kept, unless a later one is a GHSA advisory, which carries the severity
+// label; the aliases of all records are merged into the survivor.
+func mergeVulns(vulns []osvVuln) []osvVuln {
+	var out []osvVuln
+	group := map[string]int{} // ID or alias -> index in out
+	for _, v := range vulns {
+		keys := append([]string{v.ID}, v.Aliases...)
+		idx := -1
+		for _, k := range keys {
+			if i, ok := group[k]; ok {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			idx = len(out)
+			out = append(out, v)
+		} else {
+			kept := out[idx]
+			if !strings.HasPrefix(kept.ID, "GHSA-") && strings.HasPrefix(v.ID, "GHSA-") {
+				kept, v = v, kept
+			}
+			kept.Aliases = mergeAliases(kept.ID, kept.Aliases, v.ID, v.Aliases)
+			slog.Debug("duplicate advisory merged", "id", v.ID, "into", kept.ID)
+			out[idx] = kept
+		}
+		for _, k := range append([]string{out[idx].ID}, out[idx].Aliases...) {
+			group[k] = idx
+		}
+		for _, k := range keys {
+			group[k] = idx
+		}
+	}
+	return out
+}
+
+// mergeAliases returns the union of both alias lists plus the other ID,
+// without id itself.
+func mergeAliases(id string, aliases []string, otherID string, other []string) []string {
+	var out []string
+	for _, a := range append(append(slices.Clone(aliases), otherID), other...) {
+		if a != id && !slices.Contains(out, a) {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// loadOSVFiles reads all -json inputs and merges their advisories. The
+// returned source names the single file or lists all of them.
+func loadOSVFiles(args []string) ([]osvVuln, string, error) {
+	paths, err := osvPaths(args)
+	if err != nil {
+		return nil, "", err
+	}
+	var all []osvVuln
+	for _, p := range paths {
+		vs, err := readOSVFile(p)
+		if err != nil {
+			return nil, "", fmt.Errorf("%s: %w", p, err)
+		}
+		all = append(all, vs...)
+	}
+	merged := mergeVulns(all)
+	if len(paths) > 1 {
+		slog.Info("OSV files merged", "files", len(paths), "records", len(all), "advisories", len(merged))
+	}
+	return merged, strings.Join(paths, ", "), nil
+}
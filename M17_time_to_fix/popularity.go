@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"golang.org/x/mod/semver"
+
+	"baa_fs25/shared/i18n"
+)
+
+/* ---------- Package popularity (-popularity) ---------- */
+
+// Download counts are taken for the last week from the public statistics
+// services: api.npmjs.org (package total and per version) and pypistats.org
+// (package total only). Go modules have no public download statistics – the
+// module proxy does not publish any – so Go rows and all other ecosystems
+// keep nil.
+const (
+	npmDownloadsAPI = "https://api.npmjs.org"
+	pypiStatsAPI    = "https://pypistats.org/api"
+)
+
+// popularity holds the downloads of one registry package; byVersion is nil
+// where the service has no per-version counts.
+type popularity struct {
+	total     *int64
+	byVersion map[string]int64
+}
+
+// addPopularity sets r.downloads and, for npm, r.affDownloads (downloads of
+// the versions in [introTag, fixTag)) on every row with a registry package.
+func addPopularity(rows []row) {
+	pops := map[string]*popularity{} // eco/pkg -> counts, nil if unavailable
+	for i := range rows {
+		r := &rows[i]
+		if r.eco == "" {
+			continue
+		}
+		key := r.eco + "/" + r.regPkg
+		p, seen := pops[key]
+		if !seen {
+			var err error
+			if p, err = fetchPopularity(r.eco, r.regPkg); err != nil {
+				slog.Warn("download counts unavailable", "eco", r.eco, "pkg", r.regPkg, "err", err)
+			}
+			pops[key] = p
+		}
+		if p == nil {
+			continue
+		}
+		r.downloads = p.total
+		if p.byVersion != nil {
+			r.affDownloads = downloadsInRange(p.byVersion, r.introTag, r.fixTag)
+		}
+	}
+}
+
+// fetchPopularity returns nil without an error for ecosystems without a
+// statistics service.
+func fetchPopularity(eco, name string) (*popularity, error) {
+	switch eco {
+	case "npm":
+		// scoped packages keep the @ but need the slash escaped
+		esc := strings.Replace(name, "/", "%2F", 1)
+		var total struct {
+			Downloads int64 `json:"downloads"`
+		}
+		if err := getJSON(npmDownloadsAPI+"/downloads/point/last-week/"+esc, &total); err != nil {
+			return nil, err
+		}
+		var vers struct {
+			Downloads map[string]int64 `json:"downloads"`
+		}
+		if err := getJSON(npmDownloadsAPI+"/versions/"+esc+"/last-week", &vers); err != nil {
+			return nil, err
+		}
+		return &popularity{total: &total.Downloads, byVersion: vers.Downloads}, nil
+	case "PyPI":
+		var recent struct {
+			Data struct {
+				LastWeek int64 `json:"last_week"`
+			} `json:"data"`
+		}
+		u := fmt.Sprintf("%s/packages/%s/recent", pypiStatsAPI, url.PathEscape(strings.ToLower(name)))
+		if err := getJSON(u, &recent); err != nil {
+			return nil, err
+		}
+		return &popularity{total: &recent.Data.LastWeek}, nil
+	}
+	slog.Debug("no download statistics for ecosystem", "eco", eco, "pkg", name)
+	return nil, nil
+}
+
+// downloadsInRange sums the downloads of the versions in [intro, fix),
+// pre-releases included; nil if fix is not a comparable version.
+func downloadsInRange(byVersion map[string]int64, intro, fix string) *int64 {
+	lo, hi := rangeBound(intro), rangeBound(fix)
+	if hi == "" {
+		return nil
+	}
+	var n int64
+	for v, d := range byVersion {
+		sv := rangeBound(v)
+		if sv == "" {
+			continue
+		}
+		if (lo == "" || semver.Compare(sv, lo) >= 0) && semver.Compare(sv, hi) < 0 {
+			n += d
+		}
+	}
+	return &n
+}
+
+// exposureWeight is the weight of a row in the download-weighted ΔExposure:
+// the downloads of its affected versions, else those of the whole package.
+func exposureWeight(r row) *int64 {
+	if r.affDownloads != nil {
+		return r.affDownloads
+	}
+	return r.downloads
+}
+
+// weightedExposure is the mean ΔExposure weighted by exposureWeight over the
+// rows that have both; n counts them. Rows with zero downloads carry no
+// weight, so the result is nil if the total weight is zero.
+func weightedExposure(rows []row) (mean *float64, n int) {
+	var sum, weights float64
+	for _, r := range rows {
+		w := exposureWeight(r)
+		if r.dExp == nil || w == nil {
+			continue
+		}
+		sum += *r.dExp * float64(*w)
+		weights += float64(*w)
+		n++
+	}
+	if weights == 0 {
+		return nil, n
+	}
+	m := sum / weights
+	return &m, n
+}
+
+func printPopularity(mean *float64, n int) {
+	if mean == nil {
+		i18n.Printf("Ø Exposure Window gewichtet nach Downloads: n/a (%d CVEs mit Download-Zahlen)\n", n)
+		return
+	}
+	i18n.Printf("Ø Exposure Window gewichtet nach Downloads: %.1f Tage (%d CVEs, Downloads/Woche der betroffenen Versionen)\n", *mean, n)
+}
+
+// fmtCount is a table cell for an optional count.
+func fmtCount(n *int64) string {
+	if n == nil {
+		return "-"
+	}
+	return fmt.Sprint(*n)
+}
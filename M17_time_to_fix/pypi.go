@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+/* ---------- PyPI via OSV ---------- */
+
+const osvAPI = "https://api.osv.dev/v1"
+
+// pypiVulns collects all PyPI advisories for name through OSV's querybatch
+// endpoint, following next_page_token until the result set is exhausted.
+// querybatch only returns IDs, so every record is fetched in full afterwards.
+func pypiVulns(name string) ([]osvVuln, error) {
+	var ids []string
+	token := ""
+	for {
+		q := map[string]any{
+			"package": map[string]string{"name": name, "ecosystem": "PyPI"},
+		}
+		if token != "" {
+			q["page_token"] = token
+		}
+		body, _ := json.Marshal(map[string]any{"queries": []any{q}})
+		resp, err := http.Post(osvAPI+"/querybatch", "application/json", bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		var r struct {
+			Results []struct {
+				Vulns []struct {
+					ID string `json:"id"`
+				} `json:"vulns"`
+				NextPageToken string `json:"next_page_token"`
+			} `json:"results"`
+		}
+		if resp.StatusCode != 200 {
+			resp.Body.Close()
+			return nil, fmt.Errorf("osv querybatch: %s", resp.Status)
+		}
+		err = json.NewDecoder(resp.Body).Decode(&r)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if len(r.Results) == 0 {
+			break
+		}
+		for _, v := range r.Results[0].Vulns {
+			ids = append(ids, v.ID)
+		}
+		if token = r.Results[0].NextPageToken; token == "" {
+			break
+		}
+	}
+
+	var vulns []osvVuln
+	for _, id := range ids {
+		var v osvVuln
+		if err := getJSON(osvAPI+"/vulns/"+id, &v); err != nil {
+			return nil, fmt.Errorf("osv %s: %w", id, err)
+		}
+		aff := v.Affected[:0]
+		for _, a := range v.Affected {
+			if a.Package.Ecosystem == "PyPI" && strings.EqualFold(a.Package.Name, name) {
+				aff = append(aff, a)
+			}
+		}
+		v.Affected = aff
+		vulns = append(vulns, v)
+	}
+	return vulns, nil
+}
+
+// pypiDate returns the upload time of version ver of the -pkg package.
+func pypiDate(ver string) (*time.Time, error) {
+	return registryDate("PyPI", *pkg, ver)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	"baa_fs25/shared/registry"
+)
+
+/* ---------- Registry dates ---------- */
+
+// registries resolve publish dates directly from the package registry, keyed
+// by OSV ecosystem. They are tried before GitHub tags because registries
+// record every release, while tagging discipline varies between projects.
+var registries = map[string]registry.Client{
+	"npm":       &registry.NPM{},
+	"PyPI":      &registry.PyPI{},
+	"Go":        &registry.GoProxy{},
+	"Maven":     &registry.Maven{},
+	"crates.io": &registry.Crates{},
+}
+
+// platEcosystems maps -plat (libraries.io names) to OSV ecosystems.
+var platEcosystems = map[string]string{"npm": "npm", "pypi": "PyPI", "go": "Go", "maven": "Maven", "cargo": "crates.io"}
+
+// osvEcosystem returns the registries key matching -ecosystem, ignoring case;
+// ok is false for ecosystems without a date registry.
+func osvEcosystem(name string) (eco string, ok bool) {
+	for e := range registries {
+		if strings.EqualFold(e, name) {
+			return e, true
+		}
+	}
+	return "", false
+}
+
+// libioPlatform is -plat, or the libraries.io platform of -ecosystem.
+func libioPlatform() string {
+	if *plat != "" || *ecoFlag == "" {
+		return *plat
+	}
+	for p, e := range platEcosystems {
+		if e == *ecoFlag {
+			return p
+		}
+	}
+	return ""
+}
+
+// registryTarget picks the registry package of an advisory: the affected
+// package matching -pkg if there is one, otherwise the first affected package
+// of a supported ecosystem (only -ecosystem if set). eco is "" if none
+// applies.
+func registryTarget(v osvVuln) (eco, name string) {
+	for _, a := range v.Affected {
+		e, n := a.Package.Ecosystem, a.Package.Name
+		if registries[e] == nil || n == "" || (e == "Go" && (n == "stdlib" || n == "toolchain")) {
+			continue
+		}
+		if *ecoFlag != "" && e != *ecoFlag {
+			continue
+		}
+		if *pkg != "" && strings.EqualFold(n, *pkg) {
+			return e, n
+		}
+		if eco == "" {
+			eco, name = e, n
+		}
+	}
+	if eco == "" && *pkg != "" {
+		if *ecoFlag != "" {
+			return *ecoFlag, *pkg
+		}
+		if e, ok := platEcosystems[strings.ToLower(*plat)]; ok {
+			return e, *pkg
+		}
+	}
+	return eco, name
+}
+
+// purlTarget is registryTarget, falling back to the first affected package
+// for ecosystems without a date registry.
+func purlTarget(v osvVuln) (eco, name string) {
+	if eco, name = registryTarget(v); eco == "" && len(v.Affected) > 0 {
+		eco, name = v.Affected[0].Package.Ecosystem, v.Affected[0].Package.Name
+	}
+	return eco, name
+}
+
+// registryDate returns the publish date of ver in the registry of eco.
+func registryDate(eco, name, ver string) (*time.Time, error) {
+	if eco == "Go" && !strings.HasPrefix(ver, "v") {
+		ver = "v" + ver // OSV lists Go versions without the prefix
+	}
+	t, err := registries[eco].ReleaseTime(name, ver)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import "baa_fs25/shared/i18n"
+
+/* ---------- Silent fixes (fix released before disclosure) ---------- */
+
+// Disclosure classes of an advisory with a fix date and a published date.
+// A silent fix has a negative ΔExposure: users could upgrade before anyone
+// knew there was something to upgrade for. Mixing both classes pulls the
+// mean ΔExposure toward zero, so they are counted separately.
+const (
+	silentFix      = "silent-fix"
+	fixedAfterDisc = "fixed-after-disclosure"
+)
+
+// classifyDisclosure sets r.disclosure and, for silent fixes, r.dLead
+// (published − fix release, in days).
+func classifyDisclosure(r *row) {
+	if r.publishedDate == nil || r.fixDate == nil {
+		return
+	}
+	lead := r.publishedDate.Sub(*r.fixDate).Hours() / 24
+	if lead <= 0 {
+		r.disclosure = fixedAfterDisc
+		return
+	}
+	r.disclosure = silentFix
+	r.dLead = &lead
+}
+
+// silentSummary counts both classes and takes the median lead time of the
+// silent fixes.
+func silentSummary(rows []row) (silent, after int, medianLead *float64) {
+	var leads []float64
+	for _, r := range rows {
+		switch r.disclosure {
+		case silentFix:
+			silent++
+			leads = append(leads, *r.dLead)
+		case fixedAfterDisc:
+			after++
+		}
+	}
+	return silent, after, medianOf(leads)
+}
+
+func printSilent(silent, after int, medianLead *float64) {
+	if silent == 0 {
+		return
+	}
+	i18n.Printf("%d CVEs still vor der Veröffentlichung gefixt (Median %.1f Tage Vorlauf, nicht in ΔExposure), %d nach der Veröffentlichung\n",
+		silent, *medianLead, after)
+}
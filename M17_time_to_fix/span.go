@@ -0,0 +1,69 @@
+package main
+
+import (
+	"log/slog"
+	"strings"
+
+	"baa_fs25/shared/registry"
+	"golang.org/x/mod/semver"
+)
+
+/* ---------- Affected range span ---------- */
+
+// rangeVersions counts the releases of each row's registry package inside
+// the affected range [introTag, fixTag). Without an intro tag the range
+// starts at the first release. Pre-releases and versions that are not
+// semver-like are left out. Rows without a registry package keep nil.
+func rangeVersions(rows []row) {
+	lists := map[string][]string{} // eco/pkg -> versions, nil if the lookup failed
+	for i := range rows {
+		r := &rows[i]
+		l, ok := registries[r.eco].(registry.Lister)
+		if !ok {
+			continue
+		}
+		key := r.eco + "/" + r.regPkg
+		vers, seen := lists[key]
+		if !seen {
+			var err error
+			if vers, err = l.Versions(r.regPkg); err != nil {
+				slog.Debug("version list unavailable", "eco", r.eco, "pkg", r.regPkg, "err", err)
+			}
+			lists[key] = vers
+		}
+		if vers != nil {
+			r.nAffected = countInRange(vers, r.introTag, r.fixTag)
+		}
+	}
+}
+
+// countInRange returns nil if fix is not a comparable version.
+func countInRange(vers []string, intro, fix string) *int {
+	lo, hi := rangeBound(intro), rangeBound(fix)
+	if hi == "" {
+		return nil
+	}
+	n := 0
+	for _, v := range vers {
+		sv := rangeBound(v)
+		if sv == "" || semver.Prerelease(sv) != "" {
+			continue
+		}
+		if (lo == "" || semver.Compare(sv, lo) >= 0) && semver.Compare(sv, hi) < 0 {
+			n++
+		}
+	}
+	return &n
+}
+
+// rangeBound turns an OSV or registry version into canonical semver, "" if
+// it is empty or not comparable.
+func rangeBound(v string) string {
+	if v == "" || v == "0" {
+		return ""
+	}
+	if !strings.HasPrefix(v, "v") {
+		v = "v" + v
+	}
+	return semver.Canonical(v)
+}
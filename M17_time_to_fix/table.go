@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"baa_fs25/shared/i18n"
+)
+
+/* ---------- Advisory table (-columns, -no-table) ---------- */
+
+type column struct {
+	key, title string
+	right      bool // numbers are right-aligned
+}
+
+// tableColumns lists every column of the advisory table in display order.
+var tableColumns = []column{
+	{key: "id", title: "CVE-ID"},
+	{key: "severity", title: "Sev"},
+	{key: "cvss", title: "CVSS", right: true},
+	{key: "introtag", title: "Intro-Tag"},
+	{key: "fixtag", title: "Fix-Tag"},
+	{key: "published", title: "Published"},
+	{key: "introdate", title: "Intro-Date"},
+	{key: "fixdate", title: "Fix-Date"},
+	{key: "dfix", title: "ΔFix", right: true},
+	{key: "dexposure", title: "ΔExposure", right: true},
+	{key: "ddisclosure", title: "ΔDisclosure", right: true},
+	{key: "versions", title: "#Vers", right: true}, // releases in the affected range
+	{key: "span", title: "Span", right: true},      // intro -> fix release in days
+	{key: "cwe", title: "CWE"},
+	{key: "unfixed", title: "Unfixed-Branch"}, // affected majors without a fix
+	// published − fix release of silent fixes, see silent.go
+	{key: "lead", title: "Silent-Lead", right: true},
+	// last-week downloads of the affected versions (npm), see popularity.go
+	{key: "downloads", title: "Downloads", right: true},
+}
+
+// openColumns is the fixed layout of the open-advisories table.
+var openColumns = []column{
+	{key: "id", title: "CVE-ID"},
+	{key: "severity", title: "Sev"},
+	{key: "lastaffected", title: "Last-Affected"},
+	{key: "published", title: "Published"},
+	{key: "age", title: "Age", right: true},
+}
+
+const defaultColumns = "id,severity,introtag,fixtag,published,introdate,fixdate,dfix,dexposure,ddisclosure"
+
+// selectColumns resolves a comma-separated -columns value. Keys may be given
+// in any order; the table keeps that order.
+func selectColumns(spec string) ([]column, error) {
+	byKey := map[string]column{}
+	var keys []string
+	for _, c := range tableColumns {
+		byKey[c.key] = c
+		keys = append(keys, c.key)
+	}
+	var cols []column
+	for _, k := range strings.Split(spec, ",") {
+		k = strings.ToLower(strings.TrimSpace(k))
+		if k == "" {
+			continue
+		}
+		c, ok := byKey[k]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q (available: %s)", k, strings.Join(keys, ","))
+		}
+		cols = append(cols, c)
+	}
+	if len(cols) == 0 {
+		return nil, fmt.Errorf("no columns selected")
+	}
+	return cols, nil
+}
+
+// table collects the cells first so that every column gets the width of
+// its widest value.
+type table struct {
+	cols  []column
+	cells [][]string
+}
+
+func (t *table) add(row map[string]string) {
+	line := make([]string, len(t.cols))
+	for i, c := range t.cols {
+		line[i] = row[c.key]
+	}
+	t.cells = append(t.cells, line)
+}
+
+func (t *table) print() {
+	widths := make([]int, len(t.cols))
+	for i, c := range t.cols {
+		widths[i] = utf8.RuneCountInString(i18n.T(c.title))
+	}
+	for _, line := range t.cells {
+		for i, v := range line {
+			widths[i] = max(widths[i], utf8.RuneCountInString(v))
+		}
+	}
+	total := 3 * (len(widths) - 1)
+	for _, w := range widths {
+		total += w
+	}
+	titles := make([]string, len(t.cols))
+	for i, c := range t.cols {
+		titles[i] = i18n.T(c.title)
+	}
+	t.printLine(titles, widths)
+	fmt.Println(strings.Repeat("-", total))
+	for _, line := range t.cells {
+		t.printLine(line, widths)
+	}
+	fmt.Println(strings.Repeat("-", total))
+}
+
+func (t *table) printLine(vals []string, widths []int) {
+	parts := make([]string, len(vals))
+	for i, v := range vals {
+		pad := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(v))
+		if t.cols[i].right {
+			parts[i] = pad + v
+		} else {
+			parts[i] = v + pad
+		}
+	}
+	fmt.Println(strings.TrimRight(strings.Join(parts, " | "), " "))
+}
@@ -2,56 +2,117 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"sort"
 	"strings"
 	"time"
 
+	"baa_fs25/shared/anon"
+	"baa_fs25/shared/clones"
+	"baa_fs25/shared/exitcode"
+	"baa_fs25/shared/httpcache"
+	"baa_fs25/shared/i18n"
+	"baa_fs25/shared/logging"
+	"baa_fs25/shared/netcfg"
+	"baa_fs25/shared/provenance"
+	"baa_fs25/shared/purl"
+	"baa_fs25/shared/registry"
+	"baa_fs25/shared/report"
+	"baa_fs25/shared/schema"
 	"golang.org/x/mod/semver"
 )
 
 /* ---------- Flags ---------- */
 
 var (
-	jsonFile = flag.String("json", "", "OSV JSON file")
-	repoSlug = flag.String("repo", "", "owner/repo on GitHub")
-	plat     = flag.String("plat", "", "libraries.io platform (npm, pypi …)")
-	pkg      = flag.String("pkg", "", "package name on that platform")
+	jsonIn    jsonFiles
+	source    = flag.String("source", "file", "advisory source: file (-json), govulndb (-pkg = Go module) or pypi (-pkg = PyPI package)")
+	repoSlug  = flag.String("repo", "", "owner/repo on GitHub; after renames or org moves a comma-separated list, current location first (tags are looked up in each in order)")
+	plat      = flag.String("plat", "", "libraries.io platform (npm, pypi …)")
+	pkg       = flag.String("pkg", "", "package name on that platform")
+	ecoFlag   = flag.String("ecosystem", "", "OSV ecosystem of -pkg for registry dates: npm, PyPI, Go, Maven (group:artifact), crates.io (default: from the advisories)")
+	tagFmt    = flag.String("tag-format", "", "tag template tried first, e.g. \"{pkg}/v{version}\" ({version}, {pkg})")
+	outFile   = flag.String("out", "", "also write results as JSON (\"-\" = stdout)")
+	emitFile  = flag.String("emit-osv", "", "write the OSV records enriched with dates and deltas")
+	normFlag  = flag.Bool("normalize", false, "add advisories per KLOC and per dependency")
+	sizeDir   = flag.String("size-dir", "", "checkout to measure for -normalize (default: GitHub languages and dependency graph of -repo)")
+	cvssFlag  = flag.Bool("cvss", false, "count advisories by CVSS base score instead of the severity label; adds a CVSS-weighted ΔFix and a breakdown by band")
+	cweFlag   = flag.Bool("cwe", false, "add median and mean ΔFix per vulnerability class (CWE)")
+	popFlag   = flag.Bool("popularity", false, "add last-week download counts (npm, PyPI) per advisory and a ΔExposure weighted by the downloads of the affected versions")
+	columns   = flag.String("columns", "", "comma-separated table columns (default: "+defaultColumns+", plus cvss with -cvss)")
+	noTable   = flag.Bool("no-table", false, "print only the summaries, no per-advisory tables")
+	tuiFlag   = flag.Bool("tui", false, "explore the advisories interactively after the run (sort, filter, ranges and references); implies -no-table")
+	chartFile = flag.String("chart", "", "write severity distribution and cumulative fix curve (.svg or .html)")
+	downRepo  = flag.String("downstream-repo", "", "dependent repo (dir or clone URL); reports when it adopted each fix of -pkg")
+	cacheDir  = flag.String("cache-dir", httpcache.DefaultDir("ttf"), "disk cache for GitHub, libraries.io and OSV responses (revalidated via ETag)")
+	noCache   = flag.Bool("no-cache", false, "disable the response cache")
+	ckptFile  = flag.String("checkpoint", "", "keep resolved dates in this file so an interrupted run resumes where it stopped")
+	logOpts   = logging.Register(flag.CommandLine)
+	netOpts   = netcfg.Register(flag.CommandLine)
+	anonOpts  = anon.Register(flag.CommandLine)
+	schemaVer = schema.Register(flag.CommandLine)
+	cloneOpts = clones.Register(flag.CommandLine, "")
+	langOpts  = i18n.Register(flag.CommandLine)
+	exitOpts  = exitcode.Register(flag.CommandLine)
 )
 
 const dateFmt = "2006-01-02 15:04"
 
+// repoAliases are the further slugs of a comma-separated -repo, i.e. former
+// locations of the repo; *repoSlug keeps the first one.
+var repoAliases []string
+
 /* ---------- Types ---------- */
 
 type osvFile struct {
-	Vulns []struct {
-		ID string `json:"id"`
-
-		// ➊  NEU: Severity in die Struktur aufnehmen
-		EcosystemSpecific struct {
-			Severity string `json:"severity"`
-		} `json:"ecosystem_specific"`
-
-		DatabaseSpecific struct {
-			Severity       string    `json:"severity"`
-			NVDPublishedAt time.Time `json:"nvd_published_at"`
-		} `json:"database_specific"`
-
-		Published string `json:"published"`
-
-		Affected []struct {
-			Ranges []struct {
-				Type   string `json:"type"`
-				Events []struct {
-					Introduced string `json:"introduced,omitempty"`
-					Fixed      string `json:"fixed,omitempty"`
-				} `json:"events"`
-			} `json:"ranges"`
-		} `json:"affected"`
-	} `json:"vulns"`
+	Vulns []osvVuln `json:"vulns"`
+}
+
+type osvVuln struct {
+	raw json.RawMessage // original record, see emit.go
+
+	ID      string   `json:"id"`
+	Aliases []string `json:"aliases"`
+
+	// ➊  NEU: Severity in die Struktur aufnehmen
+	EcosystemSpecific struct {
+		Severity string `json:"severity"`
+	} `json:"ecosystem_specific"`
+
+	DatabaseSpecific struct {
+		Severity       string    `json:"severity"`
+		NVDPublishedAt time.Time `json:"nvd_published_at"`
+		CWEIDs         []string  `json:"cwe_ids"` // GHSA, taken over from NVD
+	} `json:"database_specific"`
+
+	Published string `json:"published"`
+
+	Severity []osvSeverity `json:"severity"`
+
+	References []struct {
+		Type string `json:"type"`
+		URL  string `json:"url"`
+	} `json:"references"` // shown by -tui
+
+	Affected []struct {
+		Package struct {
+			Name      string `json:"name"`
+			Ecosystem string `json:"ecosystem"`
+		} `json:"package"`
+		Ranges []struct {
+			Type   string `json:"type"`
+			Events []struct {
+				Introduced   string `json:"introduced,omitempty"`
+				Fixed        string `json:"fixed,omitempty"`
+				LastAffected string `json:"last_affected,omitempty"`
+			} `json:"events"`
+		} `json:"ranges"`
+	} `json:"affected"`
 }
 
 type row struct {
@@ -59,16 +120,160 @@ type row struct {
 	introTag, fixTag   string
 	introDate, fixDate *time.Time
 	publishedDate      *time.Time
+	dFix, dExp, dDisc  *float64 // in days, nil if not computable
+	adopt              *adoption
+	dAdopt             *float64 // fix release -> downstream adoption
+	cvss               *float64 // base score, nil if the advisory has none
+	eco, regPkg        string   // registry package for the dates, see regdate.go
+	nAffected          *int     // releases in [introTag, fixTag), see span.go
+	dSpan              *float64 // intro -> fix release, regardless of severity
+	cwes               []string // CWE IDs, see cwe.go
+	purlEco, purlPkg   string   // affected package for the purl, see purlTarget
+	unfixed            []string // affected majors without a fix, see branch.go
+	disclosure         string   // silent-fix | fixed-after-disclosure, see silent.go
+	dLead              *float64 // silent fixes: published − fix release
+	downloads          *int64   // last week, whole package, see popularity.go
+	affDownloads       *int64   // last week, versions in [introTag, fixTag)
+}
+
+type osvSeverity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"` // vector or number
+}
+
+/* ---------- JSON output ---------- */
+
+type advisoryOut struct {
+	ID                  string     `json:"id"`
+	Purl                string     `json:"purl,omitempty"` // affected package at the fix version
+	Severity            string     `json:"severity"`
+	CVSS                *float64   `json:"cvss,omitempty"`
+	IntroTag            string     `json:"intro_tag,omitempty"`
+	FixTag              string     `json:"fix_tag"`
+	Published           *time.Time `json:"published,omitempty"`
+	IntroDate           *time.Time `json:"intro_date,omitempty"`
+	FixDate             *time.Time `json:"fix_date,omitempty"`
+	DeltaFixDays        *float64   `json:"delta_fix_days,omitempty"`
+	DeltaExposureDays   *float64   `json:"delta_exposure_days,omitempty"`
+	DeltaDisclosureDays *float64   `json:"delta_disclosure_days,omitempty"` // published − intro_date
+	AdoptCommit         string     `json:"adopt_commit,omitempty"`
+	AdoptDate           *time.Time `json:"adopt_date,omitempty"`
+	DeltaAdoptDays      *float64   `json:"delta_adopt_days,omitempty"`
+	AffectedVersions    *int       `json:"affected_versions,omitempty"`  // releases in [intro_tag, fix_tag)
+	AffectedSpanDays    *float64   `json:"affected_span_days,omitempty"` // fix_date − intro_date
+	CWEs                []string   `json:"cwe,omitempty"`
+	// majors that never got a fix; with a cross-major fix_tag there is no ΔFix
+	UnfixedBranches []string `json:"unfixed_branches,omitempty"`
+	UnfixedOnBranch bool     `json:"unfixed_on_branch,omitempty"`
+	// silent-fix (fix released before the advisory) or fixed-after-disclosure
+	Disclosure     string   `json:"disclosure,omitempty"`
+	SilentLeadDays *float64 `json:"silent_lead_days,omitempty"` // published − fix_date
+	// only with -popularity; last-week downloads of the package and of the
+	// versions in [intro_tag, fix_tag)
+	Downloads         *int64 `json:"downloads_last_week,omitempty"`
+	AffectedDownloads *int64 `json:"affected_downloads_last_week,omitempty"`
+}
+
+// openOut is an advisory without a fixed version (none at all, or only
+// last_affected events).
+type openOut struct {
+	ID           string     `json:"id"`
+	Purl         string     `json:"purl,omitempty"` // affected package, no version
+	Severity     string     `json:"severity"`
+	LastAffected string     `json:"last_affected,omitempty"`
+	Published    *time.Time `json:"published,omitempty"`
+	AgeDays      *float64   `json:"age_days,omitempty"`
+}
+
+type summaryOut struct {
+	MeanFixDays          *float64 `json:"mean_fix_days"`
+	FixCount             int      `json:"fix_count"`
+	MeanExposureDays     *float64 `json:"mean_exposure_days"`
+	ExposureCount        int      `json:"exposure_count"`
+	NegativeExposure     int      `json:"negative_exposure"` // = silent_fix_count
+	SilentFixCount       int      `json:"silent_fix_count"`
+	FixedAfterDisclosure int      `json:"fixed_after_disclosure_count"`
+	MedianSilentLeadDays *float64 `json:"median_silent_lead_days,omitempty"`
+	MeanDisclosureDays   *float64 `json:"mean_disclosure_days"`
+	DisclosureCount      int      `json:"disclosure_count"`
+	NegativeDisclosure   int      `json:"negative_disclosure"`
+	Ignored              int      `json:"ignored"`
+	UnfixedOnBranch      int      `json:"unfixed_on_branch"`
+	OpenCount            int      `json:"open_count"`
+	MeanOpenAgeDays      *float64 `json:"mean_open_age_days,omitempty"`
+	MeanAdoptDays        *float64 `json:"mean_adopt_days,omitempty"`
+	AdoptCount           int      `json:"adopt_count,omitempty"`
+	MeanAffectedVersions *float64 `json:"mean_affected_versions,omitempty"`
+	AffectedCount        int      `json:"affected_count,omitempty"`
+	// only with -cvss
+	CVSSWeightedFixDays *float64   `json:"cvss_weighted_fix_days,omitempty"`
+	CVSSBands           []cvssBand `json:"cvss_bands,omitempty"`
+	// only with -cwe
+	CWEGroups []cweGroup `json:"cwe_groups,omitempty"`
+	// only with -popularity
+	DownloadWeightedExposureDays *float64 `json:"download_weighted_exposure_days,omitempty"`
+	DownloadWeightedCount        int      `json:"download_weighted_count,omitempty"`
+}
+
+type resultOut struct {
+	Schema     string        `json:"schema"` // see shared/schema
+	Repo       string        `json:"repo"`
+	Source     string        `json:"source"`
+	Advisories []advisoryOut `json:"advisories"`
+	Open       []openOut     `json:"open,omitempty"`
+	Summary    summaryOut    `json:"summary"`
+	Normalized *normOut      `json:"normalized,omitempty"`
+
+	Provenance *provenance.Info `json:"provenance,omitempty"`
 }
 
 /* ---------- GitHub helper ---------- */
 
+// tagFallbacks are tried after -tag-format; they cover the usual monorepo and
+// release-branch naming schemes.
+var tagFallbacks = []string{
+	"{version}",
+	"v{version}",
+	"{pkg}/v{version}",
+	"{pkg}/{version}",
+	"{pkg}-v{version}",
+	"{pkg}-{version}",
+	"{pkg}@{version}",
+	"release-{version}",
+	"release/v{version}",
+}
+
+// tagCandidates expands the templates for a version, without duplicates.
+func tagCandidates(slug, version string) []string {
+	name := *pkg
+	if name == "" {
+		name = slug[strings.LastIndex(slug, "/")+1:]
+	}
+	version = strings.TrimPrefix(version, "v")
+	templates := tagFallbacks
+	if *tagFmt != "" {
+		templates = append([]string{*tagFmt}, tagFallbacks...)
+	}
+	seen := map[string]bool{}
+	var tags []string
+	for _, tpl := range templates {
+		t := strings.NewReplacer("{version}", version, "{pkg}", name).Replace(tpl)
+		if !seen[t] {
+			seen[t] = true
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+// ghTagDate looks for a GitHub release under every tag candidate and, if
+// there is none, uses the date of the first existing git tag instead.
 func ghTagDate(slug, tag string) (*time.Time, error) {
 	tok := os.Getenv("GH_PAT")
 	if tok == "" {
 		return nil, nil
 	}
-	try := []string{tag, "v" + tag}
+	try := tagCandidates(slug, tag)
 	for _, t := range try {
 		u := fmt.Sprintf("https://api.github.com/repos/%s/releases/tags/%s", slug, t)
 		req, _ := http.NewRequest("GET", u, nil)
@@ -78,15 +283,34 @@ func ghTagDate(slug, tag string) (*time.Time, error) {
 		if err != nil {
 			return nil, err
 		}
+		if resp.StatusCode == http.StatusTooManyRequests ||
+			resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			resp.Body.Close()
+			return nil, fmt.Errorf("github %s: %s: %w", slug, resp.Status, registry.ErrRateLimited)
+		}
 		if resp.StatusCode == 200 {
 			var v struct {
 				PublishedAt time.Time `json:"published_at"`
 			}
-			if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+			err := json.NewDecoder(resp.Body).Decode(&v)
+			resp.Body.Close()
+			if err != nil {
 				return nil, err
 			}
 			return &v.PublishedAt, nil
 		}
+		resp.Body.Close()
+		slog.Debug("release tag not found", "repo", slug, "tag", t, "status", resp.StatusCode)
+	}
+	gh := registry.GitHub{Token: tok}
+	for _, t := range try {
+		d, err := gh.TagTime(slug, t)
+		if err == nil {
+			return &d, nil
+		}
+		if errors.Is(err, registry.ErrRateLimited) {
+			return nil, err
+		}
 	}
 	return nil, nil
 }
@@ -100,8 +324,12 @@ func libioDate(platform, name, ver string) (*time.Time, error) {
 	}
 	u := fmt.Sprintf("https://libraries.io/api/%s/%s?api_key=%s", platform, name, key)
 	resp, err := http.Get(u)
-	if err != nil || resp.StatusCode != 200 {
-		return nil, nil
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("libraries.io %s", resp.Status)
 	}
 	var r struct {
 		Versions []struct {
@@ -110,7 +338,7 @@ func libioDate(platform, name, ver string) (*time.Time, error) {
 		} `json:"versions"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
-		return nil, nil
+		return nil, err
 	}
 	for _, v := range r.Versions {
 		if v.Number == ver {
@@ -120,35 +348,147 @@ func libioDate(platform, name, ver string) (*time.Time, error) {
 	return nil, nil
 }
 
+// resolveDate takes the publish date from the package registry (npm, PyPI,
+// Go proxy, Maven Central, crates.io; see regdate.go) and falls back to GitHub releases/tags and then
+// libraries.io. Lookup errors are logged with context and treated as "not
+// found"; ok is false if one occurred and no date was found, so that
+// -checkpoint retries the advisory. With -source pypi only PyPI is asked.
+func resolveDate(r *row, tag string) (d *time.Time, ok bool) {
+	id := r.id
+	if *source == "pypi" {
+		d, err := pypiDate(tag)
+		if err != nil {
+			slog.Warn("PyPI lookup failed", "id", id, "pkg", *pkg, "ver", tag, "err", err)
+		}
+		return d, err == nil
+	}
+	ok = true
+	var regErr error
+	if r.eco != "" {
+		if d, regErr = registryDate(r.eco, r.regPkg, tag); regErr == nil {
+			return d, true
+		}
+		slog.Debug("registry lookup failed, trying GitHub", "id", id, "eco", r.eco, "pkg", r.regPkg, "ver", tag, "err", regErr)
+	}
+	var err error
+	for _, slug := range append([]string{*repoSlug}, repoAliases...) {
+		if d, err = ghTagDate(slug, tag); err != nil {
+			slog.Warn("GitHub lookup failed", "id", id, "repo", slug, "tag", tag, "err", err)
+			ok = false
+			if errors.Is(err, registry.ErrRateLimited) {
+				break
+			}
+		}
+		if d != nil {
+			if slug != *repoSlug {
+				slog.Debug("tag found under former repo location", "id", id, "repo", slug, "tag", tag)
+			}
+			break
+		}
+	}
+	if p := libioPlatform(); d == nil && p != "" {
+		d, err = libioDate(p, *pkg, tag)
+		if err != nil {
+			slog.Warn("libraries.io lookup failed", "id", id, "plat", p, "pkg", *pkg, "ver", tag, "err", err)
+			ok = false
+		}
+	}
+	return d, ok && (d != nil || regErr == nil)
+}
+
 /* ---------- main ---------- */
 
 func main() {
 	var ignored int
 	flag.Parse()
-	if *jsonFile == "" || *repoSlug == "" {
-		fmt.Println("usage: go run ttf_fix.go -json osv.json -repo owner/repo [-plat npm -pkg express]")
-		return
+	provenance.Start("ttf", flag.CommandLine)
+	if err := logOpts.Setup(); err != nil {
+		logging.Fatal("logging setup failed", "err", err)
+	}
+	if err := netOpts.Setup(); err != nil {
+		logging.Fatal("network setup failed", "err", err)
+	}
+	if err := anonOpts.Setup(); err != nil {
+		logging.Usage("invalid -anonymize", "err", err)
+	}
+	if err := schemaVer.Setup(); err != nil {
+		logging.Usage("invalid -schema-version", "err", err)
+	}
+	if err := cloneOpts.Setup(); err != nil {
+		logging.Usage("invalid mirror flags", "err", err)
+	}
+	if err := langOpts.Setup(); err != nil {
+		logging.Usage("invalid -lang", "err", err)
+	}
+	if err := exitOpts.Setup(); err != nil {
+		logging.Usage("invalid -max-skipped", "err", err)
+	}
+	if !*noCache {
+		// every lookup goes through http.DefaultClient
+		if err := httpcache.Install(http.DefaultClient, *cacheDir); err != nil {
+			slog.Warn("response cache disabled", "dir", *cacheDir, "err", err)
+		}
+	}
+	if slugs := strings.Split(*repoSlug, ","); len(slugs) > 1 {
+		*repoSlug = strings.TrimSpace(slugs[0])
+		for _, s := range slugs[1:] {
+			if s = strings.TrimSpace(s); s != "" && s != *repoSlug {
+				repoAliases = append(repoAliases, s)
+			}
+		}
+	}
+	if *source == "govulndb" && *repoSlug == "" && strings.HasPrefix(*pkg, "github.com/") {
+		// github.com/owner/repo[/vN] -> owner/repo
+		if parts := strings.Split(*pkg, "/"); len(parts) >= 3 {
+			*repoSlug = parts[1] + "/" + parts[2]
+		}
+	}
+	if (*repoSlug == "" && *source != "pypi") || (*source == "file" && len(jsonIn) == 0) || (*source != "file" && *pkg == "") {
+		fmt.Fprintln(os.Stderr, "usage: go run . -json osv.json|dir [-json ...] -repo owner/repo[,old-owner/repo...] [-plat npm -pkg express] [-ecosystem npm|PyPI|Go|Maven|crates.io] [-tag-format v{version}] [-out res.json] [-emit-osv osv.out.json] [-downstream-repo dir|url [-mirror-dir dir [-clone-quota 50G]]] [-normalize [-size-dir dir]] [-chart fix.svg] [-cvss] [-cwe] [-popularity] [-columns id,severity,dfix,...] [-no-table] [-tui] [-cache-dir dir|-no-cache] [-anonymize] [-schema-version N] [-checkpoint file] [-ca-bundle pem] [-insecure-skip-verify] [-log-level L] [-log-format text|json] [-lang en|de] [-max-skipped F]")
+		fmt.Fprintln(os.Stderr, "       go run . -source govulndb -pkg <go-module> [-repo owner/repo] [-out res.json]")
+		fmt.Fprintln(os.Stderr, "       go run . -source pypi -pkg <pypi-package> [-out res.json]")
+		os.Exit(exitcode.Usage)
+	}
+	if *ecoFlag != "" {
+		e, ok := osvEcosystem(*ecoFlag)
+		if !ok {
+			logging.Usage("unsupported -ecosystem (npm, PyPI, Go, Maven, crates.io)", "ecosystem", *ecoFlag)
+		}
+		*ecoFlag = e
+	}
+	if *tuiFlag {
+		*noTable = true
 	}
 	if *plat != "" && *pkg == "" {
 		parts := strings.Split(*repoSlug, "/")
 		*pkg = parts[len(parts)-1]
 	}
 
-	// load OSV
-	f, err := os.Open(*jsonFile)
+	colSpec := *columns
+	if colSpec == "" {
+		colSpec = defaultColumns
+		if *cvssFlag {
+			colSpec = strings.Replace(colSpec, "severity,", "severity,cvss,", 1)
+		}
+	}
+	cols, err := selectColumns(colSpec)
 	if err != nil {
-		panic(err)
+		logging.Usage("invalid -columns", "err", err)
 	}
-	var osv osvFile
-	if err := json.NewDecoder(f).Decode(&osv); err != nil {
-		panic(err)
+
+	vulns, src := loadVulns()
+	subject := *repoSlug
+	if subject == "" {
+		subject = *pkg
 	}
 
 	// build rows
 	var rows []row
-	for _, v := range osv.Vulns {
+	var open []openOut
+	for _, v := range vulns {
 		var fixes []string
 		introForFix := map[string]string{} // fixTag -> introTag
+		var lastAffected string
 
 		for _, aff := range v.Affected {
 			for _, rg := range aff.Ranges {
@@ -164,21 +504,12 @@ func main() {
 						fixes = append(fixes, ev.Fixed)
 						introForFix[ev.Fixed] = curIntro
 					}
+					if ev.LastAffected != "" {
+						lastAffected = ev.LastAffected
+					}
 				}
 			}
 		}
-		if len(fixes) == 0 {
-			continue
-		}
-		// pick earliest fixed (smallest semver)
-		sort.Slice(fixes, func(i, j int) bool {
-			return semver.Compare("v"+fixes[i], "v"+fixes[j]) < 0
-		})
-		fix := fixes[0]
-		intro := introForFix[fix]
-		if intro == "0" { // treat "0" as unspecified
-			intro = ""
-		}
 
 		sev := strings.ToUpper(v.EcosystemSpecific.Severity)
 		if sev == "" {
@@ -212,43 +543,93 @@ func main() {
 			published = published2
 		}
 
+		if len(fixes) == 0 {
+			// no fixed version: still open, or only an upper bound is known
+			eco, name := purlTarget(v)
+			o := openOut{ID: v.ID, Purl: purl.For(eco, name, ""), Severity: sev, LastAffected: lastAffected, Published: published}
+			if published != nil {
+				age := time.Since(*published).Hours() / 24
+				o.AgeDays = &age
+			}
+			open = append(open, o)
+			continue
+		}
+		// pick earliest fixed (smallest semver)
+		sort.Slice(fixes, func(i, j int) bool {
+			return semver.Compare("v"+fixes[i], "v"+fixes[j]) < 0
+		})
+		fix := fixes[0]
+		intro := introForFix[fix]
+		if intro == "0" { // treat "0" as unspecified
+			intro = ""
+		}
+
+		eco, regPkg := registryTarget(v)
+		purlEco, purlPkg := purlTarget(v)
 		rows = append(rows, row{
 			id: v.ID, severity: sev, introTag: intro, fixTag: fix,
-			publishedDate: published,
+			publishedDate: published, cvss: cvssScore(v.Severity),
+			eco: eco, regPkg: regPkg, cwes: cweIDs(v),
+			purlEco: purlEco, purlPkg: purlPkg, unfixed: unfixedBranches(v),
 		})
 	}
 
 	/* ---- fetch dates ---- */
+	cp := loadCheckpoint(*ckptFile, subject)
+	failed := 0
 	for i := range rows {
-		if rows[i].introTag != "" {
-			rows[i].introDate, _ = ghTagDate(*repoSlug, rows[i].introTag)
-			if rows[i].introDate == nil && *plat != "" {
-				rows[i].introDate, _ = libioDate(*plat, *pkg, rows[i].introTag)
-			}
+		r := &rows[i]
+		if done, ok := cp.Rows[r.id]; ok {
+			r.introDate, r.fixDate = done.IntroDate, done.FixDate
+			continue
+		}
+		introOK := true
+		if r.introTag != "" {
+			r.introDate, introOK = resolveDate(r, r.introTag)
 		}
-		rows[i].fixDate, _ = ghTagDate(*repoSlug, rows[i].fixTag)
-		if rows[i].fixDate == nil && *plat != "" {
-			rows[i].fixDate, _ = libioDate(*plat, *pkg, rows[i].fixTag)
+		var fixOK bool
+		r.fixDate, fixOK = resolveDate(r, r.fixTag)
+		if !introOK || !fixOK {
+			failed++
+			continue
 		}
+		cp.put(r.id, checkpointRow{IntroDate: r.introDate, FixDate: r.fixDate})
+	}
+	cp.finish(failed)
+	// advisories whose intro or fix date could not be resolved count as skipped
+	exitcode.Skipped(failed, len(rows))
+	rangeVersions(rows)
+	if *popFlag {
+		addPopularity(rows)
 	}
 
 	/* ---- output ---- */
-	fmt.Printf("\n=== %s ===\n", *repoSlug)
-	fmt.Printf("%-20s | %-6s | %-12s | %-12s | %-16s | %-16s | %-16s | %-10s | %-10s\n",
-		"CVE-ID", "Sev", "Intro-Tag", "Fix-Tag", "Published", "Intro-Date", "Fix-Date", "ΔFix", "ΔExposure")
-	fmt.Println(strings.Repeat("-", 112))
+	i18n.Printf("\n=== %s ===\n", subject)
+	tbl := &table{cols: cols}
 
 	var sum float64
 	var cnt int
 	var sumExp float64
 	var cntExp int
 	var skippedExp int
-	for _, r := range rows {
-		iDate := "not found"
-		fDate := "not found"
-		diffFix := "   n/a"
-		diffExp := "   n/a"
-		pubDate := "not found"
+	var sumDisc float64
+	var cntDisc, skippedDisc int
+	var sumAff float64
+	var cntAff int
+	var cntUnfixed int
+	for i := range rows {
+		r := &rows[i]
+		iDate := i18n.T("not found")
+		fDate := i18n.T("not found")
+		diffFix := "n/a"
+		diffExp := "n/a"
+		diffDisc := "n/a"
+		nVers, span := "n/a", "n/a"
+		score := "-"
+		if r.cvss != nil {
+			score = fmt.Sprintf("%.1f", *r.cvss)
+		}
+		pubDate := i18n.T("not found")
 
 		if r.introDate != nil {
 			iDate = r.introDate.Format(dateFmt)
@@ -258,49 +639,293 @@ func main() {
 		}
 
 		validSeverity := r.severity == "HIGH" || r.severity == "CRITICAL" || r.severity == "MODERATE"
+		if *cvssFlag && r.cvss != nil {
+			// the score replaces the label; unscored advisories keep the label filter
+			validSeverity = true
+		}
 
-		// ΔFix
-		if validSeverity && r.introDate != nil && r.fixDate != nil {
-			d := r.fixDate.Sub(*r.introDate).Hours() / 24
-			diffFix = fmt.Sprintf("%6.1f", d)
-			sum += d
+		// ΔFix; a fix only on a newer major is an upgrade path, not a patch
+		if unfixedOnBranch(r) {
+			diffFix = "unfixed-on-branch"
+			cntUnfixed++
+			if !validSeverity {
+				ignored++
+			}
+		} else if d := fixDelta(r); validSeverity && d != nil {
+			diffFix = fmt.Sprintf("%.1f", *d)
+			r.dFix = d
+			sum += *d
 			cnt++
 		} else if !validSeverity {
 			ignored++
 		}
 
-		// ΔExp
+		// affected range: span and released versions, independent of severity
+		if r.introDate != nil && r.fixDate != nil {
+			d := r.fixDate.Sub(*r.introDate).Hours() / 24
+			r.dSpan = &d
+			span = fmt.Sprintf("%.1f", d)
+		}
+		if r.nAffected != nil {
+			nVers = fmt.Sprint(*r.nAffected)
+			if validSeverity {
+				sumAff += float64(*r.nAffected)
+				cntAff++
+			}
+		}
+
+		// ΔExp; silent fixes are reported on their own
+		lead := ""
 		if validSeverity && r.publishedDate != nil && r.fixDate != nil {
 			d := r.fixDate.Sub(*r.publishedDate).Hours() / 24
 			pubDate = r.publishedDate.Format(dateFmt)
+			classifyDisclosure(r)
 			if d >= 0 {
-				diffExp = fmt.Sprintf("%6.1f", d)
+				diffExp = fmt.Sprintf("%.1f", d)
+				r.dExp = &d
 				sumExp += d
 				cntExp++
 			} else {
-				diffExp = "  < 0"
+				diffExp = silentFix
+				lead = fmt.Sprintf("%.1f", *r.dLead)
 				skippedExp++
 			}
 		}
 
-		fmt.Printf("%-20s | %-6s | %-12s | %-12s | %-16s | %-16s | %-16s | %6s | %6s\n",
-			r.id, r.severity, r.introTag, r.fixTag, pubDate, iDate, fDate, diffFix, diffExp)
+		// ΔDisclosure
+		if validSeverity && r.publishedDate != nil && r.introDate != nil {
+			d := r.publishedDate.Sub(*r.introDate).Hours() / 24
+			pubDate = r.publishedDate.Format(dateFmt)
+			if d >= 0 {
+				diffDisc = fmt.Sprintf("%.1f", d)
+				r.dDisc = &d
+				sumDisc += d
+				cntDisc++
+			} else {
+				diffDisc = "< 0"
+				skippedDisc++
+			}
+		}
+
+		tbl.add(map[string]string{
+			"id": r.id, "severity": r.severity, "cvss": score, "introtag": r.introTag, "fixtag": r.fixTag,
+			"published": pubDate, "introdate": iDate, "fixdate": fDate,
+			"dfix": diffFix, "dexposure": diffExp, "ddisclosure": diffDisc,
+			"versions": nVers, "span": span, "cwe": strings.Join(r.cwes, ","),
+			"unfixed": strings.Join(r.unfixed, ","), "lead": lead, "downloads": fmtCount(r.affDownloads),
+		})
+	}
+	if !*noTable {
+		tbl.print()
 	}
-	fmt.Println(strings.Repeat("-", 112))
 	if cnt == 0 {
-		fmt.Printf("Ø Time-to-Fix (ΔFix): n/a (0 CVEs)\n")
+		i18n.Printf("Ø Time-to-Fix (ΔFix): n/a (0 CVEs)\n")
 	} else {
-		fmt.Printf("Ø Time-to-Fix (ΔFix): %.1f Tage (%d CVEs)\n", sum/float64(cnt), cnt)
+		i18n.Printf("Ø Time-to-Fix (ΔFix): %.1f Tage (%d CVEs)\n", sum/float64(cnt), cnt)
 	}
 	if cntExp == 0 {
-		fmt.Printf("Ø Exposure Window (ΔExposure): n/a (0 CVEs)\n")
+		i18n.Printf("Ø Exposure Window (ΔExposure): n/a (0 CVEs)\n")
 	} else {
-		fmt.Printf("Ø Exposure Window (ΔExposure): %.1f Tage (%d CVEs)\n", sumExp/float64(cntExp), cntExp)
+		i18n.Printf("Ø Exposure Window (ΔExposure): %.1f Tage (%d CVEs)\n", sumExp/float64(cntExp), cntExp)
 	}
-	if skippedExp > 0 {
-		fmt.Printf("%d CVEs mit negativem Exposure Window ignoriert\n", skippedExp)
+	silent, afterDisc, medianLead := silentSummary(rows)
+	printSilent(silent, afterDisc, medianLead)
+	if cntDisc == 0 {
+		i18n.Printf("Ø Time-to-Disclosure (ΔDisclosure): n/a (0 CVEs)\n")
+	} else {
+		i18n.Printf("Ø Time-to-Disclosure (ΔDisclosure): %.1f Tage (%d CVEs)\n", sumDisc/float64(cntDisc), cntDisc)
+	}
+	if skippedDisc > 0 {
+		i18n.Printf("%d CVEs mit Veröffentlichung vor dem Intro-Release ignoriert\n", skippedDisc)
+	}
+	if cntAff > 0 {
+		i18n.Printf("Ø betroffene Versionen: %.1f (%d CVEs)\n", sumAff/float64(cntAff), cntAff)
 	}
 	if ignored > 0 {
-		fmt.Printf("%d CVEs nicht berücksichtigt (LOW oder keine Severity)\n", ignored)
+		i18n.Printf("%d CVEs nicht berücksichtigt (LOW oder keine Severity)\n", ignored)
+	}
+	if cntUnfixed > 0 {
+		i18n.Printf("%d CVEs auf mindestens einem Major nie gefixt (unfixed-on-branch, Cross-Major-Fix nicht in ΔFix)\n", cntUnfixed)
+	}
+	var cvssWeighted *float64
+	var bands []cvssBand
+	if *cvssFlag {
+		cvssWeighted, bands = printCVSS(rows)
+	}
+	var cweGroups []cweGroup
+	if *cweFlag {
+		cweGroups = printCWE(rows)
+	}
+	var popExp *float64
+	var cntPop int
+	if *popFlag {
+		popExp, cntPop = weightedExposure(rows)
+		printPopularity(popExp, cntPop)
+	}
+
+	var sumOpen float64
+	var cntOpen int
+	if len(open) > 0 {
+		sort.Slice(open, func(i, j int) bool { return open[i].ID < open[j].ID })
+		i18n.Printf("\n=== Open advisories (no fixed version) ===\n")
+		ot := &table{cols: openColumns}
+		for _, o := range open {
+			pub, age := i18n.T("not found"), "n/a"
+			if o.Published != nil {
+				pub = o.Published.Format(dateFmt)
+			}
+			if o.AgeDays != nil {
+				age = fmt.Sprintf("%.1f", *o.AgeDays)
+				sumOpen += *o.AgeDays
+				cntOpen++
+			}
+			la := o.LastAffected
+			if la == "" {
+				la = "-"
+			}
+			ot.add(map[string]string{"id": o.ID, "severity": o.Severity, "lastaffected": la, "published": pub, "age": age})
+		}
+		if !*noTable {
+			ot.print()
+		}
+		if cntOpen == 0 {
+			i18n.Printf("%d advisories still unfixed\n", len(open))
+		} else {
+			i18n.Printf("%d advisories still unfixed, Ø %.1f Tage seit Veröffentlichung\n", len(open), sumOpen/float64(cntOpen))
+		}
+	}
+
+	if *chartFile != "" {
+		if err := writeChart(*chartFile, "Time to fix – "+subject, rows, open); err != nil {
+			logging.Fatal("cannot write chart", "file", *chartFile, "err", err)
+		}
+	}
+
+	var norm *normOut
+	if *normFlag {
+		norm = normalize(len(vulns))
+		norm.print()
+	}
+
+	var sumAdopt float64
+	var cntAdopt int
+	if *downRepo != "" {
+		sumAdopt, cntAdopt = printAdoption(rows)
+	}
+
+	var advs []advisoryOut
+	for _, r := range rows {
+		a := advisoryOut{
+			ID: r.id, Purl: purl.For(r.purlEco, r.purlPkg, r.fixTag), Severity: r.severity, CVSS: r.cvss, IntroTag: r.introTag, FixTag: r.fixTag,
+			Published: r.publishedDate, IntroDate: r.introDate, FixDate: r.fixDate,
+			DeltaFixDays: r.dFix, DeltaExposureDays: r.dExp, DeltaDisclosureDays: r.dDisc,
+			DeltaAdoptDays: r.dAdopt, AffectedVersions: r.nAffected, AffectedSpanDays: r.dSpan,
+			CWEs: r.cwes, UnfixedBranches: r.unfixed, Disclosure: r.disclosure, SilentLeadDays: r.dLead,
+			Downloads: r.downloads, AffectedDownloads: r.affDownloads,
+			UnfixedOnBranch: unfixedOnBranch(&r),
+		}
+		if r.adopt != nil {
+			a.AdoptCommit, a.AdoptDate = r.adopt.commit, &r.adopt.date
+		}
+		advs = append(advs, a)
+	}
+	subject, src = anonymizeOutput(subject, src, advs, norm)
+
+	if *outFile != "" {
+		res := resultOut{
+			Schema: schema.ID("ttf-result"),
+			Repo:   subject,
+			Source: src,
+			Summary: summaryOut{
+				MeanFixDays: avg(sum, cnt), FixCount: cnt,
+				MeanExposureDays: avg(sumExp, cntExp), ExposureCount: cntExp,
+				NegativeExposure: skippedExp, Ignored: ignored, UnfixedOnBranch: cntUnfixed,
+				SilentFixCount: silent, FixedAfterDisclosure: afterDisc, MedianSilentLeadDays: medianLead,
+				MeanDisclosureDays: avg(sumDisc, cntDisc), DisclosureCount: cntDisc,
+				NegativeDisclosure:  skippedDisc,
+				CVSSWeightedFixDays: cvssWeighted, CVSSBands: bands, CWEGroups: cweGroups,
+				OpenCount: len(open), MeanOpenAgeDays: avg(sumOpen, cntOpen),
+				MeanAdoptDays: avg(sumAdopt, cntAdopt), AdoptCount: cntAdopt,
+				MeanAffectedVersions: avg(sumAff, cntAff), AffectedCount: cntAff,
+				DownloadWeightedExposureDays: popExp, DownloadWeightedCount: cntPop,
+			},
+			Advisories: advs,
+			Open:       open,
+			Normalized: norm,
+			Provenance: provenance.Get(),
+		}
+		if err := report.WriteJSON(*outFile, res); err != nil {
+			logging.Fatal("cannot write JSON output", "file", *outFile, "err", err)
+		}
+	}
+	if *emitFile != "" {
+		if err := emitOSV(*emitFile, subject, src, vulns, advs); err != nil {
+			logging.Fatal("cannot write enriched OSV", "file", *emitFile, "err", err)
+		}
+	}
+	if *tuiFlag {
+		if err := runTUI(subject, advs, open, vulns); err != nil {
+			logging.Fatal("cannot start -tui", "err", err)
+		}
+	}
+	exitcode.Finish()
+}
+
+// anonymizeOutput pseudonymizes the repo, local paths and adoption commits
+// of the JSON outputs (-anonymize). A bare -pkg subject is a public package
+// and stays readable.
+func anonymizeOutput(subject, src string, advs []advisoryOut, norm *normOut) (string, string) {
+	if !anon.Enabled() {
+		return subject, src
+	}
+	if *repoSlug != "" {
+		subject = anon.Repo(subject)
+	}
+	if *source == "file" {
+		src = anon.Path(src)
+	}
+	for i := range advs {
+		advs[i].AdoptCommit = anon.Commit(advs[i].AdoptCommit)
+	}
+	if norm != nil && *sizeDir != "" {
+		norm.DepsSource = "manifests in " + anon.Path(*sizeDir)
+	}
+	return subject, src
+}
+
+// loadVulns reads the advisories from the selected source and returns them
+// together with a description of where they came from.
+func loadVulns() ([]osvVuln, string) {
+	switch *source {
+	case "file":
+		vulns, src, err := loadOSVFiles(jsonIn)
+		if err != nil {
+			logging.Fatal("cannot read OSV files", "err", err)
+		}
+		return vulns, src
+	case "govulndb":
+		vulns, err := govulndbVulns(*pkg)
+		if err != nil {
+			logging.Fatal("cannot load advisories from govulndb", "module", *pkg, "err", err)
+		}
+		return vulns, govulndbBase + " (" + *pkg + ")"
+	case "pypi":
+		vulns, err := pypiVulns(*pkg)
+		if err != nil {
+			logging.Fatal("cannot load PyPI advisories from OSV", "pkg", *pkg, "err", err)
+		}
+		return vulns, osvAPI + " (PyPI " + *pkg + ")"
+	default:
+		logging.Usage("unknown advisory source", "source", *source)
+		return nil, ""
+	}
+}
+
+// avg returns sum/n, or nil when there is nothing to average.
+func avg(sum float64, n int) *float64 {
+	if n == 0 {
+		return nil
 	}
+	v := sum / float64(n)
+	return &v
 }
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"baa_fs25/shared/registry"
+	"baa_fs25/shared/registry/registrytest"
+)
+
+func day(y int, m time.Month, d int) *time.Time {
+	t := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	return &t
+}
+
+// withRegistry replaces the date registry of eco for the duration of a test.
+func withRegistry(t *testing.T, eco string, c registry.Client) {
+	old := registries[eco]
+	registries[eco] = c
+	t.Cleanup(func() { registries[eco] = old })
+}
+
+// datesOnly hides the version list of a registry.
+type datesOnly struct{ registry.Client }
+
+func TestCrossMajor(t *testing.T) {
+	for _, tc := range []struct {
+		intro, fix string
+		want       bool
+	}{
+		{"1.4.0", "2.0.1", true},
+		{"1.4.0", "1.4.2", false},
+		{"2.0.0", "1.9.9", false},
+		{"", "2.0.1", false},         // unspecified intro
+		{"0.9.0", "1.0.0", true},     // v0 counts as a major of its own
+		{"abc123", "2.0.1", false},   // GIT hash
+		{"1.4.0", "2.0.0rc1", false}, // PEP 440 pre-release, not semver
+		{"1.4.0", "v2.0.1", false},   // OSV versions carry no "v"
+		{"1.4.0-beta.1", "2.0.0", true},
+	} {
+		if got := crossMajor(tc.intro, tc.fix); got != tc.want {
+			t.Errorf("crossMajor(%q, %q) = %v, want %v", tc.intro, tc.fix, got, tc.want)
+		}
+	}
+}
+
+func TestUnfixedBranches(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		ranges string
+		want   []string
+	}{
+		{"fix on a newer major only", `[{"type": "SEMVER", "events": [{"introduced": "1.4.0"}, {"fixed": "2.0.1"}]}]`, []string{"v1"}},
+		{"backported", `[{"type": "SEMVER", "events": [{"introduced": "1.4.0"}, {"fixed": "1.4.2"}, {"introduced": "2.0.0"}, {"fixed": "2.0.1"}]}]`, nil},
+		{"two majors unfixed", `[{"type": "ECOSYSTEM", "events": [{"introduced": "1.0.0"}, {"introduced": "2.0.0"}, {"fixed": "3.0.0"}]}]`, []string{"v1", "v2"}},
+		{"unspecified intro", `[{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "2.0.1"}]}]`, nil},
+		{"GIT range", `[{"type": "GIT", "events": [{"introduced": "1.0.0"}, {"fixed": "2.0.0"}]}]`, nil},
+	} {
+		var v osvVuln
+		if err := json.Unmarshal([]byte(`{"affected": [{"ranges": `+tc.ranges+`}]}`), &v); err != nil {
+			t.Fatal(err)
+		}
+		if got := unfixedBranches(v); !slices.Equal(got, tc.want) {
+			t.Errorf("%s: %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestFixDelta(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		r       row
+		days    float64 // -1: no ΔFix
+		unfixed bool
+	}{
+		{"patch", row{introTag: "1.4.0", fixTag: "1.4.2", introDate: day(2024, 1, 1), fixDate: day(2024, 3, 1)}, 60, false},
+		{"cross-major with unfixed branch", row{introTag: "1.4.0", fixTag: "2.0.1", introDate: day(2023, 1, 1), fixDate: day(2024, 1, 1), unfixed: []string{"v1"}}, -1, true},
+		{"cross-major, backported", row{introTag: "1.4.0", fixTag: "2.0.1", introDate: day(2023, 1, 1), fixDate: day(2024, 1, 1)}, 365, false},
+		{"fix before intro", row{introTag: "1.4.0", fixTag: "1.4.2", introDate: day(2024, 3, 1), fixDate: day(2024, 1, 1)}, -60, false},
+		{"no intro date", row{introTag: "1.4.0", fixTag: "1.4.2", fixDate: day(2024, 1, 1)}, -1, false},
+		{"no fix date", row{fixTag: "1.4.2", introDate: day(2024, 1, 1)}, -1, false},
+	} {
+		if got := unfixedOnBranch(&tc.r); got != tc.unfixed {
+			t.Errorf("%s: unfixedOnBranch = %v, want %v", tc.name, got, tc.unfixed)
+		}
+		d := fixDelta(&tc.r)
+		switch {
+		case tc.days == -1 && d != nil:
+			t.Errorf("%s: ΔFix %.1f, want none", tc.name, *d)
+		case tc.days != -1 && (d == nil || *d != tc.days):
+			t.Errorf("%s: ΔFix %v, want %.1f", tc.name, d, tc.days)
+		}
+	}
+}
+
+func TestCountInRange(t *testing.T) {
+	vers := []string{"1.0.0", "1.1.0", "1.2.0-rc.1", "1.2.0", "2.0.0", "latest"}
+	for _, tc := range []struct {
+		intro, fix string
+		want       int // -1: nil
+	}{
+		{"1.1.0", "2.0.0", 2},
+		{"", "1.2.0", 2},
+		{"0", "2.0.0", 3},
+		{"1.0.0", "1.0.0", 0},
+		{"1.0.0", "", -1},
+		{"1.0.0", "abc123", -1},
+	} {
+		got := countInRange(vers, tc.intro, tc.fix)
+		if tc.want == -1 && got != nil || tc.want != -1 && (got == nil || *got != tc.want) {
+			t.Errorf("countInRange(%q, %q) = %v, want %d", tc.intro, tc.fix, got, tc.want)
+		}
+	}
+}
+
+func TestRegistryDates(t *testing.T) {
+	withRegistry(t, "npm", registrytest.Static{"a": {"1.0.0": *day(2023, 1, 1), "1.0.1": *day(2023, 2, 1), "2.0.0": *day(2024, 1, 1)}})
+	withRegistry(t, "Go", registrytest.Static{"example.com/m": {"v1.2.0": *day(2022, 5, 1)}})
+	withRegistry(t, "PyPI", registrytest.Static{"p": {"3.1": *day(2021, 7, 1)}})
+
+	if d, err := registryDate("Go", "example.com/m", "1.2.0"); err != nil || !d.Equal(*day(2022, 5, 1)) {
+		t.Errorf("Go without v prefix: %v, %v", d, err)
+	}
+	r := row{eco: "npm", regPkg: "a"}
+	if d, ok := resolveDate(&r, "1.0.1"); !ok || d == nil || !d.Equal(*day(2023, 2, 1)) {
+		t.Errorf("npm: %v, %v", d, ok)
+	}
+
+	defer func(s, p string) { *source, *pkg = s, p }(*source, *pkg)
+	*source, *pkg = "pypi", "p"
+	if d, ok := resolveDate(&row{}, "3.1"); !ok || d == nil || !d.Equal(*day(2021, 7, 1)) {
+		t.Errorf("-source pypi: %v, %v", d, ok)
+	}
+	if d, ok := resolveDate(&row{}, "9.9"); ok || d != nil {
+		t.Errorf("-source pypi, unknown version: %v, %v", d, ok)
+	}
+
+	rows := []row{
+		{eco: "npm", regPkg: "a", introTag: "1.0.0", fixTag: "2.0.0"},
+		{eco: "npm", regPkg: "a", fixTag: "1.0.1"},
+		{eco: "Maven", regPkg: "g:a", fixTag: "1.0.0"}, // no Lister
+	}
+	withRegistry(t, "Maven", datesOnly{registrytest.Static{}})
+	rangeVersions(rows)
+	if n := rows[0].nAffected; n == nil || *n != 2 {
+		t.Errorf("range [1.0.0, 2.0.0): %v, want 2", n)
+	}
+	if n := rows[1].nAffected; n == nil || *n != 1 {
+		t.Errorf("range [, 1.0.1): %v, want 1", n)
+	}
+	if rows[2].nAffected != nil {
+		t.Errorf("registry without version list: %v, want nil", *rows[2].nAffected)
+	}
+}
@@ -0,0 +1,475 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"baa_fs25/shared/i18n"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+/* ---------- Interactive explorer (-tui) ---------- */
+
+// The explorer lists fixed and open advisories of the run. s cycles the sort
+// key, r reverses it, / opens the filter prompt and the pane below the list
+// shows ranges and references of the selected advisory. A filter is a list
+// of terms that must all match:
+//
+//	sev:high,critical   severity label
+//	dfix>30 dfix<365    ΔFix in days (advisories without ΔFix drop out)
+//	after:2023-01-01    published on or after the date (before: likewise)
+//	express             anything else: substring of ID, alias or purl
+
+// tuiItem is one row of the explorer; v is nil if the record is missing.
+type tuiItem struct {
+	adv  advisoryOut
+	open *openOut // set for advisories without a fixed version
+	v    *osvVuln
+}
+
+func (it tuiItem) id() string {
+	if it.open != nil {
+		return it.open.ID
+	}
+	return it.adv.ID
+}
+
+func (it tuiItem) severity() string {
+	if it.open != nil {
+		return it.open.Severity
+	}
+	return it.adv.Severity
+}
+
+func (it tuiItem) published() *time.Time {
+	if it.open != nil {
+		return it.open.Published
+	}
+	return it.adv.Published
+}
+
+// tuiSorts are the sort keys in the order s cycles through them.
+var tuiSorts = []string{"severity", "dfix", "published", "id"}
+
+// severityRank orders the labels from LOW (1) to CRITICAL (4).
+func severityRank(s string) int {
+	switch s {
+	case "CRITICAL":
+		return 4
+	case "HIGH":
+		return 3
+	case "MODERATE", "MEDIUM":
+		return 2
+	case "LOW":
+		return 1
+	}
+	return 0
+}
+
+type tuiFilter struct {
+	sevs           map[string]bool
+	minFix, maxFix *float64
+	after, before  *time.Time
+	text           []string
+}
+
+// parseTUIFilter reads the terms of the filter prompt.
+func parseTUIFilter(s string) (tuiFilter, error) {
+	var f tuiFilter
+	for _, t := range strings.Fields(s) {
+		lt := strings.ToLower(t)
+		switch {
+		case strings.HasPrefix(lt, "sev:"):
+			f.sevs = map[string]bool{}
+			for _, v := range strings.Split(t[4:], ",") {
+				f.sevs[strings.ToUpper(v)] = true
+			}
+		case strings.HasPrefix(lt, "dfix>"), strings.HasPrefix(lt, "dfix<"):
+			d, err := strconv.ParseFloat(t[5:], 64)
+			if err != nil {
+				return f, fmt.Errorf("%s: days expected", t)
+			}
+			if t[4] == '>' {
+				f.minFix = &d
+			} else {
+				f.maxFix = &d
+			}
+		case strings.HasPrefix(lt, "after:"), strings.HasPrefix(lt, "before:"):
+			k, v, _ := strings.Cut(lt, ":")
+			d, err := time.Parse("2006-01-02", v)
+			if err != nil {
+				return f, fmt.Errorf("%s: YYYY-MM-DD expected", t)
+			}
+			if k == "after" {
+				f.after = &d
+			} else {
+				f.before = &d
+			}
+		default:
+			f.text = append(f.text, lt)
+		}
+	}
+	return f, nil
+}
+
+func (f tuiFilter) match(it tuiItem) bool {
+	if f.sevs != nil && !f.sevs[it.severity()] {
+		return false
+	}
+	if f.minFix != nil || f.maxFix != nil {
+		d := it.adv.DeltaFixDays
+		if it.open != nil || d == nil || f.minFix != nil && *d <= *f.minFix || f.maxFix != nil && *d >= *f.maxFix {
+			return false
+		}
+	}
+	if p := it.published(); f.after != nil || f.before != nil {
+		if p == nil || f.after != nil && p.Before(*f.after) || f.before != nil && !p.Before(*f.before) {
+			return false
+		}
+	}
+	for _, t := range f.text {
+		hay := []string{it.id(), it.adv.Purl}
+		if it.open != nil {
+			hay = append(hay, it.open.Purl)
+		}
+		if it.v != nil {
+			hay = append(hay, it.v.Aliases...)
+		}
+		if !strings.Contains(strings.ToLower(strings.Join(hay, " ")), t) {
+			return false
+		}
+	}
+	return true
+}
+
+type tuiModel struct {
+	subject        string
+	all, shown     []tuiItem
+	cursor, offset int
+	sortBy         int // index into tuiSorts
+	desc           bool
+	filter         tuiFilter
+	filterText     string
+	editing        bool
+	input, err     string
+	width, height  int
+}
+
+// runTUI opens the explorer on the terminal and returns when it is closed.
+func runTUI(subject string, advs []advisoryOut, open []openOut, vulns []osvVuln) error {
+	if fi, err := os.Stdout.Stat(); err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return errors.New("stdout is not a terminal")
+	}
+	byID := map[string]*osvVuln{}
+	for i := range vulns {
+		byID[vulns[i].ID] = &vulns[i]
+	}
+	m := &tuiModel{subject: subject, desc: true, width: 100, height: 30}
+	for _, a := range advs {
+		m.all = append(m.all, tuiItem{adv: a, v: byID[a.ID]})
+	}
+	for i := range open {
+		m.all = append(m.all, tuiItem{open: &open[i], v: byID[open[i].ID]})
+	}
+	m.apply()
+	_, err := tea.NewProgram(m, tea.WithAltScreen()).Run()
+	return err
+}
+
+func (m *tuiModel) Init() tea.Cmd { return nil }
+
+// apply filters and sorts all into shown and keeps the cursor in range.
+func (m *tuiModel) apply() {
+	m.shown = m.shown[:0]
+	for _, it := range m.all {
+		if m.filter.match(it) {
+			m.shown = append(m.shown, it)
+		}
+	}
+	key := tuiSorts[m.sortBy]
+	sort.SliceStable(m.shown, func(i, j int) bool {
+		a, b := m.shown[i], m.shown[j]
+		less := func(x, y float64) bool {
+			if m.desc {
+				return x > y
+			}
+			return x < y
+		}
+		switch key {
+		case "severity":
+			if ra, rb := severityRank(a.severity()), severityRank(b.severity()); ra != rb {
+				return less(float64(ra), float64(rb))
+			}
+		case "dfix":
+			da, db := a.adv.DeltaFixDays, b.adv.DeltaFixDays
+			if a.open != nil {
+				da = nil
+			}
+			if b.open != nil {
+				db = nil
+			}
+			if (da == nil) != (db == nil) {
+				return db == nil // without ΔFix last
+			}
+			if da != nil && *da != *db {
+				return less(*da, *db)
+			}
+		case "published":
+			pa, pb := a.published(), b.published()
+			if (pa == nil) != (pb == nil) {
+				return pb == nil
+			}
+			if pa != nil && !pa.Equal(*pb) {
+				return less(float64(pa.Unix()), float64(pb.Unix()))
+			}
+		}
+		if m.desc && key == "id" {
+			return a.id() > b.id()
+		}
+		return a.id() < b.id()
+	})
+	m.cursor = min(m.cursor, max(len(m.shown)-1, 0))
+}
+
+// listHeight is the number of advisory rows; the detail pane gets the rest.
+func (m *tuiModel) listHeight() int {
+	return max(3, (m.height-4)/2)
+}
+
+func (m *tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	case tea.KeyMsg:
+		if m.editing {
+			return m, m.edit(msg)
+		}
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return m, tea.Quit
+		case "up", "k":
+			m.cursor = max(m.cursor-1, 0)
+		case "down", "j":
+			m.cursor = min(m.cursor+1, max(len(m.shown)-1, 0))
+		case "pgup":
+			m.cursor = max(m.cursor-m.listHeight(), 0)
+		case "pgdown":
+			m.cursor = min(m.cursor+m.listHeight(), max(len(m.shown)-1, 0))
+		case "home", "g":
+			m.cursor = 0
+		case "end", "G":
+			m.cursor = max(len(m.shown)-1, 0)
+		case "s":
+			m.sortBy = (m.sortBy + 1) % len(tuiSorts)
+			m.desc = tuiSorts[m.sortBy] != "id"
+			m.apply()
+		case "r":
+			m.desc = !m.desc
+			m.apply()
+		case "/":
+			m.editing, m.input, m.err = true, m.filterText, ""
+		case "esc":
+			m.filter, m.filterText, m.err = tuiFilter{}, "", ""
+			m.apply()
+		}
+	}
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	} else if h := m.listHeight(); m.cursor >= m.offset+h {
+		m.offset = m.cursor - h + 1
+	}
+	return m, nil
+}
+
+// edit handles a key while the filter prompt is open.
+func (m *tuiModel) edit(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return tea.Quit
+	case tea.KeyEsc:
+		m.editing = false
+	case tea.KeyEnter:
+		f, err := parseTUIFilter(m.input)
+		if err != nil {
+			m.err = err.Error()
+			return nil
+		}
+		m.filter, m.filterText, m.editing, m.err = f, m.input, false, ""
+		m.cursor, m.offset = 0, 0
+		m.apply()
+	case tea.KeyBackspace:
+		if r := []rune(m.input); len(r) > 0 {
+			m.input = string(r[:len(r)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.input += string(msg.Runes)
+	}
+	return nil
+}
+
+var (
+	tuiBold     = lipgloss.NewStyle().Bold(true)
+	tuiSelected = lipgloss.NewStyle().Reverse(true)
+	tuiDim      = lipgloss.NewStyle().Faint(true)
+)
+
+const tuiRowFmt = "%-22s %-9s %5s %-12s %-12s %8s  %-10s"
+
+func (m *tuiModel) View() string {
+	var b strings.Builder
+	dir := "↑"
+	if m.desc {
+		dir = "↓"
+	}
+	title := i18n.Sprintf("ttf – %s: %d of %d advisories, sort %s %s", m.subject, len(m.shown), len(m.all), tuiSorts[m.sortBy], dir)
+	if m.filterText != "" {
+		title += i18n.Sprintf(", filter %q", m.filterText)
+	}
+	b.WriteString(tuiBold.Render(m.clip(title)) + "\n")
+	b.WriteString(tuiBold.Render(m.clip(fmt.Sprintf(tuiRowFmt, "ID", "SEV", "CVSS", "INTRO", "FIX", "ΔFIX", strings.ToUpper(i18n.T("Published"))))) + "\n")
+	h := m.listHeight()
+	for i := m.offset; i < m.offset+h; i++ {
+		if i >= len(m.shown) {
+			b.WriteString("\n")
+			continue
+		}
+		line := m.clip(m.row(m.shown[i]))
+		if i == m.cursor {
+			line = tuiSelected.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+	b.WriteString(tuiDim.Render(strings.Repeat("─", max(m.width, 1))) + "\n")
+	detail := m.detail()
+	for i := 0; i < m.height-h-4; i++ {
+		if i < len(detail) {
+			b.WriteString(m.clip(detail[i]))
+		}
+		b.WriteString("\n")
+	}
+	switch {
+	case m.editing:
+		b.WriteString(i18n.Sprintf("filter: %s", m.input) + "█")
+	case m.err != "":
+		b.WriteString(m.clip("! " + m.err))
+	default:
+		b.WriteString(tuiDim.Render(m.clip(i18n.T("↑/↓ move  s sort  r reverse  / filter (sev:high dfix>30 after:2023-01-01 text)  esc clear  q quit"))))
+	}
+	return b.String()
+}
+
+func (m *tuiModel) row(it tuiItem) string {
+	if o := it.open; o != nil {
+		return fmt.Sprintf(tuiRowFmt, o.ID, o.Severity, "-", "", i18n.T("(open)"), "-", tuiDate(o.Published))
+	}
+	a := it.adv
+	cvss := "-"
+	if a.CVSS != nil {
+		cvss = fmt.Sprintf("%.1f", *a.CVSS)
+	}
+	return fmt.Sprintf(tuiRowFmt, a.ID, a.Severity, cvss, a.IntroTag, a.FixTag, tuiDays(a.DeltaFixDays), tuiDate(a.Published))
+}
+
+// detail lists everything known about the selected advisory.
+func (m *tuiModel) detail() []string {
+	if len(m.shown) == 0 {
+		return []string{i18n.T("no advisories match the filter")}
+	}
+	it := m.shown[m.cursor]
+	var out []string
+	add := func(format string, args ...any) { out = append(out, i18n.Sprintf(format, args...)) }
+	head := it.id()
+	if it.v != nil && len(it.v.Aliases) > 0 {
+		head += "  (" + strings.Join(it.v.Aliases, ", ") + ")"
+	}
+	out = append(out, tuiBold.Render(head))
+	if o := it.open; o != nil {
+		add("Package: %s", o.Purl)
+		add("Severity: %s   no fixed version, last affected: %s", o.Severity, o.LastAffected)
+		add("Published: %s   open for %s days", tuiDate(o.Published), tuiDays(o.AgeDays))
+	} else {
+		a := it.adv
+		add("Package: %s", a.Purl)
+		sev := a.Severity
+		if a.CVSS != nil {
+			sev += fmt.Sprintf("   CVSS %.1f", *a.CVSS)
+		}
+		if len(a.CWEs) > 0 {
+			sev += "   " + strings.Join(a.CWEs, ", ")
+		}
+		add("Severity: %s", sev)
+		add("Introduced: %s (%s)   fixed: %s (%s)   published: %s", a.IntroTag, tuiDate(a.IntroDate), a.FixTag, tuiDate(a.FixDate), tuiDate(a.Published))
+		add("ΔFix %s   ΔExposure %s   ΔDisclosure %s   affected versions %s", tuiDays(a.DeltaFixDays), tuiDays(a.DeltaExposureDays), tuiDays(a.DeltaDisclosureDays), tuiCount(a.AffectedVersions))
+		if a.Disclosure != "" {
+			add("Disclosure: %s   lead %s days", a.Disclosure, tuiDays(a.SilentLeadDays))
+		}
+		if len(a.UnfixedBranches) > 0 {
+			add("Majors without fix: %s", strings.Join(a.UnfixedBranches, ", "))
+		}
+		if a.AdoptDate != nil {
+			add("Adopted downstream: %s (%s), ΔAdopt %s days", tuiDate(a.AdoptDate), a.AdoptCommit, tuiDays(a.DeltaAdoptDays))
+		}
+	}
+	if it.v == nil {
+		return out
+	}
+	out = append(out, "", tuiBold.Render(i18n.T("Ranges")))
+	for _, aff := range it.v.Affected {
+		for _, rg := range aff.Ranges {
+			var evs []string
+			for _, ev := range rg.Events {
+				switch {
+				case ev.Introduced != "":
+					evs = append(evs, "introduced "+ev.Introduced)
+				case ev.Fixed != "":
+					evs = append(evs, "fixed "+ev.Fixed)
+				case ev.LastAffected != "":
+					evs = append(evs, "last_affected "+ev.LastAffected)
+				}
+			}
+			out = append(out, fmt.Sprintf("  %s %s  %s: %s", aff.Package.Ecosystem, aff.Package.Name, rg.Type, strings.Join(evs, ", ")))
+		}
+	}
+	if len(it.v.References) > 0 {
+		out = append(out, "", tuiBold.Render(i18n.T("References")))
+		for _, r := range it.v.References {
+			out = append(out, fmt.Sprintf("  %-8s %s", r.Type, r.URL))
+		}
+	}
+	return out
+}
+
+// clip cuts s to the terminal width; styled text keeps its escape codes.
+func (m *tuiModel) clip(s string) string {
+	if m.width <= 0 {
+		return s
+	}
+	return lipgloss.NewStyle().MaxWidth(m.width).Render(s)
+}
+
+func tuiDate(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format("2006-01-02")
+}
+
+func tuiDays(d *float64) string {
+	if d == nil {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.1f", *d)
+}
+
+func tuiCount(n *int) string {
+	if n == nil {
+		return "n/a"
+	}
+	return strconv.Itoa(*n)
+}
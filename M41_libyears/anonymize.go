@@ -0,0 +1,72 @@
+// anonymize.go – --anonymize: Pfade und Workspace-Namen pseudonymisieren
+package main
+
+import (
+	"slices"
+
+	"baa_fs25/shared/anon"
+)
+
+// anonymized liefert eine Kopie von res mit pseudonymisierten Quellpfaden und
+// Workspace-Namen (die Namen privater Monorepo-Pakete verraten das Repo);
+// res selbst bleibt für --watch und den PR-Kommentar unverändert.
+func anonymized(res result) result {
+	if !anon.Enabled() {
+		return res
+	}
+	res.Source = anonPaths(res.Source)
+	res.Deps = slices.Clone(res.Deps)
+	for i := range res.Deps {
+		res.Deps[i] = anonDep(res.Deps[i])
+	}
+	res.Skipped = slices.Clone(res.Skipped)
+	for i := range res.Skipped {
+		res.Skipped[i] = anonSkip(res.Skipped[i])
+	}
+	res.Workspaces = slices.Clone(res.Workspaces)
+	for i := range res.Workspaces {
+		res.Workspaces[i].Name = anon.ID(res.Workspaces[i].Name)
+	}
+	res.Conflicts = slices.Clone(res.Conflicts)
+	for i := range res.Conflicts {
+		pins := slices.Clone(res.Conflicts[i].Pins)
+		for j := range pins {
+			pins[j].File = anon.Path(pins[j].File)
+		}
+		res.Conflicts[i].Pins = pins
+	}
+	res.Duplicates = anonDuplicates(res.Duplicates)
+	res.Exceptions = slices.Clone(res.Exceptions)
+	for i := range res.Exceptions {
+		res.Exceptions[i].ApprovedBy = anon.ID(res.Exceptions[i].ApprovedBy)
+	}
+	return res
+}
+
+func anonDep(d dep) dep {
+	if !anon.Enabled() {
+		return d
+	}
+	d.Workspace = anon.ID(d.Workspace)
+	d.Files = anonPaths(d.Files)
+	return d
+}
+
+func anonSkip(s skipped) skipped {
+	if !anon.Enabled() {
+		return s
+	}
+	s.Workspace, s.File = anon.ID(s.Workspace), anon.Path(s.File)
+	return s
+}
+
+func anonPaths(paths []string) []string {
+	if paths == nil {
+		return nil
+	}
+	out := make([]string, len(paths))
+	for i, p := range paths {
+		out[i] = anon.Path(p)
+	}
+	return out
+}
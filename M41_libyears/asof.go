@@ -0,0 +1,81 @@
+// asof.go – --as-of 2023-06-01: Lag so berechnen, wie er an einem Stichtag
+// aussah (rückwirkende Messung passend zu den Zeitfenstern der MTTU-Studie)
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// asOf ist der Stichtag (00:00 UTC); Null = heute. Als "neueste Version"
+// gilt dann das neueste Release vor dem Stichtag, spätere Releases sind
+// unsichtbar.
+var asOf time.Time
+
+const asOfLayout = "2006-01-02"
+
+func parseAsOf(s string) error {
+	t, err := time.Parse(asOfLayout, s)
+	if err != nil {
+		return fmt.Errorf("erwartet JJJJ-MM-TT: %w", err)
+	}
+	if t.After(time.Now()) {
+		return fmt.Errorf("%s liegt in der Zukunft", s)
+	}
+	asOf = t
+	return nil
+}
+
+// visible meldet, ob ein Release am Stichtag schon veröffentlicht war.
+func visible(t time.Time) bool {
+	return asOf.IsZero() || t.Before(asOf)
+}
+
+// asOfResult ist der Stichtag für das --out-JSON, nil ohne --as-of.
+func asOfResult() *time.Time {
+	if asOf.IsZero() {
+		return nil
+	}
+	t := asOf
+	return &t
+}
+
+// afterAsOf ist der Fehler für Versionen, die es am Stichtag noch nicht gab.
+func afterAsOf(pkg, ver string) error {
+	return skipErrorf(reasonAfterAsOf, "%s@%s erst nach %s veröffentlicht", pkg, ver, asOf.Format(asOfLayout))
+}
+
+// goLatestAsOf sucht die höchste getaggte stabile Version eines Moduls, die
+// vor dem Stichtag veröffentlicht war. Die Versionen werden absteigend
+// geprüft, damit meist nur wenige .info-Abfragen nötig sind.
+func goLatestAsOf(path string) *modVersion {
+	esc, err := module.EscapePath(path)
+	if err != nil {
+		return nil
+	}
+	vers, err := goProxy.Versions(esc)
+	if err != nil {
+		return nil
+	}
+	var stable []string
+	for _, v := range vers {
+		if semverTag.MatchString(v) {
+			stable = append(stable, v)
+		}
+	}
+	sort.Slice(stable, func(i, j int) bool { return semver.Compare(stable[i], stable[j]) > 0 })
+	for _, v := range stable {
+		t, err := goProxy.ReleaseTime(esc, v)
+		if err != nil {
+			return nil
+		}
+		if visible(t) {
+			return &modVersion{Version: v, Time: &t}
+		}
+	}
+	return nil
+}
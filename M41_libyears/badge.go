@@ -0,0 +1,49 @@
+// badge.go – shields.io-Endpoint-JSON für README-Badges
+package main
+
+import (
+	"fmt"
+
+	"baa_fs25/shared/logging"
+	"baa_fs25/shared/report"
+)
+
+// badge ist das Schema von https://shields.io/badges/endpoint-badge.
+type badge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// badgeColors ordnet dem Gesamt-Lag (Jahre, Obergrenze exklusiv) eine Farbe zu.
+var badgeColors = []struct {
+	below float64
+	color string
+}{
+	{1, "brightgreen"},
+	{5, "green"},
+	{10, "yellow"},
+	{25, "orange"},
+}
+
+func newBadge(total float64) badge {
+	b := badge{SchemaVersion: 1, Label: "libyears", Message: fmt.Sprintf("%.1f", total), Color: "red"}
+	for _, c := range badgeColors {
+		if total < c.below {
+			b.Color = c.color
+			break
+		}
+	}
+	return b
+}
+
+// writeBadge schreibt das Badge, falls --badge gesetzt ist.
+func (c *common) writeBadge(deps []dep) {
+	if c.badge == "" {
+		return
+	}
+	if err := report.WriteJSON(c.badge, newBadge(sumLag(dedupeDeps(c.counted(deps))))); err != nil {
+		logging.Fatal("Badge-Ausgabe fehlgeschlagen", "file", c.badge, "err", err)
+	}
+}
@@ -0,0 +1,338 @@
+// batch.go – libyears batch: viele Projekte parallel auswerten und als
+// Vergleichstabelle ausgeben
+//
+// projects.yml:
+//
+//	jobs: 4                         # optional, sonst --jobs
+//	projects:
+//	  - name: api
+//	    eco: go
+//	    path: services/api          # relativ zur YAML-Datei bzw. zum Klon
+//	  - name: web
+//	    eco: npm
+//	    repo: https://github.com/org/web.git   # wird flach geklont (mit
+//	                                           # --mirror-dir aus dem Mirror)
+//	    ref: main
+//	    path: package.json
+//	  - name: ml
+//	    eco: py
+//	    paths: [requirements.txt, requirements-dev.txt]
+//	    args: [--eol]               # weitere Flags des Subcommands
+//
+// Jedes Projekt läuft als eigener Prozess des Subcommands (ein Fehler bricht
+// nur dieses Projekt ab); alle teilen sich über --cache-dir denselben
+// Registry-Cache. Endet ein Projekt mit Exit-Code 3 (Teilergebnis, s.
+// shared/exitcode), wird sein Ergebnis übernommen; für --max-skipped des
+// Batches zählt es wie ein fehlgeschlagenes. Mit --mirror-dir kommen die Klone aus gemeinsamen
+// Bare-Mirrors, die auch mttu, ttf und "baa" nutzen; --clone-quota begrenzt
+// deren Platz (s. shared/clones).
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"baa_fs25/shared/anon"
+	"baa_fs25/shared/clones"
+	"baa_fs25/shared/exitcode"
+	"baa_fs25/shared/httpcache"
+	"baa_fs25/shared/i18n"
+	"baa_fs25/shared/logging"
+	"baa_fs25/shared/netcfg"
+	"baa_fs25/shared/provenance"
+	"baa_fs25/shared/report"
+	"baa_fs25/shared/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// batchProject ist ein Eintrag in projects.yml.
+type batchProject struct {
+	Name  string   `yaml:"name"`
+	Eco   string   `yaml:"eco"`   // go | npm | py
+	Path  string   `yaml:"path"`  // Modulverzeichnis, package.json bzw. requirements.txt
+	Paths []string `yaml:"paths"` // py: mehrere requirements-Dateien
+	Repo  string   `yaml:"repo"`  // optional: Git-URL, path ist dann relativ zum Klon
+	Ref   string   `yaml:"ref"`
+	Args  []string `yaml:"args"`
+}
+
+type batchConfig struct {
+	Jobs     int            `yaml:"jobs"`
+	Projects []batchProject `yaml:"projects"`
+}
+
+// batchRow ist eine Zeile der Vergleichstabelle bzw. des --out-JSON.
+type batchRow struct {
+	Project  string   `json:"project"`
+	Eco      string   `json:"eco"`
+	Count    int      `json:"count"`
+	TotalLag float64  `json:"total_lag_years"`
+	MeanLag  float64  `json:"mean_lag_years"`
+	Worst    *dep     `json:"worst,omitempty"`
+	Coverage float64  `json:"coverage"`
+	Error    string   `json:"error,omitempty"`
+	Source   []string `json:"source,omitempty"`
+	// Provenance stammt aus dem Lauf des Projekts (u. a. dessen Cache-Quote).
+	Provenance *provenance.Info `json:"provenance,omitempty"`
+	// partial: das Projekt endete mit Exit-Code 3 (zu viele übersprungene
+	// Dependencies), sein Ergebnis wird trotzdem übernommen.
+	partial bool
+}
+
+// batchResult ist das --out-JSON des ganzen Batches; mit --schema-version 1
+// wird nur Projects als Array geschrieben.
+type batchResult struct {
+	Schema     string           `json:"schema"`
+	Projects   []batchRow       `json:"projects"`
+	Provenance *provenance.Info `json:"provenance,omitempty"`
+}
+
+func runBatch(args []string) {
+	fs := flag.NewFlagSet("batch", flag.ExitOnError)
+	logOpts, netOpts, anonOpts := logging.Register(fs), netcfg.Register(fs), anon.Register(fs)
+	schemaOpts, cloneOpts, langOpts := schema.Register(fs), clones.Register(fs, ""), i18n.Register(fs)
+	exitOpts := exitcode.Register(fs)
+	projects := fs.String("projects", "projects.yml", "YAML-Datei mit den Projekten")
+	jobs := fs.Int("jobs", 4, "Projekte gleichzeitig auswerten")
+	cacheDir := fs.String("cache-dir", httpcache.DefaultDir("libyears"), "gemeinsamer Registry-Cache aller Projekte")
+	threshold := fs.Float64("threshold", 1, "Lag in Jahren, ab dem eine Dependency als veraltet gezählt wird")
+	out := fs.String("out", "", "Vergleich zusätzlich als JSON schreiben (\"-\" = stdout)")
+	asOfFlag := fs.String("as-of", "", "alle Projekte zum Stichtag JJJJ-MM-TT auswerten (s. asof.go)")
+	_ = fs.Parse(args) // ExitOnError
+	provenance.Start("libyears batch", fs)
+	if err := logOpts.Setup(); err != nil {
+		logging.Fatal("Logging-Setup fehlgeschlagen", "err", err)
+	}
+	if err := netOpts.Setup(); err != nil {
+		logging.Fatal("Netz-Setup fehlgeschlagen", "err", err)
+	}
+	if err := anonOpts.Setup(); err != nil {
+		logging.Usage("ungültiges --anonymize", "err", err)
+	}
+	anonOpts.Export() // die Projekte anonymisieren ihre Ausgabe selbst
+	if err := schemaOpts.Setup(); err != nil {
+		logging.Usage("ungültiges --schema-version", "err", err)
+	}
+	if err := cloneOpts.Setup(); err != nil {
+		logging.Usage("ungültige Mirror-Flags", "err", err)
+	}
+	if err := langOpts.Setup(); err != nil {
+		logging.Usage("ungültiges --lang", "err", err)
+	}
+	langOpts.Export() // die Projekte schreiben in derselben Sprache
+	if err := exitOpts.Setup(); err != nil {
+		logging.Usage("ungültiges --max-skipped", "err", err)
+	}
+	exitOpts.Export()
+
+	var cfg batchConfig
+	b, err := os.ReadFile(*projects)
+	if err == nil {
+		err = yaml.Unmarshal(b, &cfg)
+	}
+	if err != nil {
+		logging.Fatal("Projektliste nicht lesbar", "file", *projects, "err", err)
+	}
+	if len(cfg.Projects) == 0 {
+		logging.Usage("Projektliste enthält keine Projekte", "file", *projects)
+	}
+	if cfg.Jobs > 0 && !flagSet(fs, "jobs") {
+		*jobs = cfg.Jobs
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		logging.Fatal("eigenes Programm nicht auffindbar", "err", err)
+	}
+
+	// Logging- und Netz-Flags gelten auch für die Projekte.
+	pass := []string{"--cache-dir", *cacheDir, "--threshold", strconv.FormatFloat(*threshold, 'f', -1, 64)}
+	if *asOfFlag != "" {
+		if err := parseAsOf(*asOfFlag); err != nil {
+			logging.Usage("ungültiges --as-of", "err", err)
+		}
+		pass = append(pass, "--as-of", *asOfFlag)
+	}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "log-level", "log-format", "ca-bundle", "insecure-skip-verify":
+			pass = append(pass, "--"+f.Name+"="+f.Value.String())
+		}
+	})
+
+	base := filepath.Dir(*projects)
+	rows := make([]batchRow, len(cfg.Projects))
+	sem := make(chan struct{}, max(*jobs, 1))
+	var wg sync.WaitGroup
+	for i, p := range cfg.Projects {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			rows[i] = runProject(exe, base, p, pass)
+		}()
+	}
+	wg.Wait()
+	// fehlgeschlagene und unvollständige Projekte zählen für --max-skipped
+	skipped := 0
+	for _, r := range rows {
+		if r.Error != "" || r.partial {
+			skipped++
+		}
+	}
+	exitcode.Skipped(skipped, len(rows))
+
+	// fehlgeschlagene Projekte ans Ende, sonst nach Gesamt-Lag absteigend
+	sort.SliceStable(rows, func(i, j int) bool {
+		if (rows[i].Error == "") != (rows[j].Error == "") {
+			return rows[i].Error == ""
+		}
+		return rows[i].TotalLag > rows[j].TotalLag
+	})
+	printBatch(rows)
+	if *out != "" {
+		for i := range rows {
+			rows[i].Project = anon.ID(rows[i].Project)
+		}
+		var doc any = batchResult{Schema: schema.ID("libyears-batch"), Projects: rows, Provenance: provenance.Get()}
+		if schema.Version("libyears-batch") == 1 {
+			doc = rows
+		}
+		if err := report.WriteJSON(*out, doc); err != nil {
+			logging.Fatal("JSON-Ausgabe fehlgeschlagen", "file", *out, "err", err)
+		}
+	}
+}
+
+// runProject wertet ein Projekt in einem eigenen Prozess aus und liest
+// dessen --out-JSON.
+func runProject(exe, base string, p batchProject, pass []string) batchRow {
+	row := batchRow{Project: p.Name, Eco: p.Eco}
+	if row.Project == "" {
+		row.Project = p.Repo + p.Path
+	}
+	fail := func(err error) batchRow {
+		slog.Error("Projekt fehlgeschlagen", "project", row.Project, "err", err)
+		row.Error = err.Error()
+		return row
+	}
+	switch p.Eco {
+	case "go", "npm", "py":
+	default:
+		return fail(fmt.Errorf("unbekanntes eco %q (go, npm, py)", p.Eco))
+	}
+
+	tmp, err := os.MkdirTemp("", "libyears-batch-")
+	if err != nil {
+		return fail(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	root := base
+	if p.Repo != "" {
+		root = filepath.Join(tmp, "repo")
+		src, clone := p.Repo, []string{"clone", "--quiet", "--depth", "1"}
+		if clones.Enabled() {
+			// Checkout teilt die Objekte des Mirrors, --depth entfällt
+			mirror, release, err := clones.Mirror(p.Repo)
+			if err != nil {
+				return fail(fmt.Errorf("mirror: %w", err))
+			}
+			defer release()
+			src, clone = mirror, []string{"clone", "--quiet", "--shared"}
+		}
+		if p.Ref != "" {
+			clone = append(clone, "--branch", p.Ref)
+		}
+		if msg, err := exec.Command("git", append(clone, src, root)...).CombinedOutput(); err != nil {
+			return fail(fmt.Errorf("git clone: %v: %s", err, strings.TrimSpace(string(msg))))
+		}
+	}
+	paths := p.Paths
+	if p.Path != "" {
+		paths = append([]string{p.Path}, paths...)
+	}
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	for i, pth := range paths {
+		if !filepath.IsAbs(pth) {
+			paths[i] = filepath.Join(root, pth)
+		}
+	}
+
+	resFile := filepath.Join(tmp, "result.json")
+	args := append([]string{p.Eco, "--out", resFile}, pass...)
+	args = append(append(args, p.Args...), paths...)
+	cmd := exec.Command(exe, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr // stdout (Tabelle) wird verworfen
+	slog.Info("starte Projekt", "project", row.Project, "eco", p.Eco)
+	if err := cmd.Run(); err != nil {
+		var ee *exec.ExitError
+		if !errors.As(err, &ee) || ee.ExitCode() != exitcode.Partial {
+			return fail(fmt.Errorf("%v: %s", err, lastLine(stderr.String())))
+		}
+		row.partial = true
+	}
+
+	var res result
+	if err := report.ReadJSON(resFile, &res); err != nil {
+		return fail(err)
+	}
+	row.Source, row.Coverage, row.Provenance = res.Source, res.Coverage, res.Provenance
+	row.Count, row.TotalLag, row.MeanLag = res.Summary.Count, res.Summary.TotalLag, res.Summary.MeanLag
+	for i, d := range res.Deps {
+		if row.Worst == nil || d.Lag > row.Worst.Lag {
+			row.Worst = &res.Deps[i]
+		}
+	}
+	return row
+}
+
+func printBatch(rows []batchRow) {
+	fmt.Printf("%-25s %-4s %6s %10s %8s  %-35s %6s\n", i18n.T("Projekt"), "Eco", i18n.T("Pakete"), "Lag(yr)", "Ø", i18n.T("Schlechteste Dependency"), i18n.T("Abdeck."))
+	var total float64
+	failed := 0
+	for _, r := range rows {
+		if r.Error != "" {
+			i18n.Printf("%-25s %-4s  Fehler: %s\n", r.Project, r.Eco, r.Error)
+			failed++
+			continue
+		}
+		worst := "-"
+		if r.Worst != nil {
+			worst = fmt.Sprintf("%s (%.2f)", r.Worst.Package, r.Worst.Lag)
+		}
+		fmt.Printf("%-25s %-4s %6d %10.2f %8.2f  %-35s %5.0f%%\n", r.Project, r.Eco, r.Count, r.TotalLag, r.MeanLag, worst, r.Coverage*100)
+		total += r.TotalLag
+	}
+	i18n.Printf("\nTOTAL Lag über %d Projekte: %.2f", len(rows)-failed, total)
+	if failed > 0 {
+		i18n.Printf("  |  %d fehlgeschlagen", failed)
+	}
+	fmt.Println()
+}
+
+// flagSet meldet, ob name explizit auf der Kommandozeile stand.
+func flagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) { set = set || f.Name == name })
+	return set
+}
+
+// lastLine liefert die letzte nicht-leere Zeile, meist die Fatal-Meldung.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return lines[len(lines)-1]
+}
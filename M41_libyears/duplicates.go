@@ -0,0 +1,146 @@
+// duplicates.go – --duplicates: Pakete, die laut package-lock.json in
+// mehreren Versionen nebeneinander installiert sind (verschachtelte
+// node_modules), mit Lag je Instanz
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"baa_fs25/shared/anon"
+	"baa_fs25/shared/i18n"
+	"golang.org/x/mod/semver"
+)
+
+// duplicate ist ein Paket mit mehr als einer installierten Version.
+// Installs zählt alle Lockfile-Pfade, Extra die Versionen über die erste
+// hinaus – beides ein Hygiene-Signal unabhängig vom Lag.
+type duplicate struct {
+	Package   string        `json:"package"`
+	Installs  int           `json:"installs"`
+	Extra     int           `json:"extra_versions"`
+	Instances []dupInstance `json:"instances"`
+}
+
+// dupInstance ist eine installierte Version; Lag fehlt, wenn die Registry
+// keine Angabe liefert (Detail nennt den Grund).
+type dupInstance struct {
+	Version string   `json:"version"`
+	Latest  string   `json:"latest,omitempty"`
+	Lag     *float64 `json:"lag_years,omitempty"`
+	Detail  string   `json:"detail,omitempty"`
+	Paths   []string `json:"paths"`
+}
+
+// lockPackage liefert den Paketnamen eines Lockfile-Pfads ("…/node_modules/
+// @scope/x" → "@scope/x"); Workspace-Verzeichnisse ohne node_modules und der
+// Root ("") liefern "".
+func lockPackage(path string) string {
+	i := strings.LastIndex(path, "node_modules/")
+	if i < 0 {
+		return ""
+	}
+	return path[i+len("node_modules/"):]
+}
+
+// npmDuplicates sucht im Lockfile die Pakete mit mehreren Versionen und
+// bestimmt den Lag jeder Version zur neuesten. Von lockfileVersion 1 liest
+// readNPMLock nur die oberste Ebene; dort bleibt die Liste leer.
+func (c *common) npmDuplicates(lock npmLock) []duplicate {
+	byName := map[string]map[string][]string{} // Paket → Version → Pfade
+	for path, ver := range lock {
+		name := lockPackage(path)
+		if name == "" {
+			continue
+		}
+		if byName[name] == nil {
+			byName[name] = map[string][]string{}
+		}
+		byName[name][ver] = append(byName[name][ver], path)
+	}
+	var out []duplicate
+	for name, vers := range byName {
+		if len(vers) < 2 {
+			continue
+		}
+		d := duplicate{Package: name, Extra: len(vers) - 1}
+		for ver, paths := range vers {
+			sort.Strings(paths)
+			in := dupInstance{Version: ver, Paths: paths}
+			if h := npmLibyearCached(name, ver, ""); h.err != nil {
+				in.Detail = h.err.Error()
+			} else {
+				lag := h.lag
+				in.Latest, in.Lag = h.latest, &lag
+			}
+			d.Installs += len(paths)
+			d.Instances = append(d.Instances, in)
+		}
+		sort.Slice(d.Instances, func(i, j int) bool {
+			return semver.Compare("v"+d.Instances[i].Version, "v"+d.Instances[j].Version) < 0
+		})
+		out = append(out, d)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Extra != out[j].Extra {
+			return out[i].Extra > out[j].Extra
+		}
+		return out[i].Package < out[j].Package
+	})
+	return out
+}
+
+// printDuplicates gibt je dupliziertem Paket die Versionen mit Lag und
+// Anzahl der Installationen aus.
+func printDuplicates(dups []duplicate) {
+	if len(dups) == 0 {
+		i18n.Println("\nDuplikate: keine Pakete in mehreren Versionen installiert")
+		return
+	}
+	extra := 0
+	for _, d := range dups {
+		extra += d.Extra
+	}
+	i18n.Printf("\nDuplikate (%d Pakete in mehreren Versionen, %d überzählige Versionen):\n", len(dups), extra)
+	fmt.Printf("%-25s %-10s %-10s %8s %6s\n", i18n.T("Package"), "Version", i18n.T("Latest"), "Lag(yr)", "Inst.")
+	for _, d := range dups {
+		for i, in := range d.Instances {
+			name := d.Package
+			if i > 0 {
+				name = ""
+			}
+			lag := "     n/a"
+			if in.Lag != nil {
+				lag = fmt.Sprintf("%8.2f", *in.Lag)
+			}
+			fmt.Printf("%-25s %-10s %-10s %s %6d\n", name, in.Version, in.Latest, lag, len(in.Paths))
+		}
+	}
+}
+
+// anonDuplicates pseudonymisiert die Workspace-Verzeichnisse in den
+// Lockfile-Pfaden; die node_modules-Kette besteht aus öffentlichen Paketen.
+func anonDuplicates(dups []duplicate) []duplicate {
+	if dups == nil {
+		return nil
+	}
+	out := make([]duplicate, len(dups))
+	for i, d := range dups {
+		ins := make([]dupInstance, len(d.Instances))
+		for j, in := range d.Instances {
+			paths := make([]string, len(in.Paths))
+			for k, p := range in.Paths {
+				if dir, rest, ok := strings.Cut(p, "/node_modules/"); ok && !strings.HasPrefix(p, "node_modules/") {
+					p = anon.Path(dir) + "/node_modules/" + rest
+				}
+				paths[k] = p
+			}
+			in.Paths = paths
+			ins[j] = in
+		}
+		d.Instances = ins
+		out[i] = d
+	}
+	return out
+}
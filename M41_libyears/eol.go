@@ -0,0 +1,140 @@
+// eol.go – EOL-/Wartungsstatus (--eol): endoflife.date und Deprecation-Flags
+// der Registries
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/semver"
+)
+
+// eolProducts bildet Pakete auf Produkte von endoflife.date ab. Nur Pakete,
+// deren Versionszyklen dort direkt der Paketversion entsprechen.
+var eolProducts = map[string]map[string]string{
+	"npm": {
+		"react": "react", "react-dom": "react", "@angular/core": "angular", "angular": "angularjs",
+		"vue": "vue", "jquery": "jquery", "next": "nextjs", "nuxt": "nuxt", "electron": "electron",
+		"bootstrap": "bootstrap", "ember-source": "emberjs", "svelte": "svelte",
+	},
+	"py": {
+		"django": "django", "numpy": "numpy", "pandas": "pandas", "ansible-core": "ansible-core",
+		"sqlalchemy": "sqlalchemy", "celery": "celery", "wagtail": "wagtail",
+	},
+	"go": {},
+}
+
+type eolCycle struct {
+	Cycle       string          `json:"cycle"`
+	EOL         json.RawMessage `json:"eol"` // Datum oder bool
+	ReleaseDate string          `json:"releaseDate"`
+	LTS         json.RawMessage `json:"lts"` // Beginn des LTS-Supports oder bool
+}
+
+// isLTS meldet, ob der Zyklus (am --as-of-Stichtag) eine LTS-Linie ist.
+func (cy eolCycle) isLTS() bool {
+	var flag bool
+	if json.Unmarshal(cy.LTS, &flag) == nil {
+		return flag
+	}
+	var date string
+	if json.Unmarshal(cy.LTS, &date) != nil {
+		return false
+	}
+	t, err := time.Parse("2006-01-02", date)
+	return err == nil && visible(t) && t.Before(time.Now())
+}
+
+var eolCache = map[string][]eolCycle{}
+
+// eolStatus liefert "EOL <Datum>" bzw. "EOL", wenn der Release-Zyklus der
+// verwendeten Version abgekündigt ist, sonst "".
+func eolStatus(eco, pkg, ver string) string {
+	name := pkg
+	if eco == "py" {
+		name = canonicalName(pkg)
+	}
+	product, ok := eolProducts[eco][name]
+	if !ok {
+		return ""
+	}
+	cycles, err := eolCycles(product)
+	if err != nil {
+		slog.Warn("endoflife.date nicht abrufbar", "product", product, "err", err)
+	}
+	sv := "v" + strings.TrimPrefix(ver, "v")
+	for _, cy := range cycles {
+		c := "v" + cy.Cycle
+		if c != semver.Major(sv) && c != semver.MajorMinor(sv) {
+			continue
+		}
+		var flag bool
+		if json.Unmarshal(cy.EOL, &flag) == nil {
+			if flag {
+				return "EOL"
+			}
+			return ""
+		}
+		var date string
+		if json.Unmarshal(cy.EOL, &date) == nil {
+			if t, err := time.Parse("2006-01-02", date); err == nil && t.Before(time.Now()) {
+				return "EOL " + date
+			}
+		}
+		return ""
+	}
+	return ""
+}
+
+// eolCycles liefert die Zyklen eines Produkts, pro Lauf nur einmal
+// abgefragt (auch fehlgeschlagene Abfragen).
+func eolCycles(product string) ([]eolCycle, error) {
+	if cycles, ok := eolCache[product]; ok {
+		return cycles, nil
+	}
+	cycles, err := fetchEOL(product)
+	eolCache[product] = cycles
+	return cycles, err
+}
+
+func fetchEOL(product string) ([]eolCycle, error) {
+	resp, err := client.Get("https://endoflife.date/api/" + url.PathEscape(product) + ".json")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	var cycles []eolCycle
+	err = json.NewDecoder(resp.Body).Decode(&cycles)
+	return cycles, err
+}
+
+// enrich markiert Ausnahmen (--exceptions) und setzt bei --eol EOL-Status
+// und Deprecation einer Dependency; deprecated kommt aus der bereits
+// geladenen Registry-Antwort.
+func (c *common) enrich(d *dep, deprecated string) {
+	c.applyException(d)
+	if !c.eol {
+		return
+	}
+	d.EOL = eolStatus(c.eco, d.Package, d.Current)
+	d.Deprecated = deprecated
+}
+
+// eolMark kennzeichnet EOL- und deprecated-Dependencies in der Tabelle.
+func eolMark(d dep) string {
+	var s string
+	if d.EOL != "" {
+		s += "  " + d.EOL
+	}
+	if d.Deprecated != "" {
+		s += "  deprecated"
+	}
+	return s
+}
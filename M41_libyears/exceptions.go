@@ -0,0 +1,262 @@
+// exceptions.go – --exceptions: genehmigte Ausnahmen (wer, warum, bis wann)
+// zählen nicht in den Lag; abgelaufene Ausnahmen verfallen automatisch.
+// --exceptions-out schreibt sie als CycloneDX-Dokument im VEX-Stil.
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"baa_fs25/shared/anon"
+	"baa_fs25/shared/i18n"
+	"baa_fs25/shared/logging"
+	"baa_fs25/shared/purl"
+	"baa_fs25/shared/report"
+	"gopkg.in/yaml.v3"
+)
+
+// exception ist ein Eintrag der Ausnahmeliste (YAML oder JSON):
+//
+//	exceptions:
+//	  - package: lodash
+//	    version: 4.17.15        # optional, leer = jede Version
+//	    eco: npm                # optional, leer = jedes Ökosystem
+//	    reason: Migration auf v5 geplant
+//	    approved_by: jane.doe
+//	    expires: 2026-12-31
+type exception struct {
+	Package    string `yaml:"package" json:"package"`
+	Version    string `yaml:"version" json:"version,omitempty"`
+	Eco        string `yaml:"eco" json:"eco,omitempty"`
+	Reason     string `yaml:"reason" json:"reason"`
+	ApprovedBy string `yaml:"approved_by" json:"approved_by"`
+	Expires    string `yaml:"expires" json:"expires"`
+	until      time.Time
+}
+
+// Zustände einer Ausnahme im Ergebnis.
+const (
+	exceptionActive  = "active"  // gültig und angewendet
+	exceptionExpired = "expired" // abgelaufen, die Dependency zählt wieder
+	exceptionUnused  = "unused"  // gültig, aber keine passende Dependency
+)
+
+// exceptionStatus ist eine Ausnahme im --out-JSON mit ihrem Zustand und
+// den betroffenen Versionen.
+type exceptionStatus struct {
+	exception
+	State   string   `json:"state"`
+	Matches []string `json:"matches,omitempty"` // Paket@Version
+}
+
+// loadExceptions liest --exceptions. Grund, Genehmigung und Ablaufdatum sind
+// Pflicht, damit jede Ausnahme nachvollziehbar bleibt.
+func (c *common) loadExceptions() {
+	if c.exceptionsFile == "" {
+		return
+	}
+	b, err := os.ReadFile(c.exceptionsFile)
+	if err != nil {
+		logging.Fatal("Ausnahmeliste nicht lesbar", "file", c.exceptionsFile, "err", err)
+	}
+	var doc struct {
+		Exceptions []exception `yaml:"exceptions"`
+	}
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		logging.Usage("Ausnahmeliste ungültig", "file", c.exceptionsFile, "err", err)
+	}
+	for i, e := range doc.Exceptions {
+		if e.Package == "" || e.Reason == "" || e.ApprovedBy == "" || e.Expires == "" {
+			logging.Usage("Ausnahme unvollständig (package, reason, approved_by, expires sind Pflicht)", "file", c.exceptionsFile, "index", i)
+		}
+		if e.until, err = time.Parse("2006-01-02", e.Expires); err != nil {
+			logging.Usage("ungültiges expires (JJJJ-MM-TT)", "package", e.Package, "value", e.Expires)
+		}
+		doc.Exceptions[i] = e
+	}
+	c.exceptions = doc.Exceptions
+}
+
+// expired meldet, ob die Ausnahme am Ende ihres expires-Tages abgelaufen ist.
+func (e exception) expired() bool {
+	return !time.Now().Before(e.until.AddDate(0, 0, 1))
+}
+
+func (e exception) matches(eco string, d dep) bool {
+	return strings.EqualFold(e.Package, d.Package) &&
+		(e.Version == "" || e.Version == d.Current) &&
+		(e.Eco == "" || e.Eco == eco)
+}
+
+// applyException markiert d, wenn eine gültige Ausnahme passt. Passt nur eine
+// abgelaufene, wird das gemeldet und d zählt normal.
+func (c *common) applyException(d *dep) {
+	for _, e := range c.exceptions {
+		if !e.matches(c.eco, *d) {
+			continue
+		}
+		if e.expired() {
+			slog.Warn("Ausnahme abgelaufen, zählt wieder", "pkg", d.Package, "version", d.Current, "expires", e.Expires, "approved_by", e.ApprovedBy)
+			continue
+		}
+		d.Excepted = true
+		return
+	}
+}
+
+// exceptionStates bestimmt Zustand und Treffer jeder Ausnahme.
+func (c *common) exceptionStates(deps []dep) []exceptionStatus {
+	var out []exceptionStatus
+	for _, e := range c.exceptions {
+		s := exceptionStatus{exception: e, State: exceptionUnused}
+		for _, d := range deps {
+			if e.matches(c.eco, d) {
+				s.Matches = append(s.Matches, d.Package+"@"+d.Current)
+			}
+		}
+		switch {
+		case e.expired():
+			s.State = exceptionExpired
+		case len(s.Matches) > 0:
+			s.State = exceptionActive
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// printExceptions fasst die Ausnahmen unter der Tabelle zusammen.
+func printExceptions(states []exceptionStatus) {
+	n := map[string]int{}
+	for _, s := range states {
+		n[s.State]++
+	}
+	if len(states) == 0 {
+		return
+	}
+	i18n.Printf("Ausnahmen: %d aktiv, %d abgelaufen, %d ohne Treffer\n", n[exceptionActive], n[exceptionExpired], n[exceptionUnused])
+	for _, s := range states {
+		if s.State == exceptionExpired {
+			i18n.Printf("  abgelaufen: %-25s seit %s (genehmigt von %s)\n", s.Package, s.Expires, s.ApprovedBy)
+		}
+	}
+}
+
+// exceptionMark kennzeichnet Dependencies mit gültiger Ausnahme in der
+// Tabelle.
+func exceptionMark(d dep) string {
+	if d.Excepted {
+		return "  [ausnahme]"
+	}
+	return ""
+}
+
+// cdxDoc ist CycloneDX 1.5 im VEX-Stil: jede Ausnahme ist ein Befund mit
+// analysis.state und response. Gültige Ausnahmen sind akzeptiertes Risiko
+// (exploitable + will_not_fix), abgelaufene wieder offen (in_triage);
+// Genehmigung und Ablaufdatum stehen in properties.
+type cdxDoc struct {
+	BOMFormat       string    `json:"bomFormat"`
+	SpecVersion     string    `json:"specVersion"`
+	Version         int       `json:"version"`
+	Metadata        cdxMeta   `json:"metadata"`
+	Components      []cdxComp `json:"components,omitempty"`
+	Vulnerabilities []cdxVuln `json:"vulnerabilities"`
+}
+
+type cdxMeta struct {
+	Timestamp time.Time `json:"timestamp"`
+	Tools     struct {
+		Components []cdxComp `json:"components"`
+	} `json:"tools"`
+}
+
+type cdxComp struct {
+	Type    string `json:"type"`
+	BOMRef  string `json:"bom-ref,omitempty"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Purl    string `json:"purl,omitempty"`
+}
+
+type cdxVuln struct {
+	ID     string `json:"id"`
+	Source struct {
+		Name string `json:"name"`
+	} `json:"source"`
+	Description    string      `json:"description"`
+	Recommendation string      `json:"recommendation,omitempty"`
+	Affects        []cdxAffect `json:"affects,omitempty"`
+	Analysis       struct {
+		State       string    `json:"state"`
+		Response    []string  `json:"response,omitempty"`
+		Detail      string    `json:"detail"`
+		LastUpdated time.Time `json:"lastUpdated"`
+	} `json:"analysis"`
+	Properties []cdxProp `json:"properties"`
+}
+
+type cdxAffect struct {
+	Ref string `json:"ref"`
+}
+
+type cdxProp struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// writeExceptions schreibt das Ausnahmedokument, falls --exceptions-out
+// gesetzt ist. Mit --anonymize wird approved_by gehasht.
+func (c *common) writeExceptions(deps []dep) {
+	if c.exceptionsOut == "" {
+		return
+	}
+	now := time.Now().UTC()
+	doc := cdxDoc{BOMFormat: "CycloneDX", SpecVersion: "1.5", Version: 1, Vulnerabilities: []cdxVuln{}}
+	doc.Metadata.Timestamp = now
+	doc.Metadata.Tools.Components = []cdxComp{{Type: "application", Name: "libyears " + c.eco}}
+	refs := map[string]bool{}
+	for _, s := range c.exceptionStates(deps) {
+		v := cdxVuln{ID: "LIBYEARS-EXCEPTION-" + s.Package, Description: "Veraltete Dependency: " + s.Package}
+		if s.Version != "" {
+			v.ID += "@" + s.Version
+		}
+		v.Source.Name = "libyears"
+		for _, d := range deps {
+			if !s.matches(c.eco, d) {
+				continue
+			}
+			p := purl.For(c.eco, d.Package, d.Current)
+			if !refs[p] {
+				refs[p] = true
+				doc.Components = append(doc.Components, cdxComp{Type: "library", BOMRef: p, Name: d.Package, Version: d.Current, Purl: p})
+			}
+			v.Affects = append(v.Affects, cdxAffect{Ref: p})
+			v.Description = fmt.Sprintf("%s %s liegt %.2f Jahre hinter %s", d.Package, d.Current, d.Lag, d.Latest)
+			v.Recommendation = "Update auf " + d.Latest
+		}
+		v.Analysis.Detail, v.Analysis.LastUpdated = s.Reason, now
+		switch s.State {
+		case exceptionExpired:
+			v.Analysis.State = "in_triage"
+		default:
+			v.Analysis.State, v.Analysis.Response = "exploitable", []string{"will_not_fix"}
+		}
+		approver := s.ApprovedBy
+		if anon.Enabled() {
+			approver = anon.ID(approver)
+		}
+		v.Properties = []cdxProp{
+			{Name: "baa:exception:state", Value: s.State},
+			{Name: "baa:exception:approved-by", Value: approver},
+			{Name: "baa:exception:expires", Value: s.Expires},
+		}
+		doc.Vulnerabilities = append(doc.Vulnerabilities, v)
+	}
+	if err := report.WriteJSON(c.exceptionsOut, doc); err != nil {
+		logging.Fatal("Ausnahmedokument nicht geschrieben", "file", c.exceptionsOut, "err", err)
+	}
+}
@@ -0,0 +1,98 @@
+// fix.go – --fix-script: Upgrade-Befehle für die veralteten Dependencies
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"baa_fs25/shared/logging"
+)
+
+// writeFixScript schreibt, falls --fix-script gesetzt ist, ein Shell-Skript
+// mit je einem Upgrade-Befehl pro Dependency über --threshold, nach Lag
+// absteigend sortiert.
+func (c *common) writeFixScript(eco string, source []string, deps []dep) {
+	if c.fixScript == "" {
+		return
+	}
+	outdated := make([]dep, 0, len(deps))
+	for _, d := range deps {
+		if d.Lag > c.threshold && d.Latest != "" && d.Latest != d.Current {
+			outdated = append(outdated, d)
+		}
+	}
+	sort.SliceStable(outdated, func(i, j int) bool { return outdated[i].Lag > outdated[j].Lag })
+
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	fmt.Fprintf(&b, "# libyears %s: %d Dependencies über %.1f Jahr(en), nach Lag sortiert\n", eco, len(outdated), c.threshold)
+	b.WriteString("set -e\n")
+	switch eco {
+	case "go":
+		fmt.Fprintf(&b, "cd %s\n", shQuote(source[0]))
+	case "npm":
+		fmt.Fprintf(&b, "cd %s\n", shQuote(filepath.Dir(source[0])))
+	}
+	seen := map[string]bool{}
+	for _, d := range outdated {
+		cmd := c.fixCommand(eco, d)
+		if seen[cmd] {
+			continue // py: derselbe Pin in mehreren Dateien
+		}
+		seen[cmd] = true
+		fmt.Fprintf(&b, "\n# %s %s → %s (%.2f Jahre)\n", d.Package, d.Current, d.Latest, d.Lag)
+		if d.Overridden {
+			b.WriteString("# Achtung: Version ist überschrieben (" + overrideSource(eco) + "), dort ebenfalls anpassen\n")
+		}
+		b.WriteString(cmd + "\n")
+	}
+	if eco == "go" && len(outdated) > 0 {
+		b.WriteString("\ngo mod tidy\n")
+	}
+	if err := os.WriteFile(c.fixScript, []byte(b.String()), 0o755); err != nil {
+		logging.Fatal("Fix-Skript nicht schreibbar", "file", c.fixScript, "err", err)
+	}
+}
+
+// fixCommand liefert den Upgrade-Befehl für d.
+func (c *common) fixCommand(eco string, d dep) string {
+	switch eco {
+	case "go":
+		return "go get " + shQuote(d.Package+"@"+d.Latest)
+	case "npm":
+		cmd := "npm install " + shQuote(d.Package+"@"+d.Latest)
+		switch d.Kind { // sonst landet das Paket in dependencies
+		case kindDev:
+			cmd += " --save-dev"
+		case kindOptional:
+			cmd += " --save-optional"
+		}
+		if d.Workspace != "" && d.Workspace != c.npmRoot {
+			cmd += " -w " + shQuote(d.Workspace)
+		}
+		return cmd
+	default:
+		return "pip install -U " + shQuote(d.Package+"=="+d.Latest)
+	}
+}
+
+func overrideSource(eco string) string {
+	if eco == "go" {
+		return "replace in go.mod"
+	}
+	return "overrides/resolutions in package.json"
+}
+
+var shSafe = regexp.MustCompile(`^[A-Za-z0-9@%+=:,./_-]+$`)
+
+// shQuote quotet s für sh, falls nötig.
+func shQuote(s string) string {
+	if shSafe.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
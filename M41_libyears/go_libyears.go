@@ -1,10 +1,10 @@
-// go_libyears_k8s.go
+// go_libyears.go
 //
 // Usage:
-//   go run go_libyears_k8s.go /path/to/moduleRoot
+//   go run . go /path/to/moduleRoot
 //
 // Beispiel:
-//   go run go_libyears_k8s.go /tmp/libyears-123/kubernetes
+//   go run . go /tmp/libyears-123/kubernetes
 //
 // Der Befehl muss INSIDE eines Go-Moduls ausgeführt werden
 // (go mod download sollte fehlerfrei sein).
@@ -15,11 +15,18 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
 	"time"
+
+	"baa_fs25/shared/exitcode"
+	"baa_fs25/shared/i18n"
+	"baa_fs25/shared/purl"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
 )
 
 var semverTag = regexp.MustCompile(`^v[0-9]+\.[0-9]+\.[0-9]+$`)
@@ -30,72 +37,187 @@ type Mod struct {
 	Time     *time.Time
 	Indirect bool
 	Main     bool
-	Update   *struct {
+	// Deprecated ist der "// Deprecated:"-Kommentar aus go.mod des Moduls
+	// (go list -u).
+	Deprecated string
+	Update     *modVersion
+	Replace    *struct {
+		Path    string
 		Version string
 		Time    *time.Time
 	}
 }
 
-func main() {
-	if len(os.Args) != 2 {
-		fmt.Println("Usage: go run go_libyears_k8s.go /path/to/moduleRoot")
-		os.Exit(1)
+// modVersion ist eine Version mit Zeitstempel, wie go list und der Proxy sie
+// liefern.
+type modVersion struct {
+	Version string
+	Time    *time.Time
+}
+
+func runGo(args []string) {
+	fs, c := newFlagSet("go")
+	parseFlags(fs, c, args)
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: go run . go [flags] /path/to/moduleRoot")
+		os.Exit(exitcode.Usage)
 	}
-	modDir := filepath.Clean(os.Args[1])
+	modDir := filepath.Clean(fs.Arg(0))
 
-	// go list -m -u -json all  ==> Current + Latest Info
-	cmd := exec.Command("go", "list", "-mod=mod", "-m", "-u", "-json", "all")
+	c.watch(func() error {
+		// go list -m -u -json all  ==> Current + Latest Info
+		cmd := exec.Command("go", "list", "-mod=mod", "-m", "-u", "-json", "all")
 
-	cmd.Dir = modDir
-	cmd.Env = append(os.Environ(), "GOWORK=off")
-	out, err := cmd.Output()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "go list failed: %v\n", err)
-		os.Exit(1)
-	}
+		cmd.Dir = modDir
+		cmd.Env = append(os.Environ(), "GOWORK=off")
+		out, err := cmd.Output()
+		if err != nil {
+			return fmt.Errorf("go list in %s: %w", modDir, err)
+		}
 
-	dec := json.NewDecoder(bytes.NewReader(out))
+		dec := json.NewDecoder(bytes.NewReader(out))
 
-	var (
-		totalDirect int
-		usedCount   int
-		totalLag    float64
-	)
+		var (
+			totalDirect int
+			usedCount   int
+			countedN    int // ohne Ausnahmen (--exceptions)
+			totalLag    float64
+			deps        []dep
+		)
 
-	fmt.Printf("%-28s %-12s %-12s %8s\n", "Package", "Current", "Latest", "Lag(yr)")
-	for dec.More() {
-		var m Mod
-		if err := dec.Decode(&m); err != nil {
-			fmt.Fprintf(os.Stderr, "decode error: %v\n", err)
-			os.Exit(1)
-		}
+		i18n.Printf("%-28s %-12s %-12s %8s\n", i18n.T("Package"), i18n.T("Current"), i18n.T("Latest"), "Lag(yr)")
+		for dec.More() {
+			var m Mod
+			if err := dec.Decode(&m); err != nil {
+				return fmt.Errorf("go list Ausgabe nicht lesbar (%s): %w", modDir, err)
+			}
+
+			if m.Main || m.Indirect {
+				continue // nur direkte Fremd-Module
+			}
+			totalDirect++
+			goCurrent(&m)
+
+			// replace-Direktiven bestimmen, was tatsächlich gebaut wird.
+			overridden := false
+			if r := m.Replace; r != nil {
+				if r.Version == "" {
+					c.skip(skipped{Package: m.Path, Version: m.Version, Reason: reasonLocal, Detail: r.Path})
+					continue
+				}
+				overridden = true
+				m.Version, m.Time = r.Version, r.Time
+				if r.Path != m.Path {
+					m.Update = latestGoModule(r.Path)
+				}
+			}
 
-		if m.Main || m.Indirect {
-			continue // nur direkte Fremd-Module
+			// --as-of: neueste Version vor dem Stichtag statt der von go list
+			if !asOf.IsZero() && m.Time != nil {
+				if !visible(*m.Time) {
+					c.skip(skipped{Package: m.Path, Version: m.Version, Reason: reasonAfterAsOf})
+					continue
+				}
+				latest := m.Path
+				if r := m.Replace; r != nil {
+					latest = r.Path
+				}
+				m.Update = goLatestAsOf(latest)
+				if m.Update != nil && semver.Compare(m.Update.Version, m.Version) < 0 {
+					m.Update = &modVersion{Version: m.Version, Time: m.Time}
+				}
+			}
+
+			lagY, reason := goLag(m)
+			if reason != "" {
+				c.skip(skipped{Package: m.Path, Version: m.Version, Reason: reason})
+				continue
+			}
+			usedCount++
+			d := dep{Package: m.Path, Purl: purl.For("go", m.Path, m.Version), Current: m.Version, Latest: m.Update.Version, Lag: lagY, Overridden: overridden}
+			c.enrich(&d, m.Deprecated)
+			d.released = *m.Time
+			if c.counts(d) {
+				totalLag += lagY
+				countedN++
+			}
+			deps = append(deps, d)
+			c.emitDep(d)
+
+			c.printRow(d, "%-28s %-12s %-12s %8.2f%s%s%s\n",
+				m.Path, m.Version, m.Update.Version, lagY, overrideMark(overridden), eolMark(d), exceptionMark(d))
 		}
-		totalDirect++
+		c.flushRows()
+
+		c.runtime = goRuntime(modDir)
+		defer c.printRuntime()
+		c.writeResult("go", []string{modDir}, deps)
 
-		// Wir brauchen: echte Tags + Release-Zeiten
-		if m.Update == nil || m.Time == nil || m.Update.Time == nil ||
-			!semverTag.MatchString(m.Version) || !semverTag.MatchString(m.Update.Version) {
-			fmt.Printf("[SKIP] %-22s  keine verwertbare Release-Info\n", m.Path)
-			continue
+		// Zusammenfassung
+		if usedCount == 0 {
+			i18n.Println("Keine auswertbaren Dependencies gefunden.")
+			c.printSkips()
+			return nil
 		}
+		fmt.Println()
+		i18n.Printf("TOTAL Lag: %.2f  |  Ø %.2f  |  %d/%d direkte Dependencies ausgewertet\n",
+			totalLag, totalLag/float64(max(countedN, 1)), usedCount, totalDirect)
+		c.printStats(deps)
+		return nil
+	})
+}
+
+// latestGoModule fragt die neueste Version eines Ersatzmoduls beim Go-Proxy
+// ab, da go list -u nur das ursprüngliche Modul prüft.
+func latestGoModule(path string) *modVersion {
+	esc, err := module.EscapePath(path)
+	if err != nil {
+		return nil
+	}
+	resp, err := client.Get("https://proxy.golang.org/" + esc + "/@latest")
+	if err != nil {
+		slog.Warn("Go-Proxy nicht erreichbar", "module", path, "err", err)
+		return nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		slog.Warn("Go-Proxy", "module", path, "status", resp.Status)
+		return nil
+	}
+	var info modVersion
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil
+	}
+	return &info
+}
 
-		lagY := m.Update.Time.Sub(*m.Time).Hours() / 24 / 365.0
-		totalLag += lagY
-		usedCount++
+// goCurrent setzt Update bei aktuellen Modulen auf die eigene Version: go
+// list -u füllt Update nur, wenn es eine neuere Version gibt, ein aktuelles
+// Modul hat also Lag 0 statt "kein Release-Datum".
+func goCurrent(m *Mod) {
+	if m.Update == nil && m.Time != nil && semverTag.MatchString(m.Version) {
+		m.Update = &modVersion{Version: m.Version, Time: m.Time}
+	}
+}
 
-		fmt.Printf("%-28s %-12s %-12s %8.2f\n",
-			m.Path, m.Version, m.Update.Version, lagY)
+// goLag liefert den Lag in Jahren bzw. den Grund, warum m nicht ausgewertet
+// wird. Gebraucht werden echte Tags und Release-Zeiten.
+func goLag(m Mod) (float64, string) {
+	if m.Update == nil || m.Time == nil || m.Update.Time == nil ||
+		!semverTag.MatchString(m.Version) || !semverTag.MatchString(m.Update.Version) {
+		return 0, goSkipReason(m)
 	}
+	return m.Update.Time.Sub(*m.Time).Hours() / 24 / 365.0, ""
+}
 
-	// Zusammenfassung
-	if usedCount == 0 {
-		fmt.Println("Keine auswertbaren Dependencies gefunden.")
-		return
+// goSkipReason unterscheidet Module ohne bekannte neuere Version von
+// Pseudo-Versionen bzw. Versionen ohne Zeitstempel.
+func goSkipReason(m Mod) string {
+	switch {
+	case m.Update == nil && m.Time == nil:
+		return reasonNotFound
+	case !semverTag.MatchString(m.Version):
+		return reasonRange
 	}
-	fmt.Println()
-	fmt.Printf("TOTAL Lag: %.2f  |  Ø %.2f  |  %d/%d direkte Dependencies ausgewertet\n",
-		totalLag, totalLag/float64(usedCount), usedCount, totalDirect)
+	return reasonNoDate
 }
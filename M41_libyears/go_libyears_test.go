@@ -0,0 +1,59 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"baa_fs25/shared/registry/registrytest"
+)
+
+func TestGoLag(t *testing.T) {
+	at := func(y int) *time.Time {
+		t := time.Date(y, 1, 1, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+	for _, tc := range []struct {
+		name   string
+		m      Mod
+		lag    float64
+		reason string
+	}{
+		{"aktuell", Mod{Path: "a", Version: "v1.2.3", Time: at(2020)}, 0, ""},
+		{"veraltet", Mod{Path: "b", Version: "v1.0.0", Time: at(2020), Update: &modVersion{Version: "v1.1.0", Time: at(2022)}}, 731.0 / 365, ""},
+		{"Pseudo-Version", Mod{Path: "c", Version: "v0.0.0-20200101000000-abcdefabcdef", Time: at(2020)}, 0, reasonRange},
+		{"ohne Zeitstempel", Mod{Path: "d", Version: "v1.0.0"}, 0, reasonNotFound},
+		{"Update ohne Zeitstempel", Mod{Path: "e", Version: "v1.0.0", Time: at(2020), Update: &modVersion{Version: "v1.1.0"}}, 0, reasonNoDate},
+	} {
+		goCurrent(&tc.m)
+		lag, reason := goLag(tc.m)
+		if lag != tc.lag || reason != tc.reason {
+			t.Errorf("%s: Lag %.4f/%q, erwartet %.4f/%q", tc.name, lag, reason, tc.lag, tc.reason)
+		}
+	}
+}
+
+func TestGoLatestAsOf(t *testing.T) {
+	old := goProxy
+	t.Cleanup(func() { goProxy = old })
+	goProxy = registrytest.Static{"github.com/!burnt!sushi/toml": {
+		"v1.0.0":                             day(2020, 1, 1),
+		"v1.1.0":                             day(2021, 1, 1),
+		"v1.2.0-rc.1":                        day(2021, 3, 1),
+		"v0.0.0-20210401000000-abcdefabcdef": day(2021, 4, 1),
+		"v2.0.0":                             day(2022, 1, 1),
+	}}
+	for at, want := range map[time.Time]string{
+		day(2021, 6, 1):  "v1.1.0", // ohne Vorab- und Pseudo-Versionen
+		day(2023, 1, 1):  "v2.0.0",
+		day(2019, 12, 1): "",
+	} {
+		setAsOf(t, at)
+		got := ""
+		if v := goLatestAsOf("github.com/BurntSushi/toml"); v != nil {
+			got = v.Version
+		}
+		if got != want {
+			t.Errorf("--as-of %s: %q, erwartet %q", at.Format(asOfLayout), got, want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// group.go – Zwischensummen je Scope/Organisation (--group-by scope)
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"baa_fs25/shared/i18n"
+)
+
+// groupSummary ist die Zwischensumme einer Gruppe.
+type groupSummary struct {
+	Group    string  `json:"group"`
+	Count    int     `json:"count"`
+	TotalLag float64 `json:"total_lag_years"`
+	MeanLag  float64 `json:"mean_lag_years"`
+}
+
+// scopeOf liefert die Gruppe eines Pakets: npm-@scope, bei Go Host und
+// Organisation des Modulpfads, bei Python der Namespace vor dem ersten
+// Trennzeichen (azure-mgmt-compute → azure).
+func scopeOf(eco, pkg string) string {
+	switch eco {
+	case "npm":
+		if strings.HasPrefix(pkg, "@") {
+			return pkg[:strings.Index(pkg+"/", "/")]
+		}
+		return "(ohne Scope)"
+	case "go":
+		parts := strings.Split(pkg, "/")
+		if len(parts) >= 2 && strings.Contains(parts[0], ".") {
+			return parts[0] + "/" + parts[1]
+		}
+		return parts[0]
+	default:
+		name := canonicalName(pkg)
+		if i := strings.Index(name, "-"); i > 0 {
+			return name[:i]
+		}
+		return name
+	}
+}
+
+// groupDeps fasst die (deduplizierten) Dependencies je Scope zusammen,
+// sortiert nach Gesamt-Lag absteigend.
+func groupDeps(eco string, deps []dep) []groupSummary {
+	idx := map[string]int{}
+	var out []groupSummary
+	for _, d := range dedupeDeps(deps) {
+		g := scopeOf(eco, d.Package)
+		i, ok := idx[g]
+		if !ok {
+			i = len(out)
+			idx[g] = i
+			out = append(out, groupSummary{Group: g})
+		}
+		out[i].Count++
+		out[i].TotalLag += d.Lag
+	}
+	for i := range out {
+		out[i].MeanLag = out[i].TotalLag / float64(out[i].Count)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].TotalLag != out[j].TotalLag {
+			return out[i].TotalLag > out[j].TotalLag
+		}
+		return out[i].Group < out[j].Group
+	})
+	return out
+}
+
+func printGroups(groups []groupSummary) {
+	fmt.Printf("\n%-35s %6s %10s %8s\n", "Scope", i18n.T("Pakete"), "Lag(yr)", "Ø")
+	for _, g := range groups {
+		fmt.Printf("%-35s %6d %10.2f %8.2f\n", g.Group, g.Count, g.TotalLag, g.MeanLag)
+	}
+}
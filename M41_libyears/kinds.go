@@ -0,0 +1,160 @@
+// kinds.go – Art der Dependency (runtime, dev, optional), Zwischensummen je
+// Art, --exclude-kind für die Gesamtzahl und --include-kind für die
+// npm-Listen, die überhaupt ausgewertet werden
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"baa_fs25/shared/depkind"
+	"baa_fs25/shared/i18n"
+	"baa_fs25/shared/logging"
+)
+
+// Arten einer Dependency; Go kennt keine Unterscheidung (Kind bleibt leer
+// und zählt wie runtime).
+const (
+	kindRuntime  = depkind.Runtime
+	kindDev      = depkind.Dev
+	kindOptional = depkind.Optional
+)
+
+var kindOrder = []string{kindRuntime, kindDev, kindOptional}
+
+// kindSummary ist die Zwischensumme einer Art im --out-JSON. Excluded
+// markiert Arten, die per --exclude-kind nicht in die Gesamtzahl eingehen.
+type kindSummary struct {
+	Kind     string  `json:"kind"`
+	Count    int     `json:"count"`
+	TotalLag float64 `json:"total_lag_years"`
+	MeanLag  float64 `json:"mean_lag_years"`
+	Excluded bool    `json:"excluded,omitempty"`
+}
+
+// parseKinds prüft --exclude-kind und --include-kind (kommagetrennt). npm
+// wertet wie bisher nur dependencies aus; devDependencies und
+// optionalDependencies kommen erst mit --include-kind dazu, damit sich die
+// Gesamtzahl nicht ungefragt ändert.
+func (c *common) parseKinds() {
+	c.excluded = map[string]bool{}
+	for _, k := range strings.Split(c.excludeKind, ",") {
+		k = strings.TrimSpace(k)
+		switch k {
+		case "":
+		case kindDev, kindOptional, kindRuntime:
+			c.excluded[k] = true
+		default:
+			logging.Usage("ungültiges --exclude-kind (erlaubt: runtime, dev, optional)", "value", k)
+		}
+	}
+	c.included = map[string]bool{kindRuntime: true}
+	for _, k := range strings.Split(c.includeKind, ",") {
+		k = strings.TrimSpace(k)
+		switch k {
+		case "", kindRuntime:
+		case kindDev, kindOptional:
+			c.included[k] = true
+		default:
+			logging.Usage("ungültiges --include-kind (erlaubt: dev, optional)", "value", k)
+		}
+	}
+}
+
+func kindOf(d dep) string {
+	if d.Kind == "" {
+		return kindRuntime
+	}
+	return d.Kind
+}
+
+// counts meldet, ob d in die Gesamtzahl eingeht: weder per --exclude-kind
+// ausgeschlossen noch per --exceptions ausgenommen.
+func (c *common) counts(d dep) bool {
+	return !c.excluded[kindOf(d)] && !d.Excepted
+}
+
+// counted liefert die Dependencies, die in die Gesamtzahl eingehen.
+func (c *common) counted(deps []dep) []dep {
+	if len(c.excluded) == 0 && len(c.exceptions) == 0 {
+		return deps
+	}
+	var out []dep
+	for _, d := range deps {
+		if c.counts(d) {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// kindSummaries bildet die Zwischensummen der (deduplizierten) Dependencies;
+// nil, wenn es nur eine Art gibt und nichts ausgeschlossen ist.
+func (c *common) kindSummaries(deps []dep) []kindSummary {
+	byKind := map[string]*kindSummary{}
+	for _, d := range dedupeDeps(deps) {
+		k := kindOf(d)
+		s := byKind[k]
+		if s == nil {
+			s = &kindSummary{Kind: k, Excluded: c.excluded[k]}
+			byKind[k] = s
+		}
+		s.Count++
+		s.TotalLag += d.Lag
+	}
+	if len(byKind) < 2 && len(c.excluded) == 0 {
+		return nil
+	}
+	var out []kindSummary
+	for _, k := range kindOrder {
+		if s := byKind[k]; s != nil {
+			s.MeanLag = s.TotalLag / float64(s.Count)
+			out = append(out, *s)
+		}
+	}
+	return out
+}
+
+func printKinds(kinds []kindSummary) {
+	var parts []string
+	for _, k := range kinds {
+		p := fmt.Sprintf("%s %.2f (%d, Ø %.2f)", k.Kind, k.TotalLag, k.Count, k.MeanLag)
+		if k.Excluded {
+			p += " " + i18n.T("ausgeschlossen")
+		}
+		parts = append(parts, p)
+	}
+	i18n.Printf("Nach Art: %s\n", strings.Join(parts, "  |  "))
+}
+
+// kindMark kennzeichnet dev- und optionale Dependencies in der Tabelle.
+func kindMark(d dep) string {
+	if d.Kind == "" || d.Kind == kindRuntime {
+		return ""
+	}
+	return "  [" + d.Kind + "]"
+}
+
+// pyKind ist die Art einer requirements-Datei, wie mttu sie zählt (s.
+// depkind). Relative Pfade unterhalb des Arbeitsverzeichnisses gehen ganz
+// ein (tests/requirements.txt), sonst nur der Dateiname.
+func pyKind(path string) string {
+	if !filepath.IsLocal(path) {
+		path = filepath.Base(path)
+	}
+	return depkind.Requirements(path)
+}
+
+// pinKind ist die Art eines Pins aus mehreren Dateien: runtime vor optional
+// vor dev.
+func pinKind(files []string) string {
+	rank := map[string]int{kindRuntime: 0, kindOptional: 1, kindDev: 2}
+	best := kindDev
+	for _, f := range files {
+		if k := pyKind(f); rank[k] < rank[best] {
+			best = k
+		}
+	}
+	return best
+}
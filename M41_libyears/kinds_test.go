@@ -0,0 +1,41 @@
+package main
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestPyKind(t *testing.T) {
+	for path, want := range map[string]string{
+		"requirements.txt":          kindRuntime,
+		"requirements-tox.txt":      kindDev,
+		"requirements/local.txt":    kindDev,
+		"tests/requirements.txt":    kindDev,
+		"requirements-optional.txt": kindOptional,
+		"/home/ci/requirements.txt": kindRuntime, // absolute Verzeichnisse zählen nicht
+		"../dev/requirements.txt":   kindRuntime,
+	} {
+		if got := pyKind(path); got != want {
+			t.Errorf("pyKind(%q) = %s, erwartet %s", path, got, want)
+		}
+	}
+}
+
+func TestNPMKinds(t *testing.T) {
+	p := packageJSON{
+		Dependencies:         map[string]string{"a": "1.0.0", "shared": "1.0.0"},
+		DevDependencies:      map[string]string{"jest": "29.0.0", "shared": "1.0.0"},
+		OptionalDependencies: map[string]string{"fsevents": "2.3.3"},
+	}
+	c := &common{}
+	c.parseKinds()
+	if got, want := p.kinds(c.included), map[string]string{"a": kindRuntime, "shared": kindRuntime}; !maps.Equal(got, want) {
+		t.Errorf("Default: %v, erwartet %v", got, want)
+	}
+	c.includeKind = "dev,optional"
+	c.parseKinds()
+	want := map[string]string{"a": kindRuntime, "shared": kindRuntime, "jest": kindDev, "fsevents": kindOptional}
+	if got := p.kinds(c.included); !maps.Equal(got, want) {
+		t.Errorf("--include-kind dev,optional: %v, erwartet %v", got, want)
+	}
+}
@@ -0,0 +1,344 @@
+// libyears – Libyears für Go-Module, npm-Pakete und requirements.txt
+//
+// Usage:
+//
+//	go run . go  [flags] /path/to/moduleRoot
+//	go run . npm [flags] path/to/package.json
+//	go run . py  [flags] requirements.txt [...]
+//	go run . batch [--projects projects.yml] [--jobs N]   (s. batch.go)
+//
+// Die Flags beschreibt --help, die Details stehen bei ihrer Umsetzung (je
+// Feature eine Datei, z. B. asof.go, watch.go, exceptions.go; die Flags aller
+// Tools in shared/). Exit-Codes: 0 ok, 1 Analysefehler, 2 Aufruffehler, 3
+// Teilergebnis (s. shared/exitcode).
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"baa_fs25/shared/anon"
+	"baa_fs25/shared/exitcode"
+	"baa_fs25/shared/httpcache"
+	"baa_fs25/shared/i18n"
+	"baa_fs25/shared/logging"
+	"baa_fs25/shared/netcfg"
+	"baa_fs25/shared/provenance"
+	"baa_fs25/shared/report"
+	"baa_fs25/shared/schema"
+)
+
+var client = &http.Client{Timeout: 15 * time.Second}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	cmd, args := os.Args[1], os.Args[2:]
+	switch cmd {
+	case "go":
+		runGo(args)
+	case "npm":
+		runNPM(args)
+	case "py", "python":
+		runPy(args)
+	case "batch":
+		runBatch(args)
+	default:
+		usage()
+	}
+	exitcode.Finish()
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s <go|npm|py|batch> [flags] <args>\n", os.Args[0])
+	os.Exit(exitcode.Usage)
+}
+
+// common hält die Flags, die alle Subcommands teilen.
+type common struct {
+	eco       string // Subcommand: go | npm | py
+	log       *logging.Options
+	net       *netcfg.Options
+	anon      *anon.Options
+	schema    *schema.Options
+	lang      *i18n.Options
+	exit      *exitcode.Options
+	out       string
+	badge     string
+	threshold float64
+	githubPR  int
+	base      string
+	groupBy   string
+	eol       bool
+	withRT    bool          // --runtime (npm, py)
+	conflicts []pinConflict // py: widersprüchliche Pins mehrerer Dateien
+	dupes     bool          // --duplicates (npm)
+	dups      []duplicate   // Pakete in mehreren Versionen (duplicates.go)
+	runtime   []runtimeLag  // Lag der Sprach-Runtime (runtime.go)
+	skips     []skipped
+	// watchEvery steuert --watch (s. watch.go), webhook und notifyAt die
+	// Benachrichtigung (s. notify.go); last ist das Ergebnis des letzten
+	// Laufs für das Delta.
+	watchEvery time.Duration
+	webhook    string
+	notifyAt   float64
+	last       *result
+	fixScript  string
+	npmRoot    string // Workspace-Name des Root-package.json (fix.go)
+	// format ist --format; stream nimmt bei jsonl die Zeilen auf, stdout
+	// ist das echte stdout, wenn die Tabelle unterdrückt wird (stream.go).
+	format string
+	stream io.Writer
+	stdout *os.File
+	// cacheDir ist --cache-dir; batch reicht ein gemeinsames Verzeichnis an
+	// alle Projekte weiter.
+	cacheDir string
+	// excludeKind ist --exclude-kind, excluded die geprüften Arten;
+	// includeKind ist --include-kind, included die zusätzlich ausgewerteten
+	// npm-Listen (kinds.go).
+	excludeKind string
+	excluded    map[string]bool
+	includeKind string
+	included    map[string]bool
+	// exceptionsFile und exceptionsOut sind --exceptions und
+	// --exceptions-out, exceptions die geladene Liste (exceptions.go).
+	exceptionsFile string
+	exceptionsOut  string
+	exceptions     []exception
+	// sortBy, top und minLag sind --sort, --top und --min-lag; rows hält
+	// die Tabellenzeilen bis zur Ausgabe zurück (order.go).
+	sortBy string
+	top    int
+	minLag float64
+	rows   []tableRow
+	tui    bool // --tui (tui.go)
+}
+
+// dep ist eine ausgewertete Dependency.
+type dep struct {
+	Package string  `json:"package"`
+	Purl    string  `json:"purl,omitempty"` // Package-URL der aktuellen Version
+	Current string  `json:"current"`
+	Latest  string  `json:"latest"`
+	Lag     float64 `json:"lag_years"`
+	// Workspace ist bei npm-Workspaces der Name des Pakets, das die
+	// Dependency deklariert.
+	Workspace string `json:"workspace,omitempty"`
+	// Overridden markiert Versionen aus npm-overrides/-resolutions bzw.
+	// einer replace-Direktive in go.mod.
+	Overridden bool `json:"overridden,omitempty"`
+	// Files sind bei mehreren requirements-Dateien die Dateien, die diesen
+	// Pin enthalten.
+	Files []string `json:"files,omitempty"`
+	// LatestInRange ist die neueste Version innerhalb der deklarierten Range
+	// (npm), LagInRange der Lag bis dorthin.
+	LatestInRange string   `json:"latest_in_range,omitempty"`
+	LagInRange    *float64 `json:"lag_in_range_years,omitempty"`
+	// EOL und Deprecated setzt --eol: abgekündigter Release-Zyklus laut
+	// endoflife.date bzw. Deprecation-/Yank-Hinweis der Registry.
+	EOL        string `json:"eol,omitempty"`
+	Deprecated string `json:"deprecated,omitempty"`
+	// Kind ist bei npm und Python runtime, dev oder optional.
+	Kind string `json:"kind,omitempty"`
+	// Excepted markiert Dependencies mit gültiger Ausnahme (--exceptions);
+	// sie zählen nicht in die Gesamtzahl.
+	Excepted bool `json:"excepted,omitempty"`
+	// released ist das Release-Datum der aktuellen Version (--sort age).
+	released time.Time
+}
+
+// result ist das JSON-Dokument, das --out schreibt.
+type result struct {
+	Schema     string            `json:"schema"` // s. shared/schema
+	Eco        string            `json:"eco"`
+	Source     []string          `json:"source"`
+	Deps       []dep             `json:"deps"`
+	Summary    lagStats          `json:"summary"`
+	Workspaces []wsSummary       `json:"workspaces,omitempty"`
+	Conflicts  []pinConflict     `json:"conflicts,omitempty"`
+	Duplicates []duplicate       `json:"duplicates,omitempty"`
+	Exceptions []exceptionStatus `json:"exceptions,omitempty"`
+	Groups     []groupSummary    `json:"groups,omitempty"`
+	Kinds      []kindSummary     `json:"kinds,omitempty"`
+	Runtime    []runtimeLag      `json:"runtime,omitempty"`
+	// Skipped sind die nicht ausgewerteten Dependencies mit Grund; Coverage
+	// ist der ausgewertete Anteil.
+	Skipped  []skipped `json:"skipped,omitempty"`
+	Coverage float64   `json:"coverage"`
+	// AsOf ist der Stichtag bei --as-of.
+	AsOf *time.Time `json:"as_of,omitempty"`
+
+	// Provenance: Version, Flags, Zeitpunkt, Endpunkte und Cache-Quote des
+	// Laufs (s. shared/provenance).
+	Provenance *provenance.Info `json:"provenance,omitempty"`
+}
+
+// wsSummary ist die Zusammenfassung eines npm-Workspaces.
+type wsSummary struct {
+	Name     string  `json:"name"`
+	Count    int     `json:"count"`
+	TotalLag float64 `json:"total_lag_years"`
+}
+
+// newFlagSet legt das FlagSet eines Subcommands inkl. der gemeinsamen Flags an.
+func newFlagSet(name string) (*flag.FlagSet, *common) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	c := &common{eco: name, log: logging.Register(fs), net: netcfg.Register(fs), anon: anon.Register(fs), schema: schema.Register(fs), lang: i18n.Register(fs), exit: exitcode.Register(fs)}
+	fs.StringVar(&c.out, "out", "", "Ergebnisse zusätzlich als JSON schreiben (\"-\" = stdout)")
+	fs.StringVar(&c.format, "format", "json", "Format von --out: json | jsonl (eine Zeile je Dependency, sofort geschrieben; ohne --out nach stdout)")
+	fs.StringVar(&c.badge, "badge", "", "shields.io-Endpoint-JSON mit dem Gesamt-Lag schreiben")
+	fs.IntVar(&c.githubPR, "github-pr", 0, "Ergebnis als Kommentar an diesen PR posten ($GITHUB_TOKEN, $GITHUB_REPOSITORY)")
+	fs.StringVar(&c.base, "base", "", "--out-JSON des Basis-Branches für den Vergleich im PR-Kommentar")
+	fs.Float64Var(&c.threshold, "threshold", 1, "Lag in Jahren, ab dem eine Dependency als veraltet gezählt wird")
+	fs.BoolVar(&c.eol, "eol", false, "EOL-Status (endoflife.date) und Deprecation der Pakete ergänzen")
+	fs.DurationVar(&c.watchEvery, "watch", 0, "Analyse in diesem Abstand wiederholen (z. B. 24h) und nur das Delta ausgeben")
+	fs.StringVar(&c.webhook, "notify-webhook", "", "Zusammenfassung bzw. Delta (--watch) an diesen Slack-/Teams-Webhook posten")
+	fs.Float64Var(&c.notifyAt, "notify-threshold", 0, "nur posten, wenn der Gesamt-Lag diese Jahre übersteigt (0 = immer)")
+	fs.StringVar(&c.fixScript, "fix-script", "", "Shell-Skript mit Upgrade-Befehlen für die Dependencies über --threshold schreiben")
+	fs.StringVar(&c.cacheDir, "cache-dir", "", "Registry-Antworten in diesem Verzeichnis cachen (per ETag revalidiert)")
+	fs.StringVar(&c.excludeKind, "exclude-kind", "", "diese Arten nicht in die Gesamtzahl einrechnen, z. B. dev,optional (npm, py)")
+	fs.StringVar(&c.includeKind, "include-kind", "", "npm: auch devDependencies bzw. optionalDependencies auswerten, z. B. dev,optional (Default: nur dependencies)")
+	fs.StringVar(&c.exceptionsFile, "exceptions", "", "Ausnahmeliste (YAML/JSON: package, version, reason, approved_by, expires); gültige Ausnahmen zählen nicht in den Lag")
+	fs.StringVar(&c.exceptionsOut, "exceptions-out", "", "Ausnahmen mit Zustand als CycloneDX-Dokument im VEX-Stil schreiben")
+	fs.Func("as-of", "Lag zum Stichtag JJJJ-MM-TT berechnen: neueste Version = letztes Release davor", parseAsOf)
+	fs.BoolVar(&c.dupes, "duplicates", false, "npm: Pakete melden, die laut package-lock.json in mehreren Versionen installiert sind, mit Lag je Version")
+	fs.BoolVar(&c.withRT, "runtime", false, "npm/py: Lag von engines.node bzw. python_requires zum neuesten (LTS-)Release ergänzen")
+	fs.StringVar(&c.sortBy, "sort", "", "Tabelle sortieren: lag (größter zuerst), name oder age (älteste verwendete Version zuerst)")
+	fs.IntVar(&c.top, "top", 0, "nur die ersten N Zeilen der Tabelle ausgeben (0 = alle)")
+	fs.Float64Var(&c.minLag, "min-lag", 0, "nur Dependencies mit mindestens diesem Lag in Jahren in der Tabelle ausgeben")
+	fs.BoolVar(&c.tui, "tui", false, "nach der Auswertung ein interaktives Dashboard öffnen: Dependencies nach Lag, Enter zeigt die Release-Historie")
+	fs.StringVar(&c.groupBy, "group-by", "", "Zwischensummen bilden: scope (npm-@scope, Go-Host/Org, Python-Namespace)")
+	return fs, c
+}
+
+// parseFlags parst die Argumente und installiert den Logger.
+func parseFlags(fs *flag.FlagSet, c *common, args []string) {
+	_ = fs.Parse(args) // ExitOnError
+	provenance.Start("libyears "+c.eco, fs)
+	if err := c.log.Setup(); err != nil {
+		logging.Fatal("Logging-Setup fehlgeschlagen", "err", err)
+	}
+	if err := c.net.Setup(); err != nil {
+		logging.Fatal("Netz-Setup fehlgeschlagen", "err", err)
+	}
+	if err := c.anon.Setup(); err != nil {
+		logging.Usage("ungültiges --anonymize", "err", err)
+	}
+	if err := c.schema.Setup(); err != nil {
+		logging.Usage("ungültiges --schema-version", "err", err)
+	}
+	if err := c.lang.Setup(); err != nil {
+		logging.Usage("ungültiges --lang", "err", err)
+	}
+	if err := c.exit.Setup(); err != nil {
+		logging.Usage("ungültiges --max-skipped", "err", err)
+	}
+	if c.groupBy != "" && c.groupBy != "scope" {
+		logging.Usage("ungültiges --group-by (erlaubt: scope)", "value", c.groupBy)
+	}
+	if c.tui && (c.watchEvery > 0 || c.out == "-") {
+		logging.Usage("--tui geht nicht mit --watch oder --out -")
+	}
+	c.parseKinds()
+	c.checkOrder()
+	c.loadExceptions()
+	c.setupStream()
+	if c.cacheDir != "" {
+		if err := httpcache.Install(client, c.cacheDir); err != nil {
+			logging.Fatal("Cache-Verzeichnis nicht nutzbar", "dir", c.cacheDir, "err", err)
+		}
+	}
+}
+
+// writeResult schreibt die Ergebnisse als JSON, falls --out gesetzt ist, das
+// Badge, falls --badge gesetzt ist, das Fix-Skript bei --fix-script und den
+// PR-Kommentar bei --github-pr.
+// Das Ergebnis bleibt für --watch in c.last.
+func (c *common) writeResult(eco string, source []string, deps []dep) {
+	c.writeBadge(deps)
+	c.writeFixScript(eco, source, deps)
+	c.writeExceptions(deps)
+	res := result{Schema: schema.ID("libyears-result"), Eco: eco, Source: source, Deps: deps, Conflicts: c.conflicts, Duplicates: c.dups, Skipped: c.skips, Runtime: c.runtime, AsOf: asOfResult()}
+	if n := len(deps) + len(c.skips); n > 0 {
+		res.Coverage = float64(len(deps)) / float64(n)
+	}
+	exitcode.Skipped(len(c.skips), len(deps)+len(c.skips))
+	if res.Deps == nil {
+		res.Deps = []dep{}
+	}
+	// Bei Workspaces zählt jedes Paket@Version für die Summe nur einmal;
+	// ausgeschlossene Arten bleiben in Deps, zählen aber nicht mit.
+	res.Summary = computeStats(dedupeDeps(c.counted(deps)), c.threshold)
+	res.Kinds = c.kindSummaries(deps)
+	res.Exceptions = c.exceptionStates(deps)
+	for _, d := range c.counted(deps) {
+		if d.Workspace == "" {
+			continue
+		}
+		if n := len(res.Workspaces); n == 0 || res.Workspaces[n-1].Name != d.Workspace {
+			res.Workspaces = append(res.Workspaces, wsSummary{Name: d.Workspace})
+		}
+		ws := &res.Workspaces[len(res.Workspaces)-1]
+		ws.Count++
+		ws.TotalLag += d.Lag
+	}
+	if c.groupBy != "" {
+		res.Groups = groupDeps(eco, deps)
+	}
+	c.last = &res
+	if c.out == "" && c.githubPR == 0 {
+		return
+	}
+	c.postPRComment(res)
+	if c.out == "" {
+		return
+	}
+	res.Provenance = provenance.Get()
+	if c.format == "jsonl" {
+		c.finishStream(anonymized(res))
+		return
+	}
+	if err := report.WriteJSON(c.out, anonymized(res)); err != nil {
+		logging.Fatal("JSON-Ausgabe fehlgeschlagen", "file", c.out, "err", err)
+	}
+}
+
+func sumLag(deps []dep) float64 {
+	var t float64
+	for _, d := range deps {
+		t += d.Lag
+	}
+	return t
+}
+
+// dedupeDeps entfernt Workspace-Dependencies, die als Paket@Version bereits
+// in einem anderen Workspace gezählt wurden. Ohne Workspaces bleibt die
+// Liste unverändert.
+func dedupeDeps(deps []dep) []dep {
+	seen := map[string]bool{}
+	var out []dep
+	for _, d := range deps {
+		if d.Workspace != "" {
+			key := d.Package + "@" + d.Current
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+// overrideMark kennzeichnet überschriebene Versionen in der Tabelle.
+func overrideMark(overridden bool) string {
+	if overridden {
+		return "  *override"
+	}
+	return ""
+}
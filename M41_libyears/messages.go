@@ -0,0 +1,54 @@
+// messages.go – Kataloge für --lang: die Zusammenfassungen sind deutsch,
+// einige Tabellenköpfe und Meldungen englisch geschrieben
+package main
+
+import "baa_fs25/shared/i18n"
+
+func init() {
+	i18n.Add(i18n.EN, map[string]string{
+		"Keine auswertbaren Dependencies gefunden.":                                  "No dependencies could be evaluated.",
+		"TOTAL Lag: %.2f  |  Ø %.2f  |  %d/%d direkte Dependencies ausgewertet\n":    "TOTAL Lag: %.2f  |  mean %.2f  |  %d/%d direct dependencies evaluated\n",
+		"\nTOTAL Lag: %.2f  |  Ø %.2f\n":                                             "\nTOTAL Lag: %.2f  |  mean %.2f\n",
+		"\nTOTAL Lag (%d Workspaces, %d eindeutige Pakete): %.2f  |  Ø %.2f\n":       "\nTOTAL Lag (%d workspaces, %d unique packages): %.2f  |  mean %.2f\n",
+		"Median %.2f  |  P90 %.2f  |  Max %.2f  |  %d/%d über %.1f Jahr(en)\n":       "Median %.2f  |  P90 %.2f  |  Max %.2f  |  %d/%d above %.1f year(s)\n",
+		"Innerhalb der Range erreichbar: %.2f von %.2f (%d Pakete)\n":                "Reachable within the declared range: %.2f of %.2f (%d packages)\n",
+		"Ausnahmen: %d aktiv, %d abgelaufen, %d ohne Treffer\n":                      "Exceptions: %d active, %d expired, %d unused\n",
+		"  abgelaufen: %-25s seit %s (genehmigt von %s)\n":                           "  expired: %-25s since %s (approved by %s)\n",
+		"\nDuplikate: keine Pakete in mehreren Versionen installiert":                "\nDuplicates: no package installed in several versions",
+		"\nDuplikate (%d Pakete in mehreren Versionen, %d überzählige Versionen):\n": "\nDuplicates (%d packages in several versions, %d extra versions):\n",
+		"Runtime %-22s → %-6s Lag %.2f  |  %d Versionen zurück\n":                    "Runtime %-22s → %-6s Lag %.2f  |  %d versions behind\n",
+		"%-25s %-4s  Fehler: %s\n":                                                   "%-25s %-4s  error: %s\n",
+		"\nTOTAL Lag über %d Projekte: %.2f":                                         "\nTOTAL Lag across %d projects: %.2f",
+		"  |  %d fehlgeschlagen":                                                     "  |  %d failed",
+		"\nKonflikte (%d Pakete mit unterschiedlichen Pins):\n":                      "\nConflicts (%d packages with differing pins):\n",
+		"Nach Art: %s\n":         "By kind: %s\n",
+		"ausgeschlossen":         "excluded",
+		"%d übersprungen (%s)\n": "%d skipped (%s)\n",
+		"… %d weitere Dependencies ausgeblendet (--top/--min-lag)\n": "… %d more dependencies hidden (--top/--min-lag)\n",
+		"Projekt":                 "Project",
+		"Pakete":                  "Packages",
+		"Schlechteste Dependency": "Worst dependency",
+		"Abdeck.":                 "Cover.",
+		"libyears %s – %d Dependencies, Lag gesamt %.2f Jahre (Ø %.2f, max %.2f), sortiert nach %s": "libyears %s – %d dependencies, total lag %.2f years (mean %.2f, max %.2f), sorted by %s",
+		"↑/↓ bewegen  Enter Release-Historie  s sortieren (lag, name, age)  q beenden":              "↑/↓ move  enter release history  s sort (lag, name, age)  q quit",
+		"%s – verwendet %s, neueste %s, Lag %.2f Jahre":                                             "%s – using %s, latest %s, lag %.2f years",
+		"Release-Historie wird geladen …":                                                           "Loading release history …",
+		"Release-Historie nicht verfügbar: %v":                                                      "Release history unavailable: %v",
+		"%d Releases, %d neuer als die verwendete Version":                                          "%d releases, %d newer than the version in use",
+		"%d Releases":            "%d releases",
+		"Datum":                  "Date",
+		"Lag danach":             "Lag after",
+		"← verwendet":            "← in use",
+		"← neueste":              "← latest",
+		"← neueste in der Range": "← latest in range",
+		"↑/↓ bewegen  Esc zurück  q beenden": "↑/↓ move  esc back  q quit",
+	})
+	i18n.Add(i18n.DE, map[string]string{
+		"No valid packages processed.":                          "Keine gültigen Pakete ausgewertet.",
+		"No dependencies with exact or trimmed versions found.": "Keine Dependencies mit exakter oder gekürzter Version gefunden.",
+		"Package": "Paket",
+		"Current": "Aktuell",
+		"Latest":  "Neueste",
+		"File":    "Datei",
+	})
+}
@@ -0,0 +1,80 @@
+// notify.go – --notify-webhook: Zusammenfassung an Slack/Teams, optional nur
+// bei Überschreiten von --notify-threshold (Gesamt-Lag in Jahren)
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"baa_fs25/shared/report"
+)
+
+// notifyTop ist die Zahl der größten Verursacher in der Nachricht.
+const notifyTop = 5
+
+// notify postet die Zusammenfassung von c.last, falls --notify-webhook
+// gesetzt ist und der Gesamt-Lag --notify-threshold übersteigt. prev ist der
+// Vergleichslauf (vorige --watch-Runde bzw. --base), optional.
+func (c *common) notify(prev *result) {
+	if c.webhook == "" || c.last == nil {
+		return
+	}
+	total := c.last.Summary.TotalLag
+	if c.notifyAt > 0 && total <= c.notifyAt {
+		slog.Info("Gesamt-Lag unter Schwelle, keine Benachrichtigung",
+			"total_lag", fmt.Sprintf("%.2f", total), "threshold", c.notifyAt)
+		return
+	}
+	if err := (report.Webhook{URL: c.webhook}).Post(notifyText(prev, *c.last, c.notifyAt)); err != nil {
+		slog.Error("Webhook fehlgeschlagen", "err", err)
+		return
+	}
+	slog.Info("Benachrichtigung gesendet", "total_lag", fmt.Sprintf("%.2f", total))
+}
+
+func notifyText(prev *result, cur result, threshold float64) string {
+	var b strings.Builder
+	s := cur.Summary
+	fmt.Fprintf(&b, "libyears (%s): Gesamt-Lag %.2f Jahre", cur.Eco, s.TotalLag)
+	if threshold > 0 {
+		fmt.Fprintf(&b, " – über der Schwelle von %.1f", threshold)
+	}
+	fmt.Fprintf(&b, "\n%d Dependencies, %d über %.1f Jahr(en), Median %.2f, P90 %.2f\n",
+		s.Count, s.Above, s.Threshold, s.MedianLag, s.P90Lag)
+	if prev != nil {
+		fmt.Fprintf(&b, "Δ zum letzten Lauf: %s (%.2f → %.2f)\n",
+			report.Delta(prev.Summary.TotalLag, s.TotalLag, 2), prev.Summary.TotalLag, s.TotalLag)
+	}
+
+	top := append([]dep(nil), dedupeDeps(cur.Deps)...)
+	sort.SliceStable(top, func(i, j int) bool { return top[i].Lag > top[j].Lag })
+	if len(top) > notifyTop {
+		top = top[:notifyTop]
+	}
+	if len(top) > 0 {
+		b.WriteString("Größte Verursacher:\n")
+		for _, d := range top {
+			fmt.Fprintf(&b, "  • %s %s → %s (%.2f)\n", d.Package, d.Current, d.Latest, d.Lag)
+		}
+	}
+	if prev != nil {
+		d := diffRuns(*prev, cur, s.Threshold)
+		if len(d.NewOutdated) > 0 {
+			fmt.Fprintf(&b, "Neu veraltet: %s\n", depNames(d.NewOutdated))
+		}
+		if len(d.Resolved) > 0 {
+			fmt.Fprintf(&b, "Behoben: %s\n", depNames(d.Resolved))
+		}
+	}
+	return b.String()
+}
+
+func depNames(deps []dep) string {
+	names := make([]string, len(deps))
+	for i, d := range deps {
+		names[i] = d.Package
+	}
+	return strings.Join(names, ", ")
+}
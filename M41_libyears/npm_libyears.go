@@ -1,111 +1,265 @@
-// libyears_npm_trim.go – npm-Libyears, Caret/Tilde werden entfernt
+// npm_libyears.go – npm-Libyears, Caret/Tilde werden entfernt; bei
+// Workspaces gilt die installierte Version aus package-lock.json
 package main
 
 import (
-	"encoding/json"
 	"fmt"
-	"log"
-	"net/http"
-	"net/url"
-	"os"
+	"math"
+	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
-)
-
-type npmResp struct {
-	Time     map[string]string `json:"time"`
-	DistTags map[string]string `json:"dist-tags"`
-}
 
-var (
-	rxExact = regexp.MustCompile(`^\d+\.\d+\.\d+(-[\w\.]+)?$`)
-	client  = &http.Client{Timeout: 15 * time.Second}
+	"baa_fs25/shared/i18n"
+	"baa_fs25/shared/logging"
+	"baa_fs25/shared/purl"
+	"golang.org/x/mod/semver"
 )
 
-func main() {
-	if len(os.Args) != 2 {
-		log.Fatalf("Usage: %s path/to/package.json", os.Args[0])
-	}
-	pkgJSON := os.Args[1]
+var rxExact = regexp.MustCompile(`^\d+\.\d+\.\d+(-[\w\.]+)?$`)
 
-	var pkg struct {
-		Dependencies map[string]string `json:"dependencies"`
-	}
-	j, _ := os.ReadFile(pkgJSON)
-	if err := json.Unmarshal(j, &pkg); err != nil {
-		log.Fatal(err)
+func runNPM(args []string) {
+	fs, c := newFlagSet("npm")
+	parseFlags(fs, c, args)
+	if fs.NArg() != 1 {
+		logging.Usage("Usage: go run . npm [flags] path/to/package.json")
 	}
+	pkgJSON := fs.Arg(0)
 
-	fmt.Printf("%-25s %-10s %-10s %8s\n", "Package", "Current", "Latest", "Lag(yr)")
-	total, count := 0.0, 0
+	c.watch(func() error {
+		pkg, err := readPackageJSON(pkgJSON)
+		if err != nil {
+			return fmt.Errorf("package.json nicht lesbar: %w", err)
+		}
 
-	for name, verRaw := range pkg.Dependencies {
-		// 1. Caret (^) oder Tilde (~) einfach abschneiden
-		ver := strings.TrimLeft(verRaw, "^~")
+		if c.withRT {
+			c.runtime = npmRuntime(pkg)
+			defer c.printRuntime()
+		}
+		root := filepath.Dir(pkgJSON)
+		pinned := pkg.pinned() // npm wertet overrides nur im Root aus
+		wss := findWorkspaces(root, pkg.workspacePatterns())
+		if c.dupes {
+			c.dups = c.npmDuplicates(readNPMLock(root))
+			defer printDuplicates(c.dups)
+		}
+		if len(wss) == 0 {
+			deps := c.npmTable("", pkg, pinned, trimmedVersion)
+			c.writeResult("npm", []string{pkgJSON}, deps)
+			if counted := c.counted(deps); len(counted) > 0 {
+				total := sumLag(counted)
+				i18n.Printf("\nTOTAL Lag: %.2f  |  Ø %.2f\n", total, total/float64(len(counted)))
+				c.printStats(deps)
+			} else {
+				i18n.Println("No dependencies with exact or trimmed versions found.")
+				c.printSkips()
+			}
+			return nil
+		}
 
-		// 2. nur exakte Major.Minor.Patch akzeptieren
-		if !rxExact.MatchString(ver) {
-			continue // überspringe Ranges wie ">=" usw.
+		// Workspaces: Versionen kommen aus dem Root-Lockfile, Abhängigkeiten
+		// zwischen den Workspaces selbst werden nicht gezählt.
+		lock := readNPMLock(root)
+		local := map[string]bool{}
+		for _, ws := range wss {
+			local[ws.Pkg.Name] = true
+		}
+		rootName := pkg.Name
+		if rootName == "" {
+			rootName = "(root)"
 		}
+		c.npmRoot = rootName
+		all := append([]workspace{{Name: rootName, Dir: ".", Pkg: pkg}}, wss...)
 
-		latest, lag, err := libyear(name, ver)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "[SKIP] %-20s %v\n", name, err)
+		sources := []string{pkgJSON}
+		var deps []dep
+		for _, ws := range all {
+			if ws.Dir != "." {
+				sources = append(sources, filepath.ToSlash(filepath.Join(root, ws.Dir, "package.json")))
+			}
+			fmt.Printf("\n== %s (%s) ==\n", ws.Name, ws.Dir)
+			wsDeps := c.npmTable(ws.Name, ws.Pkg, pinned, func(name, raw string) (string, bool) {
+				if local[name] {
+					return "", false
+				}
+				if v, ok := lock.installed(ws.Dir, name); ok {
+					return v, true
+				}
+				return trimmedVersion(name, raw)
+			})
+			if counted := c.counted(wsDeps); len(counted) > 0 {
+				fmt.Printf("Lag: %.2f  |  Ø %.2f\n", sumLag(counted), sumLag(counted)/float64(len(counted)))
+			}
+			deps = append(deps, wsDeps...)
+		}
+		c.writeResult("npm", sources, deps)
+
+		uniq := dedupeDeps(c.counted(deps))
+		if len(uniq) > 0 {
+			total := sumLag(uniq)
+			i18n.Printf("\nTOTAL Lag (%d Workspaces, %d eindeutige Pakete): %.2f  |  Ø %.2f\n",
+				len(all), len(uniq), total, total/float64(len(uniq)))
+			c.printStats(deps)
+		} else {
+			i18n.Println("No dependencies with exact or trimmed versions found.")
+			c.printSkips()
+		}
+		return nil
+	})
+}
+
+// trimmedVersion schneidet Caret (^) oder Tilde (~) ab und akzeptiert nur
+// exakte Major.Minor.Patch; Ranges wie ">=" werden übersprungen.
+func trimmedVersion(_, raw string) (string, bool) {
+	ver := strings.TrimLeft(raw, "^~")
+	return ver, rxExact.MatchString(ver)
+}
+
+// npmTable wertet die dependencies von pkg aus, mit --include-kind auch
+// devDependencies und optionalDependencies, und gibt sie als Tabelle aus.
+// Einträge aus pinned ersetzen die deklarierte Angabe, resolve bestimmt
+// daraus die verwendete Version oder lehnt die Dependency ab.
+func (c *common) npmTable(ws string, pkg packageJSON, pinned map[string]string, resolve func(name, raw string) (string, bool)) []dep {
+	kinds := pkg.kinds(c.included)
+	names := make([]string, 0, len(kinds))
+	for name := range kinds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Printf("%-25s %-10s %-10s %8s %-10s %8s\n", i18n.T("Package"), i18n.T("Current"), i18n.T("Latest"), "Lag(yr)", "InRange", "Lag(rng)")
+	var out []dep
+	for _, name := range names {
+		raw, overridden := pkg.spec(name, kinds[name]), false
+		if p, ok := pinned[name]; ok {
+			raw, overridden = p, true
+		}
+		ver, ok := resolve(name, raw)
+		if !ok {
+			if ver != "" { // "" = Workspace-Paket, wird bewusst nicht gezählt
+				c.skip(skipped{Package: name, Version: raw, Reason: npmSpecReason(raw), Workspace: ws})
+			}
+			continue
+		}
+		h := npmLibyearCached(name, ver, raw)
+		if h.err != nil {
+			c.skip(skipped{Package: name, Version: ver, Reason: reasonOf(h.err), Detail: h.err.Error(), Workspace: ws})
 			continue
 		}
-		fmt.Printf("%-25s %-10s %-10s %8.2f\n", name, ver, latest, lag)
-		total += lag
-		count++
+		inRange, lagInRange := "-", "     n/a"
+		if h.lagInRange != nil {
+			inRange, lagInRange = h.inRange, fmt.Sprintf("%8.2f", *h.lagInRange)
+		}
+		d := dep{
+			Package: name, Purl: purl.For("npm", name, ver), Current: ver, Latest: h.latest, Lag: h.lag, Workspace: ws, Overridden: overridden,
+			LatestInRange: h.inRange, LagInRange: h.lagInRange, Kind: kinds[name],
+		}
+		c.enrich(&d, h.deprecated)
+		d.released = h.released
+		c.printRow(d, "%-25s %-10s %-10s %8.2f %-10s %s%s%s%s%s\n", name, ver, h.latest, h.lag, inRange, lagInRange, overrideMark(overridden), eolMark(d), kindMark(d), exceptionMark(d))
+		out = append(out, d)
+		c.emitDep(d)
 	}
+	c.flushRows()
+	return out
+}
+
+// npmHit ist das Ergebnis einer Registry-Abfrage: Lag zur absolut neuesten
+// Version und zur neuesten Version innerhalb der deklarierten Range (nil,
+// wenn die Range nicht auswertbar ist).
+type npmHit struct {
+	latest     string
+	lag        float64
+	inRange    string
+	lagInRange *float64
+	deprecated string
+	released   time.Time // Release der verwendeten Version
+	err        error
+}
+
+var npmCache = map[string]npmHit{}
 
-	if count > 0 {
-		fmt.Printf("\nTOTAL Lag: %.2f  |  Ø %.2f\n", total, total/float64(count))
-	} else {
-		fmt.Println("No dependencies with exact or trimmed versions found.")
+// npmLibyearCached vermeidet doppelte Registry-Abfragen für Pakete, die in
+// mehreren Workspaces vorkommen.
+func npmLibyearCached(pkg, ver, raw string) npmHit {
+	key := pkg + "@" + ver + " " + raw
+	if h, ok := npmCache[key]; ok {
+		return h
 	}
+	h := npmLibyear(pkg, ver, raw)
+	npmCache[key] = h
+	return h
 }
 
-func libyear(pkg, usedVer string) (latestVer string, lag float64, err error) {
-	resp, err := client.Get("https://registry.npmjs.org/" + url.PathEscape(pkg))
-	if err != nil {
+func npmLibyear(pkg, usedVer, raw string) (h npmHit) {
+	var times map[string]time.Time
+	if times, h.err = releaseTimes(npmRegistry, pkg); h.err != nil {
 		return
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != 200 {
-		err = fmt.Errorf("HTTP %d", resp.StatusCode)
+	h.latest, h.lag, h.err = npmLag(pkg, usedVer, times)
+	if h.err != nil {
 		return
 	}
+	h.inRange, h.lagInRange = npmInRange(usedVer, raw, times)
+	h.deprecated = deprecation(npmRegistry, pkg, usedVer)
+	h.released = times[usedVer]
+	return
+}
 
-	var js npmResp
-	if err = json.NewDecoder(resp.Body).Decode(&js); err != nil {
-		return
+// npmInRange sucht die höchste stabile Version, die raw erfüllt, und den Lag
+// von usedVer bis zu deren Release – das, was "npm update" erreichen kann.
+func npmInRange(usedVer, raw string, times map[string]time.Time) (string, *float64) {
+	match, ok := npmRange(raw)
+	usedTime, known := times[usedVer]
+	if !ok || !known {
+		return "", nil
 	}
+	best := usedVer
+	for ver, t := range times {
+		sv := "v" + ver
+		if !semver.IsValid(sv) || semver.Prerelease(sv) != "" || !match(ver) || !visible(t) {
+			continue
+		}
+		if semver.Compare(sv, "v"+best) > 0 {
+			best = ver
+		}
+	}
+	lag := math.Max(0, times[best].Sub(usedTime).Hours()/24/365.25)
+	return best, &lag
+}
 
-	usedTimeStr, ok := js.Time[usedVer]
+func npmLag(pkg, usedVer string, times map[string]time.Time) (latestVer string, lag float64, err error) {
+	usedTime, ok := times[usedVer]
 	if !ok {
-		err = fmt.Errorf("timestamp for %s@%s not found", pkg, usedVer)
+		err = skipErrorf(reasonNoDate, "timestamp for %s@%s not found", pkg, usedVer)
+		return
+	}
+	if !visible(usedTime) {
+		err = afterAsOf(pkg, usedVer)
 		return
 	}
 
-	var newest string
 	var newestTime time.Time
-	for ver, t := range js.Time {
-		if ver == "created" || ver == "modified" {
-			continue
-		}
-		tt, _ := time.Parse(time.RFC3339, t)
-		if tt.After(newestTime) {
-			newestTime, newest = tt, ver
+	for ver, t := range times {
+		if t.After(newestTime) && visible(t) {
+			newestTime, latestVer = t, ver
 		}
 	}
-	latestVer, latestTimeStr := newest, newestTime.Format(time.RFC3339)
+	lag = newestTime.Sub(usedTime).Hours() / 24 / 365.25
 	if lag < 0 {
 		lag = 0
 	}
-
-	usedTime, _ := time.Parse(time.RFC3339, usedTimeStr)
-	latestTime, _ := time.Parse(time.RFC3339, latestTimeStr)
-	lag = latestTime.Sub(usedTime).Hours() / 24 / 365.25
 	return
 }
+
+// npmSpecReason ordnet eine nicht auflösbare Angabe zu: Quellen außerhalb der
+// Registry (Pfade, Git, URLs) gelten als private, alles andere als Range.
+func npmSpecReason(raw string) string {
+	for _, p := range []string{"file:", "link:", "git", "github:", "http:", "https:"} {
+		if strings.HasPrefix(raw, p) {
+			return reasonPrivate
+		}
+	}
+	return reasonRange
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"baa_fs25/shared/registry"
+	"baa_fs25/shared/registry/registrytest"
+)
+
+func day(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+// setAsOf setzt den --as-of-Stichtag für die Dauer eines Tests.
+func setAsOf(t *testing.T, at time.Time) {
+	old := asOf
+	asOf = at
+	t.Cleanup(func() { asOf = old })
+}
+
+func TestNPMLag(t *testing.T) {
+	times := map[string]time.Time{
+		"1.0.0": day(2020, 1, 1),
+		"1.1.0": day(2021, 1, 1),
+		"2.0.0": day(2022, 1, 1),
+	}
+	for _, tc := range []struct {
+		name, used string
+		asOf       time.Time
+		latest     string
+		days       float64
+		reason     string
+	}{
+		{"veraltet", "1.0.0", time.Time{}, "2.0.0", 731, ""},
+		{"aktuell", "2.0.0", time.Time{}, "2.0.0", 0, ""},
+		{"--as-of", "1.0.0", day(2021, 6, 1), "1.1.0", 366, ""},
+		{"--as-of vor dem Release", "2.0.0", day(2021, 6, 1), "", 0, reasonAfterAsOf},
+		{"unbekannte Version", "9.9.9", time.Time{}, "", 0, reasonNoDate},
+	} {
+		setAsOf(t, tc.asOf)
+		latest, lag, err := npmLag("x", tc.used, times)
+		if tc.reason != "" {
+			if reasonOf(err) != tc.reason {
+				t.Errorf("%s: Fehler %v, erwartet Grund %s", tc.name, err, tc.reason)
+			}
+			continue
+		}
+		if err != nil || latest != tc.latest || lag != tc.days/365.25 {
+			t.Errorf("%s: %s/%.4f/%v, erwartet %s/%.4f", tc.name, latest, lag, err, tc.latest, tc.days/365.25)
+		}
+	}
+}
+
+func TestNPMInRange(t *testing.T) {
+	times := map[string]time.Time{
+		"1.2.0":      day(2020, 1, 1),
+		"1.3.0":      day(2020, 6, 1),
+		"1.2.5":      day(2020, 9, 1), // Backport nach 1.3.0
+		"1.4.0-rc.1": day(2020, 10, 1),
+		"2.0.0":      day(2021, 1, 1),
+	}
+	for _, tc := range []struct {
+		name, used, raw string
+		asOf            time.Time
+		best            string
+		days            float64 // -1: Range nicht auswertbar
+	}{
+		{"Caret", "1.2.0", "^1.2.0", time.Time{}, "1.3.0", 152},
+		{"Tilde", "1.2.0", "~1.2.0", time.Time{}, "1.2.5", 244},
+		{"älteres Release in Range", "1.2.5", "^1.2.5", time.Time{}, "1.3.0", 0}, // negativ, auf 0 begrenzt
+		{"--as-of", "1.2.0", "~1.2.0", day(2020, 7, 1), "1.2.0", 0},
+		{"zusammengesetzt", "1.2.0", "^1.2.0 || ^2.0.0", time.Time{}, "", -1},
+		{"unbekannte Version", "1.1.0", "^1.1.0", time.Time{}, "", -1},
+	} {
+		setAsOf(t, tc.asOf)
+		best, lag := npmInRange(tc.used, tc.raw, times)
+		if tc.days < 0 {
+			if best != "" || lag != nil {
+				t.Errorf("%s: %q/%v, erwartet keine Range", tc.name, best, lag)
+			}
+			continue
+		}
+		if best != tc.best || lag == nil || *lag != tc.days/365.25 {
+			t.Errorf("%s: %q/%v, erwartet %s/%.4f", tc.name, best, lag, tc.best, tc.days/365.25)
+		}
+	}
+}
+
+// failing ist eine Registry, die jede Abfrage mit einem HTTP-Status ablehnt.
+type failing int
+
+func (f failing) ReleaseTime(string, string) (time.Time, error) { return time.Time{}, f.err() }
+func (f failing) Versions(string) ([]string, error)             { return nil, f.err() }
+func (f failing) err() error                                    { return &registry.StatusError{API: "test", Code: int(f)} }
+
+func TestNPMLibyear(t *testing.T) {
+	old := npmRegistry
+	t.Cleanup(func() { npmRegistry = old })
+	setAsOf(t, time.Time{})
+
+	npmRegistry = registrytest.Static{"a": {"1.0.0": day(2020, 1, 1), "1.0.1": day(2020, 2, 1), "2.0.0": day(2021, 1, 1)}}
+	h := npmLibyear("a", "1.0.0", "^1.0.0")
+	if h.err != nil || h.latest != "2.0.0" || h.lag != 366/365.25 || h.inRange != "1.0.1" || !h.released.Equal(day(2020, 1, 1)) {
+		t.Errorf("a: %+v", h)
+	}
+	if h := npmLibyear("fehlt", "1.0.0", "1.0.0"); reasonOf(h.err) != reasonNoDate {
+		t.Errorf("unbekanntes Paket: %v, erwartet %s", h.err, reasonNoDate)
+	}
+	for code, want := range map[int]string{404: reasonNotFound, 403: reasonPrivate, 500: reasonError} {
+		npmRegistry = failing(code)
+		if h := npmLibyear("a", "1.0.0", "1.0.0"); reasonOf(h.err) != want {
+			t.Errorf("HTTP %d: %v, erwartet %s", code, h.err, want)
+		}
+	}
+}
+
+func TestTrimmedVersion(t *testing.T) {
+	for raw, want := range map[string]bool{
+		"1.2.3":       true,
+		"^1.2.3":      true,
+		"~1.2.3-rc.1": true,
+		">=1.2.3":     false,
+		"1.2":         false,
+		"latest":      false,
+		"file:../x":   false,
+	} {
+		if _, ok := trimmedVersion("x", raw); ok != want {
+			t.Errorf("trimmedVersion(%q) = %v, erwartet %v", raw, ok, want)
+		}
+	}
+}
+
+func TestReadPackageJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "package.json")
+	err := os.WriteFile(path, []byte(`{
+  "name": "app",
+  "dependencies": {"a": "^1.0.0", "b": "~2.0.0", "c": "github:x/c"},
+  "devDependencies": {"jest": "^29.0.0"},
+  "workspaces": {"packages": ["packages/*", "!packages/old"]},
+  "overrides": {"a": "1.0.5", "b": {".": "$a"}, "tief": {"x": "1.0.0"}},
+  "resolutions": {"**/d": "3.0.0", "e/f": "1.0.0"}
+}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := readPackageJSON(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &common{}
+	c.parseKinds()
+	if got := slices.Sorted(maps.Keys(p.kinds(c.included))); !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Errorf("kinds: %v", got)
+	}
+	if got := p.spec("jest", kindDev); got != "^29.0.0" {
+		t.Errorf("spec(jest) = %q", got)
+	}
+	if got := p.workspacePatterns(); !slices.Equal(got, []string{"packages/*", "!packages/old"}) {
+		t.Errorf("workspacePatterns: %v", got)
+	}
+	want := map[string]string{"a": "1.0.5", "b": "^1.0.0", "d": "3.0.0"}
+	if got := p.pinned(); !maps.Equal(got, want) {
+		t.Errorf("pinned: %v, erwartet %v", got, want)
+	}
+}
@@ -0,0 +1,71 @@
+// npm_range.go – einfache npm-Ranges für den Lag "innerhalb des Constraints"
+package main
+
+import (
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// npmRange prüft, ob eine Version die deklarierte Angabe erfüllt. Unterstützt
+// werden die üblichen Formen ^, ~, exakte Versionen, x-Ranges ("1.x", "1"),
+// ">=" und "*"; zusammengesetzte Ranges ("||", Leerzeichen) liefern ok=false.
+func npmRange(raw string) (match func(ver string) bool, ok bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || raw == "*" || raw == "latest" || raw == "x" {
+		return func(string) bool { return true }, true
+	}
+	if strings.ContainsAny(raw, "| <") {
+		return nil, false
+	}
+	if strings.HasPrefix(raw, ">=") {
+		lo := "v" + strings.TrimSpace(strings.TrimPrefix(raw, ">="))
+		if !semver.IsValid(lo) {
+			return nil, false
+		}
+		return func(v string) bool { return semver.Compare("v"+v, lo) >= 0 }, true
+	}
+
+	op := ""
+	if raw[0] == '^' || raw[0] == '~' {
+		op, raw = raw[:1], raw[1:]
+	}
+	parts := strings.Split(strings.TrimPrefix(raw, "v"), ".")
+	// x-Range: "1", "1.x", "1.2.*"
+	for i, p := range parts {
+		if p == "x" || p == "X" || p == "*" {
+			parts = parts[:i]
+			break
+		}
+	}
+	lo := "v" + strings.Join(parts, ".")
+	if !semver.IsValid(lo) {
+		return nil, false
+	}
+	var prefix string // Versionen müssen semver.Major/MajorMinor = prefix erfüllen
+	switch {
+	case op == "^" && parts[0] != "0":
+		prefix = semver.Major(lo)
+	case len(parts) < 3:
+		prefix = lo // "1" → v1, "1.2" → v1.2
+	case op == "^" && parts[1] != "0":
+		prefix = semver.MajorMinor(lo)
+	case op == "^":
+		return func(v string) bool { return "v"+v == lo }, true // ^0.0.x
+	case op == "~":
+		prefix = semver.MajorMinor(lo)
+	default:
+		return func(v string) bool { return "v"+v == lo }, true
+	}
+	depth := strings.Count(prefix, ".")
+	return func(v string) bool {
+		sv := "v" + v
+		if semver.Compare(sv, lo) < 0 {
+			return false
+		}
+		if depth == 0 {
+			return semver.Major(sv) == prefix
+		}
+		return semver.MajorMinor(sv) == prefix
+	}, true
+}
@@ -0,0 +1,228 @@
+// npm_workspaces.go – Workspaces (package.json "workspaces") und Root-Lockfile
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// packageJSON enthält die Felder von package.json, die libyears braucht.
+type packageJSON struct {
+	Name                 string            `json:"name"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	Workspaces           json.RawMessage   `json:"workspaces"`
+	Overrides            json.RawMessage   `json:"overrides"`
+	Resolutions          map[string]string `json:"resolutions"`
+	Engines              map[string]string `json:"engines"`
+}
+
+// workspace ist ein zu analysierendes Paket; Dir ist relativ zum Root.
+type workspace struct {
+	Name string
+	Dir  string
+	Pkg  packageJSON
+}
+
+func readPackageJSON(path string) (packageJSON, error) {
+	var p packageJSON
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return p, err
+	}
+	err = json.Unmarshal(b, &p)
+	return p, err
+}
+
+// kinds ordnet jede deklarierte Dependency der Arten in include ihrer Art
+// zu; steht ein Paket in mehreren Listen, gilt dependencies vor
+// optionalDependencies vor devDependencies (so installiert auch npm
+// --omit=dev).
+func (p packageJSON) kinds(include map[string]bool) map[string]string {
+	out := map[string]string{}
+	for _, l := range []struct {
+		kind string
+		deps map[string]string
+	}{{kindRuntime, p.Dependencies}, {kindOptional, p.OptionalDependencies}, {kindDev, p.DevDependencies}} {
+		if !include[l.kind] {
+			continue
+		}
+		for name := range l.deps {
+			if _, ok := out[name]; !ok {
+				out[name] = l.kind
+			}
+		}
+	}
+	return out
+}
+
+// spec liefert die deklarierte Angabe aus der Liste der Art.
+func (p packageJSON) spec(name, kind string) string {
+	switch kind {
+	case kindDev:
+		return p.DevDependencies[name]
+	case kindOptional:
+		return p.OptionalDependencies[name]
+	}
+	return p.Dependencies[name]
+}
+
+// workspacePatterns liest "workspaces" als Liste oder als {"packages": [...]}
+// (Yarn-Form).
+func (p packageJSON) workspacePatterns() []string {
+	if len(p.Workspaces) == 0 {
+		return nil
+	}
+	var list []string
+	if json.Unmarshal(p.Workspaces, &list) == nil {
+		return list
+	}
+	var obj struct {
+		Packages []string `json:"packages"`
+	}
+	if json.Unmarshal(p.Workspaces, &obj) == nil {
+		return obj.Packages
+	}
+	return nil
+}
+
+// pinned liefert die Versionen, die npm-"overrides" bzw. Yarn-"resolutions"
+// für direkte Dependencies erzwingen. Verschachtelte Overrides gelten nur für
+// transitive Dependencies und werden ignoriert, außer ihrem "."-Eintrag;
+// "$name" verweist auf die eigene Dependency-Angabe.
+func (p packageJSON) pinned() map[string]string {
+	out := map[string]string{}
+	var ov map[string]json.RawMessage
+	if len(p.Overrides) > 0 && json.Unmarshal(p.Overrides, &ov) != nil {
+		slog.Warn("overrides ungültig, ignoriert")
+	}
+	for name, raw := range ov {
+		var v string
+		if json.Unmarshal(raw, &v) != nil {
+			var nested map[string]json.RawMessage
+			if json.Unmarshal(raw, &nested) != nil || json.Unmarshal(nested["."], &v) != nil {
+				continue
+			}
+		}
+		if strings.HasPrefix(v, "$") {
+			v = p.Dependencies[strings.TrimPrefix(v, "$")]
+		}
+		if v != "" {
+			out[name] = v
+		}
+	}
+	for key, v := range p.Resolutions {
+		// "foo" und "**/foo" betreffen auch direkte Dependencies, "a/foo" nicht.
+		name := strings.TrimPrefix(key, "**/")
+		if strings.Count(name, "/") > strings.Count(name, "@") {
+			continue
+		}
+		out[name] = v
+	}
+	return out
+}
+
+// findWorkspaces löst die Workspace-Muster relativ zu root auf. "**" wird wie
+// "*" behandelt, Muster mit führendem "!" schließen Verzeichnisse aus.
+func findWorkspaces(root string, patterns []string) []workspace {
+	excluded := map[string]bool{}
+	var dirs []string
+	for _, pat := range patterns {
+		neg := strings.HasPrefix(pat, "!")
+		pat = strings.ReplaceAll(strings.TrimPrefix(pat, "!"), "**", "*")
+		matches, err := filepath.Glob(filepath.Join(root, filepath.FromSlash(pat)))
+		if err != nil {
+			slog.Warn("ungültiges Workspace-Muster", "pattern", pat, "err", err)
+			continue
+		}
+		for _, m := range matches {
+			rel, _ := filepath.Rel(root, m)
+			rel = filepath.ToSlash(rel)
+			if neg {
+				excluded[rel] = true
+			} else {
+				dirs = append(dirs, rel)
+			}
+		}
+	}
+	sort.Strings(dirs)
+
+	var wss []workspace
+	seen := map[string]bool{}
+	for _, d := range dirs {
+		if excluded[d] || seen[d] {
+			continue
+		}
+		seen[d] = true
+		p, err := readPackageJSON(filepath.Join(root, d, "package.json"))
+		if err != nil {
+			if !os.IsNotExist(err) {
+				slog.Warn("Workspace übersprungen", "dir", d, "err", err)
+			}
+			continue
+		}
+		name := p.Name
+		if name == "" {
+			name = d
+		}
+		wss = append(wss, workspace{Name: name, Dir: d, Pkg: p})
+	}
+	return wss
+}
+
+// npmLock bildet Lockfile-Pfade ("node_modules/x", "pkgs/a/node_modules/x")
+// auf die installierte Version ab.
+type npmLock map[string]string
+
+// readNPMLock liest package-lock.json im Root (lockfileVersion 1 bis 3).
+// Fehlt die Datei, ist das Ergebnis leer.
+func readNPMLock(root string) npmLock {
+	b, err := os.ReadFile(filepath.Join(root, "package-lock.json"))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			slog.Warn("package-lock.json nicht lesbar", "err", err)
+		}
+		return npmLock{}
+	}
+	var lf struct {
+		Packages map[string]struct {
+			Version string `json:"version"`
+		} `json:"packages"`
+		Dependencies map[string]struct {
+			Version string `json:"version"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal(b, &lf); err != nil {
+		slog.Warn("package-lock.json ungültig", "err", err)
+		return npmLock{}
+	}
+	lock := npmLock{}
+	for path, p := range lf.Packages {
+		if p.Version != "" {
+			lock[path] = p.Version
+		}
+	}
+	for name, d := range lf.Dependencies { // lockfileVersion 1
+		if _, ok := lock["node_modules/"+name]; !ok && d.Version != "" {
+			lock["node_modules/"+name] = d.Version
+		}
+	}
+	return lock
+}
+
+// installed liefert die Version, die Node für name aus dir heraus auflöst:
+// zuerst das node_modules des Workspaces, dann die gehoistete im Root.
+func (l npmLock) installed(dir, name string) (string, bool) {
+	if dir != "." {
+		if v, ok := l[dir+"/node_modules/"+name]; ok {
+			return v, true
+		}
+	}
+	v, ok := l["node_modules/"+name]
+	return v, ok
+}
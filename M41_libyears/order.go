@@ -0,0 +1,86 @@
+// order.go – --sort, --top und --min-lag: Tabelle sortieren und auf die
+// relevanten Zeilen kürzen; Summen, --out und die übrigen Ausgaben bleiben
+// vollständig
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"baa_fs25/shared/i18n"
+	"baa_fs25/shared/logging"
+)
+
+// tableRow ist eine zurückgehaltene Tabellenzeile samt Dependency.
+type tableRow struct {
+	d    dep
+	line string
+}
+
+// checkOrder prüft --sort und --top.
+func (c *common) checkOrder() {
+	switch c.sortBy {
+	case "", "lag", "name", "age":
+	default:
+		logging.Usage("ungültiges --sort (erlaubt: lag, name, age)", "value", c.sortBy)
+	}
+	if c.top < 0 {
+		logging.Usage("ungültiges --top", "value", c.top)
+	}
+}
+
+// ordered meldet, ob die Tabelle bis flushRows zurückgehalten wird; ohne
+// die Flags erscheinen die Zeilen sofort in Auswertungsreihenfolge.
+func (c *common) ordered() bool {
+	return c.sortBy != "" || c.top > 0 || c.minLag > 0
+}
+
+// printRow gibt die Zeile von d aus oder hält sie für flushRows zurück.
+func (c *common) printRow(d dep, format string, args ...any) {
+	line := fmt.Sprintf(format, args...)
+	if !c.ordered() {
+		fmt.Print(line)
+		return
+	}
+	c.rows = append(c.rows, tableRow{d: d, line: line})
+}
+
+// flushRows gibt die zurückgehaltenen Zeilen gefiltert (--min-lag),
+// sortiert (--sort) und gekürzt (--top) aus.
+func (c *common) flushRows() {
+	if !c.ordered() {
+		return
+	}
+	rows := c.rows[:0:0]
+	for _, r := range c.rows {
+		if r.d.Lag >= c.minLag {
+			rows = append(rows, r)
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i].d, rows[j].d
+		switch c.sortBy {
+		case "lag":
+			return a.Lag > b.Lag
+		case "name":
+			return a.Package < b.Package
+		case "age":
+			// älteste verwendete Version zuerst, ohne Datum ans Ende
+			if a.released.IsZero() != b.released.IsZero() {
+				return b.released.IsZero()
+			}
+			return a.released.Before(b.released)
+		}
+		return false
+	})
+	if c.top > 0 && len(rows) > c.top {
+		rows = rows[:c.top]
+	}
+	for _, r := range rows {
+		fmt.Print(r.line)
+	}
+	if hidden := len(c.rows) - len(rows); hidden > 0 {
+		i18n.Printf("… %d weitere Dependencies ausgeblendet (--top/--min-lag)\n", hidden)
+	}
+	c.rows = nil
+}
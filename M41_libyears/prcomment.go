@@ -0,0 +1,83 @@
+// prcomment.go – --github-pr: Freshness-Delta als PR-Kommentar
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"baa_fs25/shared/logging"
+	"baa_fs25/shared/report"
+)
+
+// postPRComment vergleicht das aktuelle Ergebnis mit --base (einem --out-JSON
+// des Basis-Branches, optional) und postet die Tabelle an den Pull Request.
+func (c *common) postPRComment(res result) {
+	if c.githubPR == 0 {
+		return
+	}
+	pc := report.PRComment{PR: c.githubPR, Marker: "libyears:" + res.Eco, Body: prMarkdown(c.loadBase(), res)}
+	if err := pc.Post(); err != nil {
+		logging.Fatal("PR-Kommentar fehlgeschlagen", "pr", c.githubPR, "err", err)
+	}
+}
+
+// loadBase liest --base; ohne --base nil.
+func (c *common) loadBase() *result {
+	if c.base == "" {
+		return nil
+	}
+	base := &result{}
+	if err := report.ReadJSON(c.base, base); err != nil {
+		logging.Fatal("Basis-Ergebnis nicht lesbar", "file", c.base, "err", err)
+	}
+	return base
+}
+
+func prMarkdown(base *result, cur result) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "### libyears (%s)\n\n", cur.Eco)
+	s := cur.Summary
+	if base == nil {
+		b.WriteString("| | PR |\n|---|---:|\n")
+		fmt.Fprintf(&b, "| Gesamt-Lag (Jahre) | %.2f |\n", s.TotalLag)
+		fmt.Fprintf(&b, "| Median | %.2f |\n| P90 | %.2f |\n", s.MedianLag, s.P90Lag)
+		fmt.Fprintf(&b, "| Dependencies | %d |\n", s.Count)
+		fmt.Fprintf(&b, "| über %.1f Jahr(en) | %d |\n", s.Threshold, s.Above)
+		return b.String()
+	}
+
+	bs := base.Summary
+	b.WriteString("| | Basis | PR | Δ |\n|---|---:|---:|---:|\n")
+	fmt.Fprintf(&b, "| Gesamt-Lag (Jahre) | %.2f | %.2f | %s |\n", bs.TotalLag, s.TotalLag, report.Delta(bs.TotalLag, s.TotalLag, 2))
+	fmt.Fprintf(&b, "| Median | %.2f | %.2f | %s |\n", bs.MedianLag, s.MedianLag, report.Delta(bs.MedianLag, s.MedianLag, 2))
+	fmt.Fprintf(&b, "| P90 | %.2f | %.2f | %s |\n", bs.P90Lag, s.P90Lag, report.Delta(bs.P90Lag, s.P90Lag, 2))
+	fmt.Fprintf(&b, "| Dependencies | %d | %d | %+d |\n", bs.Count, s.Count, s.Count-bs.Count)
+	fmt.Fprintf(&b, "| über %.1f Jahr(en) | %d | %d | %+d |\n", s.Threshold, bs.Above, s.Above, s.Above-bs.Above)
+
+	key := func(d dep) string { return d.Workspace + "\x00" + d.Package }
+	old := map[string]dep{}
+	for _, d := range base.Deps {
+		old[key(d)] = d
+	}
+	var rows []string
+	for _, d := range cur.Deps {
+		o, ok := old[key(d)]
+		delete(old, key(d))
+		switch {
+		case !ok:
+			rows = append(rows, fmt.Sprintf("| %s | – | %s | %s |", d.Package, d.Current, report.Delta(0, d.Lag, 2)))
+		case o.Current != d.Current:
+			rows = append(rows, fmt.Sprintf("| %s | %s | %s | %s |", d.Package, o.Current, d.Current, report.Delta(o.Lag, d.Lag, 2)))
+		}
+	}
+	for _, o := range old {
+		rows = append(rows, fmt.Sprintf("| %s | %s | – | %s |", o.Package, o.Current, report.Delta(o.Lag, 0, 2)))
+	}
+	if len(rows) > 0 {
+		sort.Strings(rows)
+		b.WriteString("\n| Paket | Basis | PR | Δ Lag |\n|---|---|---|---:|\n")
+		b.WriteString(strings.Join(rows, "\n") + "\n")
+	}
+	return b.String()
+}
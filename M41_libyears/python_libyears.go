@@ -3,76 +3,191 @@ package main
 
 import (
 	"bufio"
-	"encoding/json"
 	"fmt"
-	"log"
-	"net/http"
-	"net/url"
+	"log/slog"
 	"os"
 	"regexp"
+	"strings"
 	"time"
+
+	"baa_fs25/shared/i18n"
+	"baa_fs25/shared/logging"
+	"baa_fs25/shared/purl"
+	"baa_fs25/shared/registry"
 )
 
-type releaseInfo struct {
-	Upload string `json:"upload_time_iso_8601"`
-}
-type pypiResponse struct {
-	Info struct {
-		Version string `json:"version"`
-	} `json:"info"`
-	Releases map[string][]releaseInfo `json:"releases"`
-}
+var rx = regexp.MustCompile(`^\s*([A-Za-z0-9._-]+)==([A-Za-z0-9._-]+)`)
 
-var (
-	rx     = regexp.MustCompile(`^\s*([A-Za-z0-9._-]+)==([A-Za-z0-9._-]+)`)
-	client = &http.Client{Timeout: 15 * time.Second}
-)
+// reqRx erkennt sonstige Anforderungen ("pkg>=1.0", "pkg[extra]", "pkg"),
+// die sich nicht auf eine exakte Version festlegen.
+var reqRx = regexp.MustCompile(`^\s*([A-Za-z0-9][A-Za-z0-9._-]*)\s*(?:\[[^\]]*\])?\s*((?:[<>=!~][^#;]*)?)(?:[#;].*)?$`)
 
-func main() {
-	if len(os.Args) < 2 {
-		log.Fatalf("Usage: %s requirements.txt [...] ", os.Args[0])
+func runPy(args []string) {
+	fs, c := newFlagSet("py")
+	parseFlags(fs, c, args)
+	if fs.NArg() < 1 {
+		logging.Usage("Usage: go run . py [flags] requirements.txt [...]")
 	}
 
-	var total float64
-	var count int
+	c.watch(func() error {
+		pins, err := c.readPins(fs.Args())
+		if err != nil {
+			return err
+		}
+		multi := fs.NArg() > 1
+		c.conflicts = pinConflicts(pins)
+		if c.withRT {
+			c.runtime = pyRuntime(fs.Arg(0))
+			defer c.printRuntime()
+		}
 
-	fmt.Printf("%-25s %-10s %-10s %8s\n", "Package", "Current", "Latest", "Lag(yr)")
+		var total float64
+		var count int
+		var deps []dep
 
-	for _, file := range os.Args[1:] {
-		processFile(file, &total, &count)
-	}
+		fmt.Printf("%-25s %-10s %-10s %8s", i18n.T("Package"), i18n.T("Current"), i18n.T("Latest"), "Lag(yr)")
+		if multi {
+			fmt.Printf("  %s", i18n.T("File"))
+		}
+		fmt.Println()
 
-	if count > 0 {
-		fmt.Printf("\nTOTAL Lag: %.2f  |  Ø %.2f\n", total, total/float64(count))
-	} else {
-		fmt.Println("No valid packages processed.")
-	}
+		for _, p := range pins {
+			latest, lag, deprecated, released, err := pyLibyear(p.name, p.ver)
+			if err != nil {
+				c.skip(skipped{Package: p.name, Version: p.ver, Reason: reasonOf(err), Detail: err.Error(), File: p.files[0]})
+				continue
+			}
+			d := dep{Package: p.name, Purl: purl.For("py", p.name, p.ver), Current: p.ver, Latest: latest, Lag: lag, Kind: pinKind(p.files)}
+			if multi {
+				d.Files = p.files
+			}
+			c.enrich(&d, deprecated)
+			d.released = released
+			files := ""
+			if multi {
+				files = "  " + strings.Join(p.files, ", ")
+			}
+			c.printRow(d, "%-25s %-10s %-10s %8.2f%s%s\n", p.name, p.ver, latest, lag, files, eolMark(d)+kindMark(d)+exceptionMark(d))
+			if c.counts(d) {
+				total += lag
+				count++
+			}
+			deps = append(deps, d)
+			c.emitDep(d)
+		}
+		c.flushRows()
+		c.writeResult("py", fs.Args(), deps)
+
+		if count > 0 {
+			i18n.Printf("\nTOTAL Lag: %.2f  |  Ø %.2f\n", total, total/float64(count))
+			c.printStats(deps)
+		} else {
+			i18n.Println("No valid packages processed.")
+			c.printSkips()
+		}
+		if len(c.conflicts) > 0 {
+			i18n.Printf("\nKonflikte (%d Pakete mit unterschiedlichen Pins):\n", len(c.conflicts))
+			for _, k := range c.conflicts {
+				fmt.Printf("  %-25s", k.Package)
+				for _, p := range k.Pins {
+					fmt.Printf("  %s==%s", p.File, p.Version)
+				}
+				fmt.Println()
+			}
+		}
+		return nil
+	})
 }
 
-func processFile(path string, total *float64, count *int) {
-	f, err := os.Open(path)
-	if err != nil {
-		log.Fatalf("open %s: %v", path, err)
+// pin ist ein Paket==Version aus einer oder mehreren requirements-Dateien.
+// Identische Pins aus mehreren Dateien werden nur einmal ausgewertet.
+type pin struct {
+	name, ver string
+	files     []string
+}
+
+// readPins liest alle Dateien und fasst identische Pins zusammen; die
+// Reihenfolge folgt dem ersten Auftreten.
+func (c *common) readPins(paths []string) ([]*pin, error) {
+	var pins []*pin
+	byKey := map[string]*pin{}
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("requirements-Datei nicht lesbar: %w", err)
+		}
+		sc := bufio.NewScanner(f)
+		for sc.Scan() {
+			name, cur, ok := parse(sc.Text())
+			if !ok {
+				if m := reqRx.FindStringSubmatch(sc.Text()); m != nil {
+					c.skip(skipped{Package: m[1], Version: strings.TrimSpace(m[2]), Reason: reasonRange, File: path})
+				}
+				continue
+			}
+			key := canonicalName(name) + "==" + cur
+			if p, ok := byKey[key]; ok {
+				if p.files[len(p.files)-1] != path {
+					p.files = append(p.files, path)
+				}
+				continue
+			}
+			p := &pin{name: name, ver: cur, files: []string{path}}
+			byKey[key] = p
+			pins = append(pins, p)
+		}
+		f.Close()
 	}
-	defer f.Close()
+	return pins, nil
+}
 
-	sc := bufio.NewScanner(f)
-	for sc.Scan() {
-		name, cur, ok := parse(sc.Text())
-		if !ok {
-			continue
+// pinConflict ist ein Paket, das in verschiedenen Dateien auf
+// unterschiedliche Versionen gepinnt ist.
+type pinConflict struct {
+	Package string    `json:"package"`
+	Pins    []filePin `json:"pins"`
+}
+
+type filePin struct {
+	File    string `json:"file"`
+	Version string `json:"version"`
+}
+
+func pinConflicts(pins []*pin) []pinConflict {
+	byName := map[string][]*pin{}
+	var names []string
+	for _, p := range pins {
+		n := canonicalName(p.name)
+		if _, ok := byName[n]; !ok {
+			names = append(names, n)
 		}
-		latest, lag, err := libyear(name, cur)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "[SKIP] %-20s %v\n", name, err)
+		byName[n] = append(byName[n], p)
+	}
+	var out []pinConflict
+	for _, n := range names {
+		ps := byName[n]
+		if len(ps) < 2 {
 			continue
 		}
-		fmt.Printf("%-25s %-10s %-10s %8.2f\n", name, cur, latest, lag)
-		*total += lag
-		*count++
+		k := pinConflict{Package: ps[0].name}
+		for _, p := range ps {
+			for _, f := range p.files {
+				k.Pins = append(k.Pins, filePin{File: f, Version: p.ver})
+			}
+		}
+		slog.Warn("Pins widersprechen sich", "pkg", k.Package, "pins", len(k.Pins))
+		out = append(out, k)
 	}
+	return out
+}
+
+// canonicalName normalisiert Paketnamen nach PEP 503.
+func canonicalName(name string) string {
+	return strings.ToLower(sepRx.ReplaceAllString(name, "-"))
 }
 
+var sepRx = regexp.MustCompile(`[-_.]+`)
+
 func parse(line string) (name, ver string, ok bool) {
 	m := rx.FindStringSubmatch(line)
 	if len(m) == 3 {
@@ -81,36 +196,56 @@ func parse(line string) (name, ver string, ok bool) {
 	return
 }
 
-func libyear(pkg, usedVer string) (latestVer string, lag float64, err error) {
-	resp, err := client.Get("https://pypi.org/pypi/" + url.PathEscape(pkg) + "/json")
+// pyLibyear liefert neben dem Lag einen Deprecation-Hinweis: gelöschte
+// (yanked) Version oder Projekt mit Classifier "7 - Inactive".
+func pyLibyear(pkg, usedVer string) (latestVer string, lag float64, deprecated string, released time.Time, err error) {
+	times, err := releaseTimes(pypiRegistry, pkg)
 	if err != nil {
 		return
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != 200 {
-		err = fmt.Errorf("HTTP %d", resp.StatusCode)
+	usedTime, ok := times[usedVer]
+	if !ok {
+		err = skipErrorf(reasonNoDate, "no release info for %s %s", pkg, usedVer)
 		return
 	}
-
-	var js pypiResponse
-	if err = json.NewDecoder(resp.Body).Decode(&js); err != nil {
+	released = usedTime
+	if !visible(usedTime) {
+		err = afterAsOf(pkg, usedVer)
 		return
 	}
-
-	usedList, ok := js.Releases[usedVer]
-	if !ok || len(usedList) == 0 {
-		err = fmt.Errorf("no release info for %s %s", pkg, usedVer)
-		return
+	latestVer = pyLatestAsOf(times)
+	if l, ok := pypiRegistry.(registry.LatestReporter); ok && asOf.IsZero() {
+		if latestVer, err = l.Latest(pkg); err != nil {
+			err = regErr(err)
+			return
+		}
 	}
-	latestVer = js.Info.Version
-	latestList := js.Releases[latestVer]
-	if len(latestList) == 0 {
-		err = fmt.Errorf("no release info for latest %s", latestVer)
+	latestTime, ok := times[latestVer]
+	if !ok {
+		err = skipErrorf(reasonNoDate, "no release info for latest %s", latestVer)
 		return
 	}
-
-	usedTime, _ := time.Parse(time.RFC3339, usedList[0].Upload)
-	latestTime, _ := time.Parse(time.RFC3339, latestList[0].Upload)
+	deprecated = deprecation(pypiRegistry, pkg, usedVer)
 	lag = latestTime.Sub(usedTime).Hours() / 24 / 365.25
 	return
 }
+
+// pyPreRx erkennt Vorab-Versionen nach PEP 440 (a, b, rc, dev).
+var pyPreRx = regexp.MustCompile(`(?i)(a|b|c|rc|alpha|beta|pre|preview|dev)\d*`)
+
+// pyLatestAsOf liefert das zuletzt hochgeladene stabile Release vor dem
+// --as-of-Stichtag, wie es info.version damals gemeldet hätte. Ohne
+// Stichtag ist es das zuletzt hochgeladene stabile Release überhaupt.
+func pyLatestAsOf(times map[string]time.Time) string {
+	var best string
+	var bestTime time.Time
+	for ver, t := range times {
+		if pyPreRx.MatchString(ver) || !visible(t) {
+			continue
+		}
+		if t.After(bestTime) {
+			best, bestTime = ver, t
+		}
+	}
+	return best
+}
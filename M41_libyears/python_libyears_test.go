@@ -0,0 +1,129 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"baa_fs25/shared/registry/registrytest"
+)
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		line, name, ver string
+		ok              bool
+	}{
+		{"Django==4.2.7", "Django", "4.2.7", true},
+		{"  zope.interface==6.1  # Kommentar", "zope.interface", "6.1", true},
+		{"numpy==1.26.2 ; python_version >= '3.9'", "numpy", "1.26.2", true},
+		{"requests>=2.31", "", "", false},
+		{"# Django==4.2.7", "", "", false},
+		{"-r base.txt", "", "", false},
+	} {
+		name, ver, ok := parse(tc.line)
+		if name != tc.name || ver != tc.ver || ok != tc.ok {
+			t.Errorf("parse(%q) = %q, %q, %v", tc.line, name, ver, ok)
+		}
+	}
+}
+
+func TestReadPins(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, txt string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(txt), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	base := write("requirements.txt", "Django==4.2.7\nrequests>=2.31\nflask[async] ~= 3.0 ; python_version > '3.8'\n# Kommentar\n-r dev.txt\n")
+	dev := write("requirements-dev.txt", "django==4.2.7\npytest==7.4.0\nDjango==4.1.0\n")
+
+	c := &common{}
+	pins, err := c.readPins([]string{base, dev})
+	if err != nil {
+		t.Fatal(err)
+	}
+	type want struct {
+		name, ver string
+		files     int
+	}
+	wants := []want{{"Django", "4.2.7", 2}, {"pytest", "7.4.0", 1}, {"Django", "4.1.0", 1}}
+	if len(pins) != len(wants) {
+		t.Fatalf("%d Pins, erwartet %d", len(pins), len(wants))
+	}
+	for i, w := range wants {
+		if p := pins[i]; p.name != w.name || p.ver != w.ver || len(p.files) != w.files {
+			t.Errorf("Pin %d = %s==%s %v, erwartet %+v", i, p.name, p.ver, p.files, w)
+		}
+	}
+	if len(c.skips) != 2 || c.skips[0].Package != "requests" || c.skips[1].Package != "flask" || c.skips[1].Version != "~= 3.0" || c.skips[0].Reason != reasonRange {
+		t.Errorf("skips: %+v", c.skips)
+	}
+	if k := pinConflicts(pins); len(k) != 1 || len(k[0].Pins) != 3 {
+		t.Errorf("Konflikte: %+v", k)
+	}
+	if _, err := c.readPins([]string{filepath.Join(dir, "fehlt.txt")}); err == nil {
+		t.Error("fehlende Datei ohne Fehler")
+	}
+}
+
+// pypiFake ergänzt die feste Registry um info.version und Yank-Hinweise.
+type pypiFake struct {
+	registrytest.Static
+	latest string
+	notes  map[string]string
+}
+
+func (f pypiFake) Latest(string) (string, error)            { return f.latest, nil }
+func (f pypiFake) Deprecated(_, ver string) (string, error) { return f.notes[ver], nil }
+
+func TestPyLibyear(t *testing.T) {
+	old := pypiRegistry
+	t.Cleanup(func() { pypiRegistry = old })
+	times := map[string]time.Time{
+		"1.0":   day(2020, 1, 1),
+		"1.1":   day(2021, 1, 1),
+		"2.0b1": day(2021, 6, 1),
+		"1.2":   day(2021, 9, 1), // Backport nach der Beta
+	}
+	pypiRegistry = pypiFake{Static: registrytest.Static{"p": times}, latest: "1.1", notes: map[string]string{"1.0": "yanked CVE"}}
+
+	for _, tc := range []struct {
+		name, used string
+		asOf       time.Time
+		latest     string
+		days       float64
+		deprecated string
+		reason     string
+	}{
+		{"info.version", "1.0", time.Time{}, "1.1", 366, "yanked CVE", ""},
+		{"--as-of ohne Vorab-Versionen", "1.0", day(2022, 1, 1), "1.2", 609, "yanked CVE", ""},
+		{"--as-of vor dem Release", "1.1", day(2020, 6, 1), "", 0, "", reasonAfterAsOf},
+		{"unbekannte Version", "0.9", time.Time{}, "", 0, "", reasonNoDate},
+	} {
+		setAsOf(t, tc.asOf)
+		latest, lag, deprecated, _, err := pyLibyear("p", tc.used)
+		if tc.reason != "" {
+			if reasonOf(err) != tc.reason {
+				t.Errorf("%s: Fehler %v, erwartet Grund %s", tc.name, err, tc.reason)
+			}
+			continue
+		}
+		if err != nil || latest != tc.latest || lag != tc.days/365.25 || deprecated != tc.deprecated {
+			t.Errorf("%s: %s/%.4f/%q/%v, erwartet %s/%.4f/%q", tc.name, latest, lag, deprecated, err, tc.latest, tc.days/365.25, tc.deprecated)
+		}
+	}
+
+	// ohne info.version: neuestes stabiles Release
+	pypiRegistry = registrytest.Static{"p": times}
+	setAsOf(t, time.Time{})
+	if latest, _, _, _, err := pyLibyear("p", "1.0"); err != nil || latest != "1.2" {
+		t.Errorf("ohne info.version: %s/%v, erwartet 1.2", latest, err)
+	}
+	pypiRegistry = failing(404)
+	if _, _, _, _, err := pyLibyear("p", "1.0"); reasonOf(err) != reasonNotFound {
+		t.Errorf("HTTP 404: %v, erwartet %s", err, reasonNotFound)
+	}
+}
@@ -0,0 +1,63 @@
+// registries.go – Registry-Zugriff für npm, PyPI und den Go-Proxy; die
+// Variablen sind austauschbar, Tests setzen feste Registries ein
+package main
+
+import (
+	"errors"
+	"time"
+
+	"baa_fs25/shared/registry"
+)
+
+// historyRegistry kennt alle Versionen eines Pakets und ihre Zeitpunkte.
+type historyRegistry interface {
+	registry.Client
+	registry.Lister
+}
+
+var npmRegistry, pypiRegistry, goProxy = newRegistries()
+
+func newRegistries() (npm, pypi, goproxy historyRegistry) {
+	return &registry.NPM{HTTP: client}, &registry.PyPI{HTTP: client}, &registry.GoProxy{HTTP: client}
+}
+
+// resetRegistries verwirft die Registry-Caches, damit --watch in jeder
+// Runde neue Releases sieht.
+func resetRegistries() {
+	npmRegistry, pypiRegistry, goProxy = newRegistries()
+}
+
+// releaseTimes liefert die Zeitpunkte aller Versionen von pkg.
+func releaseTimes(reg historyRegistry, pkg string) (map[string]time.Time, error) {
+	vers, err := reg.Versions(pkg)
+	if err != nil {
+		return nil, regErr(err)
+	}
+	times := make(map[string]time.Time, len(vers))
+	for _, v := range vers {
+		if t, err := reg.ReleaseTime(pkg, v); err == nil {
+			times[v] = t
+		}
+	}
+	return times, nil
+}
+
+// regErr ordnet einen HTTP-Status der Registry wie httpSkip einem Grund zu.
+func regErr(err error) error {
+	var se *registry.StatusError
+	if errors.As(err, &se) {
+		return httpSkip(se.Code)
+	}
+	return err
+}
+
+// deprecation liefert den Deprecation-Hinweis einer Version, "" wenn die
+// Registry keine kennt.
+func deprecation(reg historyRegistry, pkg, ver string) string {
+	d, ok := reg.(registry.Deprecator)
+	if !ok {
+		return ""
+	}
+	msg, _ := d.Deprecated(pkg, ver)
+	return msg
+}
@@ -0,0 +1,194 @@
+// runtime.go – Lag der Sprach-Runtime: go-/toolchain-Direktive in go.mod
+// gegenüber dem neuesten Go-Release, mit --runtime auch python_requires
+// bzw. engines.node gegenüber dem neuesten Python- bzw. Node-LTS-Release
+// (Daten von endoflife.date)
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/modfile"
+
+	"baa_fs25/shared/i18n"
+)
+
+// runtimeLag ist der Abstand einer Runtime-Angabe zum neuesten Release.
+type runtimeLag struct {
+	Runtime   string  `json:"runtime"`   // go | python | nodejs (Produkt bei endoflife.date)
+	Directive string  `json:"directive"` // Angabe wie in der Datei, z. B. "go 1.19"
+	Cycle     string  `json:"cycle"`     // Release-Zyklus der Untergrenze, z. B. 1.19
+	Latest    string  `json:"latest"`    // neuester (bei nodejs: LTS-)Zyklus, bei --as-of vor dem Stichtag
+	Lag       float64 `json:"lag_years"`
+	Behind    int     `json:"versions_behind"` // Zyklen zwischen Angabe und neuestem
+}
+
+// rxCycle liest den Zyklus "1.21" aus "1.21.3", "go1.21.3" oder "1.21rc1".
+var rxCycle = regexp.MustCompile(`(\d+)\.(\d+)`)
+
+// goRuntime liest go- und toolchain-Direktive aus modDir/go.mod.
+func goRuntime(modDir string) []runtimeLag {
+	path := filepath.Join(modDir, "go.mod")
+	b, err := os.ReadFile(path)
+	if err != nil {
+		slog.Warn("go.mod nicht lesbar, kein Toolchain-Lag", "file", path, "err", err)
+		return nil
+	}
+	// ParseLax überspringt die toolchain-Direktive, daher nur als Rückfall
+	mf, err := modfile.Parse(path, b, nil)
+	if err != nil {
+		mf, err = modfile.ParseLax(path, b, nil)
+	}
+	if err != nil {
+		slog.Warn("go.mod nicht lesbar, kein Toolchain-Lag", "file", path, "err", err)
+		return nil
+	}
+	var out []runtimeLag
+	if mf.Go != nil {
+		out = appendRuntime(out, "go", "go "+mf.Go.Version, goCycle(mf.Go.Version))
+	}
+	if mf.Toolchain != nil {
+		out = appendRuntime(out, "go", "toolchain "+mf.Toolchain.Name, goCycle(mf.Toolchain.Name))
+	}
+	return out
+}
+
+func goCycle(ver string) string {
+	if m := rxCycle.FindStringSubmatch(ver); m != nil {
+		return m[1] + "." + m[2]
+	}
+	return ""
+}
+
+// pyRuntime sucht python_requires im Verzeichnis der ersten
+// requirements-Datei: pyproject.toml (requires-python bzw. Poetrys python),
+// setup.cfg und setup.py.
+func pyRuntime(reqFile string) []runtimeLag {
+	dir := filepath.Dir(reqFile)
+	for _, f := range []string{"pyproject.toml", "setup.cfg", "setup.py"} {
+		b, err := os.ReadFile(filepath.Join(dir, f))
+		if err != nil {
+			continue
+		}
+		if m := rxPyRequires.FindSubmatch(b); m != nil {
+			spec := strings.Trim(strings.TrimSpace(string(m[1])), `"'`)
+			return appendRuntime(nil, "python", f+": "+spec, runtimeFloor(spec, 2))
+		}
+	}
+	slog.Info("keine python_requires-Angabe gefunden", "dir", dir)
+	return nil
+}
+
+var rxPyRequires = regexp.MustCompile(`(?m)^\s*(?:requires-python|python_requires|python)\s*=\s*(["']?[^"'\n#]+["']?)`)
+
+// npmRuntime liest engines.node aus package.json.
+func npmRuntime(pkg packageJSON) []runtimeLag {
+	spec := pkg.Engines["node"]
+	if spec == "" {
+		slog.Info("package.json ohne engines.node")
+		return nil
+	}
+	return appendRuntime(nil, "nodejs", "engines.node "+spec, runtimeFloor(spec, 1))
+}
+
+// rxBound ist eine Versionsangabe mit optionalem Operator in einer Range
+// wie ">=3.8, !=3.9.*" oder "^16 || >=18".
+var rxBound = regexp.MustCompile(`(<=|>=|<|>|~=|===|==|!=|\^|~|=)?\s*v?(\d+)(?:\.(\d+))?(?:\.[\w*]+)*`)
+
+// runtimeFloor liefert die kleinste zugelassene Version einer Range als
+// Zyklus mit parts Stellen ("3.8" bzw. "16"); Ober- und Ausschlussgrenzen
+// zählen nicht.
+func runtimeFloor(spec string, parts int) string {
+	best, bestMinor := -1, -1
+	for _, m := range rxBound.FindAllStringSubmatch(spec, -1) {
+		switch m[1] {
+		case "<", "<=", "!=":
+			continue
+		}
+		major, _ := strconv.Atoi(m[2])
+		minor, _ := strconv.Atoi(m[3])
+		if best < 0 || major < best || major == best && minor < bestMinor {
+			best, bestMinor = major, minor
+		}
+	}
+	switch {
+	case best < 0:
+		return ""
+	case parts == 1:
+		return strconv.Itoa(best)
+	}
+	return fmt.Sprintf("%d.%d", best, bestMinor)
+}
+
+// appendRuntime ergänzt out um den Lag von ver; ist der Zyklus nicht
+// bestimmbar oder endoflife.date nicht erreichbar, bleibt out unverändert.
+func appendRuntime(out []runtimeLag, product, directive, cycle string) []runtimeLag {
+	if cycle == "" {
+		slog.Warn("Runtime-Version nicht lesbar", "directive", directive)
+		return out
+	}
+	r, err := cycleLag(product, cycle)
+	if err != nil {
+		slog.Warn("Runtime-Lag nicht bestimmbar", "directive", directive, "err", err)
+		return out
+	}
+	r.Directive = directive
+	return append(out, r)
+}
+
+// cycleLag misst den Abstand des Zyklus cycle zum neuesten Zyklus von
+// product, der vor dem --as-of-Stichtag erschienen ist. Bei Node zählen
+// als Ziel und für "Versionen zurück" nur LTS-Linien.
+func cycleLag(product, cycle string) (runtimeLag, error) {
+	r := runtimeLag{Runtime: product, Cycle: cycle}
+	cycles, err := eolCycles(product)
+	if err != nil {
+		return r, err
+	}
+	var own, newest time.Time
+	var released []time.Time
+	for _, cy := range cycles {
+		t, err := time.Parse("2006-01-02", cy.ReleaseDate)
+		if err != nil || !visible(t) {
+			continue
+		}
+		if cy.Cycle == cycle {
+			own = t
+		}
+		if product == "nodejs" && !cy.isLTS() {
+			continue
+		}
+		released = append(released, t)
+		if t.After(newest) {
+			newest, r.Latest = t, cy.Cycle
+		}
+	}
+	if own.IsZero() {
+		return r, fmt.Errorf("Zyklus %s bei endoflife.date/%s unbekannt", cycle, product)
+	}
+	for _, t := range released {
+		if t.After(own) {
+			r.Behind++
+		}
+	}
+	r.Lag = newest.Sub(own).Hours() / 24 / 365.25
+	return r, nil
+}
+
+// printRuntime gibt die Runtime-Lags unter der Zusammenfassung aus.
+func (c *common) printRuntime() {
+	if len(c.runtime) == 0 {
+		return
+	}
+	fmt.Println()
+	for _, r := range c.runtime {
+		i18n.Printf("Runtime %-22s → %-6s Lag %.2f  |  %d Versionen zurück\n",
+			r.Directive, r.Latest, r.Lag, r.Behind)
+	}
+}
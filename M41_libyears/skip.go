@@ -0,0 +1,90 @@
+// skip.go – maschinenlesbare Gründe für nicht ausgewertete Dependencies
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+
+	"baa_fs25/shared/i18n"
+)
+
+// Gründe, aus denen eine Dependency nicht in den Lag eingeht.
+const (
+	reasonNotFound  = "not-found"          // Paket/Version in der Registry unbekannt
+	reasonRange     = "range-unresolvable" // keine exakte Version ableitbar
+	reasonNoDate    = "no-release-date"    // Release-Zeitpunkt fehlt
+	reasonPrivate   = "private"            // Registry verweigert Zugriff oder Quelle ist keine Registry
+	reasonLocal     = "local-replace"      // go.mod-replace auf ein Verzeichnis
+	reasonError     = "error"              // Netzwerk- oder Formatfehler
+	reasonAfterAsOf = "after-as-of"        // Version erst nach dem --as-of-Stichtag veröffentlicht
+)
+
+// skipped ist ein übersprungener Eintrag im --out-JSON.
+type skipped struct {
+	Package   string `json:"package"`
+	Version   string `json:"version,omitempty"`
+	Reason    string `json:"reason"`
+	Detail    string `json:"detail,omitempty"`
+	Workspace string `json:"workspace,omitempty"`
+	File      string `json:"file,omitempty"`
+}
+
+// skipError trägt den Grund durch die Registry-Funktionen.
+type skipError struct {
+	reason string
+	msg    string
+}
+
+func (e *skipError) Error() string { return e.msg }
+
+func skipErrorf(reason, format string, args ...any) error {
+	return &skipError{reason: reason, msg: fmt.Sprintf(format, args...)}
+}
+
+// httpSkip ordnet einen HTTP-Status einem Grund zu.
+func httpSkip(status int) error {
+	reason := reasonError
+	switch status {
+	case http.StatusNotFound, http.StatusGone:
+		reason = reasonNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		reason = reasonPrivate
+	}
+	return skipErrorf(reason, "HTTP %d", status)
+}
+
+func reasonOf(err error) string {
+	var se *skipError
+	if errors.As(err, &se) {
+		return se.reason
+	}
+	return reasonError
+}
+
+// skip vermerkt eine übersprungene Dependency und loggt sie.
+func (c *common) skip(s skipped) {
+	slog.Warn("übersprungen", "pkg", s.Package, "version", s.Version, "reason", s.Reason, "detail", s.Detail)
+	c.skips = append(c.skips, s)
+	c.emit(skipRecord{Record: recordSkipped, skipped: anonSkip(s)})
+}
+
+// printSkips fasst die Gründe unter der Tabelle zusammen.
+func (c *common) printSkips() {
+	if len(c.skips) == 0 {
+		return
+	}
+	n := map[string]int{}
+	for _, s := range c.skips {
+		n[s.Reason]++
+	}
+	var parts []string
+	for r, k := range n {
+		parts = append(parts, fmt.Sprintf("%s: %d", r, k))
+	}
+	sort.Strings(parts)
+	i18n.Printf("%d übersprungen (%s)\n", len(c.skips), strings.Join(parts, ", "))
+}
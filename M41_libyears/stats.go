@@ -0,0 +1,94 @@
+// stats.go – Verteilungskennzahlen der Lags
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"baa_fs25/shared/i18n"
+)
+
+// lagStats fasst die Lags zusammen. Median und P90 sind robuster als der
+// Mittelwert, den eine einzelne uralte Dependency leicht dominiert.
+type lagStats struct {
+	Count     int     `json:"count"`
+	TotalLag  float64 `json:"total_lag_years"`
+	MeanLag   float64 `json:"mean_lag_years"`
+	MedianLag float64 `json:"median_lag_years"`
+	P90Lag    float64 `json:"p90_lag_years"`
+	MaxLag    float64 `json:"max_lag_years"`
+	Threshold float64 `json:"threshold_years"`
+	Above     int     `json:"above_threshold"`
+	// TotalLagInRange summiert die Lags bis zur neuesten Version innerhalb
+	// der deklarierten Range (nur Dependencies, für die es einen gibt).
+	TotalLagInRange *float64 `json:"total_lag_in_range_years,omitempty"`
+	InRangeCount    int      `json:"in_range_count,omitempty"`
+	EOLCount        int      `json:"eol_count,omitempty"`
+	DeprecatedCount int      `json:"deprecated_count,omitempty"`
+}
+
+// computeStats berechnet die Kennzahlen; Perzentile nach Nearest-Rank.
+func computeStats(deps []dep, threshold float64) lagStats {
+	s := lagStats{Count: len(deps), Threshold: threshold}
+	if len(deps) == 0 {
+		return s
+	}
+	lags := make([]float64, len(deps))
+	for i, d := range deps {
+		lags[i] = d.Lag
+		s.TotalLag += d.Lag
+		if d.Lag > threshold {
+			s.Above++
+		}
+		if d.EOL != "" {
+			s.EOLCount++
+		}
+		if d.Deprecated != "" {
+			s.DeprecatedCount++
+		}
+		if d.LagInRange != nil {
+			if s.TotalLagInRange == nil {
+				s.TotalLagInRange = new(float64)
+			}
+			*s.TotalLagInRange += *d.LagInRange
+			s.InRangeCount++
+		}
+	}
+	sort.Float64s(lags)
+	n := len(lags)
+	s.MeanLag = s.TotalLag / float64(n)
+	if n%2 == 1 {
+		s.MedianLag = lags[n/2]
+	} else {
+		s.MedianLag = (lags[n/2-1] + lags[n/2]) / 2
+	}
+	s.P90Lag = lags[int(math.Ceil(0.9*float64(n)))-1]
+	s.MaxLag = lags[n-1]
+	return s
+}
+
+// printStats gibt die Verteilung unter der TOTAL-Zeile aus.
+func (c *common) printStats(deps []dep) {
+	defer c.printSkips()
+	defer printExceptions(c.exceptionStates(deps))
+	s := computeStats(dedupeDeps(c.counted(deps)), c.threshold)
+	if kinds := c.kindSummaries(deps); kinds != nil {
+		defer printKinds(kinds)
+	}
+	if s.Count == 0 {
+		return
+	}
+	i18n.Printf("Median %.2f  |  P90 %.2f  |  Max %.2f  |  %d/%d über %.1f Jahr(en)\n",
+		s.MedianLag, s.P90Lag, s.MaxLag, s.Above, s.Count, s.Threshold)
+	if s.TotalLagInRange != nil {
+		i18n.Printf("Innerhalb der Range erreichbar: %.2f von %.2f (%d Pakete)\n",
+			*s.TotalLagInRange, s.TotalLag, s.InRangeCount)
+	}
+	if c.eol {
+		fmt.Printf("EOL: %d  |  deprecated: %d\n", s.EOLCount, s.DeprecatedCount)
+	}
+	if c.groupBy != "" {
+		printGroups(groupDeps(c.eco, deps))
+	}
+}
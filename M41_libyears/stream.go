@@ -0,0 +1,117 @@
+// stream.go – --format jsonl: ein JSON-Objekt pro Zeile, sobald eine
+// Dependency ausgewertet ist, am Ende eine Zusammenfassungszeile
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"baa_fs25/shared/logging"
+	"baa_fs25/shared/provenance"
+)
+
+// Zeilentypen im Feld "record".
+const (
+	recordDep     = "dep"
+	recordSkipped = "skipped"
+	recordSummary = "summary"
+)
+
+// depRecord und skipRecord sind Einzelzeilen; die Felder entsprechen denen
+// im --out-JSON.
+type depRecord struct {
+	Record string `json:"record"`
+	dep
+}
+
+type skipRecord struct {
+	Record string `json:"record"`
+	skipped
+}
+
+// summaryRecord ist die letzte Zeile: result ohne die bereits gestreamten
+// Listen.
+type summaryRecord struct {
+	Record     string            `json:"record"`
+	Schema     string            `json:"schema"`
+	Eco        string            `json:"eco"`
+	Source     []string          `json:"source"`
+	Summary    lagStats          `json:"summary"`
+	Workspaces []wsSummary       `json:"workspaces,omitempty"`
+	Conflicts  []pinConflict     `json:"conflicts,omitempty"`
+	Duplicates []duplicate       `json:"duplicates,omitempty"`
+	Exceptions []exceptionStatus `json:"exceptions,omitempty"`
+	Groups     []groupSummary    `json:"groups,omitempty"`
+	Runtime    []runtimeLag      `json:"runtime,omitempty"`
+	Skipped    int               `json:"skipped"`
+	Coverage   float64           `json:"coverage"`
+	AsOf       *time.Time        `json:"as_of,omitempty"`
+
+	Provenance *provenance.Info `json:"provenance,omitempty"`
+}
+
+// setupStream prüft --format. Bei jsonl nach stdout wird die Tabelle
+// unterdrückt, damit die Ausgabe direkt in jq/duckdb gepipt werden kann.
+func (c *common) setupStream() {
+	switch c.format {
+	case "json":
+		return
+	case "jsonl":
+	default:
+		logging.Usage("ungültiges --format (erlaubt: json, jsonl)", "value", c.format)
+	}
+	if c.out == "" {
+		c.out = "-"
+	}
+	if c.out != "-" {
+		return
+	}
+	c.stdout = os.Stdout
+	if null, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0); err == nil {
+		os.Stdout = null
+	}
+}
+
+// emit schreibt eine Zeile, sofern --format jsonl gesetzt ist. Die Datei
+// wird beim ersten Eintrag eines Laufs angelegt.
+func (c *common) emit(v any) {
+	if c.format != "jsonl" {
+		return
+	}
+	if c.stream == nil {
+		if c.out == "-" {
+			c.stream = c.stdout
+		} else {
+			f, err := os.Create(c.out)
+			if err != nil {
+				logging.Fatal("JSONL-Ausgabe fehlgeschlagen", "file", c.out, "err", err)
+			}
+			c.stream = f
+		}
+	}
+	// ungepuffert: jede Zeile ist sofort für den Leser sichtbar
+	if err := json.NewEncoder(c.stream).Encode(v); err != nil {
+		logging.Fatal("JSONL-Ausgabe fehlgeschlagen", "file", c.out, "err", err)
+	}
+}
+
+func (c *common) emitDep(d dep) { c.emit(depRecord{Record: recordDep, dep: anonDep(d)}) }
+
+// finishStream schreibt die Zusammenfassung und schließt die Datei; bei
+// --watch beginnt der nächste Lauf eine neue.
+func (c *common) finishStream(res result) {
+	c.emit(summaryRecord{
+		Record: recordSummary, Schema: res.Schema, Eco: res.Eco, Source: res.Source, Summary: res.Summary,
+		Workspaces: res.Workspaces, Conflicts: res.Conflicts, Duplicates: res.Duplicates, Exceptions: res.Exceptions, Groups: res.Groups, Runtime: res.Runtime,
+		Skipped: len(res.Skipped), Coverage: res.Coverage, AsOf: res.AsOf, Provenance: res.Provenance,
+	})
+	if f, ok := c.stream.(io.Closer); ok && c.out != "-" {
+		if err := f.Close(); err != nil {
+			slog.Error("JSONL-Ausgabe nicht geschlossen", "file", c.out, "err", err)
+		}
+	}
+	c.stream = nil
+}
@@ -0,0 +1,355 @@
+// tui.go – --tui: Dependencies nach Lag im Terminal durchsehen; Enter zeigt
+// die Release-Historie eines Pakets (alle Versionen mit Datum aus der
+// Registry) für die Upgrade-Planung
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"baa_fs25/shared/i18n"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/mod/module"
+)
+
+// release ist eine veröffentlichte Version eines Pakets.
+type release struct {
+	Version string
+	Date    time.Time
+}
+
+// releaseHistory holt alle Releases von pkg, neueste zuerst. Mit --as-of
+// fehlen die Releases nach dem Stichtag.
+func releaseHistory(eco, pkg string) ([]release, error) {
+	var reg historyRegistry
+	switch eco {
+	case "npm":
+		reg = npmRegistry
+	case "py":
+		reg = pypiRegistry
+	case "go":
+		esc, err := module.EscapePath(pkg)
+		if err != nil {
+			return nil, err
+		}
+		pkg, reg = esc, goProxy
+	default:
+		return nil, fmt.Errorf("keine Release-Historie für %s", eco)
+	}
+	vers, err := reg.Versions(pkg)
+	if err != nil {
+		return nil, err
+	}
+	var out []release
+	for _, v := range vers {
+		t, err := reg.ReleaseTime(pkg, v)
+		if err != nil || !visible(t) {
+			continue
+		}
+		out = append(out, release{Version: v, Date: t})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Date.After(out[j].Date) })
+	return out, nil
+}
+
+// tuiSorts sind die Sortierungen, die s durchläuft (wie --sort).
+var tuiSorts = []string{"lag", "name", "age"}
+
+// historyMsg liefert eine geladene Release-Historie an das Modell.
+type historyMsg struct {
+	pkg  string
+	rels []release
+	err  error
+}
+
+type tuiModel struct {
+	eco     string
+	deps    []dep
+	summary lagStats
+	sortBy  int // Index in tuiSorts
+	cursor  int
+	offset  int
+	width   int
+	height  int
+	// Drill-down: open ist die gewählte Dependency, history die geladenen
+	// Historien je Paket (eine Abfrage zurzeit, die Registry-Caches sind
+	// nicht nebenläufig).
+	open    *dep
+	loading bool
+	history map[string][]release
+	errs    map[string]error
+	hcursor int
+	hoffset int
+}
+
+// runTUI öffnet das Dashboard für das Ergebnis res und kehrt zurück, wenn
+// es geschlossen wird.
+func (c *common) runTUI(res result) error {
+	if fi, err := os.Stdout.Stat(); err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return errors.New("stdout ist kein Terminal")
+	}
+	m := &tuiModel{eco: c.eco, deps: append([]dep(nil), res.Deps...), summary: res.Summary,
+		width: 100, height: 30, history: map[string][]release{}, errs: map[string]error{}}
+	m.sort()
+	_, err := tea.NewProgram(m, tea.WithAltScreen()).Run()
+	return err
+}
+
+func (m *tuiModel) Init() tea.Cmd { return nil }
+
+func (m *tuiModel) sort() {
+	key := tuiSorts[m.sortBy]
+	sort.SliceStable(m.deps, func(i, j int) bool {
+		a, b := m.deps[i], m.deps[j]
+		switch key {
+		case "name":
+			return a.Package < b.Package
+		case "age":
+			if a.released.IsZero() != b.released.IsZero() {
+				return b.released.IsZero()
+			}
+			return a.released.Before(b.released)
+		}
+		return a.Lag > b.Lag
+	})
+}
+
+// rows ist die Zahl sichtbarer Tabellenzeilen.
+func (m *tuiModel) rows() int {
+	return max(3, m.height-4)
+}
+
+// scroll hält cursor im sichtbaren Ausschnitt ab offset.
+func scroll(cursor, offset, rows int) int {
+	if cursor < offset {
+		return cursor
+	}
+	if cursor >= offset+rows {
+		return cursor - rows + 1
+	}
+	return offset
+}
+
+func (m *tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	case historyMsg:
+		m.loading = false
+		m.history[msg.pkg], m.errs[msg.pkg] = msg.rels, msg.err
+		if m.open != nil {
+			if _, ok := m.history[m.open.Package]; !ok {
+				return m, m.fetch(*m.open) // während des Ladens gewechselt
+			}
+			m.hcursor = m.currentIndex()
+			m.hoffset = scroll(m.hcursor, 0, m.historyRows())
+		}
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" || msg.String() == "q" {
+			return m, tea.Quit
+		}
+		if m.open != nil {
+			return m, m.updateHistory(msg)
+		}
+		n := len(m.deps)
+		switch msg.String() {
+		case "up", "k":
+			m.cursor = max(m.cursor-1, 0)
+		case "down", "j":
+			m.cursor = min(m.cursor+1, max(n-1, 0))
+		case "pgup":
+			m.cursor = max(m.cursor-m.rows(), 0)
+		case "pgdown":
+			m.cursor = min(m.cursor+m.rows(), max(n-1, 0))
+		case "home", "g":
+			m.cursor = 0
+		case "end", "G":
+			m.cursor = max(n-1, 0)
+		case "s":
+			m.sortBy = (m.sortBy + 1) % len(tuiSorts)
+			m.sort()
+		case "enter", "right", "l":
+			if n == 0 {
+				break
+			}
+			d := m.deps[m.cursor]
+			m.open, m.hcursor, m.hoffset = &d, 0, 0
+			if _, ok := m.history[d.Package]; ok {
+				m.hcursor = m.currentIndex()
+				m.hoffset = scroll(m.hcursor, 0, m.historyRows())
+			} else if !m.loading {
+				return m, m.fetch(d)
+			}
+		}
+		m.offset = scroll(m.cursor, m.offset, m.rows())
+	}
+	return m, nil
+}
+
+// fetch lädt die Release-Historie von d im Hintergrund.
+func (m *tuiModel) fetch(d dep) tea.Cmd {
+	m.loading = true
+	eco := m.eco
+	return func() tea.Msg {
+		rels, err := releaseHistory(eco, d.Package)
+		return historyMsg{pkg: d.Package, rels: rels, err: err}
+	}
+}
+
+// updateHistory bedient die Tasten in der Release-Historie.
+func (m *tuiModel) updateHistory(msg tea.KeyMsg) tea.Cmd {
+	n := len(m.history[m.open.Package])
+	switch msg.String() {
+	case "esc", "left", "h", "backspace":
+		m.open = nil
+		return nil
+	case "up", "k":
+		m.hcursor = max(m.hcursor-1, 0)
+	case "down", "j":
+		m.hcursor = min(m.hcursor+1, max(n-1, 0))
+	case "pgup":
+		m.hcursor = max(m.hcursor-m.historyRows(), 0)
+	case "pgdown":
+		m.hcursor = min(m.hcursor+m.historyRows(), max(n-1, 0))
+	case "home", "g":
+		m.hcursor = 0
+	case "end", "G":
+		m.hcursor = max(n-1, 0)
+	}
+	m.hoffset = scroll(m.hcursor, m.hoffset, m.historyRows())
+	return nil
+}
+
+// currentIndex ist die Zeile der verwendeten Version in der Historie (0,
+// wenn sie dort fehlt, z. B. bei Go-Pseudo-Versionen).
+func (m *tuiModel) currentIndex() int {
+	if i, ok := m.findRelease(m.open.Current); ok {
+		return i
+	}
+	return 0
+}
+
+// findRelease sucht ver in der Historie der geöffneten Dependency; Go
+// listet die Versionen mit "v".
+func (m *tuiModel) findRelease(ver string) (int, bool) {
+	for i, r := range m.history[m.open.Package] {
+		if r.Version == ver || r.Version == "v"+ver {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// historyRows ist die Zahl sichtbarer Releases unter dem Kopf der Historie.
+func (m *tuiModel) historyRows() int {
+	return max(3, m.height-8)
+}
+
+var (
+	tuiBold     = lipgloss.NewStyle().Bold(true)
+	tuiSelected = lipgloss.NewStyle().Reverse(true)
+	tuiDim      = lipgloss.NewStyle().Faint(true)
+)
+
+const tuiDepFmt = "%-32s %-14s %-14s %8s  %s"
+
+func (m *tuiModel) View() string {
+	if m.open != nil {
+		return m.historyView()
+	}
+	var b strings.Builder
+	title := i18n.Sprintf("libyears %s – %d Dependencies, Lag gesamt %.2f Jahre (Ø %.2f, max %.2f), sortiert nach %s",
+		m.eco, len(m.deps), m.summary.TotalLag, m.summary.MeanLag, m.summary.MaxLag, tuiSorts[m.sortBy])
+	b.WriteString(tuiBold.Render(m.clip(title)) + "\n")
+	b.WriteString(tuiBold.Render(m.clip(fmt.Sprintf(tuiDepFmt, i18n.T("Package"), i18n.T("Current"), i18n.T("Latest"), "Lag(yr)", ""))) + "\n")
+	for i := m.offset; i < m.offset+m.rows(); i++ {
+		if i >= len(m.deps) {
+			b.WriteString("\n")
+			continue
+		}
+		line := m.clip(m.depRow(m.deps[i]))
+		if i == m.cursor {
+			line = tuiSelected.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+	b.WriteString(tuiDim.Render(m.clip(i18n.T("↑/↓ bewegen  Enter Release-Historie  s sortieren (lag, name, age)  q beenden"))))
+	return b.String()
+}
+
+func (m *tuiModel) depRow(d dep) string {
+	var notes []string
+	if d.Workspace != "" {
+		notes = append(notes, d.Workspace)
+	}
+	if d.Kind != "" && d.Kind != kindRuntime {
+		notes = append(notes, d.Kind)
+	}
+	if d.EOL != "" || d.Deprecated != "" {
+		notes = append(notes, "EOL/deprecated")
+	}
+	return fmt.Sprintf(tuiDepFmt, d.Package, d.Current, d.Latest, fmt.Sprintf("%.2f", d.Lag), strings.Join(notes, ", ")+exceptionMark(d))
+}
+
+// historyView zeigt die Releases eines Pakets. "Lag danach" ist der Lag,
+// der nach einem Update auf diese Version bliebe.
+func (m *tuiModel) historyView() string {
+	d := m.open
+	var b strings.Builder
+	b.WriteString(tuiBold.Render(m.clip(i18n.Sprintf("%s – verwendet %s, neueste %s, Lag %.2f Jahre", d.Package, d.Current, d.Latest, d.Lag))) + "\n")
+	rels, ok := m.history[d.Package]
+	switch {
+	case m.loading && !ok:
+		b.WriteString(i18n.T("Release-Historie wird geladen …") + "\n")
+	case m.errs[d.Package] != nil:
+		b.WriteString(m.clip(i18n.Sprintf("Release-Historie nicht verfügbar: %v", m.errs[d.Package])) + "\n")
+	default:
+		var latest time.Time
+		if i, ok := m.findRelease(d.Latest); ok {
+			latest = rels[i].Date
+		}
+		if newer, ok := m.findRelease(d.Current); ok {
+			// absteigend sortiert: alle Zeilen davor sind neuer
+			b.WriteString(m.clip(i18n.Sprintf("%d Releases, %d neuer als die verwendete Version", len(rels), newer)) + "\n\n")
+		} else {
+			b.WriteString(m.clip(i18n.Sprintf("%d Releases", len(rels))) + "\n\n")
+		}
+		b.WriteString(tuiBold.Render(m.clip(fmt.Sprintf("%-24s %-10s %10s  %s", "Version", i18n.T("Datum"), i18n.T("Lag danach"), ""))) + "\n")
+		for i := m.hoffset; i < m.hoffset+m.historyRows() && i < len(rels); i++ {
+			r := rels[i]
+			after := "-"
+			if !latest.IsZero() && !r.Date.After(latest) {
+				after = fmt.Sprintf("%.2f", latest.Sub(r.Date).Hours()/24/365.25)
+			}
+			mark := ""
+			switch r.Version {
+			case d.Current, "v" + d.Current:
+				mark = i18n.T("← verwendet")
+			case d.Latest, "v" + d.Latest:
+				mark = i18n.T("← neueste")
+			case d.LatestInRange:
+				mark = i18n.T("← neueste in der Range")
+			}
+			line := m.clip(fmt.Sprintf("%-24s %-10s %10s  %s", r.Version, r.Date.Format("2006-01-02"), after, mark))
+			if i == m.hcursor {
+				line = tuiSelected.Render(line)
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+	b.WriteString(tuiDim.Render(m.clip(i18n.T("↑/↓ bewegen  Esc zurück  q beenden"))))
+	return b.String()
+}
+
+// clip kürzt s auf die Terminalbreite; Escape-Sequenzen bleiben erhalten.
+func (m *tuiModel) clip(s string) string {
+	if m.width <= 0 {
+		return s
+	}
+	return lipgloss.NewStyle().MaxWidth(m.width).Render(s)
+}
@@ -0,0 +1,158 @@
+// watch.go – --watch: Analyse periodisch wiederholen und nur das Delta
+// zum vorigen Lauf ausgeben (optional per --notify-webhook)
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"baa_fs25/shared/exitcode"
+	"baa_fs25/shared/logging"
+	"baa_fs25/shared/report"
+)
+
+// watchDelta ist die Änderung zwischen zwei Läufen.
+type watchDelta struct {
+	PrevTotal, Total float64
+	NewOutdated      []dep // jetzt über --threshold, vorher nicht (oder neu)
+	Resolved         []dep // vorher über --threshold, jetzt nicht mehr (oder entfernt)
+}
+
+func (d watchDelta) empty() bool {
+	return len(d.NewOutdated) == 0 && len(d.Resolved) == 0 && fmt.Sprintf("%.2f", d.PrevTotal) == fmt.Sprintf("%.2f", d.Total)
+}
+
+// watch führt run einmal aus bzw. bei --watch in diesem Abstand immer
+// wieder. Ab dem zweiten Lauf wird die Tabelle unterdrückt und nur das Delta
+// ausgegeben. Ohne --watch bzw. bei --notify-threshold wird auch nach dem
+// ersten Lauf benachrichtigt (Vergleich mit --base). Ein Fehler im ersten
+// Lauf beendet das Programm; spätere Läufe (etwa bei einem Netzausfall)
+// werden nur protokolliert, verglichen wird dann mit dem letzten
+// erfolgreichen Lauf.
+func (c *common) watch(run func() error) {
+	if err := run(); err != nil {
+		logging.Fatal("Analyse fehlgeschlagen", "err", err)
+	}
+	if c.watchEvery <= 0 || c.notifyAt > 0 {
+		c.notify(c.loadBase())
+	}
+	if c.tui && c.last != nil {
+		if err := c.runTUI(*c.last); err != nil {
+			logging.Fatal("--tui nicht startbar", "err", err)
+		}
+	}
+	if c.watchEvery <= 0 {
+		return
+	}
+	prev := c.last
+	for {
+		slog.Info("nächster Lauf", "in", c.watchEvery.String())
+		time.Sleep(c.watchEvery)
+		c.reset()
+		if err := quiet(run); err != nil {
+			slog.Error("Lauf fehlgeschlagen, nächster Versuch im nächsten Intervall", "err", err)
+			continue
+		}
+		if prev != nil && c.last != nil {
+			d := diffRuns(*prev, *c.last, c.threshold)
+			c.reportDelta(d, prev)
+		}
+		if c.last != nil {
+			prev = c.last
+		}
+	}
+}
+
+// reset verwirft den Zustand des vorigen Laufs, damit neue Releases
+// gesehen werden und Übersprungenes nur in der eigenen Runde zählt.
+func (c *common) reset() {
+	c.skips, c.conflicts, c.dups, c.runtime, c.last = nil, nil, nil, nil, nil
+	exitcode.Reset()
+	clear(npmCache)
+	clear(eolCache)
+	resetRegistries()
+}
+
+// quiet führt f mit stdout nach /dev/null aus.
+func quiet(f func() error) error {
+	null, err := os.Open(os.DevNull)
+	if err != nil {
+		return f()
+	}
+	defer null.Close()
+	stdout := os.Stdout
+	os.Stdout = null
+	defer func() { os.Stdout = stdout }()
+	return f()
+}
+
+// diffRuns vergleicht zwei Läufe; Schlüssel ist Workspace + Paket.
+func diffRuns(prev, cur result, threshold float64) watchDelta {
+	d := watchDelta{PrevTotal: prev.Summary.TotalLag, Total: cur.Summary.TotalLag}
+	key := func(x dep) string { return x.Workspace + "\x00" + x.Package }
+	old := map[string]dep{}
+	for _, x := range prev.Deps {
+		old[key(x)] = x
+	}
+	for _, x := range cur.Deps {
+		o, ok := old[key(x)]
+		delete(old, key(x))
+		if x.Lag > threshold && (!ok || o.Lag <= threshold) {
+			d.NewOutdated = append(d.NewOutdated, x)
+		}
+		if ok && o.Lag > threshold && x.Lag <= threshold {
+			d.Resolved = append(d.Resolved, x)
+		}
+	}
+	for _, o := range old {
+		if o.Lag > threshold {
+			d.Resolved = append(d.Resolved, o)
+		}
+	}
+	sort.Slice(d.NewOutdated, func(i, j int) bool { return d.NewOutdated[i].Lag > d.NewOutdated[j].Lag })
+	sort.Slice(d.Resolved, func(i, j int) bool { return d.Resolved[i].Package < d.Resolved[j].Package })
+	return d
+}
+
+// reportDelta gibt das Delta aus und postet es bei --notify-webhook; mit
+// --notify-threshold stattdessen die Zusammenfassung, solange die Schwelle
+// überschritten ist.
+func (c *common) reportDelta(d watchDelta, prev *result) {
+	if c.notifyAt > 0 {
+		defer c.notify(prev)
+	}
+	if d.empty() {
+		slog.Info("keine Änderung", "total_lag", fmt.Sprintf("%.2f", d.Total))
+		return
+	}
+	text := deltaText(c.eco, d, c.threshold)
+	fmt.Printf("\n[%s] %s", time.Now().Format("2006-01-02 15:04"), text)
+	slog.Info("Delta", "total_lag", fmt.Sprintf("%.2f", d.Total), "new_outdated", len(d.NewOutdated), "resolved", len(d.Resolved))
+	if c.webhook != "" && c.notifyAt == 0 {
+		if err := (report.Webhook{URL: c.webhook}).Post(text); err != nil {
+			slog.Error("Webhook fehlgeschlagen", "err", err)
+		}
+	}
+}
+
+func deltaText(eco string, d watchDelta, threshold float64) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "libyears (%s): Gesamt-Lag %.2f → %.2f (%s)\n", eco, d.PrevTotal, d.Total, report.Delta(d.PrevTotal, d.Total, 2))
+	if len(d.NewOutdated) > 0 {
+		fmt.Fprintf(&b, "Neu über %.1f Jahr(en):\n", threshold)
+		for _, x := range d.NewOutdated {
+			fmt.Fprintf(&b, "  • %s %s → %s (%.2f)\n", x.Package, x.Current, x.Latest, x.Lag)
+		}
+	}
+	if len(d.Resolved) > 0 {
+		b.WriteString("Behoben:\n")
+		for _, x := range d.Resolved {
+			fmt.Fprintf(&b, "  • %s\n", x.Package)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"baa_fs25/shared/registry"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// -----------------------------------------------------------------------------
+// ---------- Bot-PRs (Dependabot/Renovate) -------------------------------------
+// -----------------------------------------------------------------------------
+
+// botPRs löst Merges von Dependabot/Renovate über die GitHub-API zu ihrem PR
+// auf, um neben der MTTU die Zeit vom Öffnen bis zum Merge zu erfassen –
+// oft der eigentliche Engpass.
+type botPRs struct {
+	gh    *registry.GitHub
+	slug  string
+	cache map[plumbing.Hash]*registry.PullRequest
+	off   bool // nach erschöpftem Rate-Limit keine weiteren Abfragen
+}
+
+// bots ist nil ohne --bot-pr oder wenn das Repo nicht auf GitHub liegt.
+var bots *botPRs
+
+// newBotPRs liefert nil, wenn das Repo nicht auf GitHub liegt.
+func newBotPRs(slug string) *botPRs {
+	if slug == "" {
+		return nil
+	}
+	token := os.Getenv("GH_TOKEN")
+	if token == "" {
+		slog.Warn("--bot-pr ohne $GH_TOKEN – die GitHub-API erlaubt nur 60 Anfragen pro Stunde")
+	}
+	return &botPRs{gh: &registry.GitHub{Token: token}, slug: slug, cache: map[plumbing.Hash]*registry.PullRequest{}}
+}
+
+// prRefRx findet die PR-Nummer in "Merge pull request #12 from …" bzw. im
+// Squash-Titel "Bump x from 1 to 2 (#12)".
+var prRefRx = regexp.MustCompile(`(?:Merge pull request #|\(#)(\d+)`)
+
+// botAuthors sind die GitHub-Accounts von Dependabot und Renovate; sie
+// stehen so im Autor-Namen und vor "@users.noreply.github.com".
+var botAuthors = []string{"dependabot[bot]", "renovate[bot]"}
+
+// botMergeRx erkennt den Merge eines Bot-Branches, z. B. "Merge pull request
+// #12 from acme/dependabot/npm_and_yarn/lodash-4.17.21".
+var botMergeRx = regexp.MustCompile(`^Merge pull request #\d+ from (?:[\w.-]+/)?(?:dependabot|renovate)/`)
+
+// isBotCommit erkennt Bot-Commits am Autor (Squash-/Rebase-Merge) oder an
+// der Merge-Message des Bot-Branches. Menschliche Commits wie "Update
+// renovate config" zählen nicht.
+func isBotCommit(c *object.Commit) bool {
+	name, email := strings.ToLower(c.Author.Name), strings.ToLower(c.Author.Email)
+	for _, a := range botAuthors {
+		if name == a || strings.HasSuffix(email, a+"@users.noreply.github.com") {
+			return true
+		}
+	}
+	title, _, _ := strings.Cut(c.Message, "\n")
+	return botMergeRx.MatchString(title)
+}
+
+// pr liefert den gemergten Bot-PR zu c oder nil.
+func (b *botPRs) pr(c *object.Commit) *registry.PullRequest {
+	if b == nil || b.off || !isBotCommit(c) {
+		return nil
+	}
+	if pr, ok := b.cache[c.Hash]; ok {
+		return pr
+	}
+	pr, err := b.lookup(c)
+	switch {
+	case errors.Is(err, registry.ErrRateLimited):
+		slog.Warn("GitHub-Rate-Limit erreicht – keine weiteren Bot-PR-Abfragen", "err", err)
+		b.off = true
+	case err != nil:
+		slog.Debug("Bot-PR nicht abrufbar", "commit", c.Hash.String()[:7], "err", err)
+	}
+	b.cache[c.Hash] = pr
+	return pr
+}
+
+func (b *botPRs) lookup(c *object.Commit) (*registry.PullRequest, error) {
+	title, _, _ := strings.Cut(c.Message, "\n")
+	if m := prRefRx.FindStringSubmatch(title); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		pr, err := b.gh.PullRequest(b.slug, n)
+		if err != nil {
+			return nil, err
+		}
+		if pr.MergedAt != nil {
+			return &pr, nil
+		}
+	}
+	prs, err := b.gh.CommitPulls(b.slug, c.Hash.String())
+	if err != nil {
+		return nil, err
+	}
+	for i := range prs {
+		if prs[i].MergedAt != nil {
+			return &prs[i], nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestIsBotCommit(t *testing.T) {
+	cases := []struct {
+		name, email, msg string
+		want             bool
+	}{
+		{"dependabot[bot]", "49699333+dependabot[bot]@users.noreply.github.com", "Bump lodash from 4.17.20 to 4.17.21 (#12)", true},
+		{"Renovate[bot]", "bot@renovateapp.com", "chore(deps): update x", true},
+		{"Jane Doe", "jane@example.com", "Merge pull request #7 from acme/dependabot/npm_and_yarn/lodash-4.17.21\n\nBump lodash", true},
+		{"Jane Doe", "jane@example.com", "Merge pull request #8 from renovate/go-x", true},
+		{"Jane Doe", "jane@example.com", "Update renovate config", false},
+		{"Jane Doe", "jane@example.com", "Merge pull request #9 from acme/feature/dependabot-docs", false},
+		{"ci[bot]", "ci@example.com", "Bump version", false},
+	}
+	for _, tc := range cases {
+		c := &object.Commit{Author: object.Signature{Name: tc.name, Email: tc.email}, Message: tc.msg}
+		if got := isBotCommit(c); got != tc.want {
+			t.Errorf("isBotCommit(%q, %q) = %v, erwartet %v", tc.name, tc.msg, got, tc.want)
+		}
+	}
+}
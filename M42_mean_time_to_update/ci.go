@@ -0,0 +1,45 @@
+package main
+
+import (
+	"log/slog"
+
+	"baa_fs25/shared/gitwalk"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// -----------------------------------------------------------------------------
+// ---------- CI-Änderungen im Update-Commit ------------------------------------
+// -----------------------------------------------------------------------------
+
+// ciPaths sind die CI-Konfigurationen (Pathspecs wie ecosystem.paths). Ändert
+// ein Update-Commit eine davon mit, musste für das Update vermutlich die
+// Pipeline angepasst werden – ein Hinweis auf einen Breaking-Upgrade.
+var ciPaths = []string{workflowDir, ".gitlab-ci.yml", ".circleci", ".travis.yml", "azure-pipelines.yml", "Jenkinsfile"}
+
+// ciOff ist gesetzt, wenn die Commits nur die Manifeste enthalten
+// (--remote-api); ci_change bleibt dann leer.
+var ciOff bool
+
+var ciCache = map[plumbing.Hash]*bool{}
+
+// ciChange meldet, ob c auch CI-Konfiguration ändert. nil, wenn das nicht
+// feststellbar ist (--remote-api) oder das Ökosystem selbst die CI-Dateien
+// auswertet (gha).
+func ciChange(e ecosystem, c *object.Commit) *bool {
+	if ciOff || e.name == "gha" {
+		return nil
+	}
+	if v, ok := ciCache[c.Hash]; ok {
+		return v
+	}
+	files, err := gitwalk.Changed(c, ciPaths, nil)
+	if err != nil {
+		slog.Debug("CI-Änderungen nicht ermittelbar", "commit", c.Hash.String()[:7], "err", err)
+		ciCache[c.Hash] = nil
+		return nil
+	}
+	changed := len(files) > 0
+	ciCache[c.Hash] = &changed
+	return &changed
+}
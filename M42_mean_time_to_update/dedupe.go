@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+// ---------- Bumps zusammenfassen (--dedupe-window) ----------------------------
+// -----------------------------------------------------------------------------
+
+// dedupeWindow ist das Fenster von --dedupe-window (0 = aus).
+var dedupeWindow time.Duration
+
+// parseWindow versteht "7d" sowie Go-Dauern wie "36h".
+func parseWindow(v string) (time.Duration, error) {
+	if n, ok := strings.CutSuffix(v, "d"); ok {
+		days, err := strconv.Atoi(n)
+		if err != nil || days < 0 {
+			return 0, fmt.Errorf("ungültiges Fenster %q (z. B. 7d oder 36h)", v)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("ungültiges Fenster %q (z. B. 7d oder 36h)", v)
+	}
+	return d, nil
+}
+
+// dedupeBumps fasst aufeinanderfolgende Bumps derselben Dependency (und
+// Datei) zu einem logischen Update zusammen, wenn der nächste Bump höchstens
+// window nach dem vorigen committet wurde und auf dessen Version aufsetzt –
+// typisch für Bot-Churn (1.1 → 1.2 → 1.3 binnen Tagen). Das Update reicht von
+// der ersten alten bis zur letzten neuen Version; Verzögerung, Commit und
+// übrige Felder stammen vom letzten Bump, denn erst mit ihm ist das
+// logische Update abgeschlossen; ci_change gilt, wenn einer der Bumps CI
+// geändert hat. ds muss chronologisch sortiert sein.
+func dedupeBumps(ds []delay, window time.Duration) []delay {
+	out := make([]delay, 0, len(ds))
+	last := map[string]int{} // dep+Datei → Index in out
+	for _, d := range ds {
+		key := d.File + fileSep + d.Dep
+		if i, ok := last[key]; ok {
+			p := out[i]
+			if p.NewVer == d.OldVer && d.CommitDate.Sub(p.CommitDate) <= window {
+				bumps := max(p.Bumps, 1) + 1
+				if p.Skipped != nil && d.Skipped != nil {
+					// die Zwischenversion p.NewVer zählt im logischen Update als übersprungen
+					n := *p.Skipped + *d.Skipped + 1
+					d.Skipped = &n
+				} else {
+					d.Skipped = nil
+				}
+				if p.CIChange != nil && *p.CIChange {
+					d.CIChange = p.CIChange
+				}
+				d.OldVer, d.Bumps, d.FirstCommit = p.OldVer, bumps, p.FirstCommit
+				if d.FirstCommit == "" {
+					d.FirstCommit = p.CommitHash
+				}
+				out[i] = d
+				continue
+			}
+		}
+		last[key] = len(out)
+		out = append(out, d)
+	}
+	return out
+}
+
+// rawSummary sind die Kennzahlen vor dem Zusammenfassen (nur --dedupe-window).
+type rawSummary struct {
+	Window     string  `json:"window"`
+	Updates    int     `json:"updates"`
+	MeanDays   float64 `json:"mean_days"`
+	MedianDays float64 `json:"median_days"`
+}
+
+func newRawSummary(window string, ds []delay) *rawSummary {
+	vals := make([]float64, len(ds))
+	for i, d := range ds {
+		vals[i] = d.Days
+	}
+	return &rawSummary{Window: window, Updates: len(ds), MeanDays: mean(vals), MedianDays: median(vals)}
+}
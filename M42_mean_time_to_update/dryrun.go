@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"baa_fs25/shared/anon"
+	"baa_fs25/shared/gitwalk"
+	"baa_fs25/shared/i18n"
+	"baa_fs25/shared/provenance"
+	"baa_fs25/shared/schema"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// -----------------------------------------------------------------------------
+// ---------- Dry-Run (--dry-run) -----------------------------------------------
+// -----------------------------------------------------------------------------
+
+// plannedCommit ist ein Commit, den analyze mit den aktuellen Flags begehen
+// würde.
+type plannedCommit struct {
+	Eco       string    `json:"eco"`
+	Hash      string    `json:"commit"`
+	Date      time.Time `json:"date"`
+	Manifests []string  `json:"manifests"`
+	// Upgrades zählt die Versionssprünge ohne Registry-Abfrage; analyze
+	// verwirft davon noch die ohne Release-Datum oder > 365 Tage.
+	Upgrades int `json:"upgrades"`
+}
+
+// dryRunResult ist das JSON-Dokument, das --out mit --dry-run schreibt.
+type dryRunResult struct {
+	Schema    string          `json:"schema"`
+	Repo      string          `json:"repo"`
+	Eco       string          `json:"eco"`
+	Scope     scope           `json:"scope"`
+	Commits   []plannedCommit `json:"commits"`
+	From      *time.Time      `json:"from,omitempty"`
+	To        *time.Time      `json:"to,omitempty"`
+	Manifests map[string]int  `json:"manifests"` // Pfad → Anzahl Commits
+	Upgrades  int             `json:"upgrades"`
+	Sample    *sampling       `json:"sample,omitempty"`
+
+	Provenance *provenance.Info `json:"provenance,omitempty"`
+}
+
+// plan begeht dieselben Commits wie analyze (gleiche Stopp-Kriterien), fragt
+// aber keine Registry ab. Bei --changes wird nach N erkannten
+// Versionssprüngen gestoppt – eine Untergrenze für den echten Lauf.
+func plan(src gitwalk.Source, e ecosystem, sc scope) ([]plannedCommit, error) {
+	var since *time.Time
+	if sc.Days > 0 {
+		t := time.Now().AddDate(0, 0, -sc.Days)
+		since = &t
+	}
+	var prev map[string]string
+	var out []plannedCommit
+	upgrades := 0
+
+	err := src.ForEach(e.paths, since, nil, func(c *object.Commit) error {
+		if sc.Commits > 0 && len(out) >= sc.Commits {
+			return storer.ErrStop
+		}
+		files, err := gitwalk.Changed(c, e.paths, excludeGlobs)
+		if err != nil {
+			return err
+		}
+		pc := plannedCommit{Eco: e.name, Hash: c.Hash.String()[:7], Date: normTime(commitTime(c), c), Manifests: files}
+		if curr := e.versions(c); len(curr) > 0 {
+			if sample != nil {
+				prev, _ = parentVersions(e, c)
+			}
+			if prev != nil {
+				for dep, newV := range curr {
+					oldV, ok := prev[dep]
+					if !ok || oldV == newV {
+						continue
+					}
+					// eigene upgrade-Prüfungen fragen Registries ab → jede Änderung zählt
+					if name, _ := splitDepKey(dep); e.upgrade != nil || e.isUpgrade(name, oldV, newV) {
+						pc.Upgrades++
+					}
+				}
+			}
+			prev = curr
+		}
+		out = append(out, pc)
+		upgrades += pc.Upgrades
+		if sc.Changes > 0 && upgrades >= sc.Changes {
+			return storer.ErrStop
+		}
+		return nil
+	})
+	return out, err
+}
+
+// anonymize pseudonymisiert Repo und Commits (--anonymize).
+func (r *dryRunResult) anonymize() {
+	if !anon.Enabled() {
+		return
+	}
+	r.Repo = anon.Repo(r.Repo)
+	for i := range r.Commits {
+		r.Commits[i].Hash = anon.Commit(r.Commits[i].Hash)
+	}
+}
+
+// newDryRunResult fasst die geplanten Commits zusammen.
+func newDryRunResult(repo, eco string, commits []plannedCommit) dryRunResult {
+	res := dryRunResult{
+		Schema: schema.ID("mttu-dry-run"), Repo: repo, Eco: eco, Scope: currentScope(), Commits: commits, Manifests: map[string]int{}, Sample: sample,
+		Provenance: provenance.Get(),
+	}
+	// bei mehreren Ökosystemen stehen die Commits je Ökosystem hintereinander
+	for i, c := range commits {
+		if res.From == nil || c.Date.Before(*res.From) {
+			res.From = &commits[i].Date
+		}
+		if res.To == nil || c.Date.After(*res.To) {
+			res.To = &commits[i].Date
+		}
+		for _, f := range c.Manifests {
+			res.Manifests[f]++
+		}
+		res.Upgrades += c.Upgrades
+	}
+	return res
+}
+
+func printDryRun(res dryRunResult) {
+	i18n.Printf("\nDry-Run für %s (%s) – keine Registry-Abfragen\n", res.Repo, res.Eco)
+	for _, c := range res.Commits {
+		fmt.Printf("%s  %s  %3d Upgrades  %s\n", c.Hash, c.Date.Format("2006-01-02"), c.Upgrades, strings.Join(c.Manifests, ", "))
+	}
+	i18n.Printf("\nCommits                : %d\n", len(res.Commits))
+	if s := res.Sample; s != nil {
+		s.print()
+	}
+	if res.From != nil {
+		i18n.Printf("Zeitraum               : %s – %s\n", res.From.Format("2006-01-02"), res.To.Format("2006-01-02"))
+	}
+	i18n.Printf("Versionssprünge        : %d (vor Registry-Filter)\n", res.Upgrades)
+	paths := make([]string, 0, len(res.Manifests))
+	for p := range res.Manifests {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	i18n.Println("Manifeste:")
+	for _, p := range paths {
+		i18n.Printf("  %-40s %4d Commits\n", p, res.Manifests[p])
+	}
+}
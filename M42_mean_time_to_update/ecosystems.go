@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"baa_fs25/shared/gitwalk"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// -----------------------------------------------------------------------------
+// ---------- Registrierte Ökosysteme -------------------------------------------
+// -----------------------------------------------------------------------------
+
+// Jedes Ökosystem meldet sich in einem init() seiner Datei an. Forks ergänzen
+// eigene Ökosysteme (z. B. interne Artefakt-Stores) als zusätzliche Datei –
+// bei Bedarf hinter einem Build-Tag –, ohne getAnalyzer anzufassen; --eco all
+// nimmt sie automatisch mit:
+//
+//	//go:build artifactory
+//
+//	func init() { registerEcosystem("artifactory", artifactoryEco) }
+var (
+	ecosystems = map[string]func() ecosystem{}
+	ecoAliases = map[string]string{} // Alias → Name
+)
+
+// registerEcosystem meldet den Konstruktor newEco unter name und den
+// optionalen Aliasen an. Doppelte Namen sind ein Programmierfehler.
+func registerEcosystem(name string, newEco func() ecosystem, aliases ...string) {
+	if _, dup := ecosystems[name]; dup {
+		panic("Ökosystem doppelt registriert: " + name)
+	}
+	ecosystems[name] = newEco
+	for _, a := range aliases {
+		ecoAliases[a] = name
+	}
+}
+
+// ecosystemNames listet die registrierten Ökosysteme für Hilfe und Fehler.
+func ecosystemNames() string {
+	names := make([]string, 0, len(ecosystems))
+	for n := range ecosystems {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return strings.Join(names, " | ")
+}
+
+// getAnalyzers liefert die mit --eco gewählten Ökosysteme: einen Namen,
+// eine kommagetrennte Liste ("go,npm") oder "all" (alle registrierten; main
+// behält davon nur die, deren Manifeste im Repo vorkommen).
+func getAnalyzers() ([]ecosystem, error) {
+	names := strings.Split(eco, ",")
+	if eco == "all" {
+		names = strings.Split(ecosystemNames(), " | ")
+	}
+	var out []ecosystem
+	seen := map[string]bool{}
+	for _, n := range names {
+		e, err := getAnalyzer(strings.TrimSpace(n))
+		if err != nil {
+			return nil, err
+		}
+		if !seen[e.name] {
+			seen[e.name] = true
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+// getAnalyzer liefert das Ökosystem name (oder einen Alias davon).
+func getAnalyzer(name string) (ecosystem, error) {
+	given := name
+	if n, ok := ecoAliases[name]; ok {
+		name = n
+	}
+	newEco, ok := ecosystems[name]
+	if !ok {
+		return ecosystem{}, fmt.Errorf("unbekanntes Ökosystem %q – erlaubt: %s, all", given, ecosystemNames())
+	}
+	e := newEco()
+	if e.skips == nil { // npm legt seine eigene Map an, s. npmEco
+		e.skips = specSkips{}
+	}
+	return e, nil
+}
+
+// applicable meldet, ob im HEAD-Stand von r ein Manifest von e liegt (für
+// --eco all).
+func applicable(r *git.Repository, e ecosystem) bool {
+	head, err := r.Head()
+	if err != nil {
+		return false
+	}
+	c, err := r.CommitObject(head.Hash())
+	if err != nil {
+		return false
+	}
+	tree, err := c.Tree()
+	if err != nil {
+		return false
+	}
+	found := false
+	_ = tree.Files().ForEach(func(f *object.File) error {
+		if gitwalk.Match(f.Name, e.paths) && !gitwalk.Excluded(f.Name, excludeGlobs) {
+			found = true
+			return storer.ErrStop
+		}
+		return nil
+	})
+	return found
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"log/slog"
+	"strings"
+
+	"baa_fs25/shared/gitwalk"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// -----------------------------------------------------------------------------
+// ---------- Verschobene Manifeste (--follow) ----------------------------------
+// -----------------------------------------------------------------------------
+
+// followed bildet je Manifest-Name den Commit auf den dort gültigen Pfad ab
+// (nur mit --follow).
+var followed = map[string]map[plumbing.Hash]string{}
+
+// followPath liefert den Pfad, unter dem name in c lag.
+func followPath(c *object.Commit, name string) string {
+	if p, ok := followed[name][c.Hash]; ok {
+		return p
+	}
+	return name
+}
+
+// withFollow verfolgt die einzelnen Manifest-Dateien von e über Renames
+// zurück und ergänzt e.paths um alle früheren Pfade. e.paths[0] bleibt der
+// ursprüngliche Name. Globs und Verzeichnisse werden nicht verfolgt.
+func withFollow(r *git.Repository, e ecosystem) (ecosystem, error) {
+	paths := append([]string(nil), e.paths...)
+	have := map[string]bool{}
+	for _, p := range paths {
+		have[p] = true
+	}
+	for _, name := range e.paths {
+		if strings.ContainsAny(name, "*?[") || strings.HasSuffix(name, "/") {
+			continue
+		}
+		at, hist, err := gitwalk.Follow(r, name, excludeGlobs)
+		if err != nil {
+			return e, err
+		}
+		followed[name] = at
+		for _, p := range hist {
+			if !have[p] {
+				have[p] = true
+				paths = append(paths, p)
+			}
+		}
+		if len(hist) > 1 || hist[0] != name {
+			slog.Info("Manifest verschoben", "name", name, "paths", hist)
+		}
+	}
+	e.paths = paths
+	return e, nil
+}
@@ -0,0 +1,105 @@
+// gha.go – Ökosystem "gha": Versionen der GitHub Actions in
+// .github/workflows/*.yml (uses: owner/action@ref), Release-Daten aus den
+// Tags des Action-Repos.
+
+package main
+
+import (
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"baa_fs25/shared/registry"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"golang.org/x/mod/semver"
+)
+
+const workflowDir = ".github/workflows"
+
+// usesRx: uses: owner/repo[/pfad]@ref  [# v1.2.3]
+var usesRx = regexp.MustCompile(`^\s*(?:-\s*)?uses:\s*["']?([\w.\-]+/[\w.\-]+(?:/[\w.\-/]+)?)@([\w.\-/]+)["']?\s*(?:#\s*(\S+))?`)
+
+var shaRx = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// actionVersions liest alle uses:-Referenzen eines Workflows. Bei SHA-Pins
+// gilt die Version aus dem üblichen Kommentar ("@<sha> # v4.1.1"), ohne
+// Kommentar wird der Eintrag übersprungen.
+func actionVersions(txt string, dst map[string]string) {
+	for _, l := range strings.Split(txt, "\n") {
+		m := usesRx.FindStringSubmatch(l)
+		if m == nil {
+			continue
+		}
+		action, ref := m[1], m[2]
+		if shaRx.MatchString(ref) {
+			if m[3] == "" {
+				continue
+			}
+			ref = m[3]
+		}
+		// Dieselbe Action in mehreren Workflows → höchste Version zählt
+		if old, ok := dst[action]; ok && semver.Compare(canon(old), canon(ref)) >= 0 {
+			continue
+		}
+		dst[action] = ref
+	}
+}
+
+// workflowFiles liefert den Inhalt aller *.yml/*.yaml unter .github/workflows.
+func workflowFiles(c *object.Commit) map[string]string {
+	tree, err := c.Tree()
+	if err != nil {
+		return nil
+	}
+	dir, err := tree.Tree(workflowDir)
+	if err != nil {
+		return nil
+	}
+	files := map[string]string{}
+	for _, e := range dir.Entries {
+		if !e.Mode.IsFile() {
+			continue
+		}
+		if ext := path.Ext(e.Name); ext != ".yml" && ext != ".yaml" {
+			continue
+		}
+		if txt, err := readFileFromCommit(c, workflowDir+"/"+e.Name); err == nil {
+			files[e.Name] = txt
+		}
+	}
+	return files
+}
+
+func init() { registerEcosystem("gha", ghaEco) }
+
+func ghaEco() ecosystem {
+	return ecosystem{
+		name:  "gha",
+		paths: []string{workflowDir},
+		versions: func(c *object.Commit) map[string]string {
+			m := map[string]string{}
+			for _, txt := range workflowFiles(c) {
+				actionVersions(txt, m)
+			}
+			return m
+		},
+		reg: ghaRegistry{gh: &registry.GitHub{Token: os.Getenv("GH_TOKEN")}},
+		parse: func(_, txt string) map[string]string {
+			m := map[string]string{}
+			actionVersions(txt, m)
+			return m
+		},
+	}
+}
+
+// ghaRegistry reduziert "owner/repo/pfad" auf das Repo der Action.
+type ghaRegistry struct {
+	gh *registry.GitHub
+}
+
+func (r ghaRegistry) ReleaseTime(action, ref string) (time.Time, error) {
+	parts := strings.SplitN(action, "/", 3)
+	return r.gh.ReleaseTime(parts[0]+"/"+parts[1], ref)
+}
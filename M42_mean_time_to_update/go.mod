@@ -4,7 +4,10 @@ go 1.23.0
 
 toolchain go1.23.10
 
-require github.com/go-git/go-git/v5 v5.16.2
+require (
+	github.com/go-git/go-git/v5 v5.16.2
+	github.com/klauspost/compress v1.17.9
+)
 
 require (
 	dario.cat/mergo v1.0.0 // indirect
@@ -28,3 +31,7 @@ require (
 	golang.org/x/sys v0.32.0 // indirect
 	gopkg.in/warnings.v0 v0.1.2 // indirect
 )
+
+require baa_fs25/shared v0.0.0
+
+replace baa_fs25/shared => ../shared
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"baa_fs25/shared/registry"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// -----------------------------------------------------------------------------
+// ---------- Go: Release-Daten mit GitHub-Fallback -----------------------------
+// -----------------------------------------------------------------------------
+
+// goRegistry fragt zuerst proxy.golang.org. Kennt der Proxy die Version nicht
+// (private Module, GOPRIVATE), wird das Datum des zugrunde liegenden Tags über
+// die GitHub-API ermittelt. Pseudo-Versionen brauchen keine Abfrage: sie
+// enthalten den Commit-Zeitpunkt selbst.
+type goRegistry struct {
+	proxy *registry.GoProxy
+	gh    *registry.GitHub
+}
+
+func newGoRegistry() goRegistry {
+	return goRegistry{proxy: &registry.GoProxy{}, gh: &registry.GitHub{Token: os.Getenv("GH_TOKEN")}}
+}
+
+func (r goRegistry) ReleaseTime(mod, ver string) (time.Time, error) {
+	if module.IsPseudoVersion(ver) {
+		return module.PseudoVersionTime(ver)
+	}
+	t, err := r.proxy.ReleaseTime(mod, ver)
+	if err == nil {
+		return t, nil
+	}
+	slug, sub := goGitHubRepo(mod)
+	if slug == "" {
+		return t, err
+	}
+	tag := strings.TrimSuffix(ver, "+incompatible")
+	if sub != "" {
+		tag = sub + "/" + tag
+	}
+	t, gerr := r.gh.TagTime(slug, tag)
+	if gerr != nil {
+		return time.Time{}, fmt.Errorf("%v; GitHub-Tag %s: %v", err, tag, gerr)
+	}
+	return t, nil
+}
+
+var majorSuffixRx = regexp.MustCompile(`(^|/)v[0-9]+$`)
+
+// goGitHubRepo zerlegt github.com/owner/repo[/sub][/vN] in den Repo-Slug und
+// das Unterverzeichnis, das bei Multi-Modul-Repos den Tag-Präfix bildet.
+func goGitHubRepo(mod string) (slug, sub string) {
+	parts := strings.Split(mod, "/")
+	if len(parts) < 3 || parts[0] != "github.com" {
+		return "", ""
+	}
+	rest := majorSuffixRx.ReplaceAllString(strings.Join(parts[3:], "/"), "")
+	return parts[1] + "/" + parts[2], rest
+}
+
+// goVersionKind trennt Pseudo-Versionen (v0.0.0-<zeit>-<commit>) von
+// getaggten Releases, da ein Update auf einen Commit etwas anderes misst.
+func goVersionKind(ver string) string {
+	if module.IsPseudoVersion(ver) {
+		return "pseudo"
+	}
+	return "release"
+}
+
+// -----------------------------------------------------------------------------
+// ---------- Go: Workspaces (go.work) ------------------------------------------
+// -----------------------------------------------------------------------------
+
+// goWorkModules liefert die go.mod-Pfade aller Module, die eine Fassung der
+// go.work (erster Elternpfad ab HEAD) per "use" einbindet, sortiert. Ohne
+// go.work im HEAD ist das Ergebnis leer und nur die go.mod im Wurzelverzeichnis
+// wird ausgewertet; die Historie wird dann gar nicht erst gelesen.
+func goWorkModules(r *git.Repository) []string {
+	head, err := r.Head()
+	if err != nil {
+		return nil
+	}
+	c, err := r.CommitObject(head.Hash())
+	if err != nil {
+		return nil
+	}
+	if _, err := c.File("go.work"); err != nil {
+		return nil
+	}
+	seen := map[string]bool{}
+	var last plumbing.Hash
+	for err == nil {
+		if f, ferr := c.File("go.work"); ferr == nil && f.Hash != last {
+			last = f.Hash
+			if txt, cerr := f.Contents(); cerr == nil {
+				for _, m := range goWorkUses(txt) {
+					seen[m] = true
+				}
+			}
+		}
+		if c.NumParents() == 0 {
+			break
+		}
+		c, err = c.Parent(0)
+	}
+	mods := make([]string, 0, len(seen))
+	for m := range seen {
+		mods = append(mods, m)
+	}
+	sort.Strings(mods)
+	return mods
+}
+
+// goWorkUses wandelt die "use"-Direktiven einer go.work in go.mod-Pfade um;
+// Verzeichnisse außerhalb des Repos werden ignoriert.
+func goWorkUses(txt string) []string {
+	wf, err := modfile.ParseWork("go.work", []byte(txt), nil)
+	if err != nil {
+		slog.Debug("go.work nicht lesbar", "err", err)
+		return nil
+	}
+	var mods []string
+	for _, u := range wf.Use {
+		dir := path.Clean(filepath.ToSlash(u.Path))
+		if path.IsAbs(dir) || dir == ".." || strings.HasPrefix(dir, "../") {
+			continue
+		}
+		mods = append(mods, path.Join(dir, "go.mod"))
+	}
+	return mods
+}
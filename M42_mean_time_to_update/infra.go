@@ -0,0 +1,234 @@
+// infra.go – Ökosysteme für die Infrastruktur-Ebene:
+//
+//   helm   → Chart.yaml / requirements.yaml (Chart-Dependencies, Artifact Hub)
+//            und values.yaml (image.repository + image.tag, Docker Hub)
+//   docker → FROM-Zeilen im Dockerfile (Docker Hub)
+
+package main
+
+import (
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"baa_fs25/shared/registry"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// ---------- Helm --------------------------------------------------------------
+
+// chartDeps liest die dependencies-Liste aus Chart.yaml (apiVersion v2)
+// bzw. requirements.yaml (v1). Schlüssel ist "<repository>/<name>".
+func chartDeps(txt string) map[string]string {
+	m := map[string]string{}
+	inDeps := false
+	var cur map[string]string
+	flush := func() {
+		if cur == nil {
+			return
+		}
+		repo := strings.TrimSuffix(cur["repository"], "/")
+		// "@alias" und "file://" lassen sich nicht auflösen
+		if cur["name"] != "" && cur["version"] != "" && strings.Contains(repo, "://") &&
+			!strings.HasPrefix(repo, "file://") {
+			m[repo+"/"+cur["name"]] = cur["version"]
+		}
+		cur = nil
+	}
+	for _, raw := range strings.Split(txt, "\n") {
+		line := strings.TrimRight(raw, "\r\t ")
+		l := strings.TrimSpace(line)
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		if line[0] != ' ' && line[0] != '-' {
+			flush()
+			inDeps = strings.HasPrefix(l, "dependencies:")
+			continue
+		}
+		if !inDeps {
+			continue
+		}
+		if strings.HasPrefix(l, "- ") {
+			flush()
+			cur = map[string]string{}
+			l = strings.TrimSpace(l[2:])
+		}
+		if cur == nil {
+			continue
+		}
+		if k, v, ok := strings.Cut(l, ":"); ok {
+			cur[strings.TrimSpace(k)] = strings.Trim(strings.TrimSpace(v), `"'`)
+		}
+	}
+	flush()
+	return m
+}
+
+// valuesImages sucht in values.yaml nach Blöcken mit repository + tag
+// (optional registry) sowie nach "image: name:tag". Schlüssel ist die
+// Image-Referenz ohne Tag.
+func valuesImages(txt string) map[string]string {
+	type frame struct {
+		indent int
+		key    string
+	}
+	var stack []frame
+	blocks := map[string]map[string]string{} // YAML-Pfad → Schlüssel/Werte
+	m := map[string]string{}
+
+	for _, raw := range strings.Split(txt, "\n") {
+		line := strings.TrimRight(raw, "\r\t ")
+		l := strings.TrimSpace(line)
+		if l == "" || strings.HasPrefix(l, "#") || strings.HasPrefix(l, "-") {
+			continue
+		}
+		k, v, ok := strings.Cut(l, ":")
+		if !ok {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		var path []string
+		for _, f := range stack {
+			path = append(path, f.key)
+		}
+		k = strings.TrimSpace(k)
+		v = strings.Trim(strings.TrimSpace(v), `"'`)
+		if i := strings.Index(v, " #"); i >= 0 {
+			v = strings.TrimSpace(v[:i])
+		}
+		if v == "" {
+			stack = append(stack, frame{indent, k})
+			continue
+		}
+		if k == "image" {
+			if img, tag := splitImageRef(v); tag != "" {
+				m[img] = tag
+			}
+			continue
+		}
+		p := strings.Join(path, ".")
+		if blocks[p] == nil {
+			blocks[p] = map[string]string{}
+		}
+		blocks[p][k] = v
+	}
+	for _, b := range blocks {
+		repo, tag := b["repository"], b["tag"]
+		if repo == "" || tag == "" {
+			continue
+		}
+		if reg := b["registry"]; reg != "" {
+			repo = reg + "/" + repo
+		}
+		m[repo] = tag
+	}
+	return m
+}
+
+func init() { registerEcosystem("helm", helmEco) }
+
+func helmEco() ecosystem {
+	return ecosystem{
+		name:  "helm",
+		paths: []string{"Chart.yaml", "requirements.yaml", "values.yaml"},
+		versions: func(c *object.Commit) map[string]string {
+			curr := map[string]string{}
+			for _, name := range []string{"Chart.yaml", "requirements.yaml"} {
+				if txt, err := readFileFromCommit(c, name); err == nil && txt != "" {
+					for k, v := range chartDeps(txt) {
+						curr[k] = v
+					}
+				}
+			}
+			if txt, err := readFileFromCommit(c, "values.yaml"); err == nil && txt != "" {
+				for k, v := range valuesImages(txt) {
+					curr[k] = v
+				}
+			}
+			return curr
+		},
+		reg: infraRegistry{charts: &registry.ArtifactHub{}, images: &registry.DockerHub{}},
+		parse: func(file, txt string) map[string]string {
+			if strings.HasPrefix(path.Base(file), "values") {
+				return valuesImages(txt)
+			}
+			return chartDeps(txt)
+		},
+	}
+}
+
+// infraRegistry leitet Charts ("<repo-url>/<chart>") an Artifact Hub weiter,
+// Images an Docker Hub.
+type infraRegistry struct {
+	charts *registry.ArtifactHub
+	images *registry.DockerHub
+}
+
+func (r infraRegistry) ReleaseTime(pkg, ver string) (time.Time, error) {
+	if strings.Contains(pkg, "://") {
+		return r.charts.ReleaseTime(pkg, ver)
+	}
+	return r.images.ReleaseTime(pkg, ver)
+}
+
+// ---------- Dockerfile --------------------------------------------------------
+
+// fromRx: FROM [--platform=...] image[:tag][@digest] [AS name]
+var fromRx = regexp.MustCompile(`(?i)^\s*FROM\s+(?:--\S+\s+)*(\S+)(?:\s+AS\s+(\S+))?`)
+
+// dockerfileImages liest die Basis-Images aller FROM-Zeilen. Verweise auf
+// frühere Stages, scratch und ARG-Platzhalter werden übersprungen.
+func dockerfileImages(txt string) map[string]string {
+	m := map[string]string{}
+	stages := map[string]bool{}
+	for _, l := range strings.Split(txt, "\n") {
+		mm := fromRx.FindStringSubmatch(l)
+		if mm == nil {
+			continue
+		}
+		ref := mm[1]
+		if mm[2] != "" {
+			stages[strings.ToLower(mm[2])] = true
+		}
+		if stages[strings.ToLower(ref)] || ref == "scratch" || strings.Contains(ref, "$") {
+			continue
+		}
+		if img, tag := splitImageRef(ref); tag != "" {
+			m[img] = tag
+		}
+	}
+	return m
+}
+
+// splitImageRef trennt "name:tag@sha256:..." in Name und Tag.
+func splitImageRef(ref string) (image, tag string) {
+	ref, _, _ = strings.Cut(ref, "@")
+	i := strings.LastIndex(ref, ":")
+	if i < 0 || strings.Contains(ref[i:], "/") { // ":" gehört zum Registry-Port
+		return ref, ""
+	}
+	return ref[:i], ref[i+1:]
+}
+
+func init() { registerEcosystem("docker", dockerEco) }
+
+func dockerEco() ecosystem {
+	return ecosystem{
+		name:  "docker",
+		paths: []string{"Dockerfile"},
+		versions: func(c *object.Commit) map[string]string {
+			txt, err := readFileFromCommit(c, "Dockerfile")
+			if err != nil || txt == "" {
+				return nil
+			}
+			return dockerfileImages(txt)
+		},
+		reg:   &registry.DockerHub{},
+		parse: func(_, txt string) map[string]string { return dockerfileImages(txt) },
+	}
+}
@@ -0,0 +1,134 @@
+// ios.go – Ökosysteme für iOS/macOS-Projekte:
+//
+//   cocoapods → Podfile.lock, Release-Daten aus dem CocoaPods-Trunk
+//   swiftpm   → Package.resolved, Release-Daten aus GitHub-Tags
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"regexp"
+	"strings"
+
+	"baa_fs25/shared/registry"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// ---------- CocoaPods ---------------------------------------------------------
+
+// podLineRx: "  - Name/Subspec (1.2.3)", optional in Anführungszeichen und mit
+// ":" für Pods mit eigenen Abhängigkeiten
+var podLineRx = regexp.MustCompile(`^  - "?([^\s/"(]+)(?:/[^\s"(]+)? \(([^)]+)\)"?:?$`)
+
+// podVersions liest die installierten Pods aus der PODS-Sektion von
+// Podfile.lock. Subspecs (Firebase/Core) werden auf den Root-Pod reduziert.
+func podVersions(txt string) map[string]string {
+	m := map[string]string{}
+	inPods := false
+	for _, l := range strings.Split(txt, "\n") {
+		l = strings.TrimRight(l, "\r")
+		if l != "" && !strings.HasPrefix(l, " ") {
+			inPods = l == "PODS:"
+			continue
+		}
+		if !inPods {
+			continue
+		}
+		if mm := podLineRx.FindStringSubmatch(l); mm != nil {
+			m[mm[1]] = mm[2]
+		}
+	}
+	return m
+}
+
+func init() { registerEcosystem("cocoapods", cocoapodsEco) }
+
+func cocoapodsEco() ecosystem {
+	return ecosystem{
+		name:  "cocoapods",
+		paths: []string{"Podfile.lock"},
+		versions: func(c *object.Commit) map[string]string {
+			txt, err := readFileFromCommit(c, "Podfile.lock")
+			if err != nil || txt == "" {
+				return nil
+			}
+			return podVersions(txt)
+		},
+		reg:   &registry.CocoaPods{},
+		parse: func(_, txt string) map[string]string { return podVersions(txt) },
+	}
+}
+
+// ---------- Swift Package Manager ---------------------------------------------
+
+// resolvedFile deckt Package.resolved in Version 1 (object.pins) und
+// Version 2/3 (pins) ab.
+type resolvedFile struct {
+	Object struct {
+		Pins []resolvedPin `json:"pins"`
+	} `json:"object"`
+	Pins []resolvedPin `json:"pins"`
+}
+
+type resolvedPin struct {
+	RepositoryURL string `json:"repositoryURL"` // v1
+	Location      string `json:"location"`      // v2+
+	State         struct {
+		Version string `json:"version"`
+	} `json:"state"`
+}
+
+// swiftVersions liefert owner/repo → Version für alle GitHub-Pins mit
+// Versions-Tag. Pins auf Branches/Revisionen haben keine Version.
+func swiftVersions(txt string) map[string]string {
+	var rf resolvedFile
+	if err := json.Unmarshal([]byte(txt), &rf); err != nil {
+		return nil
+	}
+	m := map[string]string{}
+	for _, p := range append(rf.Object.Pins, rf.Pins...) {
+		loc := p.Location
+		if loc == "" {
+			loc = p.RepositoryURL
+		}
+		slug := githubSlug(loc)
+		if slug == "" || p.State.Version == "" {
+			continue
+		}
+		m[slug] = p.State.Version
+	}
+	return m
+}
+
+// githubSlug leitet owner/repo aus einer GitHub-URL ab ("" sonst).
+func githubSlug(url string) string {
+	u := strings.TrimSuffix(url, ".git")
+	i := strings.Index(u, "github.com")
+	if i < 0 {
+		return ""
+	}
+	parts := strings.Split(strings.Trim(u[i+len("github.com"):], ":/"), "/")
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[0] + "/" + parts[1]
+}
+
+func init() { registerEcosystem("swiftpm", swiftpmEco) }
+
+func swiftpmEco() ecosystem {
+	return ecosystem{
+		name:  "swiftpm",
+		paths: []string{"Package.resolved"},
+		versions: func(c *object.Commit) map[string]string {
+			txt, err := readFileFromCommit(c, "Package.resolved")
+			if err != nil || txt == "" {
+				return nil
+			}
+			return swiftVersions(txt)
+		},
+		reg:   &registry.GitHub{Token: os.Getenv("GH_TOKEN")},
+		parse: func(_, txt string) map[string]string { return swiftVersions(txt) },
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"sort"
+	"time"
+
+	"baa_fs25/shared/anon"
+	"baa_fs25/shared/i18n"
+	"baa_fs25/shared/registry"
+	"golang.org/x/mod/semver"
+)
+
+// -----------------------------------------------------------------------------
+// ---------- Major-Versionen zurück (technischer Lag je Update) ---------------
+// -----------------------------------------------------------------------------
+
+// majorLag fasst majors_behind der Updates zusammen. Trajectory ist der
+// ereignisbasierte Verlauf: nach jedem Update-Commit die Summe der
+// Major-Versionen, die die bis dahin aktualisierten Dependencies hinter der
+// damals neuesten Major lagen (vergleichbar mit libyears, aber in Majors).
+type majorLag struct {
+	Updates    int          `json:"updates"`
+	Mean       float64      `json:"mean_majors_behind"`
+	Max        int          `json:"max_majors_behind"`
+	Behind     int          `json:"updates_behind"` // Updates, die danach noch ≥ 1 Major zurücklagen
+	Trajectory []majorPoint `json:"trajectory"`
+}
+
+type majorPoint struct {
+	Date   time.Time `json:"date"`
+	Commit string    `json:"commit"`
+	Total  int       `json:"total_majors_behind"`
+}
+
+// majorsBehind zählt die Major-Versionen oberhalb von ver, deren erstes
+// stabiles Release vor when erschien, und liefert die neueste davon (z. B.
+// "v3"). Als Zeitpunkt einer Major gilt ihre niedrigste Version, daher
+// reicht eine Datumsabfrage je neuerer Major. Go: die Versionsliste enthält
+// nur Majors desselben Modulpfads (v0/v1 und +incompatible).
+func majorsBehind(reg registry.Client, pkg, ver string, when time.Time) (int, string, bool) {
+	l, ok := reg.(registry.Lister)
+	cv := canon(ver)
+	if !ok || cv == "" {
+		return 0, "", false
+	}
+	vers, err := l.Versions(pkg)
+	if err != nil {
+		return 0, "", false
+	}
+	first := map[string]string{} // Major → niedrigste stabile Version
+	for _, v := range vers {
+		c := canon(v)
+		if c == "" || semver.Prerelease(c) != "" || semver.Compare(semver.Major(c), semver.Major(cv)) <= 0 {
+			continue
+		}
+		m := semver.Major(c)
+		if f, ok := first[m]; !ok || semver.Compare(c, canon(f)) < 0 {
+			first[m] = v
+		}
+	}
+	n, latest := 0, semver.Major(cv)
+	for m, v := range first {
+		t, err := reg.ReleaseTime(pkg, v)
+		if err != nil || t.After(when) {
+			continue
+		}
+		n++
+		if semver.Compare(m, latest) > 0 {
+			latest = m
+		}
+	}
+	return n, latest, true
+}
+
+// majorEvent ist das, was newMajorLag von einem Update braucht.
+type majorEvent struct {
+	date   time.Time
+	commit string
+	key    string // Dependency (+ Datei)
+	behind int    // majors_behind
+}
+
+// newMajorLag baut die Zusammenfassung und den Verlauf aus den Updates mit
+// Versionsliste (nil, wenn es keine gab).
+func newMajorLag(es []majorEvent) *majorLag {
+	if len(es) == 0 {
+		return nil
+	}
+	sort.SliceStable(es, func(i, j int) bool { return es[i].date.Before(es[j].date) })
+	ml := &majorLag{Updates: len(es)}
+	sum := 0
+	state := map[string]int{} // Dependency (+ Datei) → letzter Stand
+	for _, e := range es {
+		n := e.behind
+		sum += n
+		ml.Max = max(ml.Max, n)
+		if n > 0 {
+			ml.Behind++
+		}
+		state[e.key] = n
+		total := 0
+		for _, v := range state {
+			total += v
+		}
+		p := majorPoint{Date: e.date, Commit: e.commit, Total: total}
+		if k := len(ml.Trajectory); k > 0 && ml.Trajectory[k-1].Commit == p.Commit {
+			ml.Trajectory[k-1] = p // mehrere Updates im selben Commit
+		} else {
+			ml.Trajectory = append(ml.Trajectory, p)
+		}
+	}
+	ml.Mean = float64(sum) / float64(len(es))
+	return ml
+}
+
+// anonymize pseudonymisiert die Commits des Verlaufs (--anonymize).
+func (ml *majorLag) anonymize() {
+	if ml == nil {
+		return
+	}
+	for i := range ml.Trajectory {
+		ml.Trajectory[i].Commit = anon.Commit(ml.Trajectory[i].Commit)
+	}
+}
+
+// printMajorLag gibt Mittel, Maximum und Start/Ende des Verlaufs aus.
+func printMajorLag(ml *majorLag) {
+	if ml == nil {
+		return
+	}
+	i18n.Printf("Major-Versionen zurück : Mean %.2f / Max %d je Update, %d von %d danach noch hinter der neuesten Major\n",
+		ml.Mean, ml.Max, ml.Behind, ml.Updates)
+	first, last := ml.Trajectory[0], ml.Trajectory[len(ml.Trajectory)-1]
+	i18n.Printf("Major-Lag-Verlauf      : %d (%s) → %d (%s), %d Commits\n",
+		first.Total, first.Date.Format("2006-01-02"), last.Total, last.Date.Format("2006-01-02"), len(ml.Trajectory))
+}
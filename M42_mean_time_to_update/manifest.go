@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// -----------------------------------------------------------------------------
+// ---------- Eigene Manifest-Pfade (--manifest) --------------------------------
+// -----------------------------------------------------------------------------
+
+// manifests sind die Pfade aus --manifest relativ zur Repo-Wurzel (leer =
+// Standardpfade des Ökosystems), z. B. requirements-prod.txt oder src/go.mod.
+var manifests []string
+
+// parseManifests liest --manifest path[,path...]; Pfade dürfen mit "./" oder
+// unter Windows mit "\" angegeben werden.
+func parseManifests(v string) error {
+	manifests = nil
+	for _, p := range strings.Split(v, ",") {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		}
+		p = path.Clean(filepath.ToSlash(p))
+		if path.IsAbs(p) || p == "." || strings.HasPrefix(p, "../") {
+			return fmt.Errorf("--manifest %q: Pfad relativ zur Repo-Wurzel erwartet", p)
+		}
+		manifests = append(manifests, p)
+	}
+	return nil
+}
+
+// withManifests ersetzt die Standardpfade von e durch files. Jede Datei wird
+// getrennt verfolgt (Schlüssel "<datei>|<dep>", Records mit file) und mit
+// dem Parser gelesen, den e für ihren Namen wählt.
+func withManifests(e ecosystem, files []string) (ecosystem, error) {
+	if e.parse == nil {
+		return e, fmt.Errorf("--manifest wird für %s nicht unterstützt", e.name)
+	}
+	read := func(parse func(file, txt string) map[string]string) func(c *object.Commit) map[string]string {
+		return func(c *object.Commit) map[string]string {
+			curr := map[string]string{}
+			for _, f := range files {
+				if txt, err := readFileFromCommit(c, f); err == nil && txt != "" {
+					for k, v := range parse(f, txt) {
+						curr[f+fileSep+k] = v
+					}
+				}
+			}
+			return curr
+		}
+	}
+	e.paths, e.discover = files, nil
+	e.versions, e.specs = read(e.parse), nil
+	if e.parseSpecs != nil {
+		e.specs = read(e.parseSpecs)
+	}
+	return e, nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import "baa_fs25/shared/i18n"
+
+// -----------------------------------------------------------------------------
+// ---------- Report-Texte (--lang) ---------------------------------------------
+// -----------------------------------------------------------------------------
+
+// Der Report ist auf Deutsch geschrieben; für --lang en übersetzt der Katalog
+// ihn ins Englische. Die Beschriftungen behalten ihre Breite, damit die
+// Doppelpunkte untereinander stehen.
+func init() {
+	i18n.Add(i18n.EN, map[string]string{
+		"\nSummary für %s (%s)\n":                                                                                  "\nSummary for %s (%s)\n",
+		"Rückblick              : genau %d Commits\n":                                                              "Look-back              : exactly %d commits\n",
+		"Rückblick              : letzte %d Tage\n":                                                                "Look-back              : last %d days\n",
+		"Stop nach              : %d Datei-Änderungen\n":                                                           "Stop after             : %d file changes\n",
+		"Stichprobe             : %d von %d Commits (%s)\n":                                                        "Sample                 : %d of %d commits (%s)\n",
+		"  %-21s: %d von %d Commits\n":                                                                             "  %-21s: %d of %d commits\n",
+		"Analysierte Updates    : %d (n)\n":                                                                        "Analysed updates       : %d (n)\n",
+		"MTTU-Mean              : %.1f Tage\n":                                                                     "MTTU mean              : %.1f days\n",
+		"MTTU-Median            : %.1f Tage\n":                                                                     "MTTU median            : %.1f days\n",
+		"Ohne Registry-Version  : %d Dependencies %v\n":                                                            "No registry version    : %d dependencies %v\n",
+		"Übersprungene Releases : %.1f je Update (Mittel)\n":                                                       "Skipped releases       : %.1f per update (mean)\n",
+		"MTTU-Mean              : %.1f Tage (95%%-KI %.1f – %.1f)\n":                                               "MTTU mean              : %.1f days (95%% CI %.1f – %.1f)\n",
+		"Vor Zusammenfassung    : %d Bumps (Fenster %s), Mean %.1f / Median %.1f Tage\n":                           "Before deduplication   : %d bumps (window %s), mean %.1f / median %.1f days\n",
+		"Ab erstem neuen Release: Mean %.1f / Median %.1f Tage (n=%d)\n":                                           "From first new release : mean %.1f / median %.1f days (n=%d)\n",
+		"Bot-PR offen bis Merge : Mean %.1f / Median %.1f Tage (n=%d PRs)\n":                                       "Bot PR open to merge   : mean %.1f / median %.1f days (n=%d PRs)\n",
+		"Major-Versionen zurück : Mean %.2f / Max %d je Update, %d von %d danach noch hinter der neuesten Major\n": "Majors behind          : mean %.2f / max %d per update, %d of %d still behind the newest major afterwards\n",
+		"Major-Lag-Verlauf      : %d (%s) → %d (%s), %d Commits\n":                                                 "Major lag trend        : %d (%s) → %d (%s), %d commits\n",
+		"Nach Ökosystem":           "By ecosystem",
+		"Nach Constraint-Änderung": "By constraint change",
+		"Nach Versionsart":         "By version kind",
+		"\nLangsamste Updates:":    "\nSlowest updates:",
+		", %d übersprungen":        ", %d skipped",
+		"jeder %d.":                "every %d.",
+		"zufällig, seed=%d":        "random, seed=%d",
+		"\nDry-Run für %s (%s) – keine Registry-Abfragen\n":   "\nDry run for %s (%s) – no registry queries\n",
+		"Zeitraum               : %s – %s\n":                  "Period                 : %s – %s\n",
+		"Versionssprünge        : %d (vor Registry-Filter)\n": "Version bumps          : %d (before registry filter)\n",
+		"Repo lesbar: %s\n":     "Repo readable: %s\n",
+		"Manifeste:":            "Manifests:",
+		"  %-40s %4d Commits\n": "  %-40s %4d commits\n",
+	})
+}
@@ -1,15 +1,18 @@
 // multi_mttu.go
 //
-// Analyse-Tool für Mean-Time-To-Update (MTTU) direkt in Git-Repos.
-// Unterstützt drei Stopp-Kriterien:
+// Analyse-Tool für Mean-Time-To-Update (MTTU) direkt in Git-Repos: je
+// Dependency-Update die Zeit vom Release der neuen Version bis zum Commit,
+// der sie übernimmt.
+//
+// Genau **eines** der Stopp-Kriterien muss gesetzt sein (>0):
 //   --commits N   → exakt N jüngste Commits begehen
-//   --changes N   → bricht ab, sobald N Änderungen der
-//                   Datei (go.mod | package.json | requirements.txt) gefunden wurden
+//   --changes N   → nach N Manifest-Änderungen abbrechen
 //   --days N      → alle Commits der letzten N Tage
 //
-// Genau **eine** dieser Optionen muss gesetzt sein (>0).
-//
-// Ökosysteme: npm | go | py
+// --eco wählt ein oder mehrere Ökosysteme (s. ecosystems.go); die übrigen
+// Flags beschreibt --help, die Details stehen bei ihrer Umsetzung.
+// Exit-Codes: 0 ok, 1 Analysefehler, 2 Aufruffehler, 3 Teilergebnis (s.
+// shared/exitcode).
 //
 // go run multi_mttu.go --eco go --commits 100 https://github.com/gorilla/mux.git
 
@@ -21,20 +24,34 @@ import (
 	"errors"
 	"flag"
 	"fmt"
-	"io"
-	"log"
-	"net/http"
+	"log/slog"
+	"maps"
+	"math"
+	"math/rand"
 	"os"
-	"os/exec"
+	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
+	"baa_fs25/shared/anon"
+	"baa_fs25/shared/clones"
+	"baa_fs25/shared/depkind"
+	"baa_fs25/shared/exitcode"
+	"baa_fs25/shared/gitwalk"
+	"baa_fs25/shared/i18n"
+	"baa_fs25/shared/logging"
+	"baa_fs25/shared/netcfg"
+	"baa_fs25/shared/provenance"
+	"baa_fs25/shared/purl"
+	"baa_fs25/shared/registry"
+	"baa_fs25/shared/schema"
 	git "github.com/go-git/go-git/v5"
-	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
 	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
 	"golang.org/x/mod/semver"
 )
@@ -48,48 +65,125 @@ var (
 	maxChanges   int // Stop-Kriterium 2 (neu)
 	lookBackDays int // Stop-Kriterium 3
 	verbose      bool
+	outFile      string
+	topN         int
+	minSample    int
+	tzPolicy     string
+	datePolicy   string
+	bare         bool
+	gitBackend   string
+	checkGit     bool
+	dryRun       bool
+	sinceAvail   bool
+	follow       bool
+	remoteAPI    bool
+	withMeta     bool
+	botPR        bool
+	sample       *sampling // --sample, nil = alle Commits
+	excludeGlobs []string
+	bootstrapN   int
+	githubPR     int
+	baseFile     string
+	logOpts      *logging.Options
+	netOpts      *netcfg.Options
+	anonOpts     *anon.Options
+	schemaOpts   *schema.Options
+	langOpts     *i18n.Options
+	exitOpts     *exitcode.Options
+	cloneOpts    *clones.Options
 )
 
 func init() {
-	flag.StringVar(&eco, "eco", "", "Ökosystem: npm | go | py")
+	flag.StringVar(&eco, "eco", "", "Ökosystem, mehrere kommagetrennt oder all (s. ecosystems.go)")
 	flag.IntVar(&maxCommits, "commits", -1, "Genau N jüngste Commits analysieren")
 	flag.IntVar(&maxChanges, "changes", -1, "Stoppt nach N Datei-Änderungen")
 	flag.IntVar(&lookBackDays, "days", -1, "Historie X Tage zurück")
-	flag.BoolVar(&verbose, "v", true, "Verbose Log")
+	flag.BoolVar(&verbose, "v", false, "Kurzform für --log-level debug")
+	flag.IntVar(&topN, "top", 10, "Anzahl der langsamsten Updates in der Ausgabe (-1 = alle)")
+	flag.IntVar(&minSample, "min-sample", 10, "Warnung, wenn weniger Updates gefunden werden")
+	flag.IntVar(&bootstrapN, "bootstrap", 1000, "Resamples für das Konfidenzintervall des Mittelwerts (0 = aus)")
+	flag.Func("exclude", "Komma-separierte Globs, deren Manifeste ignoriert werden (Default: "+strings.Join(defaultExclude, ",")+"; \"\" = keine)", func(v string) error {
+		excludeGlobs = nil
+		for _, g := range strings.Split(v, ",") {
+			if g = strings.TrimSpace(g); g != "" {
+				excludeGlobs = append(excludeGlobs, g)
+			}
+		}
+		return nil
+	})
+	flag.Func("manifest", "Manifest-Pfade relativ zur Repo-Wurzel statt der Standardpfade des Ökosystems, kommagetrennt (z. B. requirements-prod.txt,src/go.mod; nur mit genau einem --eco)", parseManifests)
+	flag.Func("dedupe-window", "Bumps derselben Dependency binnen dieses Abstands (z. B. 7d) zu einem Update zusammenfassen", func(v string) (err error) {
+		dedupeWindow, err = parseWindow(v)
+		return err
+	})
+	flag.Func("sample", "nur eine Stichprobe der Manifest-Commits analysieren: every-nth=K | random=N[,seed=S]", func(v string) (err error) {
+		sample, err = parseSample(v)
+		return err
+	})
+	flag.StringVar(&tzPolicy, "tz", "utc", "Zeitzone für Commit- und Release-Zeitpunkte: utc | local | author (Offset der per --date gewählten Signatur)")
+	flag.StringVar(&datePolicy, "date", "author", "Zeitpunkt des Updates: author | committer (Rebases/Cherry-Picks behalten das alte Autor-Datum)")
+	flag.StringVar(&gitBackend, "git", "go-git", "Commit-Historie lesen über: "+gitwalk.Backends+" (cli braucht git im PATH)")
+	flag.BoolVar(&sinceAvail, "since-available", false, "zusätzlich Verzögerung ab dem ersten Release nach der alten Version (braucht die Versionsliste: npm, go, py)")
+	flag.BoolVar(&follow, "follow", false, "Manifeste über Umbenennungen/Verschiebungen hinweg verfolgen (wie git log --follow)")
+	flag.BoolVar(&checkGit, "check-git", false, "Smoke-Test für --git cli: git-Version ausgeben und (bei lokalem Verzeichnis) prüfen, ob git das Repo lesen kann, dann beenden")
+	flag.BoolVar(&dryRun, "dry-run", false, "nur Commits, Zeitraum und Manifeste auflisten, ohne Registry-Abfragen")
+	flag.BoolVar(&remoteAPI, "remote-api", false, "Manifeste über die GitHub-REST-API lesen statt zu klonen ($GH_TOKEN empfohlen); bei Rate-Limit wird doch geklont")
+	flag.BoolVar(&withMeta, "repo-meta", false, "Stars, Sprache, Alter, Contributors und Default-Branch (GitHub-API) in die JSON-Ausgabe aufnehmen")
+	flag.BoolVar(&botPR, "bot-pr", false, "Merges von Dependabot/Renovate über die GitHub-API ihrem PR zuordnen und die PR-Latenz erfassen ($GH_TOKEN empfohlen)")
+	flag.BoolVar(&bare, "bare", false, "ohne Working Tree klonen (<name>.git); Manifeste werden ohnehin aus den Commits gelesen")
+	flag.StringVar(&outFile, "out", "", "Ergebnisse zusätzlich als JSON schreiben (\"-\" = stdout; Endung .gz/.zst = komprimiert)")
+	flag.IntVar(&chunkSize, "chunk-size", 0, "--out auf Dateien mit je höchstens N Updates verteilen (<name>-00001.json, …; 0 = eine Datei)")
+	flag.StringVar(&timelineDep, "timeline", "", "Release- und Übernahmedatum jeder Version dieser Dependency als Diagramm schreiben (s. --timeline-out)")
+	flag.StringVar(&timelineOut, "timeline-out", "", "Datei für --timeline: .mmd = Mermaid-Gantt, .dot = Graphviz, \"-\" = stdout (Default: timeline-<dep>.mmd)")
+	flag.StringVar(&outFormat, "format", "json", "Format von --out: json | jsonl (je Update eine Zeile, sofort geschrieben)")
+	flag.IntVar(&githubPR, "github-pr", 0, "Ergebnis als Kommentar an diesen PR posten ($GITHUB_TOKEN, $GITHUB_REPOSITORY)")
+	flag.StringVar(&baseFile, "base", "", "--out-JSON des Basis-Branches für den Vergleich im PR-Kommentar")
+	logOpts = logging.Register(flag.CommandLine)
+	netOpts = netcfg.Register(flag.CommandLine)
+	anonOpts = anon.Register(flag.CommandLine)
+	schemaOpts = schema.Register(flag.CommandLine)
+	cloneOpts = clones.Register(flag.CommandLine, "")
+	langOpts = i18n.Register(flag.CommandLine)
+	exitOpts = exitcode.Register(flag.CommandLine)
+	excludeGlobs = defaultExclude
 }
 
-// commitsTouchingFiles ruft 'git log --pretty=%H -- <pfad>' auf
-// und liefert die Hashes (jüngster Commit zuletzt).
-func commitsTouchingFiles(repoDir string, paths []string, since, until *time.Time) ([]string, error) {
-	args := []string{"log", "--first-parent", "--reverse", "--pretty=%H"}
-	if since != nil {
-		args = append(args, fmt.Sprintf("--since=%s", since.Format(time.RFC3339)))
-	}
-	if until != nil {
-		args = append(args, fmt.Sprintf("--until=%s", until.Format(time.RFC3339)))
+// defaultExclude deckt vendorte Abhängigkeiten, eingecheckte node_modules,
+// Test-Fixtures und Beispiele ab.
+var defaultExclude = []string{"**/vendor/**", "**/node_modules/**", "**/testdata/**", "**/examples/**"}
+
+// normTime bringt t in die per --tz gewählte Zone: UTC, die lokale Zone des
+// Analyse-Rechners oder den Offset der Signatur, die --date auswählt (Autor
+// bzw. Committer). Die Verzögerung in Tagen ist eine Dauer und damit
+// unabhängig davon; die Zone bestimmt, welche Kalenderdaten ausgegeben und
+// gespeichert werden.
+func normTime(t time.Time, c *object.Commit) time.Time {
+	switch tzPolicy {
+	case "local":
+		return t.Local()
+	case "author":
+		return t.In(commitTime(c).Location())
+	default:
+		return t.UTC()
 	}
-	args = append(args, "--")
-	args = append(args, paths...)
+}
 
-	cmd := exec.Command("git", args...)
-	cmd.Dir = repoDir
-	out, err := cmd.Output()
-	if err != nil {
-		return nil, err
+// commitTime ist der per --date gewählte Zeitpunkt des Commits. Das
+// Autor-Datum überdauert Rebases und Cherry-Picks; liegt es vor dem Release,
+// wird das Update als negativ verworfen. Das Committer-Datum ist der
+// Zeitpunkt, zu dem die Änderung tatsächlich im Branch landete.
+func commitTime(c *object.Commit) time.Time {
+	if datePolicy == "committer" {
+		return c.Committer.When
 	}
-	hashes := strings.Fields(string(out))
-
-	return hashes, nil
+	return c.Author.When
 }
 
 func logChange(c *object.Commit, dep, oldV, newV string) {
-	if !verbose {
-		return
-	}
-	fmt.Printf("%s  %s  %-38s  %s → %s\n",
-		c.Author.When.Format("2006-01-02"),
-		c.Hash.String()[:7],
-		dep, oldV, newV)
+	slog.Debug("Update erkannt",
+		"date", normTime(commitTime(c), c).Format("2006-01-02"),
+		"commit", c.Hash.String()[:7],
+		"dep", dep, "old", oldV, "new", newV)
 }
 
 // Prüft, dass **genau** ein Stopp-Flag >0 ist
@@ -105,7 +199,8 @@ func validateScopeFlags() {
 		active++
 	}
 	if active != 1 {
-		log.Fatal("genau EINE der Optionen --commits, --changes oder --days setzen (positiver Wert)")
+		logging.Usage("genau EINE der Optionen --commits, --changes oder --days setzen (positiver Wert)",
+			"commits", maxCommits, "changes", maxChanges, "days", lookBackDays)
 	}
 }
 
@@ -113,74 +208,201 @@ func validateScopeFlags() {
 // Datenstrukturen
 // -----------------------------------------------------------------------------
 type delay struct {
-	Dep        string
-	OldVer     string
-	NewVer     string
-	Days       float64
-	CommitHash string
-	CommitDate time.Time
+	Eco        string    `json:"eco"` // Ökosystem des Analyzers (bei --eco go,npm verschieden)
+	Dep        string    `json:"dep"`
+	Alias      string    `json:"alias,omitempty"` // npm: Name in der package.json bei "npm:<dep>@…"
+	Purl       string    `json:"purl,omitempty"`  // Package-URL der neuen Version
+	OldVer     string    `json:"old_version"`
+	NewVer     string    `json:"new_version"`
+	Days       float64   `json:"days"`
+	CommitHash string    `json:"commit"`
+	CommitDate time.Time `json:"commit_date"` // nach --date
+	// beide Zeitpunkte des Commits; weichen sie ab, wurde er rebased oder
+	// gecherry-pickt
+	AuthorDate    time.Time `json:"author_date"`
+	CommitterDate time.Time `json:"committer_date"`
+	// Quelldatei, falls das Ökosystem mehrere Dateien getrennt verfolgt (py,
+	// Go-Workspaces), und dev/prod (nur py)
+	File     string `json:"file,omitempty"`
+	ReqScope string `json:"req_scope,omitempty"`
+	Kind     string `json:"version_kind,omitempty"` // nur go: release | pseudo
+	// Manifest ist der Pfad des Manifests in diesem Commit (nur --follow)
+	Manifest string `json:"manifest,omitempty"`
+	// Nur npm: Stil der alten/neuen Angabe und Art der Änderung (siehe npmConstraint)
+	OldStyle string `json:"constraint_old,omitempty"`
+	NewStyle string `json:"constraint_new,omitempty"`
+	Change   string `json:"constraint_change,omitempty"`
+	// Skipped zählt die übersprungenen Releases zwischen OldVer und NewVer
+	// (nil, wenn die Registry keine Versionsliste liefert).
+	Skipped *int `json:"skipped_releases,omitempty"`
+	// MajorsBehind zählt die Major-Versionen, die zum Commit-Zeitpunkt
+	// schon über NewVer erschienen waren; LatestMajor ist die neueste davon
+	// (nil bzw. leer ohne Versionsliste, s. majorlag.go).
+	MajorsBehind *int   `json:"majors_behind,omitempty"`
+	LatestMajor  string `json:"latest_major,omitempty"`
+	// Nur mit --since-available: erstes Release nach OldVer und die
+	// Verzögerung ab dessen Veröffentlichung statt ab NewVer.
+	FirstAvailable string   `json:"first_available,omitempty"`
+	AvailableDays  *float64 `json:"available_days,omitempty"`
+	// Nur mit --bot-pr, bei Merges von Dependabot/Renovate: PR-Nummer und Zeit vom
+	// Öffnen bis zum Merge des PRs, s. botpr.go.
+	BotPR         int      `json:"bot_pr,omitempty"`
+	PRLatencyDays *float64 `json:"pr_latency_days,omitempty"`
+	// CIChange: der Commit ändert auch CI-Konfiguration (.github/workflows,
+	// .gitlab-ci.yml, …); fehlt bei --remote-api und gha, s. ci.go.
+	CIChange *bool `json:"ci_change,omitempty"`
+	// Nur mit --dedupe-window: Zahl der zusammengefassten Bumps (≥ 2) und
+	// Commit des ersten davon, s. dedupe.go.
+	Bumps       int    `json:"bumps,omitempty"`
+	FirstCommit string `json:"first_commit,omitempty"`
 }
 
-func canon(v string) string {
-	// Leerstring, wenn nicht semver-konform
-	vTemp := semver.Canonical(v)
-	if vTemp == "" && !strings.HasPrefix(v, "v") {
-		v = "v" + v // 1.2.3 → v1.2.3
-	}
-	return semver.Canonical(v) // nochmal prüfen
+// result ist das JSON-Dokument, das --out schreibt.
+type result struct {
+	Schema string `json:"schema"` // s. shared/schema
+	Repo   string `json:"repo"`
+	// Meta sind die Repo-Metadaten (nur --repo-meta).
+	Meta    *repoMeta `json:"repo_meta,omitempty"`
+	Sample  *sampling `json:"sample,omitempty"` // nur --sample
+	Eco     string    `json:"eco"`
+	TZ      string    `json:"tz"`   // --tz-Policy der Zeitstempel
+	Date    string    `json:"date"` // --date: author | committer
+	Scope   scope     `json:"scope"`
+	Summary summary   `json:"summary"`
+	Updates []delay   `json:"updates"`
+	// Chunk ist die Nummer dieser Teildatei (nur --chunk-size).
+	Chunk *chunkInfo `json:"chunk,omitempty"`
+	// SkippedSpecs sind Dependencies ohne Registry-Version (npm: git, file,
+	// link, workspace, Tarball-URL; alle: unpublished-version).
+	SkippedSpecs []skippedSpec `json:"skipped_specs,omitempty"`
+
+	// Provenance: Version, Flags, Zeitpunkt, Endpunkte und Cache-Quote des
+	// Laufs (s. shared/provenance).
+	Provenance *provenance.Info `json:"provenance,omitempty"`
 }
 
-// -----------------------------------------------------------------------------
-// ---------- NPM-Helfer --------------------------------------------------------
-// -----------------------------------------------------------------------------
-type npmMeta struct {
-	Time map[string]string `json:"time"`
+// anonDelay pseudonymisiert die Commits eines Updates (--anonymize).
+func anonDelay(d delay) delay {
+	d.CommitHash, d.FirstCommit = anon.Commit(d.CommitHash), anon.Commit(d.FirstCommit)
+	return d
+}
+
+// anonymize pseudonymisiert Repo, Commits und Slug (--anonymize).
+func (r *result) anonymize() {
+	if !anon.Enabled() {
+		return
+	}
+	r.Repo = anon.Repo(r.Repo)
+	for i := range r.Updates {
+		r.Updates[i] = anonDelay(r.Updates[i])
+	}
+	r.Summary.MajorLag.anonymize()
+	if r.Meta != nil {
+		r.Meta.Slug = anon.Repo(r.Meta.Slug)
+	}
+	for i := range r.SkippedSpecs {
+		r.SkippedSpecs[i].Spec = anon.Path(r.SkippedSpecs[i].Spec) // git-URLs, lokale Pfade
+	}
 }
 
-type timeCache struct {
-	data map[string]map[string]time.Time
+type scope struct {
+	Commits int `json:"commits,omitempty"`
+	Changes int `json:"changes,omitempty"`
+	Days    int `json:"days,omitempty"`
 }
 
-func (c *timeCache) get(pkg, ver string) (time.Time, error) {
-	if c.data == nil {
-		c.data = map[string]map[string]time.Time{}
-	}
-	if m, ok := c.data[pkg]; ok {
-		if t, ok2 := m[ver]; ok2 {
-			return t, nil
-		}
+// currentScope liefert das aktive Stopp-Kriterium (nicht gesetzte Flags sind -1).
+func currentScope() scope {
+	var sc scope
+	if maxCommits > 0 {
+		sc.Commits = maxCommits
 	}
-	url := fmt.Sprintf("https://registry.npmjs.org/%s", pkg)
-	resp, err := http.Get(url)
-	if err != nil {
-		return time.Time{}, err
+	if maxChanges > 0 {
+		sc.Changes = maxChanges
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != 200 {
-		return time.Time{}, fmt.Errorf("npm api status %s", resp.Status)
+	if lookBackDays > 0 {
+		sc.Days = lookBackDays
 	}
-	body, _ := io.ReadAll(resp.Body)
-	var meta npmMeta
-	if err := json.Unmarshal(body, &meta); err != nil {
-		return time.Time{}, err
+	return sc
+}
+
+type summary struct {
+	Updates    int     `json:"updates"` // Stichprobengröße
+	MeanDays   float64 `json:"mean_days"`
+	MedianDays float64 `json:"median_days"`
+	// MeanCI95 ist das Bootstrap-95-%-Intervall des Mittelwerts (nil bei < 2 Updates).
+	MeanCI95  *[2]float64 `json:"mean_ci95,omitempty"`
+	MinSample int         `json:"min_sample"`
+	LowSample bool        `json:"low_sample"`
+	// ByConstraint gruppiert npm-Updates nach constraint_change,
+	// ByVersionKind Go-Updates nach version_kind.
+	ByConstraint  map[string]group `json:"by_constraint,omitempty"`
+	ByVersionKind map[string]group `json:"by_version_kind,omitempty"`
+	// ByEco gruppiert nach Ökosystem (nur bei mehreren --eco).
+	ByEco map[string]group `json:"by_eco,omitempty"`
+	// MeanSkipped ist der Mittelwert von skipped_releases über die Updates,
+	// für die die Versionsliste bekannt ist.
+	MeanSkipped *float64 `json:"mean_skipped_releases,omitempty"`
+	// SinceAvailable fasst available_days zusammen (nur --since-available).
+	SinceAvailable *group `json:"since_available,omitempty"`
+	// BotPRLatency fasst pr_latency_days je Bot-PR zusammen.
+	BotPRLatency *group `json:"bot_pr_latency,omitempty"`
+	// MajorLag fasst majors_behind zusammen, mit Verlauf über die Commits.
+	MajorLag *majorLag `json:"major_lag,omitempty"`
+	// Raw sind Anzahl, Mean und Median vor dem Zusammenfassen der Bumps
+	// (nur --dedupe-window); die übrigen Werte beziehen sich auf die
+	// zusammengefassten Updates.
+	Raw *rawSummary `json:"raw,omitempty"`
+}
+
+type group struct {
+	Updates    int     `json:"updates"`
+	MeanDays   float64 `json:"mean_days"`
+	MedianDays float64 `json:"median_days"`
+}
+
+// sortDelays sortiert nach Tagen absteigend, bei Gleichstand nach Dependency
+// und Commit, damit Ausgaben zwischen Läufen vergleichbar bleiben.
+func sortDelays(ds []delay) {
+	sort.SliceStable(ds, func(i, j int) bool { return slower(ds[i], ds[j]) })
+}
+
+// slower ist die Ordnung von sortDelays.
+func slower(a, b delay) bool {
+	if a.Days != b.Days {
+		return a.Days > b.Days
 	}
-	m := map[string]time.Time{}
-	for v, raw := range meta.Time {
-		t, err := time.Parse(time.RFC3339, raw)
-		if err == nil {
-			m[v] = t
-		}
+	if a.Dep != b.Dep {
+		return a.Dep < b.Dep
 	}
-	c.data[pkg] = m
-	if t, ok := m[ver]; ok {
-		return t, nil
+	return a.CommitHash < b.CommitHash
+}
+
+func canon(v string) string {
+	// Leerstring, wenn nicht semver-konform
+	vTemp := semver.Canonical(v)
+	if vTemp == "" && !strings.HasPrefix(v, "v") {
+		v = "v" + v // 1.2.3 → v1.2.3
 	}
-	return time.Time{}, fmt.Errorf("kein Datum für %s@%s", pkg, ver)
+	return semver.Canonical(v) // nochmal prüfen
 }
 
-var npmTimes timeCache
+// -----------------------------------------------------------------------------
+// ---------- NPM-Helfer --------------------------------------------------------
+// -----------------------------------------------------------------------------
+func npmVersions(js string, skips specSkips) map[string]string {
+	out := map[string]string{}
+	for dep, spec := range npmSpecs(js, skips) {
+		out[dep] = strings.TrimLeft(spec, "^~>=< ")
+	}
+	return out
+}
 
-func npmVersions(js string) map[string]string {
+// npmSpecs liefert die Angaben aus "dependencies". Aliase stehen unter
+// "<alias>@npm:<paket>" mit der Range des Ziels; git-, file-, link- und
+// workspace-Angaben sowie Tarball-URLs haben keine Registry-Version und
+// landen in skips (darf nil sein).
+func npmSpecs(js string, skips specSkips) map[string]string {
 	var root map[string]interface{}
 	_ = json.Unmarshal([]byte(js), &root)
 	out := map[string]string{}
@@ -188,7 +410,12 @@ func npmVersions(js string) map[string]string {
 		if m, ok2 := v.(map[string]interface{}); ok2 {
 			for dep, raw := range m {
 				if s, ok3 := raw.(string); ok3 {
-					out[dep] = strings.TrimLeft(s, "^~>=< ")
+					key, rng, reason := npmSpec(dep, s)
+					if reason != "" {
+						skips.add(skippedSpec{Eco: "npm", Dep: dep, Spec: s, Reason: reason})
+						continue
+					}
+					out[key] = rng
 				}
 			}
 		}
@@ -196,42 +423,137 @@ func npmVersions(js string) map[string]string {
 	return out
 }
 
-// -----------------------------------------------------------------------------
-// ---------- GO-Helfer ---------------------------------------------------------
-// -----------------------------------------------------------------------------
-type goInfo struct {
-	Time time.Time `json:"Time"`
+// npmAliasSep trennt in Versions-Maps den Alias vom Zielpaket, vgl.
+// "alias": "npm:paket@1.2.3" in der package.json.
+const npmAliasSep = "@npm:"
+
+// skippedSpec ist eine Dependency ohne auswertbare Version.
+type skippedSpec struct {
+	Eco    string `json:"eco"`
+	Dep    string `json:"dep"`
+	Spec   string `json:"spec"`
+	Reason string `json:"reason"` // git | file | link | workspace | tarball | unpublished-version
+}
+
+// specSkips sammelt die übersprungenen Angaben eines Analyse-Laufs über alle
+// Commits (je Ökosystem und Dependency die jüngste); analyze ergänzt Updates
+// auf nie veröffentlichte Versionen.
+type specSkips map[string]skippedSpec
+
+// specKey ist der Schlüssel einer Dependency über Ökosysteme hinweg.
+func specKey(eco, dep string) string { return eco + ":" + dep }
+
+func (m specSkips) add(s skippedSpec) {
+	if m != nil {
+		m[specKey(s.Eco, s.Dep)] = s
+	}
 }
 
-var goCache = map[string]map[string]time.Time{}
+var npmGitRx = regexp.MustCompile(`^(git(\+[a-z]+)?:|git@|github:|gitlab:|bitbucket:|gist:)|^[\w.-]+/[\w.-]+(#.*)?$|\.git(#.*)?$`)
 
-func goRelTime(module, ver string) (time.Time, error) {
-	if m, ok := goCache[module]; ok {
-		if t, ok2 := m[ver]; ok2 {
-			return t, nil
+// npmSpec zerlegt eine Angabe in Schlüssel und Range bzw. liefert den Grund,
+// warum sie nicht auswertbar ist.
+func npmSpec(dep, spec string) (key, rng, reason string) {
+	s := strings.TrimSpace(spec)
+	switch {
+	case strings.HasPrefix(s, "npm:"):
+		target, rng := strings.TrimPrefix(s, "npm:"), "*"
+		if i := strings.LastIndex(target, "@"); i > 0 { // "@scope/x" beginnt mit @
+			target, rng = target[:i], target[i+1:]
 		}
+		return dep + npmAliasSep + target, rng, ""
+	case strings.HasPrefix(s, "workspace:"):
+		return "", "", "workspace"
+	case strings.HasPrefix(s, "file:"):
+		return "", "", "file"
+	case strings.HasPrefix(s, "link:"):
+		return "", "", "link"
+	case npmGitRx.MatchString(s):
+		return "", "", "git"
+	case strings.HasPrefix(s, "http://"), strings.HasPrefix(s, "https://"):
+		return "", "", "tarball"
+	}
+	return dep, s, ""
+}
+
+// sorted liefert die Einträge sortiert für die Ausgabe.
+func (m specSkips) sorted() []skippedSpec {
+	out := make([]skippedSpec, 0, len(m))
+	for _, s := range m {
+		out = append(out, s)
 	}
-	url := fmt.Sprintf("https://proxy.golang.org/%s/@v/%s.info", module, ver)
-	resp, err := http.Get(url)
-	if err != nil {
-		return time.Time{}, err
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Eco != out[j].Eco {
+			return out[i].Eco < out[j].Eco
+		}
+		return out[i].Dep < out[j].Dep
+	})
+	return out
+}
+
+var npmExactRx = regexp.MustCompile(`^=?v?\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+var npmPartialRx = regexp.MustCompile(`^\d+(\.\d+)?$`) // "1", "1.2" = Range
+
+// npmStyle klassifiziert eine Angabe: exact | caret | tilde | range | tag.
+func npmStyle(spec string) string {
+	spec = strings.TrimSpace(spec)
+	switch {
+	case npmExactRx.MatchString(spec):
+		return "exact"
+	case strings.HasPrefix(spec, "^") && !strings.ContainsAny(spec, " |"):
+		return "caret"
+	case strings.HasPrefix(spec, "~") && !strings.ContainsAny(spec, " |"):
+		return "tilde"
+	case spec == "" || spec == "*" || strings.ContainsAny(spec, "<>=|xX*") || strings.Contains(spec, " - ") ||
+		npmPartialRx.MatchString(spec):
+		return "range"
+	}
+	return "tag" // latest, next, …
+}
+
+// npmConstraint beschreibt eine Änderung von oldSpec nach newSpec:
+//
+//	pin    exakte Version durch andere exakte ersetzt
+//	floor  gleicher Stil, nur die Untergrenze angehoben (^1.2.0 → ^1.4.0)
+//	range  gleicher Stil, aber der erlaubte Bereich verschoben (^1.2.0 → ^2.0.0)
+//	style  Stil gewechselt (^1.2.0 → 1.4.0)
+func npmConstraint(oldSpec, newSpec string) (oldStyle, newStyle, change string) {
+	oldStyle, newStyle = npmStyle(oldSpec), npmStyle(newSpec)
+	switch {
+	case oldStyle != newStyle:
+		change = "style"
+	case oldStyle == "exact":
+		change = "pin"
+	case oldStyle == "caret" || oldStyle == "tilde":
+		o, n := canon(strings.TrimLeft(oldSpec, "^~")), canon(strings.TrimLeft(newSpec, "^~"))
+		change = "range"
+		if o != "" && n != "" && npmRangeKey(o, oldStyle) == npmRangeKey(n, newStyle) {
+			change = "floor"
+		}
+	default:
+		change = "range"
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != 200 {
-		return time.Time{}, fmt.Errorf("proxy %s", resp.Status)
+	return
+}
+
+// npmRangeKey ist der Teil der Version, den ^/~ festhalten: bei ^ die erste
+// Stelle ungleich 0, bei ~ Major.Minor.
+func npmRangeKey(v, style string) string {
+	if style == "tilde" {
+		return semver.MajorMinor(v)
 	}
-	b, _ := io.ReadAll(resp.Body)
-	var info goInfo
-	if err := json.Unmarshal(b, &info); err != nil {
-		return time.Time{}, err
+	if semver.Major(v) != "v0" {
+		return semver.Major(v)
 	}
-	if _, ok := goCache[module]; !ok {
-		goCache[module] = map[string]time.Time{}
+	if mm := semver.MajorMinor(v); mm != "v0.0" {
+		return mm
 	}
-	goCache[module][ver] = info.Time
-	return info.Time, nil
+	return v
 }
 
+// -----------------------------------------------------------------------------
+// ---------- GO-Helfer ---------------------------------------------------------
+// -----------------------------------------------------------------------------
 var reqLine = regexp.MustCompile(`^[\t ]*([\w./\-]+)[\t ]+v[^\s]+`)
 
 func goVersions(txt string) map[string]string {
@@ -269,7 +591,13 @@ var reqRx = regexp.MustCompile(`^([A-Za-z0-9_.\-]+)==([0-9A-Za-z.+\-]+)$`)
 var iniRx = regexp.MustCompile(`(?m)^\s*install_requires\s*=\s*$`)
 var depLineRx = regexp.MustCompile(`^\s*([A-Za-z0-9_.\-]+)([=<>!~]*[0-9A-Za-z.+\-]*)`)
 
+// readFileFromCommit liest name aus dem Commit (mit --follow unter dem dort
+// gültigen Pfad); Pfade unter --exclude gelten als nicht vorhanden.
 func readFileFromCommit(c *object.Commit, name string) (string, error) {
+	name = followPath(c, name)
+	if gitwalk.Excluded(name, excludeGlobs) {
+		return "", nil
+	}
 	f, err := c.File(name)
 	if err != nil || f == nil { // Datei fehlt
 		return "", err
@@ -301,7 +629,7 @@ func cfgVersions(txt string) map[string]string {
 			// Inline form: install_requires = pkg>=1.2.3, otherpkg
 			if tail := strings.TrimSpace(m[1]); tail != "" {
 				for _, part := range strings.Split(tail, ",") {
-					addDep(depLineRx, deps, part)
+					addDep(depLineRx, deps, strings.TrimSpace(part))
 				}
 			}
 			continue
@@ -352,380 +680,486 @@ func pyVersions(txt string) map[string]string {
 	return m
 }
 
-type pypiResp struct {
-	Releases map[string][]struct {
-		UploadTimeISO8601 string `json:"upload_time_iso_8601"`
-	} `json:"releases"`
-}
+// ---------- Conda (environment.yml) -------------------------------------------
 
-var pypiCache = map[string]map[string]time.Time{}
+// condaDepRx: [kanal::]name(=|==)version[=build]
+var condaDepRx = regexp.MustCompile(`^(?:([\w.\-]+)::)?([A-Za-z0-9_.\-]+)\s*==?\s*([0-9A-Za-z.+_\-]+)(?:=\S+)?$`)
 
-func pyRel(pkg, ver string) (time.Time, error) {
-	pkg = strings.ToLower(pkg)
-	if m, ok := pypiCache[pkg]; ok {
-		if t, ok2 := m[ver]; ok2 {
-			return t, nil
-		}
-	}
-	url := fmt.Sprintf("https://pypi.org/pypi/%s/json", pkg)
-	resp, err := http.Get(url)
-	if err != nil {
-		return time.Time{}, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != 200 {
-		return time.Time{}, fmt.Errorf("pypi %s", resp.Status)
-	}
-	body, _ := io.ReadAll(resp.Body)
-	var pr pypiResp
-	if err := json.Unmarshal(body, &pr); err != nil {
-		return time.Time{}, err
-	}
-	uploads := pr.Releases[ver]
-	if len(uploads) == 0 {
-		return time.Time{}, errors.New("keine uploads")
-	}
-	t, err := time.Parse(time.RFC3339, uploads[0].UploadTimeISO8601)
-	if err != nil {
-		return time.Time{}, err
-	}
-	if _, ok := pypiCache[pkg]; !ok {
-		pypiCache[pkg] = map[string]time.Time{}
-	}
-	pypiCache[pkg][ver] = t
-	return t, nil
-}
+// condaVersions liest die dependencies einer conda environment.yml.
+// Conda-Pakete werden als "kanal::name" geführt (Kanal aus dem Eintrag oder
+// dem ersten Eintrag unter channels), Pakete aus der verschachtelten
+// pip-Sektion als normale PyPI-Namen.
+func condaVersions(txt string) map[string]string {
+	deps := map[string]string{}
+	type condaDep struct{ channel, name, ver string }
+	var conda []condaDep
 
-// -----------------------------------------------------------------------------
-// ---------- ANALYSER ----------------------------------------------------------
-// -----------------------------------------------------------------------------
-func analyzeNPM(repo string) ([]delay, error) {
-	var since *time.Time
-	if lookBackDays > 0 {
-		t := time.Now().AddDate(0, 0, -lookBackDays)
-		since = &t
-	}
-	paths := []string{"package.json"}
-	hashes, err := commitsTouchingFiles(repo, paths, since, nil)
-	if err != nil {
-		return nil, err
-	}
-	if maxCommits > 0 && len(hashes) > maxCommits {
-		hashes = hashes[:maxCommits]
-	}
+	section := ""
+	channel := ""
+	pipIndent := -1 // >= 0 solange wir in "- pip:" sind
 
-	r, err := git.PlainOpen(repo)
-	if err != nil {
-		return nil, err
-	}
-	prev := map[string]string{}
-	out := []delay{}
-
-	// r, err := git.PlainOpen(repo)
-	// if err != nil {
-	// 	return nil, err
-	// }
-	// file := "package.json"
-	// opts := git.LogOptions{
-	// 	PathFilter: func(p string) bool { return p == "package.json" },
-	// } // kein Path-Filter
-	// if lookBackDays > 0 {
-	// 	since := time.Now().AddDate(0, 0, -lookBackDays)
-	// 	opts.Since = &since
-	// }
-	// iter, err := r.Log(&opts)
-	// if err != nil {
-	// 	return nil, err
-	// }
-	// var commits []*object.Commit
-	// _ = iter.ForEach(func(c *object.Commit) error { commits = append(commits, c); return nil })
-
-	// // Jüngste zuerst
-	// for i, j := 0, len(commits)-1; i < j; i, j = i+1, j-1 {
-	// 	commits[i], commits[j] = commits[j], commits[i]
-	// }
-	// if maxCommits > 0 && len(commits) > maxCommits {
-	// 	commits = commits[:maxCommits]
-	// }
-
-	// prev := map[string]string{}
-	// out := []delay{}
-
-CommitLoop:
-	for idx, h := range hashes {
-		c, err := r.CommitObject(plumbing.NewHash(h))
-		if err != nil {
+	for _, raw := range strings.Split(txt, "\n") {
+		line := strings.TrimRight(raw, "\r\t ")
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = strings.TrimRight(line[:i], " \t")
+		}
+		l := strings.TrimSpace(line)
+		if l == "" || strings.HasPrefix(l, "#") {
 			continue
 		}
-		blob, err := c.File("package.json")
-		if err != nil || blob == nil {
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+
+		// Top-Level-Key (name:, channels:, dependencies:, ...)
+		if indent == 0 && !strings.HasPrefix(l, "-") {
+			section, _, _ = strings.Cut(l, ":")
+			pipIndent = -1
 			continue
 		}
-		cont, _ := blob.Contents()
-		curr := npmVersions(cont)
-		if idx == 0 {
-			prev = curr
+		if !strings.HasPrefix(l, "-") {
 			continue
 		}
-		for dep, newV := range curr {
-			oldV, ok := prev[dep]
-			if !ok || oldV == newV {
-				continue
-			}
-			old := canon(oldV)
-			new := canon(newV)
+		item := strings.Trim(strings.TrimSpace(strings.TrimPrefix(l, "-")), `"'`)
 
-			if old == "" || new == "" { // unbekanntes Format → überspringen
-				continue
+		switch section {
+		case "channels":
+			if channel == "" {
+				channel = item
 			}
-			if semver.Compare(old, new) >= 0 { // neue Version ist nicht größer
-				continue // => Downgrade / equal  ⇒ ignorieren
-			}
-			rel, err := npmTimes.get(dep, newV)
-			if err != nil {
+		case "dependencies":
+			if item == "pip:" {
+				pipIndent = indent
 				continue
 			}
-			diff := c.Author.When.Sub(rel).Hours() / 24
-			if diff < 0 || diff > 365 {
+			if pipIndent >= 0 && indent > pipIndent {
+				if m := reqRx.FindStringSubmatch(item); len(m) == 3 {
+					deps[strings.ToLower(m[1])] = m[2]
+				}
 				continue
 			}
-			logChange(c, dep, oldV, newV)
-			out = append(out, delay{Dep: dep, OldVer: oldV, NewVer: newV, Days: diff,
-				CommitHash: c.Hash.String()[:7], CommitDate: c.Author.When})
-
-			if maxChanges > 0 && len(out) >= maxChanges {
-				break CommitLoop
+			pipIndent = -1
+			if m := condaDepRx.FindStringSubmatch(item); m != nil {
+				conda = append(conda, condaDep{channel: m[1], name: strings.ToLower(m[2]), ver: m[3]})
 			}
-			prev[dep] = newV
 		}
 	}
-	return out, nil
+
+	switch channel {
+	case "":
+		channel = "conda-forge"
+	case "defaults":
+		channel = "anaconda" // "defaults" heißt auf anaconda.org "anaconda"
+	}
+	for _, d := range conda {
+		ch := d.channel
+		if ch == "" {
+			ch = channel
+		}
+		deps[ch+"::"+d.name] = d.ver
+	}
+	return deps
+}
+
+// pyRegistry leitet conda-Pakete ("kanal::name") an anaconda.org weiter,
+// alle übrigen an PyPI.
+type pyRegistry struct {
+	pypi  *registry.PyPI
+	conda *registry.Conda
+}
+
+func (r pyRegistry) ReleaseTime(pkg, ver string) (time.Time, error) {
+	if strings.Contains(pkg, "::") {
+		return r.conda.ReleaseTime(pkg, ver)
+	}
+	return r.pypi.ReleaseTime(pkg, ver)
 }
 
 // -----------------------------------------------------------------------------
-// ---------- analyzeGo ---------------------------------------------------------
+// ---------- ANALYSER ----------------------------------------------------------
 // -----------------------------------------------------------------------------
-func analyzeGo(repo string) ([]delay, error) {
-	var since *time.Time
-	if lookBackDays > 0 {
-		t := time.Now().AddDate(0, 0, -lookBackDays)
-		since = &t
-	}
-	paths := []string{"go.mod"}
-	hashes, err := commitsTouchingFiles(repo, paths, since, nil)
-	if err != nil {
-		return nil, err
+
+// ecosystem beschreibt, welche Manifeste ein Ökosystem hat, wie daraus die
+// Versionen gelesen werden und woher die Release-Daten kommen.
+type ecosystem struct {
+	name  string
+	paths []string
+	// versions liest dep → Version aus einem Commit; leere Map = Commit überspringen
+	versions func(c *object.Commit) map[string]string
+	reg      registry.Client
+	// specs (optional) liefert die unveränderten Angaben, um den
+	// Constraint-Stil der Updates zu erfassen.
+	specs func(c *object.Commit) map[string]string
+	// kind (optional) klassifiziert die neue Version, z. B. Go-Pseudo-Versionen.
+	kind func(ver string) string
+	// upgrade (optional) ersetzt den semver-Vergleich für Versionen ohne
+	// Ordnung (z. B. Commit-SHAs).
+	upgrade func(dep, oldV, newV string) bool
+	// discover (optional) ergänzt paths um Pfade, die erst im Repo bekannt
+	// sind (z. B. Submodul-Verzeichnisse).
+	discover func(r *git.Repository) []string
+	// parse liest dep → Version aus einer Manifest-Datei beliebigen Namens;
+	// parseSpecs entsprechend die Angaben für specs. Ohne parse unterstützt
+	// das Ökosystem kein --manifest (s. manifest.go).
+	parse      func(file, txt string) map[string]string
+	parseSpecs func(file, txt string) map[string]string
+	// skips sammelt die übersprungenen Angaben dieses Laufs (s. getAnalyzer).
+	skips specSkips
+}
+
+// isUpgrade prüft, ob newV neuer ist als oldV.
+func (e ecosystem) isUpgrade(dep, oldV, newV string) bool {
+	if e.upgrade != nil {
+		return e.upgrade(dep, oldV, newV)
 	}
-	if maxCommits > 0 && len(hashes) > maxCommits {
-		hashes = hashes[:maxCommits]
+	old, new := canon(oldV), canon(newV)
+	if old == "" || new == "" { // unbekanntes Format → überspringen
+		return false
 	}
+	return semver.Compare(old, new) < 0 // Downgrade / gleich ⇒ ignorieren
+}
 
-	r, err := git.PlainOpen(repo)
-	if err != nil {
-		return nil, err
+func init() {
+	registerEcosystem("npm", npmEco)
+	registerEcosystem("go", goEco)
+	registerEcosystem("py", pyEco, "python")
+}
+
+func npmEco() ecosystem {
+	skips := specSkips{}
+	return ecosystem{
+		name:  "npm",
+		paths: []string{"package.json"},
+		versions: func(c *object.Commit) map[string]string {
+			txt, err := readFileFromCommit(c, "package.json")
+			if err != nil || txt == "" {
+				return nil
+			}
+			return npmVersions(txt, skips)
+		},
+		reg: &registry.NPM{},
+		specs: func(c *object.Commit) map[string]string {
+			txt, _ := readFileFromCommit(c, "package.json")
+			return npmSpecs(txt, skips)
+		},
+		parse:      func(_, txt string) map[string]string { return npmVersions(txt, skips) },
+		parseSpecs: func(_, txt string) map[string]string { return npmSpecs(txt, skips) },
+		skips:      skips,
 	}
-	prev := map[string]string{}
-	out := []delay{}
+}
 
-CommitLoop:
-	for idx, h := range hashes {
-		c, err := r.CommitObject(plumbing.NewHash(h))
-		if err != nil {
-			continue
-		}
-		blob, err := c.File("go.mod")
-		if err != nil || blob == nil {
-			continue
-		}
-		cont, _ := blob.Contents()
-		curr := goVersions(cont)
-		if idx == 0 {
-			prev = curr
-			continue
-		}
-		for mod, newV := range curr {
-			oldV, ok := prev[mod]
-			if !ok || oldV == newV {
-				continue
+func goEco() ecosystem {
+	// mods sind die go.mod-Pfade der Workspace-Module (s. goWorkModules);
+	// leer, wenn das Repo keine go.work hat.
+	var mods []string
+	return ecosystem{
+		name:  "go",
+		paths: []string{"go.mod", "go.work"},
+		versions: func(c *object.Commit) map[string]string {
+			if len(mods) == 0 {
+				txt, err := readFileFromCommit(c, "go.mod")
+				if err != nil || txt == "" {
+					return nil
+				}
+				return goVersions(txt)
 			}
-			old := canon(oldV)
-			new := canon(newV)
-			if old == "" || new == "" { // unbekanntes Format → überspringen
-				continue
+			// Schlüssel wie bei py "<go.mod>|<dep>": jedes Modul wird getrennt
+			// verfolgt und der Record dem Modul zugeordnet.
+			curr := map[string]string{}
+			for _, m := range mods {
+				if txt, err := readFileFromCommit(c, m); err == nil && txt != "" {
+					for k, v := range goVersions(txt) {
+						curr[m+fileSep+k] = v
+					}
+				}
 			}
-			if semver.Compare(old, new) >= 0 { // neue Version ist nicht größer
-				continue // => Downgrade / equal  ⇒ ignorieren
+			return curr
+		},
+		reg:   newGoRegistry(),
+		kind:  goVersionKind,
+		parse: func(_, txt string) map[string]string { return goVersions(txt) },
+		discover: func(r *git.Repository) []string {
+			mods = goWorkModules(r)
+			paths := []string{"go.mod", "go.work"}
+			for _, m := range mods {
+				if !slices.Contains(paths, m) {
+					paths = append(paths, m)
+				}
 			}
-			rel, err := goRelTime(mod, newV)
-			if err != nil {
-				continue
+			return paths
+		},
+	}
+}
+
+func pyEco() ecosystem {
+	return ecosystem{
+		name: "py",
+		paths: []string{"requirements.txt", "requirements-*.txt", "requirements/",
+			"setup.cfg", "environment.yml", "environment.yaml"},
+		versions: func(c *object.Commit) map[string]string {
+			// Schlüssel sind "<datei>|<dep>", damit jede Datei getrennt verfolgt
+			// und der Record mit seiner Quelle beschriftet wird.
+			curr := map[string]string{}
+			add := func(file string, deps map[string]string) {
+				for k, v := range deps {
+					curr[file+fileSep+k] = v
+				}
 			}
-			diff := c.Author.When.Sub(rel).Hours() / 24
-			if diff < 0 || diff > 365 {
-				continue
+
+			// 1) requirements.txt, requirements-*.txt, requirements/*.txt
+			for _, name := range requirementFiles(c) {
+				if txt, err := readFileFromCommit(c, name); err == nil && txt != "" {
+					add(name, pyVersions(txt))
+				}
 			}
-			logChange(c, mod, oldV, newV)
-			out = append(out, delay{Dep: mod, OldVer: oldV, NewVer: newV, Days: diff,
-				CommitHash: c.Hash.String()[:7], CommitDate: c.Author.When})
 
-			if maxChanges > 0 && len(out) >= maxChanges {
-				break CommitLoop
+			// 2) setup.cfg
+			if txt, err := readFileFromCommit(c, "setup.cfg"); err == nil && txt != "" {
+				add("setup.cfg", cfgVersions(txt))
 			}
-			prev[mod] = newV
-		}
+
+			// 3) conda environment.yml (inkl. pip-Sektion)
+			for _, name := range []string{"environment.yml", "environment.yaml"} {
+				if txt, err := readFileFromCommit(c, name); err == nil && txt != "" {
+					add(name, condaVersions(txt))
+				}
+			}
+			return curr
+		},
+		reg:   pyRegistry{pypi: &registry.PyPI{}, conda: &registry.Conda{}},
+		parse: pyManifest,
 	}
-	return out, nil
 }
 
-// -----------------------------------------------------------------------------
-// ---------- analyzePy ---------------------------------------------------------
-// -----------------------------------------------------------------------------
-func analyzePy(repo string) ([]delay, error) {
-	// r, err := git.PlainOpen(repo)
-	// if err != nil {
-	// 	return nil, err
-	// }
-	// file := "requirements.txt"
-	// if _, err := os.Stat(filepath.Join(repo, file)); err != nil {
-	// 	return nil, fmt.Errorf("%s fehlt", file)
-	// }
-	// opts := git.LogOptions{}
-	// if lookBackDays > 0 {
-	// 	since := time.Now().AddDate(0, 0, -lookBackDays)
-	// 	opts.Since = &since
-	// }
-	// iter, err := r.Log(&opts)
-	// if err != nil {
-	// 	return nil, err
-	// }
-	// var commits []*object.Commit
-	// _ = iter.ForEach(func(c *object.Commit) error { commits = append(commits, c); return nil })
-	// for i, j := 0, len(commits)-1; i < j; i, j = i+1, j-1 {
-	// 	commits[i], commits[j] = commits[j], commits[i]
-	// }
-	// if maxCommits > 0 && len(commits) > maxCommits {
-	// 	commits = commits[:maxCommits]
-	// }
-
-	// prev := map[string]string{}
-	// out := []delay{}
-	var since *time.Time
-	if lookBackDays > 0 {
-		t := time.Now().AddDate(0, 0, -lookBackDays)
-		since = &t
-	}
-	paths := []string{"requirements.txt", "setup.cfg"}
-	hashes, err := commitsTouchingFiles(repo, paths, since, nil)
-	if err != nil {
-		return nil, err
+// pyManifest wählt den Parser nach dem Dateinamen: setup.cfg, conda
+// environment*.yml oder sonst requirements-Format.
+func pyManifest(file, txt string) map[string]string {
+	base := path.Base(file)
+	switch {
+	case base == "setup.cfg":
+		return cfgVersions(txt)
+	case strings.HasPrefix(base, "environment") && (path.Ext(base) == ".yml" || path.Ext(base) == ".yaml"):
+		return condaVersions(txt)
 	}
-	if maxCommits > 0 && len(hashes) > maxCommits {
-		hashes = hashes[:maxCommits]
+	return pyVersions(txt)
+}
+
+// fileSep trennt in Versions-Maps die Quelldatei vom Dependency-Namen.
+const fileSep = "|"
+
+// splitDepKey zerlegt "<datei>|<dep>"; ohne Trenner ist file leer.
+func splitDepKey(key string) (dep, file string) {
+	if i := strings.LastIndex(key, fileSep); i >= 0 {
+		return key[i+1:], key[:i]
 	}
+	return key, ""
+}
 
-	r, err := git.PlainOpen(repo)
+// requirementFiles findet requirements.txt, requirements-*.txt und
+// requirements/*.txt im Commit.
+func requirementFiles(c *object.Commit) []string {
+	tree, err := c.Tree()
 	if err != nil {
-		return nil, err
+		return nil
 	}
-	prev := map[string]string{}
-	out := []delay{}
-
-CommitLoop:
-	for idx, h := range hashes {
-		c, err := r.CommitObject(plumbing.NewHash(h))
-		if err != nil {
+	var files []string
+	for _, e := range tree.Entries {
+		if !e.Mode.IsFile() {
 			continue
 		}
-		// blob, err := c.File("requirements.txt")
-		// if err != nil || blob == nil {
-		// 	continue
-		// }
-		// cont, _ := blob.Contents()
-		// curr := pyVersions(cont)
-
-		curr := map[string]string{}
-
-		// 1) requirements.txt
-		if txt, err := readFileFromCommit(c, "requirements.txt"); err == nil && txt != "" {
-			for k, v := range pyVersions(txt) {
-				curr[k] = v
+		if e.Name == "requirements.txt" || (strings.HasPrefix(e.Name, "requirements-") && path.Ext(e.Name) == ".txt") {
+			files = append(files, e.Name)
+		}
+	}
+	if dir, err := tree.Tree("requirements"); err == nil {
+		for _, e := range dir.Entries {
+			if e.Mode.IsFile() && path.Ext(e.Name) == ".txt" {
+				files = append(files, "requirements/"+e.Name)
 			}
 		}
+	}
+	sort.Strings(files)
+	return files
+}
 
-		// 2) setup.cfg
-		if txt, err := readFileFromCommit(c, "setup.cfg"); err == nil && txt != "" {
-			for k, v := range cfgVersions(txt) {
-				// Werte aus setup.cfg überschreiben evtl. requirements-Eintrag
-				curr[k] = v
-			}
+// reqScope ordnet eine Manifest-Datei "dev" oder "prod" zu, wie libyears
+// (s. depkind).
+func reqScope(file string) string {
+	if depkind.Requirements(file) == depkind.Dev {
+		return "dev"
+	}
+	return "prod"
+}
+
+// analyze läuft über alle Commits aus src, die ein Manifest von e berühren,
+// und meldet found je Versionssprung (Upgrade) die Verzögerung seit dem
+// Release, sobald sie feststeht; die Updates selbst behält analyze nicht.
+func analyze(src gitwalk.Source, e ecosystem, sc scope, found func(delay)) error {
+	var since *time.Time
+	if sc.Days > 0 {
+		t := time.Now().AddDate(0, 0, -sc.Days)
+		since = &t
+	}
+
+	var prev map[string]string // nil bis zum ersten Commit mit Dependencies
+	var prevSpecs map[string]string
+	n, seen := 0, 0
+
+	err := src.ForEach(e.paths, since, nil, func(c *object.Commit) error {
+		if sc.Commits > 0 && seen >= sc.Commits {
+			return storer.ErrStop
 		}
+		seen++
 
-		// Kein Dependency-Change in diesem Commit → überspringen
+		curr := e.versions(c)
+		// Manifest fehlt oder ohne Dependencies → überspringen
 		if len(curr) == 0 {
-			continue
+			return nil
 		}
-
-		if idx == 0 {
-			prev = curr
-			continue
+		var currSpecs map[string]string
+		if e.specs != nil {
+			currSpecs = e.specs(c)
+		}
+		if sample != nil { // Stichprobe: mit dem direkten Vorgänger vergleichen
+			prev, prevSpecs = parentVersions(e, c)
+		}
+		if prev == nil {
+			prev, prevSpecs = curr, currSpecs
+			return nil
 		}
-		for dep, newV := range curr {
+		deps := make([]string, 0, len(curr))
+		for dep := range curr {
+			deps = append(deps, dep)
+		}
+		sort.Strings(deps) // feste Reihenfolge, relevant für --changes
+		for _, dep := range deps {
+			newV := curr[dep]
 			oldV, ok := prev[dep]
-
 			if !ok || oldV == newV {
 				continue
 			}
-			old := canon(oldV)
-			new := canon(newV)
-			if old == "" || new == "" { // unbekanntes Format → überspringen
-				continue
+			name, file := splitDepKey(dep)
+			alias := ""
+			if a, target, ok := strings.Cut(name, npmAliasSep); ok {
+				alias, name = a, target
 			}
-			if semver.Compare(old, new) >= 0 { // neue Version ist nicht größer
-				continue // => Downgrade / equal  ⇒ ignorieren
+			if !e.isUpgrade(name, oldV, newV) {
+				continue
 			}
-			rel, err := pyRel(dep, newV)
+			rel, err := e.reg.ReleaseTime(name, newV)
 			if err != nil {
+				if unpublished(e.reg, name, newV) {
+					slog.Info("Version nie veröffentlicht", "dep", name, "ver", newV, "commit", c.Hash.String()[:7])
+					e.skips.add(skippedSpec{Eco: e.name, Dep: name, Spec: newV, Reason: reasonUnpublished})
+					continue
+				}
+				slog.Debug("Release-Datum nicht ermittelbar", "dep", name, "ver", newV, "err", err)
 				continue
 			}
-			diff := c.Author.When.Sub(rel).Hours() / 24
+			when, rel := normTime(commitTime(c), c), normTime(rel, c)
+			diff := when.Sub(rel).Hours() / 24
 			if diff < 0 || diff > 365 {
 				continue
 			}
 			logChange(c, dep, oldV, newV)
-			out = append(out, delay{Dep: dep, OldVer: oldV, NewVer: newV, Days: diff,
-				CommitHash: c.Hash.String()[:7], CommitDate: c.Author.When})
+			d := delay{Eco: e.name, Dep: name, Purl: purl.For(e.name, name, newV), OldVer: oldV, NewVer: newV, Days: diff,
+				Alias: alias, CommitHash: c.Hash.String()[:7], CommitDate: when,
+				AuthorDate: normTime(c.Author.When, c), CommitterDate: normTime(c.Committer.When, c)}
+			if file != "" {
+				d.File = file
+				if e.name == "py" {
+					d.ReqScope = reqScope(file)
+				}
+			}
+			if follow {
+				m := e.paths[0]
+				if file != "" {
+					m = file
+				}
+				d.Manifest = followPath(c, m)
+			}
+			if e.kind != nil {
+				d.Kind = e.kind(newV)
+			}
+			if vers, err := newerVersions(e.reg, name, oldV, newV); err != nil {
+				slog.Debug("Versionsliste nicht verfügbar", "dep", name, "err", err)
+			} else {
+				n := skippedReleases(vers, newV)
+				d.Skipped = &n
+				// Zeitpunkte nur bei Bedarf abfragen (Go: eine Anfrage je Version)
+				if sinceAvail {
+					if first, ok := firstAvailable(newerReleases(e.reg, name, vers)); ok {
+						days := when.Sub(normTime(first.time, c)).Hours() / 24
+						d.FirstAvailable, d.AvailableDays = first.ver, &days
+					}
+				}
+			}
+			if n, latest, ok := majorsBehind(e.reg, name, newV, when); ok {
+				d.MajorsBehind, d.LatestMajor = &n, latest
+			}
+			if pr := bots.pr(c); pr != nil {
+				days := pr.MergedAt.Sub(pr.CreatedAt).Hours() / 24
+				d.BotPR, d.PRLatencyDays = pr.Number, &days
+			}
+			d.CIChange = ciChange(e, c)
+			if currSpecs != nil {
+				d.OldStyle, d.NewStyle, d.Change = npmConstraint(prevSpecs[dep], currSpecs[dep])
+				prevSpecs[dep] = currSpecs[dep]
+			}
+			found(d)
+			n++
 
-			if maxChanges > 0 && len(out) >= maxChanges {
-				break CommitLoop
+			if sc.Changes > 0 && n >= sc.Changes {
+				return storer.ErrStop
 			}
 			prev[dep] = newV
 		}
-	}
-	return out, nil
+		return nil
+	})
+	return err
 }
 
 // -----------------------------------------------------------------------------
 // ---------- Repo-Handling & Utils --------------------------------------------
 // -----------------------------------------------------------------------------
-func getAnalyzer() (func(string) ([]delay, error), error) {
-	switch eco {
-	case "npm":
-		return analyzeNPM, nil
-	case "go":
-		return analyzeGo, nil
-	case "py", "python":
-		return analyzePy, nil
-	default:
-		return nil, fmt.Errorf("unbekanntes Ökosystem %q – erlaubt: npm | go | py", eco)
+// repoDir ist das Klon-Verzeichnis im Arbeitsverzeichnis, benannt nach dem
+// letzten Teil der URL. Getrennt wird an "/", "\" und ":" (scp-Form
+// git@host:org/repo), damit der Name auch unter Windows ein gültiger Pfad ist.
+func repoDir(url string) string {
+	url = strings.TrimRight(url, `/\`)
+	base := strings.TrimSuffix(url[strings.LastIndexAny(url, `/\:`)+1:], ".git")
+	if bare {
+		base += ".git"
 	}
+	return filepath.Join(".", base)
 }
 
-func repoDir(url string) string {
-	base := filepath.Base(strings.TrimSuffix(url, ".git"))
-	return "./" + base
+// runGitCheck ist der Smoke-Test von --check-git: Ist git nicht aufrufbar
+// oder kann es das Repo in target (falls ein lokales Verzeichnis) nicht
+// lesen, endet mttu mit Exit-Code 1 und einem Hinweis zur Behebung.
+func runGitCheck(target string) {
+	v, err := gitwalk.Check()
+	if err != nil {
+		logging.Fatal("git nicht verwendbar", "err", err)
+	}
+	fmt.Println(v)
+	if fi, err := os.Stat(target); err != nil || !fi.IsDir() {
+		return
+	}
+	if err := gitwalk.CheckRepo(target); err != nil {
+		logging.Fatal("git kann das Repo nicht lesen", "dir", target, "err", err)
+	}
+	i18n.Printf("Repo lesbar: %s\n", target)
 }
 
 func ensureRepo(url string) (string, error) {
+	// Lokaler Checkout (z. B. von "baa study") → direkt verwenden
+	if fi, err := os.Stat(url); err == nil && fi.IsDir() {
+		return url, nil
+	}
+	// Gemeinsamer Mirror (--mirror-dir) bleibt bis Prozessende gepinnt
+	if clones.Enabled() {
+		dir, _, err := clones.Mirror(url)
+		return dir, err
+	}
 	dir := repoDir(url)
 	token := os.Getenv("GH_TOKEN")
 	var auth *githttp.BasicAuth
@@ -733,19 +1167,15 @@ func ensureRepo(url string) (string, error) {
 		auth = &githttp.BasicAuth{Username: "token", Password: token}
 	}
 	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		if verbose {
-			log.Printf("Klonen %s → %s", url, dir)
-		}
-		_, err = git.PlainClone(dir, false, &git.CloneOptions{
+		slog.Info("Klonen", "url", url, "dir", dir)
+		_, err = git.PlainClone(dir, bare, &git.CloneOptions{
 			URL:      url,
 			Auth:     auth,
 			Progress: os.Stderr,
 		})
 		return dir, err
 	}
-	if verbose {
-		log.Printf("Verwende vorhandenes Repo %s", dir)
-	}
+	slog.Info("Verwende vorhandenes Repo", "dir", dir)
 	return dir, nil
 }
 
@@ -772,63 +1202,360 @@ func median(xs []float64) float64 {
 	return xs[m]
 }
 
+func printGroups(title string, g map[string]group) {
+	if g == nil {
+		return
+	}
+	keys := make([]string, 0, len(g))
+	for k := range g {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fmt.Printf("\n%s:\n", i18n.T(title))
+	for _, k := range keys {
+		fmt.Printf("  %-8s %4d Updates  Mean %6.1f d  Median %6.1f d\n", k, g[k].Updates, g[k].MeanDays, g[k].MedianDays)
+	}
+}
+
+// bootstrapCI schätzt das 95-%-Intervall des Mittelwerts per Perzentil-
+// Bootstrap. Der feste Seed hält die Ausgabe zwischen Läufen stabil.
+func bootstrapCI(xs []float64, n int) *[2]float64 {
+	if len(xs) < 2 || n <= 0 {
+		return nil
+	}
+	rng := rand.New(rand.NewSource(1))
+	means := make([]float64, n)
+	for i := range means {
+		sum := 0.0
+		for range xs {
+			sum += xs[rng.Intn(len(xs))]
+		}
+		means[i] = sum / float64(len(xs))
+	}
+	sort.Float64s(means)
+	lo := means[int(0.025*float64(n-1))]
+	hi := means[int(math.Ceil(0.975*float64(n-1)))]
+	return &[2]float64{lo, hi}
+}
+
 // -----------------------------------------------------------------------------
 // ---------- main --------------------------------------------------------------
 // -----------------------------------------------------------------------------
 func main() {
+	flag.Lookup("eco").Usage = "Ökosystem: " + ecosystemNames() + "; mehrere kommagetrennt (go,npm) oder all"
 	flag.Parse()
+	provenance.Start("mttu", flag.CommandLine)
+	if verbose {
+		logOpts.Level = "debug"
+	}
+	if err := logOpts.Setup(); err != nil {
+		logging.Fatal("Logging-Setup fehlgeschlagen", "err", err)
+	}
+	if err := netOpts.Setup(); err != nil {
+		logging.Fatal("Netz-Setup fehlgeschlagen", "err", err)
+	}
+	if err := anonOpts.Setup(); err != nil {
+		logging.Usage("ungültiges --anonymize", "err", err)
+	}
+	if err := schemaOpts.Setup(); err != nil {
+		logging.Usage("ungültiges --schema-version", "err", err)
+	}
+	if err := cloneOpts.Setup(); err != nil {
+		logging.Usage("ungültige Mirror-Flags", "err", err)
+	}
+	if err := langOpts.Setup(); err != nil {
+		logging.Usage("ungültiges --lang", "err", err)
+	}
+	if err := exitOpts.Setup(); err != nil {
+		logging.Usage("ungültiges --max-skipped", "err", err)
+	}
+	if checkGit {
+		runGitCheck(flag.Arg(0))
+		return
+	}
 	if flag.NArg() < 1 {
-		log.Fatal("Usage: go run multi_mttu.go --eco <npm|go|py> (--commits N | --changes N | --days N) <git-url>")
+		logging.Usage("Usage: go run multi_mttu.go --eco <" + strings.ReplaceAll(ecosystemNames(), " | ", "|") + ">[,…]|all (--commits N | --changes N | --days N) [--exclude globs] [--manifest path[,path...]] [--dedupe-window 7d] [--tz utc|local|author] [--date author|committer] [--bare] [--git go-git|cli] [--check-git] [--remote-api] [--repo-meta] [--bot-pr] [--sample every-nth=K|random=N,seed=S] [--dry-run] [--follow] [--since-available] [--ca-bundle pem] [--insecure-skip-verify] [--top N] [--min-sample N] [--bootstrap N] [--out file.json[.gz|.zst] [--format jsonl] [--chunk-size N]] [--timeline dep [--timeline-out file.mmd|.dot]] [--schema-version N] [--mirror-dir dir [--clone-quota 50G]] [--anonymize] [--github-pr N [--base base.json]] [--log-level L] [--log-format text|json] [--lang en|de] [--max-skipped F] <git-url|dir>")
 	}
 	validateScopeFlags()
+	switch tzPolicy {
+	case "utc", "local", "author":
+	default:
+		logging.Usage("--tz muss utc, local oder author sein", "tz", tzPolicy)
+	}
+	if outFormat != "json" && outFormat != "jsonl" {
+		logging.Usage("--format muss json oder jsonl sein", "format", outFormat)
+	}
+	checkOutput()
+	if datePolicy != "author" && datePolicy != "committer" {
+		logging.Usage("--date muss author oder committer sein", "date", datePolicy)
+	}
 
 	repoURL := flag.Arg(0)
-	dir, err := ensureRepo(repoURL)
+	analyzers, err := getAnalyzers()
 	if err != nil {
-		log.Fatal(err)
+		logging.Usage("Analyzer-Auswahl fehlgeschlagen", "eco", eco, "err", err)
 	}
-	analyzer, err := getAnalyzer()
-	if err != nil {
-		log.Fatal(err)
+	if len(manifests) > 0 {
+		if len(analyzers) > 1 {
+			logging.Usage("--manifest geht nur mit genau einem --eco", "eco", eco)
+		}
+		if analyzers[0], err = withManifests(analyzers[0], manifests); err != nil {
+			logging.Usage("ungültiges --manifest", "err", err)
+		}
+	}
+	var r *git.Repository
+	if remoteAPI {
+		if follow {
+			logging.Usage("--remote-api geht nicht mit --follow")
+		}
+		if len(analyzers) > 1 {
+			logging.Usage("--remote-api geht nur mit genau einem --eco", "eco", eco)
+		}
+		e := analyzers[0]
+		var since *time.Time
+		if lookBackDays > 0 {
+			t := time.Now().AddDate(0, 0, -lookBackDays)
+			since = &t
+		}
+		r, err = remoteRepo(repoURL, e, since)
+		switch {
+		case errors.Is(err, registry.ErrRateLimited):
+			slog.Warn("GitHub-Rate-Limit erreicht – klone stattdessen", "err", err)
+			r = nil
+		case err != nil:
+			logging.Fatal("GitHub-API fehlgeschlagen", "url", repoURL, "err", err)
+		}
+		// Die API liefert nur die Dateien aus e.paths; findet discover weitere
+		// (Submodule, go.work-Module), braucht es doch einen Klon.
+		if r != nil && e.discover != nil {
+			if extra := slices.DeleteFunc(e.discover(r), func(p string) bool { return slices.Contains(e.paths, p) }); len(extra) > 0 {
+				slog.Warn("weitere Manifeste nur im Klon lesbar – klone stattdessen", "paths", extra)
+				r = nil
+			}
+		}
 	}
-	delays, err := analyzer(dir)
+	ciOff = r != nil // In-Memory-Repo aus --remote-api: nur die Manifeste
+	dir := ""
+	if r == nil {
+		if dir, err = ensureRepo(repoURL); err != nil {
+			logging.Fatal("Repo nicht verfügbar", "url", repoURL, "err", err)
+		}
+		if r, err = git.PlainOpen(dir); err != nil {
+			logging.Fatal("Repo nicht lesbar", "dir", dir, "err", err)
+		}
+	}
+	// Alle Ökosysteme laufen über denselben Klon; bei --eco all nur die,
+	// deren Manifeste im Repo liegen.
+	var names []string
+	for i := range analyzers {
+		e := &analyzers[i]
+		if e.discover != nil {
+			e.paths = e.discover(r)
+		}
+		if eco == "all" && !applicable(r, *e) {
+			continue
+		}
+		if follow {
+			if *e, err = withFollow(r, *e); err != nil {
+				logging.Fatal("--follow fehlgeschlagen", "eco", e.name, "err", err)
+			}
+		}
+		names = append(names, e.name)
+	}
+	if eco == "all" {
+		analyzers = slices.DeleteFunc(analyzers, func(e ecosystem) bool { return !slices.Contains(names, e.name) })
+		if len(analyzers) == 0 {
+			logging.Fatal("--eco all: keine bekannten Manifeste im Repo", "repo", repoURL)
+		}
+		slog.Info("Ökosysteme erkannt", "eco", strings.Join(names, ","))
+	}
+	ecoLabel := strings.Join(names, ",")
+	backend := gitBackend
+	if dir == "" {
+		backend = "go-git" // In-Memory-Repo aus --remote-api
+	}
+	src, err := gitwalk.New(backend, dir, r, excludeGlobs)
 	if err != nil {
-		log.Fatal(err)
+		logging.Usage("--git ungültig", "err", err)
 	}
-	if len(delays) == 0 {
-		log.Println("Keine Updates erkannt – möglicherweise keine direkten Dependencies oder Filter zu eng")
+	// --sample: jedes Ökosystem zieht aus seinen eigenen Manifest-Commits
+	srcFor := func(e ecosystem) gitwalk.Source {
+		if sample == nil {
+			return src
+		}
+		return sampledSource{src: src, s: sample, eco: e.name}
+	}
+	if dryRun {
+		var commits []plannedCommit
+		for _, e := range analyzers {
+			cs, err := plan(srcFor(e), e, currentScope())
+			if err != nil {
+				logging.Fatal("Dry-Run fehlgeschlagen", "repo", repoURL, "eco", e.name, "err", err)
+			}
+			commits = append(commits, cs...)
+		}
+		res := newDryRunResult(repoURL, ecoLabel, commits)
+		res.anonymize()
+		if outFile != "" {
+			if err := writeOut(outFile, res); err != nil {
+				logging.Fatal("JSON-Ausgabe fehlgeschlagen", "file", outFile, "err", err)
+			}
+		}
+		printDryRun(res)
 		return
 	}
-
-	vals := make([]float64, len(delays))
-	for i, d := range delays {
-		vals[i] = d.Days
+	slug := repoSlug(repoURL, r)
+	if botPR {
+		bots = newBotPRs(slug)
+	}
+	var base *result
+	if githubPR > 0 {
+		base = loadBase()
+	}
+	// behalten werden nur die Updates für --timeline und den PR-Kommentar
+	unknown := func(delay) bool { return false }
+	if base != nil {
+		unknown = unknownTo(base)
+	}
+	t := newTally(func(d delay) bool {
+		return (timelineDep != "" && (d.Dep == timelineDep || d.Alias == timelineDep)) || unknown(d)
+	})
+	openSink()
+	openSpool()
+	// record gibt ein fertiges Update aus und nimmt es in die Kennzahlen auf.
+	record := func(d delay) {
+		emitDelay(d)
+		spoolDelay(d)
+		t.add(d)
+	}
+	found := record
+	var bumps []delay // nur --dedupe-window: das braucht alle Bumps
+	if dedupeWindow > 0 {
+		found = func(d delay) { bumps = append(bumps, d) }
+	}
+	skips := specSkips{}
+	for _, e := range analyzers {
+		if err := analyze(srcFor(e), e, currentScope(), found); err != nil {
+			logging.Fatal("Analyse fehlgeschlagen", "repo", repoURL, "eco", e.name, "err", err)
+		}
+		maps.Copy(skips, e.skips)
+	}
+	var raw *rawSummary
+	if dedupeWindow > 0 {
+		raw = newRawSummary(flag.Lookup("dedupe-window").Value.String(), bumps)
+		for _, d := range dedupeBumps(bumps, dedupeWindow) {
+			record(d)
+		}
+		bumps = nil
+	}
+	sum := t.summary(len(analyzers) > 1)
+	sum.Raw = raw
+	var meta *repoMeta
+	if withMeta {
+		meta = fetchRepoMeta(slug)
+	}
+	res := result{
+		Schema:  schema.ID("mttu-result"),
+		Repo:    repoURL,
+		Eco:     ecoLabel,
+		Meta:    meta,
+		Sample:  sample,
+		TZ:      tzPolicy,
+		Date:    datePolicy,
+		Scope:   currentScope(),
+		Summary: sum,
+	}
+	if len(skips) > 0 {
+		res.SkippedSpecs = skips.sorted()
+	}
+	// --max-skipped: Dependencies ohne Registry-Version gegenüber allen
+	// erkannten Dependencies
+	for k := range skips {
+		t.deps[k] = true
+	}
+	exitcode.Skipped(len(skips), len(t.deps))
+	res.Provenance = provenance.Get()
+	if githubPR > 0 {
+		pr := res
+		pr.Updates = t.kept
+		postPRComment(base, pr)
+	}
+	if timelineDep != "" {
+		if err := writeTimeline(repoURL, t.kept); err != nil {
+			slog.Warn("--timeline nicht geschrieben", "dep", timelineDep, "err", err)
+		}
+	}
+	res.anonymize() // nach dem PR-Kommentar, der bleibt im eigenen Repo
+	if sink != nil {
+		finishSink(res)
+	} else if spool.f != nil {
+		writeResult(res)
+	}
+	if t.n > 0 && res.Summary.LowSample {
+		slog.Warn("Zu wenige Updates – Mean/Median kaum aussagekräftig",
+			"n", t.n, "min_sample", minSample)
+	}
+	if t.n == 0 {
+		slog.Warn("Keine Updates erkannt – möglicherweise keine direkten Dependencies oder Filter zu eng",
+			"repo", repoURL, "eco", ecoLabel)
+		exitcode.Finish()
+		return
 	}
 
 	// -------------------- Summary --------------------------------------------
-	fmt.Printf("\nSummary für %s (%s)\n", repoURL, eco)
+	i18n.Printf("\nSummary für %s (%s)\n", repoURL, ecoLabel)
 	switch {
 	case maxCommits > 0:
-		fmt.Printf("Rückblick              : genau %d Commits\n", maxCommits)
+		i18n.Printf("Rückblick              : genau %d Commits\n", maxCommits)
 	case lookBackDays > 0:
-		fmt.Printf("Rückblick              : letzte %d Tage\n", lookBackDays)
+		i18n.Printf("Rückblick              : letzte %d Tage\n", lookBackDays)
 	case maxChanges > 0:
-		fmt.Printf("Stop nach              : %d Datei-Änderungen\n", maxChanges)
-	}
-	fmt.Printf("Analysierte Updates    : %d\n", len(delays))
-	fmt.Printf("MTTU-Mean              : %.1f Tage\n", mean(vals))
-	fmt.Printf("MTTU-Median            : %.1f Tage\n", median(vals))
-
-	sort.Slice(delays, func(i, j int) bool { return delays[i].Days > delays[j].Days })
-	top := 10
-	if len(delays) < top {
-		top = len(delays)
-	}
-	fmt.Println("\nLangsamste Updates:")
-	for i := 0; i < top; i++ {
-		d := delays[i]
-		fmt.Printf("%-40s %7.0f d  (%s → %s) [%s %s]\n",
-			d.Dep, d.Days, d.OldVer, d.NewVer,
+		i18n.Printf("Stop nach              : %d Datei-Änderungen\n", maxChanges)
+	}
+	if s := sample; s != nil {
+		s.print()
+	}
+	i18n.Printf("Analysierte Updates    : %d (n)\n", t.n)
+	if r := sum.Raw; r != nil {
+		i18n.Printf("Vor Zusammenfassung    : %d Bumps (Fenster %s), Mean %.1f / Median %.1f Tage\n", r.Updates, r.Window, r.MeanDays, r.MedianDays)
+	}
+	if ci := res.Summary.MeanCI95; ci != nil {
+		i18n.Printf("MTTU-Mean              : %.1f Tage (95%%-KI %.1f – %.1f)\n", sum.MeanDays, ci[0], ci[1])
+	} else {
+		i18n.Printf("MTTU-Mean              : %.1f Tage\n", sum.MeanDays)
+	}
+	i18n.Printf("MTTU-Median            : %.1f Tage\n", sum.MedianDays)
+	if n := len(res.SkippedSpecs); n > 0 {
+		reasons := map[string]int{}
+		for _, s := range res.SkippedSpecs {
+			reasons[s.Reason]++
+		}
+		i18n.Printf("Ohne Registry-Version  : %d Dependencies %v\n", n, reasons)
+	}
+	if m := sum.MeanSkipped; m != nil {
+		i18n.Printf("Übersprungene Releases : %.1f je Update (Mittel)\n", *m)
+	}
+	if g := sum.SinceAvailable; g != nil {
+		i18n.Printf("Ab erstem neuen Release: Mean %.1f / Median %.1f Tage (n=%d)\n", g.MeanDays, g.MedianDays, g.Updates)
+	}
+	if g := sum.BotPRLatency; g != nil {
+		i18n.Printf("Bot-PR offen bis Merge : Mean %.1f / Median %.1f Tage (n=%d PRs)\n", g.MeanDays, g.MedianDays, g.Updates)
+	}
+	printMajorLag(sum.MajorLag)
+	printGroups("Nach Ökosystem", sum.ByEco)
+	printGroups("Nach Constraint-Änderung", sum.ByConstraint)
+	printGroups("Nach Versionsart", sum.ByVersionKind)
+
+	i18n.Println("\nLangsamste Updates:")
+	for _, d := range t.top {
+		d = anonDelay(d)
+		skipped := ""
+		if d.Skipped != nil && *d.Skipped > 0 {
+			skipped = i18n.Sprintf(", %d übersprungen", *d.Skipped)
+		}
+		fmt.Printf("%-40s %7.0f d  (%s → %s%s) [%s %s]\n",
+			d.Dep, d.Days, d.OldVer, d.NewVer, skipped,
 			d.CommitDate.Format("06-01-02"), d.CommitHash)
 	}
+	exitcode.Finish()
 }
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"maps"
+	"testing"
+	"time"
+
+	"baa_fs25/shared/gitwalk/gitwalktest"
+	"baa_fs25/shared/registry/registrytest"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestCanon(t *testing.T) {
+	for in, want := range map[string]string{
+		"1.2.3":        "v1.2.3",
+		"v1.2.3":       "v1.2.3",
+		"1.2":          "v1.2.0",
+		"v2":           "v2.0.0",
+		"1.0.0-rc.1":   "v1.0.0-rc.1",
+		"1.0.0+build":  "v1.0.0",
+		"latest":       "",
+		"^1.2.3":       "",
+		"1.2.3.4":      "",
+		"":             "",
+		"v0.0.0-2024x": "v0.0.0-2024x",
+	} {
+		if got := canon(in); got != want {
+			t.Errorf("canon(%q) = %q, erwartet %q", in, got, want)
+		}
+	}
+}
+
+func TestIsUpgrade(t *testing.T) {
+	var e ecosystem
+	for _, tc := range []struct {
+		old, new string
+		want     bool
+	}{
+		{"1.2.3", "1.2.4", true},
+		{"1.2.3", "v2.0.0", true},
+		{"1.10.0", "1.9.0", false}, // numerisch, nicht lexikographisch
+		{"1.2.3", "1.2.3", false},
+		{"1.0.0-rc.1", "1.0.0", true},
+		{"1.0.0", "1.0.0-rc.1", false},
+		{"1.2.3", "latest", false}, // unbekanntes Format
+	} {
+		if got := e.isUpgrade("x", tc.old, tc.new); got != tc.want {
+			t.Errorf("isUpgrade(%q, %q) = %v, erwartet %v", tc.old, tc.new, got, tc.want)
+		}
+	}
+	e.upgrade = func(_, oldV, newV string) bool { return oldV != newV }
+	if !e.isUpgrade("x", "abc", "def") {
+		t.Error("upgrade ersetzt den semver-Vergleich nicht")
+	}
+}
+
+func TestVersionParsers(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		parse func(string) map[string]string
+		txt   string
+		want  map[string]string
+	}{
+		{"npm", func(s string) map[string]string { return npmVersions(s, nil) }, `{
+  "dependencies": {"express": "^4.18.2", "lodash": "~4.17.21", "left-pad": "1.3.0",
+                   "b": "npm:@scope/lib@^2.0.0", "mine": "file:../mine"},
+  "devDependencies": {"jest": "^29.0.0"}
+}`, map[string]string{"express": "4.18.2", "lodash": "4.17.21", "left-pad": "1.3.0", "b@npm:@scope/lib": "2.0.0"}},
+		{"npm kaputt", func(s string) map[string]string { return npmVersions(s, nil) }, `{`, map[string]string{}},
+		{"go", goVersions, `module example.com/x
+
+go 1.22
+
+require github.com/pkg/errors v0.9.1
+
+require (
+	golang.org/x/mod v0.17.0
+	github.com/stretchr/testify v1.9.0 // indirect
+)
+
+replace golang.org/x/mod => ../mod
+`, map[string]string{"github.com/pkg/errors": "v0.9.1", "golang.org/x/mod": "v0.17.0", "github.com/stretchr/testify": "v1.9.0"}},
+		{"py", pyVersions, `# Kommentar
+Django==4.2.7
+requests>=2.31
+numpy==1.26.2 ; python_version >= "3.9"
+-r base.txt
+`, map[string]string{"django": "4.2.7"}},
+		{"setup.cfg", cfgVersions, `[metadata]
+name = x
+
+[options]
+install_requires =
+    Flask>=2.3.0
+    click==8.1.7
+    # Kommentar
+    itsdangerous
+
+[options.extras_require]
+dev = pytest==7.4.0
+`, map[string]string{"flask": "2.3.0", "click": "8.1.7", "itsdangerous": ""}},
+		{"setup.cfg inline", cfgVersions, "[options]\ninstall_requires = six>=1.16.0, attrs\n", map[string]string{"six": "1.16.0", "attrs": ""}},
+		{"conda", condaVersions, `name: env
+channels:
+  - defaults
+  - conda-forge
+dependencies:
+  - python=3.11
+  - numpy==1.26.0 # Kommentar
+  - bioconda::samtools=1.19=h50ea8bc_0
+  - pip
+  - pip:
+    - requests==2.31.0
+    - flask>=2.0
+  - pandas=2.1.4
+`, map[string]string{"anaconda::python": "3.11", "anaconda::numpy": "1.26.0", "bioconda::samtools": "1.19",
+			"requests": "2.31.0", "anaconda::pandas": "2.1.4"}},
+		{"conda ohne channels", condaVersions, "dependencies:\n  - scipy=1.11.4\n", map[string]string{"conda-forge::scipy": "1.11.4"}},
+	} {
+		if got := tc.parse(tc.txt); !maps.Equal(got, tc.want) {
+			t.Errorf("%s: %v, erwartet %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+// TestAnalyze lässt analyze über ein In-Memory-Repo mit fester Registry
+// laufen: Upgrades, Downgrades, nie veröffentlichte Versionen, negative
+// Verzögerungen und --changes.
+func TestAnalyze(t *testing.T) {
+	day := func(n int) time.Time { return time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC).AddDate(0, 0, n) }
+	pkg := func(deps string) map[string]string {
+		return map[string]string{"package.json": `{"dependencies": {` + deps + `}}`}
+	}
+	f := gitwalktest.New()
+	f.Commit(day(0), pkg(`"a": "^1.0.0", "b": "2.0.0", "c": "github:x/c"`))
+	f.Commit(day(5), map[string]string{"README": "nur Doku"})
+	f.Commit(day(10), pkg(`"a": "^1.1.0", "b": "2.0.0", "c": "github:x/c"`)) // a: 1.0.0 → 1.1.0
+	f.Commit(day(20), pkg(`"a": "^1.0.5", "b": "2.1.0"`))                    // a: Downgrade, b: vor dem Release
+	f.Commit(day(30), pkg(`"a": "^1.2.0", "b": "2.2.0"`))                    // a: 1.2.0 nie veröffentlicht, b: 2.2.0
+	f.Commit(day(40), map[string]string{"README": "x"}, "package.json")      // Manifest gelöscht
+	f.Commit(day(50), pkg(`"a": "^1.3.0", "b": "2.2.0"`))                    // a: ab 1.1.0, dem letzten gezählten Stand
+	src, err := f.Source()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg := registrytest.Static{
+		"a": {"1.0.0": day(-100), "1.0.5": day(-50), "1.1.0": day(4), "1.3.0": day(45)},
+		"b": {"2.0.0": day(-300), "2.1.0": day(25), "2.2.0": day(28), "3.0.0": day(29)},
+	}
+	e := npmEco()
+	e.reg = reg
+
+	run := func(sc scope) []delay {
+		var ds []delay
+		if err := analyze(src, e, sc, func(d delay) { ds = append(ds, d) }); err != nil {
+			t.Fatal(err)
+		}
+		return ds
+	}
+	ds := run(scope{Commits: 100})
+	type upd struct {
+		dep, old, new string
+		days          float64
+	}
+	want := []upd{{"a", "1.0.0", "1.1.0", 6}, {"b", "2.0.0", "2.2.0", 2}, {"a", "1.1.0", "1.3.0", 5}}
+	if len(ds) != len(want) {
+		t.Fatalf("%d Updates, erwartet %d: %+v", len(ds), len(want), ds)
+	}
+	for i, w := range want {
+		d := ds[i]
+		if d.Dep != w.dep || d.OldVer != w.old || d.NewVer != w.new || d.Days != w.days {
+			t.Errorf("Update %d = %s %s→%s %.1f d, erwartet %+v", i, d.Dep, d.OldVer, d.NewVer, d.Days, w)
+		}
+	}
+	if d := ds[1]; d.MajorsBehind == nil || *d.MajorsBehind != 1 || d.LatestMajor != "v3" {
+		t.Errorf("b 2.2.0: majors_behind %v/%q, erwartet 1/v3", d.MajorsBehind, d.LatestMajor)
+	}
+	if d := ds[1]; d.Skipped == nil || *d.Skipped != 1 {
+		t.Errorf("b 2.0.0→2.2.0: skipped %v, erwartet 1 (2.1.0)", d.Skipped)
+	}
+	if d := ds[0]; d.Change != "floor" || d.NewStyle != "caret" {
+		t.Errorf("a: constraint %q/%q, erwartet floor/caret", d.Change, d.NewStyle)
+	}
+
+	got := e.skips.sorted()
+	if len(got) != 2 || got[0].Dep != "a" || got[0].Reason != reasonUnpublished || got[1].Dep != "c" || got[1].Reason != "git" {
+		t.Errorf("skipped_specs = %+v", got)
+	}
+
+	if ds := run(scope{Commits: 100, Changes: 2}); len(ds) != 2 {
+		t.Errorf("--changes 2: %d Updates", len(ds))
+	}
+	if ds := run(scope{Commits: 3}); len(ds) != 1 {
+		t.Errorf("--commits 3: %d Updates, erwartet 1", len(ds))
+	}
+}
+
+// TestNormTime prüft, dass --tz author den Offset der per --date gewählten
+// Signatur verwendet.
+func TestNormTime(t *testing.T) {
+	defer func(tz, date string) { tzPolicy, datePolicy = tz, date }(tzPolicy, datePolicy)
+	cet, ny := time.FixedZone("", 3600), time.FixedZone("", -5*3600)
+	c := &object.Commit{
+		Author:    object.Signature{When: time.Date(2024, 3, 1, 23, 30, 0, 0, cet)},
+		Committer: object.Signature{When: time.Date(2024, 3, 1, 20, 0, 0, 0, ny)},
+	}
+	tzPolicy = "author"
+	for date, want := range map[string]int{"author": 3600, "committer": -5 * 3600} {
+		datePolicy = date
+		if _, off := normTime(c.Author.When, c).Zone(); off != want {
+			t.Errorf("--date %s: Offset %d, erwartet %d", date, off, want)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"baa_fs25/shared/logging"
+	"github.com/klauspost/compress/zstd"
+)
+
+// -----------------------------------------------------------------------------
+// ---------- Komprimierte und geteilte Ausgabe (--out *.gz|*.zst, --chunk-size)
+// -----------------------------------------------------------------------------
+
+// Korpus-Läufe erzeugen Millionen Update-Records. Endet --out auf .gz oder
+// .zst, wird die Ausgabe komprimiert; --chunk-size N verteilt die Updates auf
+// Dateien mit je höchstens N Records, deren Namen vor der Endung eine
+// laufende Nummer tragen (results.jsonl.zst → results-00001.jsonl.zst, …).
+// Bei --format json enthält jede Datei das vollständige Ergebnis mit ihrem
+// Teil der Updates und chunk, bei jsonl steht die Zusammenfassung in der
+// letzten Datei.
+
+var chunkSize int
+
+// chunkInfo kennzeichnet eine Teildatei (nur --chunk-size).
+type chunkInfo struct {
+	Index int `json:"index"` // ab 1
+	Of    int `json:"of"`    // Anzahl Dateien; bei jsonl erst in der letzten bekannt
+}
+
+// checkOutput prüft --chunk-size gegen --out.
+func checkOutput() {
+	if chunkSize < 0 {
+		logging.Usage("ungültiges --chunk-size", "value", chunkSize)
+	}
+	if chunkSize > 0 && (outFile == "" || outFile == "-") {
+		logging.Usage("--chunk-size braucht --out mit Dateinamen")
+	}
+	if outFile == "-" && compression(outFile) != "" {
+		logging.Usage("stdout wird nicht komprimiert")
+	}
+}
+
+// compression liefert die Kompressions-Endung von name (".gz", ".zst" oder "").
+func compression(name string) string {
+	switch ext := strings.ToLower(filepath.Ext(name)); ext {
+	case ".gz", ".zst":
+		return ext
+	}
+	return ""
+}
+
+// chunkName fügt die Nummer i vor der Endung von name ein; Kompressions- und
+// Format-Endung bleiben erhalten.
+func chunkName(name string, i int) string {
+	comp := name[len(name)-len(compression(name)):]
+	base := strings.TrimSuffix(name, comp)
+	ext := filepath.Ext(base)
+	return fmt.Sprintf("%s-%05d%s%s", strings.TrimSuffix(base, ext), i, ext, comp)
+}
+
+// outWriter schließt beim Close den Kompressor und danach die Datei.
+type outWriter struct {
+	io.Writer
+	closers []io.Closer
+}
+
+func (w *outWriter) Close() error {
+	var first error
+	for _, c := range w.closers {
+		if err := c.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+// createOut legt name an ("-" = stdout) und komprimiert je nach Endung.
+func createOut(name string) (io.WriteCloser, error) {
+	if name == "-" {
+		return nopCloser{os.Stdout}, nil
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	switch compression(name) {
+	case ".gz":
+		zw := gzip.NewWriter(f)
+		return &outWriter{Writer: zw, closers: []io.Closer{zw, f}}, nil
+	case ".zst":
+		zw, err := zstd.NewWriter(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return &outWriter{Writer: zw, closers: []io.Closer{zw, f}}, nil
+	}
+	return f, nil
+}
+
+// writeOut schreibt v eingerückt als JSON nach name (wie report.WriteJSON,
+// aber mit Kompression).
+func writeOut(name string, v any) error {
+	w, err := createOut(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// spool hält bei --format json die Updates bis zum Schluss in einer
+// temporären Datei (eine Zeile je Update, bereits anonymisiert) statt im
+// Speicher; writeResult setzt sie dann in die Ausgabe ein.
+var spool struct {
+	f   *os.File
+	enc *json.Encoder
+	n   int
+}
+
+// openSpool legt die Datei an, sofern --out mit --format json gesetzt ist.
+func openSpool() {
+	if outFile == "" || outFormat == "jsonl" {
+		return
+	}
+	f, err := os.CreateTemp("", "mttu-updates-*.jsonl")
+	if err != nil {
+		logging.Fatal("Zwischendatei nicht anlegbar", "err", err)
+	}
+	spool.f, spool.enc = f, json.NewEncoder(f)
+}
+
+// spoolDelay legt ein fertiges Update für writeResult ab.
+func spoolDelay(d delay) {
+	if spool.f == nil {
+		return
+	}
+	if err := spool.enc.Encode(anonDelay(d)); err != nil {
+		logging.Fatal("Zwischendatei nicht beschreibbar", "file", spool.f.Name(), "err", err)
+	}
+	spool.n++
+}
+
+// writeResult schreibt res mit den Updates aus spool nach --out, mit
+// --chunk-size auf Teildateien verteilt.
+func writeResult(res result) {
+	defer os.Remove(spool.f.Name())
+	defer spool.f.Close()
+	if _, err := spool.f.Seek(0, io.SeekStart); err != nil {
+		logging.Fatal("Zwischendatei nicht lesbar", "file", spool.f.Name(), "err", err)
+	}
+	dec := json.NewDecoder(bufio.NewReader(spool.f))
+	if chunkSize == 0 {
+		if err := writeDoc(outFile, res, spool.n, dec); err != nil {
+			logging.Fatal("JSON-Ausgabe fehlgeschlagen", "file", outFile, "err", err)
+		}
+		return
+	}
+	n := max(1, (spool.n+chunkSize-1)/chunkSize)
+	for i := range n {
+		part := res
+		part.Chunk = &chunkInfo{Index: i + 1, Of: n}
+		name := chunkName(outFile, i+1)
+		if err := writeDoc(name, part, min(chunkSize, spool.n-i*chunkSize), dec); err != nil {
+			logging.Fatal("JSON-Ausgabe fehlgeschlagen", "file", name, "err", err)
+		}
+	}
+	slog.Info("Ergebnis aufgeteilt", "files", n, "updates", spool.n, "chunk_size", chunkSize)
+}
+
+// updatesKey ist die Zeile von "updates" in res, eingerückt wie von writeOut;
+// andere Zeilen können nicht so beginnen, da Strings keine Zeilenumbrüche
+// enthalten und verschachtelte Felder tiefer eingerückt sind.
+const updatesKey = "\n  \"updates\": []"
+
+// writeDoc schreibt res wie writeOut, liest die n Updates aber einzeln aus
+// dec, statt sie alle zu laden.
+func writeDoc(name string, res result, n int, dec *json.Decoder) error {
+	res.Updates = []delay{}
+	doc, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		return err
+	}
+	head, tail, _ := strings.Cut(string(doc), updatesKey)
+	w, err := createOut(name)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	bw.WriteString(head + strings.TrimSuffix(updatesKey, "]"))
+	for i := range n {
+		var d delay
+		if err := dec.Decode(&d); err != nil {
+			w.Close()
+			return err
+		}
+		item, err := json.MarshalIndent(d, "    ", "  ")
+		if err != nil {
+			w.Close()
+			return err
+		}
+		if i > 0 {
+			bw.WriteString(",")
+		}
+		bw.WriteString("\n    ")
+		bw.Write(item)
+	}
+	if n > 0 {
+		bw.WriteString("\n  ")
+	}
+	bw.WriteString("]" + tail + "\n")
+	if err := bw.Flush(); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
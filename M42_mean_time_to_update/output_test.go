@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeResult setzt die Updates aus spool ein; das Ergebnis muss
+// byte-gleich mit writeOut des vollständigen Ergebnisses sein.
+func TestWriteResultMatchesWriteOut(t *testing.T) {
+	dir := t.TempDir()
+	defer func(o string, c int) { outFile, outFormat, chunkSize = o, "json", c }(outFile, chunkSize)
+	n := 3
+	ds := make([]delay, 0, 5)
+	for i := range 5 {
+		ds = append(ds, delay{Eco: "npm", Dep: "a<b>&" + string(rune('a'+i)), OldVer: "1.0.0", NewVer: "1.1.0",
+			Days: float64(i) + 0.5, CommitHash: "abc1234", CommitDate: time.Date(2024, 1, i+1, 0, 0, 0, 0, time.UTC)})
+	}
+	res := result{Schema: "mttu-result/v1", Repo: "https://example.com/x.git", Eco: "npm",
+		Summary: summary{Updates: len(ds)}, SkippedSpecs: []skippedSpec{{Eco: "npm", Dep: "x", Spec: "file:x", Reason: "file"}}}
+
+	for _, tc := range []struct {
+		name  string
+		ds    []delay
+		chunk int
+	}{{"alle", ds, 0}, {"leer", nil, 0}, {"chunks", ds, n}} {
+		t.Run(tc.name, func(t *testing.T) {
+			outFile, outFormat, chunkSize = filepath.Join(dir, tc.name+".json"), "json", tc.chunk
+			openSpool()
+			for _, d := range tc.ds {
+				spoolDelay(d)
+			}
+			writeResult(res)
+			spool.f, spool.n = nil, 0
+
+			want := res
+			want.Updates = append([]delay{}, tc.ds...)
+			name := outFile
+			if tc.chunk > 0 { // erste Teildatei
+				want.Updates, want.Chunk = want.Updates[:n], &chunkInfo{Index: 1, Of: 2}
+				name = chunkName(outFile, 1)
+			}
+			ref := filepath.Join(dir, tc.name+"-ref.json")
+			if err := writeOut(ref, want); err != nil {
+				t.Fatal(err)
+			}
+			got, _ := os.ReadFile(name)
+			exp, _ := os.ReadFile(ref)
+			if string(got) != string(exp) {
+				t.Errorf("Ausgabe weicht ab:\n%s\nerwartet:\n%s", got, exp)
+			}
+		})
+	}
+}
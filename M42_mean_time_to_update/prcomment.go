@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"baa_fs25/shared/logging"
+	"baa_fs25/shared/report"
+)
+
+// -----------------------------------------------------------------------------
+// ---------- PR-Kommentar (--github-pr) ----------------------------------------
+// -----------------------------------------------------------------------------
+
+// loadBase liest --base, ein --out-JSON des Basis-Branches (nil ohne).
+func loadBase() *result {
+	if baseFile == "" {
+		return nil
+	}
+	base := &result{}
+	if err := report.ReadJSON(baseFile, base); err != nil {
+		logging.Fatal("Basis-Ergebnis nicht lesbar", "file", baseFile, "err", err)
+	}
+	return base
+}
+
+// unknownTo meldet Updates, die base nicht enthält; main behält nur diese
+// für den Kommentar.
+func unknownTo(base *result) func(delay) bool {
+	seen := map[string]bool{}
+	for _, d := range base.Updates {
+		seen[d.CommitHash+d.Dep] = true
+	}
+	return func(d delay) bool { return !seen[d.CommitHash+d.Dep] }
+}
+
+// postPRComment postet die MTTU-Zusammenfassung an den Pull Request; mit
+// base inkl. Delta.
+func postPRComment(base *result, res result) {
+	pc := report.PRComment{PR: githubPR, Marker: "mttu:" + res.Eco, Body: prMarkdown(base, res)}
+	if err := pc.Post(); err != nil {
+		logging.Fatal("PR-Kommentar fehlgeschlagen", "pr", githubPR, "err", err)
+	}
+}
+
+func prMarkdown(base *result, cur result) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "### MTTU (%s)\n\n", cur.Eco)
+	s := cur.Summary
+	if base == nil {
+		b.WriteString("| | PR |\n|---|---:|\n")
+		fmt.Fprintf(&b, "| Updates | %d |\n", s.Updates)
+		fmt.Fprintf(&b, "| MTTU-Mean (Tage) | %.1f |\n", s.MeanDays)
+		fmt.Fprintf(&b, "| MTTU-Median (Tage) | %.1f |\n", s.MedianDays)
+	} else {
+		bs := base.Summary
+		b.WriteString("| | Basis | PR | Δ |\n|---|---:|---:|---:|\n")
+		fmt.Fprintf(&b, "| Updates | %d | %d | %+d |\n", bs.Updates, s.Updates, s.Updates-bs.Updates)
+		fmt.Fprintf(&b, "| MTTU-Mean (Tage) | %.1f | %.1f | %s |\n", bs.MeanDays, s.MeanDays, report.Delta(bs.MeanDays, s.MeanDays, 1))
+		fmt.Fprintf(&b, "| MTTU-Median (Tage) | %.1f | %.1f | %s |\n", bs.MedianDays, s.MedianDays, report.Delta(bs.MedianDays, s.MedianDays, 1))
+	}
+
+	// Updates, die der Basis-Lauf noch nicht kannte (i. d. R. die des PRs)
+	if base == nil {
+		return b.String()
+	}
+	var fresh []delay
+	unknown := unknownTo(base)
+	for _, d := range cur.Updates {
+		if unknown(d) {
+			fresh = append(fresh, d)
+		}
+	}
+	if len(fresh) == 0 {
+		return b.String()
+	}
+	sortDelays(fresh)
+	b.WriteString("\n| Dependency | Alt | Neu | Tage | Commit |\n|---|---|---|---:|---|\n")
+	for _, d := range fresh {
+		fmt.Fprintf(&b, "| %s | %s | %s | %.0f | %s |\n", d.Dep, d.OldVer, d.NewVer, d.Days, d.CommitHash)
+	}
+	return b.String()
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"baa_fs25/shared/registry"
+	"golang.org/x/mod/semver"
+)
+
+// -----------------------------------------------------------------------------
+// ---------- Versionslisten der Registries ------------------------------------
+// -----------------------------------------------------------------------------
+
+// release ist eine veröffentlichte Version mit Zeitpunkt.
+type release struct {
+	ver  string
+	time time.Time
+}
+
+var errNoList = errors.New("Registry liefert keine Versionsliste")
+
+// reasonUnpublished ist der Grund in skipped_specs für Updates auf eine
+// Version, die die Registry nicht kennt (Tippfehler, Forks, entfernte
+// Releases).
+const reasonUnpublished = "unpublished-version"
+
+// unpublished prüft, ob ver in der Versionsliste der Registry fehlt. Ohne
+// Liste oder bei unvollständigen Angaben wie "1.2" (Untergrenze einer
+// npm-Range) gilt die Version nicht als unveröffentlicht, sondern nur das
+// Datum als unbekannt.
+func unpublished(reg registry.Client, pkg, ver string) bool {
+	l, ok := reg.(registry.Lister)
+	if !ok || !npmExactRx.MatchString(ver) {
+		return false
+	}
+	vers, err := l.Versions(pkg)
+	if err != nil || len(vers) == 0 {
+		return false
+	}
+	cv := canon(ver)
+	for _, v := range vers {
+		if v == ver || canon(v) == cv {
+			return false
+		}
+	}
+	return true
+}
+
+// newerVersions liefert alle Versionen v mit old < v <= new aus der
+// Versionsliste der Registry, nach Version sortiert. Pre-Releases zählen
+// nur, wenn new selbst eins ist.
+func newerVersions(reg registry.Client, pkg, old, new string) ([]string, error) {
+	l, ok := reg.(registry.Lister)
+	if !ok {
+		return nil, errNoList
+	}
+	vers, err := l.Versions(pkg)
+	if err != nil {
+		return nil, err
+	}
+	o, n := canon(old), canon(new)
+	var out []string
+	for _, v := range vers {
+		cv := canon(v)
+		if cv == "" || semver.Compare(cv, o) <= 0 || semver.Compare(cv, n) > 0 {
+			continue
+		}
+		if semver.Prerelease(cv) != "" && semver.Prerelease(n) == "" {
+			continue
+		}
+		out = append(out, v)
+	}
+	sort.Slice(out, func(i, j int) bool { return semver.Compare(canon(out[i]), canon(out[j])) < 0 })
+	return out, nil
+}
+
+// skippedReleases zählt die Releases zwischen old und new (beide exklusiv),
+// die beim Sprung ausgelassen wurden.
+func skippedReleases(vers []string, new string) int {
+	n := 0
+	for _, v := range vers {
+		if semver.Compare(canon(v), canon(new)) < 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// newerReleases ergänzt newerVersions um die Release-Zeitpunkte; Versionen
+// ohne Datum fallen weg.
+func newerReleases(reg registry.Client, pkg string, vers []string) []release {
+	var out []release
+	for _, v := range vers {
+		if t, err := reg.ReleaseTime(pkg, v); err == nil {
+			out = append(out, release{ver: v, time: t})
+		}
+	}
+	return out
+}
+
+// firstAvailable ist das früheste der Releases, also der Zeitpunkt, ab dem
+// ein Update auf eine neuere Version als die alte möglich war.
+func firstAvailable(rs []release) (release, bool) {
+	if len(rs) == 0 {
+		return release{}, false
+	}
+	first := rs[0]
+	for _, r := range rs[1:] {
+		if r.time.Before(first.time) {
+			first = r
+		}
+	}
+	return first, true
+}
+
+// Versions implementiert registry.Lister (nur getaggte Versionen).
+func (r goRegistry) Versions(mod string) ([]string, error) {
+	return r.proxy.Versions(mod)
+}
+
+// Versions implementiert registry.Lister.
+func (r pyRegistry) Versions(pkg string) ([]string, error) {
+	if strings.Contains(pkg, "::") {
+		return r.conda.Versions(pkg)
+	}
+	return r.pypi.Versions(pkg)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"baa_fs25/shared/gitwalk"
+	"baa_fs25/shared/registry"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// -----------------------------------------------------------------------------
+// ---------- Nur GitHub-API (--remote-api) -------------------------------------
+// -----------------------------------------------------------------------------
+
+// remoteRepo liest die Manifeste von e über die GitHub-REST-API (Commit-Liste
+// je Datei, Inhalt je Commit) und baut daraus ein In-Memory-Repo mit den
+// Original-Hashes – ohne Klon. Die Historie wird nach Committer-Datum
+// linearisiert; Commits aus gemergten Branches zählen daher mit, anders als
+// bei --first-parent. Bei erschöpftem Rate-Limit ist der Fehler
+// registry.ErrRateLimited.
+func remoteRepo(url string, e ecosystem, since *time.Time) (*git.Repository, error) {
+	slug := githubSlug(url)
+	if slug == "" {
+		return nil, fmt.Errorf("--remote-api braucht eine GitHub-URL, nicht %q", url)
+	}
+	gh := &registry.GitHub{Token: os.Getenv("GH_TOKEN")}
+
+	files, err := remoteFiles(gh, slug, e.paths)
+	if err != nil {
+		return nil, err
+	}
+	touched := map[string][]string{} // SHA → geänderte Manifeste
+	meta := map[string]registry.Commit{}
+	for _, f := range files {
+		cs, err := gh.Commits(slug, f, since)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range cs {
+			touched[c.SHA] = append(touched[c.SHA], f)
+			meta[c.SHA] = c
+		}
+	}
+	shas := slices.Collect(maps.Keys(meta))
+	slices.SortStableFunc(shas, func(a, b string) int {
+		if c := meta[a].Commit.Committer.Date.Compare(meta[b].Commit.Committer.Date); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	})
+
+	// Der erste Commit braucht den vollständigen Stand, danach nur die
+	// jeweils geänderten Dateien.
+	state := map[string][]byte{}
+	var commits []gitwalk.SynthCommit
+	requests := 0
+	for i, sha := range shas {
+		fetch := touched[sha]
+		if i == 0 {
+			fetch = files
+		}
+		for _, f := range fetch {
+			requests++
+			b, err := gh.Contents(slug, f, sha)
+			switch {
+			case errors.Is(err, registry.ErrNotFound):
+				delete(state, f)
+			case err != nil:
+				return nil, err
+			default:
+				state[f] = b
+			}
+		}
+		m := meta[sha].Commit
+		commits = append(commits, gitwalk.SynthCommit{
+			Hash:      plumbing.NewHash(sha),
+			Author:    object.Signature{Name: m.Author.Name, Email: m.Author.Email, When: m.Author.Date},
+			Committer: object.Signature{Name: m.Committer.Name, Email: m.Committer.Email, When: m.Committer.Date},
+			Message:   m.Message,
+			Files:     maps.Clone(state),
+		})
+	}
+	slog.Info("Manifeste über GitHub-API gelesen", "repo", slug, "manifests", len(files),
+		"commits", len(commits), "content_requests", requests)
+	return gitwalk.Synthesize(commits)
+}
+
+// remoteFiles bestimmt die Manifeste unter paths im aktuellen Stand (HEAD).
+// Explizite Dateipfade werden auch abgefragt, wenn sie dort fehlen.
+func remoteFiles(gh *registry.GitHub, slug string, paths []string) ([]string, error) {
+	all, truncated, err := gh.Files(slug, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	if truncated {
+		slog.Warn("Dateiliste von GitHub gekürzt – Manifeste in tiefen Verzeichnissen fehlen evtl.", "repo", slug)
+	}
+	var files []string
+	for _, f := range all {
+		if gitwalk.Match(f, paths) && !gitwalk.Excluded(f, excludeGlobs) {
+			files = append(files, f)
+		}
+	}
+	for _, p := range paths {
+		if !strings.ContainsAny(p, "*?[") && !strings.HasSuffix(p, "/") && !slices.Contains(files, p) && !isDir(all, p) {
+			files = append(files, p)
+		}
+	}
+	return files, nil
+}
+
+func isDir(files []string, p string) bool {
+	return slices.ContainsFunc(files, func(f string) bool { return strings.HasPrefix(f, p+"/") })
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"time"
+
+	"baa_fs25/shared/registry"
+	git "github.com/go-git/go-git/v5"
+)
+
+// -----------------------------------------------------------------------------
+// ---------- Repo-Metadaten (--repo-meta) --------------------------------------
+// -----------------------------------------------------------------------------
+
+// repoMeta sind Kovariaten des Repos für spätere Regressionen; fehlende
+// Angaben bleiben leer.
+type repoMeta struct {
+	Slug          string    `json:"slug"`
+	Stars         int       `json:"stars"`
+	Language      string    `json:"language,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	AgeDays       int       `json:"age_days"`
+	Contributors  *int      `json:"contributors,omitempty"`
+	DefaultBranch string    `json:"default_branch,omitempty"`
+	Fork          bool      `json:"fork,omitempty"`
+	Archived      bool      `json:"archived,omitempty"`
+}
+
+// repoSlug liefert "owner/repo" aus url bzw. dem origin-Remote von r ("",
+// wenn beides nicht auf GitHub zeigt).
+func repoSlug(url string, r *git.Repository) string {
+	if slug := githubSlug(url); slug != "" || r == nil {
+		return slug
+	}
+	if rem, err := r.Remote("origin"); err == nil && len(rem.Config().URLs) > 0 {
+		return githubSlug(rem.Config().URLs[0])
+	}
+	return ""
+}
+
+// fetchRepoMeta fragt die Metadaten über die GitHub-API ab; Fehler werden
+// nur geloggt, damit die Analyse nicht daran scheitert.
+func fetchRepoMeta(slug string) *repoMeta {
+	if slug == "" {
+		slog.Warn("--repo-meta: kein GitHub-Repo, Metadaten entfallen")
+		return nil
+	}
+	gh := &registry.GitHub{Token: os.Getenv("GH_TOKEN")}
+	info, err := gh.Repo(slug)
+	if err != nil {
+		slog.Warn("Repo-Metadaten nicht abrufbar", "repo", slug, "err", err)
+		return nil
+	}
+	m := &repoMeta{
+		Slug: slug, Stars: info.Stars, Language: info.Language, CreatedAt: info.CreatedAt,
+		AgeDays:       int(time.Since(info.CreatedAt).Hours() / 24),
+		DefaultBranch: info.DefaultBranch, Fork: info.Fork, Archived: info.Archived,
+	}
+	if n, err := gh.Contributors(slug); err != nil {
+		slog.Warn("Contributor-Zahl nicht abrufbar", "repo", slug, "err", err)
+	} else {
+		m.Contributors = &n
+	}
+	return m
+}
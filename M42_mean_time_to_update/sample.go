@@ -0,0 +1,206 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"math/rand"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"baa_fs25/shared/gitwalk"
+	"baa_fs25/shared/i18n"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// -----------------------------------------------------------------------------
+// ---------- Stichproben (--sample) --------------------------------------------
+// -----------------------------------------------------------------------------
+
+// sampling beschreibt die Stichprobe und landet so in der JSON-Ausgabe.
+// Jeder gezogene Commit wird mit seinem direkten Vorgänger verglichen, nicht
+// mit dem vorigen gezogenen Commit – sonst würden Sprünge über mehrere
+// Updates hinweg als ein Update zählen.
+//
+// Jedes Ökosystem zieht seine eigene Stichprobe aus seinen Manifest-Commits;
+// Population und Sampled sind die Summen, ByEco die Werte je Ökosystem.
+type sampling struct {
+	Scheme     string                 `json:"scheme"` // every-nth | random
+	K          int                    `json:"k,omitempty"`
+	N          int                    `json:"n,omitempty"`
+	Seed       int64                  `json:"seed,omitempty"`
+	Population int                    `json:"population"` // Manifest-Commits vor der Auswahl
+	Sampled    int                    `json:"sampled"`
+	ByEco      map[string]sampleCount `json:"by_eco,omitempty"`
+}
+
+// sampleCount ist die Stichprobe eines Ökosystems.
+type sampleCount struct {
+	Population int `json:"population"`
+	Sampled    int `json:"sampled"`
+}
+
+// add nimmt die Stichprobe eines Ökosystems in die Summen auf.
+func (s *sampling) add(eco string, population, sampled int) {
+	s.Population += population
+	s.Sampled += sampled
+	if eco == "" {
+		return
+	}
+	if s.ByEco == nil {
+		s.ByEco = map[string]sampleCount{}
+	}
+	s.ByEco[eco] = sampleCount{Population: population, Sampled: sampled}
+}
+
+// parseSample liest "every-nth=K" bzw. "random=N[,seed=S]". Ohne seed wird
+// einer gewürfelt und protokolliert, damit der Lauf wiederholbar bleibt.
+func parseSample(v string) (*sampling, error) {
+	s := &sampling{}
+	seeded := false
+	for _, part := range strings.Split(v, ",") {
+		k, val, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, fmt.Errorf("--sample: %q ist nicht key=wert", part)
+		}
+		n, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("--sample: %s=%q ist keine Zahl", k, val)
+		}
+		switch k {
+		case "every-nth":
+			s.Scheme, s.K = k, int(n)
+		case "random":
+			s.Scheme, s.N = k, int(n)
+		case "seed":
+			s.Seed, seeded = n, true
+		default:
+			return nil, fmt.Errorf("--sample: unbekannt %q (every-nth=K | random=N,seed=S)", k)
+		}
+	}
+	switch {
+	case s.Scheme == "":
+		return nil, fmt.Errorf("--sample: every-nth=K oder random=N angeben")
+	case s.Scheme == "every-nth" && s.K < 1, s.Scheme == "random" && s.N < 1:
+		return nil, fmt.Errorf("--sample: %s braucht einen positiven Wert", s.Scheme)
+	case seeded && s.Scheme != "random":
+		return nil, fmt.Errorf("--sample: seed gilt nur für random")
+	}
+	if s.Scheme == "random" && !seeded {
+		s.Seed = time.Now().UnixNano()
+	}
+	return s, nil
+}
+
+// sampledSource liefert nur die gezogenen Commits von src (ältester zuerst).
+// Gemerkt werden nur die Indizes der Stichprobe, keine Commits: every-nth
+// zieht im selben Durchgang, random zählt erst die Commits und begeht src
+// dann ein zweites Mal. eco benennt das Ökosystem in sampling.ByEco.
+type sampledSource struct {
+	src gitwalk.Source
+	s   *sampling
+	eco string
+}
+
+// ForEach implementiert gitwalk.Source.
+func (ss sampledSource) ForEach(paths []string, since, until *time.Time, fn func(*object.Commit) error) error {
+	random := ss.s.Scheme == "random"
+	var picked []int
+	if random {
+		n := 0
+		if err := ss.src.ForEach(paths, since, until, func(*object.Commit) error {
+			n++
+			return nil
+		}); err != nil {
+			return err
+		}
+		picked = ss.s.pick(n)
+		ss.s.add(ss.eco, n, len(picked))
+	}
+	i, stopped := 0, false
+	err := ss.src.ForEach(paths, since, until, func(c *object.Commit) error {
+		defer func() { i++ }()
+		if random {
+			if len(picked) == 0 {
+				return storer.ErrStop
+			}
+			if picked[0] != i {
+				return nil
+			}
+			picked = picked[1:]
+		} else if stopped || i%ss.s.K != 0 {
+			return nil // every-nth zählt bis zum Ende für Population
+		}
+		err := fn(c)
+		if err == storer.ErrStop {
+			if random {
+				return err
+			}
+			stopped = true
+			return nil
+		}
+		return err
+	})
+	if !random {
+		ss.s.add(ss.eco, i, (i+ss.s.K-1)/ss.s.K)
+	}
+	return err
+}
+
+// print gibt die Stichprobe in der Zusammenfassung aus, bei mehreren
+// Ökosystemen auch je Ökosystem.
+func (s *sampling) print() {
+	i18n.Printf("Stichprobe             : %d von %d Commits (%s)\n", s.Sampled, s.Population, s.describe())
+	if len(s.ByEco) < 2 {
+		return
+	}
+	for _, eco := range slices.Sorted(maps.Keys(s.ByEco)) {
+		c := s.ByEco[eco]
+		i18n.Printf("  %-21s: %d von %d Commits\n", eco, c.Sampled, c.Population)
+	}
+}
+
+func (s *sampling) describe() string {
+	if s.Scheme == "every-nth" {
+		return i18n.Sprintf("jeder %d.", s.K)
+	}
+	return i18n.Sprintf("zufällig, seed=%d", s.Seed)
+}
+
+// pick liefert die Indizes der Stichprobe aus n Commits, aufsteigend.
+func (s *sampling) pick(n int) []int {
+	var idx []int
+	switch s.Scheme {
+	case "every-nth":
+		for i := 0; i < n; i += s.K {
+			idx = append(idx, i)
+		}
+	case "random":
+		idx = rand.New(rand.NewSource(s.Seed)).Perm(n)
+		if len(idx) > s.N {
+			idx = idx[:s.N]
+		}
+		sort.Ints(idx)
+	}
+	return idx
+}
+
+// parentVersions liest die Versionen (und ggf. Specs) im ersten Parent von
+// c; nil, wenn es keinen gibt.
+func parentVersions(e ecosystem, c *object.Commit) (versions, specs map[string]string) {
+	p, err := c.Parent(0)
+	if err != nil {
+		return nil, nil
+	}
+	v := e.versions(p)
+	if len(v) == 0 {
+		return nil, nil
+	}
+	if e.specs != nil {
+		specs = e.specs(p)
+	}
+	return v, specs
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// seqSource liefert n Commits, deren Hash ihr Index ist.
+type seqSource int
+
+func (n seqSource) ForEach(_ []string, _, _ *time.Time, fn func(*object.Commit) error) error {
+	for i := range int(n) {
+		if err := fn(&object.Commit{Hash: plumbing.Hash{byte(i)}}); err != nil {
+			if err == storer.ErrStop {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func TestSampledSource(t *testing.T) {
+	for _, tc := range []struct {
+		s    sampling
+		stop int // fn beendet nach so vielen Commits (0 = nie)
+	}{
+		{sampling{Scheme: "every-nth", K: 4}, 0},
+		{sampling{Scheme: "every-nth", K: 4}, 2},
+		{sampling{Scheme: "random", N: 5, Seed: 3}, 0},
+		{sampling{Scheme: "random", N: 5, Seed: 3}, 2},
+	} {
+		s := tc.s
+		var got []int
+		err := sampledSource{src: seqSource(10), s: &s}.ForEach(nil, nil, nil, func(c *object.Commit) error {
+			got = append(got, int(c.Hash[0]))
+			if len(got) == tc.stop {
+				return storer.ErrStop
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := s.pick(10)
+		if tc.stop > 0 {
+			want = want[:tc.stop]
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("%s/stop=%d: %v, erwartet %v", s.Scheme, tc.stop, got, want)
+		}
+		if s.Population != 10 || s.Sampled != len(s.pick(10)) {
+			t.Errorf("%s/stop=%d: %d von %d", s.Scheme, tc.stop, s.Sampled, s.Population)
+		}
+	}
+}
+
+// TestSampledSourceByEco zieht für zwei Ökosysteme mit derselben sampling;
+// die Summen und die Werte je Ökosystem müssen beide Läufe enthalten.
+func TestSampledSourceByEco(t *testing.T) {
+	s := &sampling{Scheme: "every-nth", K: 2}
+	for eco, n := range map[string]seqSource{"npm": 10, "go": 3} {
+		if err := (sampledSource{src: n, s: s, eco: eco}).ForEach(nil, nil, nil, func(*object.Commit) error { return nil }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if s.Population != 13 || s.Sampled != 7 {
+		t.Errorf("Summe %d von %d, erwartet 7 von 13", s.Sampled, s.Population)
+	}
+	if s.ByEco["npm"] != (sampleCount{10, 5}) || s.ByEco["go"] != (sampleCount{3, 2}) {
+		t.Errorf("je Ökosystem: %v", s.ByEco)
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import "testing"
+
+// Gleichnamige Dependencies verschiedener Ökosysteme dürfen sich in
+// skipped_specs nicht überschreiben.
+func TestSpecSkipsKeyedByEco(t *testing.T) {
+	skips := specSkips{}
+	got := npmSpecs(`{"dependencies":{"yaml":"github:eemeli/yaml","left-pad":"^1.3.0"}}`, skips)
+	if len(got) != 1 || got["left-pad"] != "^1.3.0" {
+		t.Fatalf("npmSpecs = %v", got)
+	}
+	skips.add(skippedSpec{Eco: "go", Dep: "yaml", Spec: "v9.9.9", Reason: reasonUnpublished})
+
+	want := []skippedSpec{
+		{Eco: "go", Dep: "yaml", Spec: "v9.9.9", Reason: reasonUnpublished},
+		{Eco: "npm", Dep: "yaml", Spec: "github:eemeli/yaml", Reason: "git"},
+	}
+	out := skips.sorted()
+	if len(out) != len(want) {
+		t.Fatalf("sorted = %v, erwartet %v", out, want)
+	}
+	for i := range want {
+		if out[i] != want[i] {
+			t.Errorf("sorted[%d] = %v, erwartet %v", i, out[i], want[i])
+		}
+	}
+	npmSpecs(`{"dependencies":{"x":"file:../x"}}`, nil) // nil: nicht sammeln
+}
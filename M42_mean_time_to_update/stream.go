@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+
+	"baa_fs25/shared/logging"
+)
+
+// -----------------------------------------------------------------------------
+// ---------- JSON Lines (--format jsonl) ---------------------------------------
+// -----------------------------------------------------------------------------
+
+// Mit --format jsonl schreibt --out jedes Update als eigene Zeile, sobald
+// analyze es gefunden hat, und zum Schluss eine Zeile mit der
+// Zusammenfassung. Sehr lange Historien lassen sich so schon während des
+// Laufs auswerten, und ein Abbruch verliert die bisherigen Records nicht.
+// Mit --dedupe-window stehen die Updates erst am Ende fest und werden dann
+// geschrieben. Kompression und --chunk-size s. output.go, --format json s.
+// spool dort.
+
+var (
+	outFormat string
+	sink      *json.Encoder
+	sinkOut   io.WriteCloser
+	sinkName  string
+	// sinkChunk ist die Nummer der offenen Teildatei, sinkRecords die Zahl
+	// ihrer Updates (nur --chunk-size).
+	sinkChunk, sinkRecords int
+)
+
+// updateRecord und summaryRecord sind die Zeilentypen (Feld "record").
+type updateRecord struct {
+	Record string `json:"record"` // "update"
+	delay
+}
+
+type summaryRecord struct {
+	Record string `json:"record"` // "summary"
+	result
+	Updates []delay `json:"updates,omitempty"` // bereits als Einzelzeilen geschrieben
+}
+
+// openSink legt die JSONL-Ausgabe an, sofern --format jsonl und --out
+// gesetzt sind; mit --chunk-size die erste Teildatei.
+func openSink() {
+	if outFormat != "jsonl" || outFile == "" {
+		return
+	}
+	sinkName = outFile
+	if chunkSize > 0 {
+		sinkChunk++
+		sinkName, sinkRecords = chunkName(outFile, sinkChunk), 0
+	}
+	var err error
+	if sinkOut, err = createOut(sinkName); err != nil {
+		logging.Fatal("JSONL-Ausgabe fehlgeschlagen", "file", sinkName, "err", err)
+	}
+	sink = json.NewEncoder(sinkOut)
+}
+
+// closeSink schließt die offene (Teil-)Datei; erst dabei schreiben die
+// Kompressoren ihren Rest.
+func closeSink() {
+	if err := sinkOut.Close(); err != nil {
+		slog.Error("JSONL-Ausgabe nicht geschlossen", "file", sinkName, "err", err)
+	}
+}
+
+func emit(v any) {
+	if err := sink.Encode(v); err != nil {
+		logging.Fatal("JSONL-Ausgabe fehlgeschlagen", "file", sinkName, "err", err)
+	}
+}
+
+// emitUpdate schreibt eine Update-Zeile und beginnt bei voller Teildatei
+// die nächste.
+func emitUpdate(d delay) {
+	if chunkSize > 0 && sinkRecords == chunkSize {
+		closeSink()
+		openSink()
+	}
+	emit(updateRecord{Record: "update", delay: d})
+	sinkRecords++
+}
+
+// emitDelay schreibt ein fertiges Update.
+func emitDelay(d delay) {
+	if sink == nil {
+		return
+	}
+	emitUpdate(anonDelay(d))
+}
+
+// finishSink schreibt die Zusammenfassung (res ist bereits anonymisiert)
+// und schließt die Datei.
+func finishSink(res result) {
+	if chunkSize > 0 {
+		res.Chunk = &chunkInfo{Index: sinkChunk, Of: sinkChunk}
+	}
+	emit(summaryRecord{Record: "summary", result: res})
+	closeSink()
+}
@@ -0,0 +1,152 @@
+// submodule.go – Ökosystem "submodule": per .gitmodules eingebundene
+// Repositories. Jeder neue Pin (Gitlink-Commit) gilt als Update; als
+// Release-Zeitpunkt zählt das Committer-Datum des gepinnten Commits im
+// Submodul-Repo (GitHub-API, sonst Bare-Klon).
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"baa_fs25/shared/registry"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// submodulePins liefert URL → gepinnter Commit für alle Submodule in c.
+func submodulePins(c *object.Commit) map[string]string {
+	txt, err := readFileFromCommit(c, ".gitmodules")
+	if err != nil || txt == "" {
+		return nil
+	}
+	mods := config.NewModules()
+	if err := mods.Unmarshal([]byte(txt)); err != nil {
+		slog.Debug(".gitmodules nicht lesbar", "commit", c.Hash.String()[:7], "err", err)
+		return nil
+	}
+	tree, err := c.Tree()
+	if err != nil {
+		return nil
+	}
+	pins := map[string]string{}
+	for _, m := range mods.Submodules {
+		e, err := tree.FindEntry(m.Path)
+		if err != nil || e.Mode != filemode.Submodule || m.URL == "" {
+			continue
+		}
+		pins[strings.TrimSuffix(m.URL, ".git")] = e.Hash.String()
+	}
+	return pins
+}
+
+// submodulePaths sammelt die Submodul-Pfade aus allen Fassungen der
+// .gitmodules, damit der Walker auch reine Pin-Bumps findet.
+func submodulePaths(r *git.Repository) []string {
+	paths := []string{".gitmodules"}
+	head, err := r.Head()
+	if err != nil {
+		return paths
+	}
+	c, err := r.CommitObject(head.Hash())
+	seen := map[string]bool{}
+	for err == nil {
+		if txt, ferr := readFileFromCommit(c, ".gitmodules"); ferr == nil && txt != "" {
+			mods := config.NewModules()
+			if mods.Unmarshal([]byte(txt)) == nil {
+				for _, m := range mods.Submodules {
+					if m.Path != "" && !seen[m.Path] {
+						seen[m.Path] = true
+						paths = append(paths, m.Path)
+					}
+				}
+			}
+		}
+		if c.NumParents() == 0 {
+			break
+		}
+		c, err = c.Parent(0)
+	}
+	return paths
+}
+
+func init() { registerEcosystem("submodule", submoduleEco, "git") }
+
+func submoduleEco() ecosystem {
+	reg := &submoduleRegistry{gh: &registry.GitHub{Token: os.Getenv("GH_TOKEN")}}
+	return ecosystem{
+		name:     "submodule",
+		paths:    []string{".gitmodules"},
+		versions: submodulePins,
+		reg:      reg,
+		discover: submodulePaths,
+		upgrade:  reg.upgrade,
+	}
+}
+
+// submoduleRegistry löst das Datum eines Commits im Submodul-Repo auf:
+// bei GitHub-URLs über die API, sonst über einen Bare-Klon im Temp-Verzeichnis.
+type submoduleRegistry struct {
+	gh     *registry.GitHub
+	clones map[string]*git.Repository
+}
+
+func (r *submoduleRegistry) ReleaseTime(url, sha string) (time.Time, error) {
+	if slug := githubSlug(url); slug != "" {
+		t, err := r.gh.CommitTime(slug, sha)
+		if err == nil {
+			return t, nil
+		}
+		slog.Debug("GitHub-Commit nicht abrufbar, klone Submodul", "slug", slug, "err", err)
+	}
+	repo, err := r.clone(url)
+	if err != nil {
+		return time.Time{}, err
+	}
+	c, err := repo.CommitObject(plumbing.NewHash(sha))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Commit %s nicht in %s: %w", sha[:7], url, err)
+	}
+	return c.Committer.When, nil
+}
+
+// upgrade wertet einen neuen Pin als Update, wenn der gepinnte Commit jünger
+// ist als der bisherige.
+func (r *submoduleRegistry) upgrade(url, oldSHA, newSHA string) bool {
+	o, err := r.ReleaseTime(url, oldSHA)
+	if err != nil {
+		return true // alter Pin unbekannt (z. B. force-push) → als Update zählen
+	}
+	n, err := r.ReleaseTime(url, newSHA)
+	return err != nil || n.After(o)
+}
+
+func (r *submoduleRegistry) clone(url string) (*git.Repository, error) {
+	if repo, ok := r.clones[url]; ok {
+		return repo, nil
+	}
+	if r.clones == nil {
+		r.clones = map[string]*git.Repository{}
+	}
+	sum := sha256.Sum256([]byte(url))
+	dir := filepath.Join(os.TempDir(), "mttu-submodules", hex.EncodeToString(sum[:8])+".git")
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		slog.Info("Klone Submodul", "url", url, "dir", dir)
+		repo, err = git.PlainClone(dir, true, &git.CloneOptions{URL: url})
+		if err != nil {
+			return nil, fmt.Errorf("Submodul %s: %w", url, err)
+		}
+	}
+	r.clones[url] = repo
+	return repo, nil
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"slices"
+	"sort"
+)
+
+// -----------------------------------------------------------------------------
+// ---------- Laufende Kennzahlen -----------------------------------------------
+// -----------------------------------------------------------------------------
+
+// tally sammelt die Kennzahlen der Zusammenfassung, während analyze Updates
+// meldet. Die Records selbst gehen sofort in die Ausgabe (--format jsonl
+// bzw. spool für json); behalten werden nur die Tage je Update für Median
+// und Konfidenzintervall, die topN langsamsten Updates und die, die keep
+// auswählt (--timeline, PR-Kommentar). Auch bei 100k+ Updates bleibt das ein
+// Bruchteil der Records.
+type tally struct {
+	n    int
+	days []float64
+	// Tage je Ökosystem, constraint_change und version_kind
+	byEco, byChange, byKind map[string][]float64
+	skippedSum              float64 // skipped_releases, nur mit Versionsliste
+	skippedN                int
+	available               []float64
+	prSeen                  map[int]bool // jeder Bot-PR zählt einmal
+	prDays                  []float64
+	majors                  []majorEvent
+	deps                    map[string]bool // specKey aller erkannten Dependencies
+	top                     []delay         // nach sortDelays, höchstens topN
+	keep                    func(delay) bool
+	kept                    []delay
+}
+
+func newTally(keep func(delay) bool) *tally {
+	return &tally{
+		byEco: map[string][]float64{}, byChange: map[string][]float64{}, byKind: map[string][]float64{},
+		prSeen: map[int]bool{}, deps: map[string]bool{}, keep: keep,
+	}
+}
+
+// add nimmt ein Update in die Kennzahlen auf.
+func (t *tally) add(d delay) {
+	t.n++
+	t.days = append(t.days, d.Days)
+	addGroup(t.byEco, d.Eco, d.Days)
+	addGroup(t.byChange, d.Change, d.Days)
+	addGroup(t.byKind, d.Kind, d.Days)
+	if d.Skipped != nil {
+		t.skippedSum += float64(*d.Skipped)
+		t.skippedN++
+	}
+	if d.AvailableDays != nil {
+		t.available = append(t.available, *d.AvailableDays)
+	}
+	if d.PRLatencyDays != nil && !t.prSeen[d.BotPR] {
+		t.prSeen[d.BotPR] = true
+		t.prDays = append(t.prDays, *d.PRLatencyDays)
+	}
+	if d.MajorsBehind != nil {
+		t.majors = append(t.majors, majorEvent{date: d.CommitDate, commit: d.CommitHash, key: d.File + "\x00" + d.Dep, behind: *d.MajorsBehind})
+	}
+	t.deps[specKey(d.Eco, d.Dep)] = true
+	if topN != 0 {
+		i := sort.Search(len(t.top), func(i int) bool { return slower(d, t.top[i]) })
+		if topN < 0 || i < topN {
+			t.top = slices.Insert(t.top, i, d)
+			if topN > 0 && len(t.top) > topN {
+				t.top = t.top[:topN]
+			}
+		}
+	}
+	if t.keep != nil && t.keep(d) {
+		t.kept = append(t.kept, d)
+	}
+}
+
+// addGroup ordnet v dem Schlüssel k zu; leere Schlüssel zählen nicht.
+func addGroup(m map[string][]float64, k string, v float64) {
+	if k != "" {
+		m[k] = append(m[k], v)
+	}
+}
+
+// groups fasst die Tage je Schlüssel zusammen (nil, wenn das Ökosystem das
+// Merkmal nicht erfasst).
+func groups(m map[string][]float64) map[string]group {
+	if len(m) == 0 {
+		return nil
+	}
+	out := map[string]group{}
+	for k, v := range m {
+		out[k] = newGroup(v)
+	}
+	return out
+}
+
+func newGroup(xs []float64) group {
+	return group{Updates: len(xs), MeanDays: mean(xs), MedianDays: median(xs)}
+}
+
+// summary baut die Zusammenfassung; byEco nur bei mehreren Ökosystemen.
+func (t *tally) summary(byEco bool) summary {
+	s := summary{
+		// median sortiert t.days; das Bootstrap-Intervall rechnet wie bisher
+		// auf den sortierten Werten.
+		Updates: t.n, MeanDays: mean(t.days), MedianDays: median(t.days),
+		MeanCI95: bootstrapCI(t.days, bootstrapN), MinSample: minSample, LowSample: t.n < minSample,
+		ByConstraint:  groups(t.byChange),
+		ByVersionKind: groups(t.byKind),
+	}
+	if t.skippedN > 0 {
+		m := t.skippedSum / float64(t.skippedN)
+		s.MeanSkipped = &m
+	}
+	if sinceAvail && len(t.available) > 0 {
+		g := newGroup(t.available)
+		s.SinceAvailable = &g
+	}
+	if len(t.prDays) > 0 {
+		g := newGroup(t.prDays)
+		s.BotPRLatency = &g
+	}
+	s.MajorLag = newMajorLag(t.majors)
+	if byEco {
+		s.ByEco = groups(t.byEco)
+	}
+	return s
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTallyTopAndGroups(t *testing.T) {
+	defer func(n int) { topN = n }(topN)
+	topN = 3
+	pr := func(v float64) *float64 { return &v }
+	ds := []delay{
+		{Eco: "go", Dep: "a", Days: 5, Kind: "release", CommitHash: "1"},
+		{Eco: "go", Dep: "b", Days: 50, Kind: "pseudo", CommitHash: "2", BotPR: 7, PRLatencyDays: pr(2)},
+		{Eco: "npm", Dep: "c", Days: 20, Change: "none", CommitHash: "3", BotPR: 7, PRLatencyDays: pr(2)},
+		{Eco: "npm", Dep: "d", Days: 20, Change: "none", CommitHash: "4"},
+		{Eco: "npm", Dep: "e", Days: 1, CommitHash: "5", BotPR: 8, PRLatencyDays: pr(4)},
+	}
+	tl := newTally(func(d delay) bool { return d.Eco == "go" })
+	for _, d := range ds {
+		tl.add(d)
+	}
+	var top []string
+	for _, d := range tl.top {
+		top = append(top, d.Dep)
+	}
+	if want := []string{"b", "c", "d"}; !slices.Equal(top, want) {
+		t.Errorf("top = %v, erwartet %v", top, want)
+	}
+	if len(tl.kept) != 2 {
+		t.Errorf("kept = %d Updates, erwartet 2", len(tl.kept))
+	}
+	s := tl.summary(true)
+	if s.Updates != 5 || s.MeanDays != 19.2 || s.MedianDays != 20 {
+		t.Errorf("Summary = %d/%v/%v", s.Updates, s.MeanDays, s.MedianDays)
+	}
+	if g := s.ByEco["npm"]; g.Updates != 3 || g.MedianDays != 20 {
+		t.Errorf("by_eco[npm] = %+v", g)
+	}
+	if g := s.ByConstraint["none"]; g.Updates != 2 {
+		t.Errorf("by_constraint[none] = %+v", g)
+	}
+	if g := s.BotPRLatency; g == nil || g.Updates != 2 || g.MeanDays != 3 {
+		t.Errorf("bot_pr_latency = %+v, erwartet 2 PRs mit Mean 3", g)
+	}
+	if s.MajorLag != nil || s.MeanSkipped != nil {
+		t.Errorf("major_lag/mean_skipped ohne Versionslisten gesetzt")
+	}
+}
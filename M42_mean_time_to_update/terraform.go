@@ -0,0 +1,142 @@
+// terraform.go – Ökosystem "terraform": Provider aus .terraform.lock.hcl und
+// required_providers sowie Registry-Module aus den *.tf im Wurzelverzeichnis.
+// Release-Daten kommen aus der Terraform Registry.
+
+package main
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"baa_fs25/shared/registry"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+var (
+	tfLockProviderRx = regexp.MustCompile(`^\s*provider\s+"([^"]+)"\s*\{`)
+	tfBlockRx        = regexp.MustCompile(`^\s*(module\s+"[^"]+"|required_providers|[\w-]+\s*=)\s*\{`)
+	tfShortProvRx    = regexp.MustCompile(`^\s*([\w-]+)\s*=\s*"([^"]+)"\s*$`) // aws = "~> 3.0" (alte Syntax)
+	tfAttrRx         = regexp.MustCompile(`^\s*(source|version)\s*=\s*"([^"]+)"`)
+)
+
+// tfLockVersions liest die exakt gelockten Provider-Versionen.
+func tfLockVersions(txt string) map[string]string {
+	m := map[string]string{}
+	cur := ""
+	for _, l := range strings.Split(txt, "\n") {
+		if mm := tfLockProviderRx.FindStringSubmatch(l); mm != nil {
+			cur = strings.TrimPrefix(mm[1], "registry.terraform.io/")
+			continue
+		}
+		if mm := tfAttrRx.FindStringSubmatch(l); mm != nil && cur != "" && mm[1] == "version" {
+			m[cur] = mm[2]
+			cur = ""
+		}
+	}
+	return m
+}
+
+// tfVersions liest required_providers-Einträge und module-Blöcke mit
+// Registry-Quelle. Constraints ("~> 5.0", ">= 1.2, < 2") werden auf die
+// erste genannte Version reduziert.
+func tfVersions(txt string) map[string]string {
+	m := map[string]string{}
+	depth := 0
+	inProviders := -1 // Tiefe des required_providers-Blocks
+	blockDepth := -1  // Tiefe des aktuellen Provider-/Modul-Blocks
+	isModule := false
+	attrs := map[string]string{}
+
+	for _, l := range strings.Split(txt, "\n") {
+		if i := strings.Index(l, "#"); i >= 0 && !strings.Contains(l[:i], `"`) {
+			l = l[:i]
+		}
+		if mm := tfBlockRx.FindStringSubmatch(l); mm != nil {
+			switch {
+			case mm[1] == "required_providers":
+				inProviders = depth + 1
+			case strings.HasPrefix(mm[1], "module"):
+				blockDepth, isModule, attrs = depth+1, true, map[string]string{}
+			case inProviders == depth: // name = { source = ..., version = ... }
+				name := strings.TrimSpace(strings.TrimSuffix(mm[1], "="))
+				blockDepth, isModule, attrs = depth+1, false, map[string]string{"source": "hashicorp/" + name}
+			}
+		} else if mm := tfShortProvRx.FindStringSubmatch(l); mm != nil && inProviders == depth {
+			if v := tfConstraintVersion(mm[2]); v != "" {
+				m["hashicorp/"+mm[1]] = v
+			}
+		} else if mm := tfAttrRx.FindStringSubmatch(l); mm != nil && blockDepth == depth {
+			attrs[mm[1]] = mm[2]
+		}
+
+		depth += strings.Count(l, "{") - strings.Count(l, "}")
+
+		if blockDepth > depth { // Block geschlossen
+			src, ver := attrs["source"], tfConstraintVersion(attrs["version"])
+			if ver != "" && tfRegistrySource(src, isModule) {
+				m[strings.TrimPrefix(src, "registry.terraform.io/")] = ver
+			}
+			blockDepth = -1
+		}
+		if inProviders > depth {
+			inProviders = -1
+		}
+	}
+	return m
+}
+
+// tfRegistrySource erkennt Registry-Adressen (ns/type bzw. ns/name/provider);
+// lokale Pfade, git::- und URL-Quellen haben keine Registry-Versionen.
+func tfRegistrySource(src string, module bool) bool {
+	if src == "" || strings.HasPrefix(src, ".") || strings.Contains(src, "::") || strings.Contains(src, "://") {
+		return false
+	}
+	n := len(strings.Split(strings.TrimPrefix(src, "registry.terraform.io/"), "/"))
+	if module {
+		return n == 3 || n == 4 // optional mit Host
+	}
+	return n == 2 || n == 3
+}
+
+func tfConstraintVersion(c string) string {
+	first, _, _ := strings.Cut(c, ",")
+	return strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(first), "~>=<! "))
+}
+
+func init() { registerEcosystem("terraform", terraformEco, "tf") }
+
+func terraformEco() ecosystem {
+	return ecosystem{
+		name:  "terraform",
+		paths: []string{".terraform.lock.hcl", "*.tf"},
+		versions: func(c *object.Commit) map[string]string {
+			curr := map[string]string{}
+			if tree, err := c.Tree(); err == nil {
+				for _, e := range tree.Entries {
+					if e.Mode.IsFile() && path.Ext(e.Name) == ".tf" {
+						if txt, err := readFileFromCommit(c, e.Name); err == nil {
+							for k, v := range tfVersions(txt) {
+								curr[k] = v
+							}
+						}
+					}
+				}
+			}
+			// Lockfile enthält die tatsächlich installierte Version
+			if txt, err := readFileFromCommit(c, ".terraform.lock.hcl"); err == nil && txt != "" {
+				for k, v := range tfLockVersions(txt) {
+					curr[k] = v
+				}
+			}
+			return curr
+		},
+		reg: &registry.Terraform{},
+		parse: func(file, txt string) map[string]string {
+			if path.Ext(file) == ".hcl" {
+				return tfLockVersions(txt)
+			}
+			return tfVersions(txt)
+		},
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"baa_fs25/shared/anon"
+)
+
+// -----------------------------------------------------------------------------
+// ---------- Zeitleiste einer Dependency (--timeline) --------------------------
+// -----------------------------------------------------------------------------
+
+// timelineDep und timelineOut sind --timeline bzw. --timeline-out. Die
+// Endung von timelineOut wählt das Format: .dot = Graphviz, sonst Mermaid
+// (gantt).
+var timelineDep, timelineOut string
+
+// timelineEntry ist eine übernommene Version: Release und Übernahme.
+type timelineEntry struct {
+	ver, file     string
+	release, used time.Time
+	days          float64
+}
+
+// writeTimeline schreibt für jede Version von timelineDep, auf die das Repo
+// aktualisiert hat, Release- und Übernahmedatum als Diagramm.
+func writeTimeline(repo string, ds []delay) error {
+	var es []timelineEntry
+	for _, d := range ds {
+		if d.Dep != timelineDep && d.Alias != timelineDep {
+			continue
+		}
+		rel := d.CommitDate.Add(-time.Duration(d.Days * float64(24*time.Hour)))
+		es = append(es, timelineEntry{ver: d.NewVer, file: d.File, release: rel, used: d.CommitDate, days: d.Days})
+	}
+	if len(es) == 0 {
+		return fmt.Errorf("keine Updates von %s im analysierten Zeitraum", timelineDep)
+	}
+	sort.SliceStable(es, func(i, j int) bool { return es[i].used.Before(es[j].used) })
+	out := timelineOut
+	if out == "" {
+		out = "timeline-" + rxFileUnsafe.ReplaceAllString(timelineDep, "_") + ".mmd"
+	}
+	text := mermaidTimeline(anon.Repo(repo), es)
+	if filepath.Ext(out) == ".dot" {
+		text = dotTimeline(anon.Repo(repo), es)
+	}
+	if out == "-" {
+		_, err := os.Stdout.WriteString(text)
+		return err
+	}
+	return os.WriteFile(out, []byte(text), 0o644)
+}
+
+var rxFileUnsafe = regexp.MustCompile(`[^\w.-]+`)
+
+func (e timelineEntry) label() string {
+	l := e.ver
+	if e.file != "" {
+		l += " (" + e.file + ")"
+	}
+	return l
+}
+
+// mermaidTimeline zeichnet je Version einen Balken vom Release bis zur
+// Übernahme; am selben Tag übernommene Versionen werden Meilensteine.
+func mermaidTimeline(repo string, es []timelineEntry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "gantt\n    title %s in %s – Release bis Übernahme\n", mermaidText(timelineDep), mermaidText(repo))
+	b.WriteString("    dateFormat YYYY-MM-DD\n    axisFormat %Y-%m\n")
+	for i, e := range es {
+		fmt.Fprintf(&b, "    section %s\n", mermaidText(e.label()))
+		if e.days < 1 {
+			fmt.Fprintf(&b, "    übernommen am Release-Tag :milestone, v%d, %s, 0d\n", i, e.used.Format("2006-01-02"))
+			continue
+		}
+		fmt.Fprintf(&b, "    %.0f Tage :v%d, %s, %s\n", e.days, i, e.release.Format("2006-01-02"), e.used.Format("2006-01-02"))
+	}
+	return b.String()
+}
+
+// mermaidText entfernt Zeichen, die in Titel- und Section-Zeilen Syntax
+// sind (Anweisungsende, Entities).
+func mermaidText(s string) string {
+	return strings.NewReplacer("#", " ", ";", " ", "\n", " ").Replace(s)
+}
+
+// dotTimeline zeichnet je Version zwei Knoten (Release, Übernahme), die
+// Versionen links nach rechts in Übernahme-Reihenfolge.
+func dotTimeline(repo string, es []timelineEntry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph timeline {\n  rankdir=LR;\n  label=%q;\n  labelloc=t;\n  node [shape=box, fontsize=10];\n",
+		timelineDep+" in "+repo+" – Release bis Übernahme")
+	for i, e := range es {
+		fmt.Fprintf(&b, "  r%d [label=%q, style=dashed];\n", i, e.label()+"\nRelease "+e.release.Format("2006-01-02"))
+		fmt.Fprintf(&b, "  u%d [label=%q];\n", i, "übernommen\n"+e.used.Format("2006-01-02"))
+		fmt.Fprintf(&b, "  r%d -> u%d [label=%q];\n", i, i, fmt.Sprintf("%.0f d", e.days))
+		if i > 0 {
+			fmt.Fprintf(&b, "  u%d -> r%d [style=invis];\n", i-1, i)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"baa_fs25/shared/logging"
+)
+
+// Env-Variablen, die in den Container durchgereicht werden (falls gesetzt).
+var dockerEnv = []string{"GH_TOKEN", "GH_PAT", "GITHUB_TOKEN", "LIBIO_KEY", "BAA_ANONYMIZE", "BAA_ANONYMIZE_SALT", "BAA_SCHEMA_VERSION"}
+
+// runDockerRun führt ein Tool (mttu | ttf | libyears | baa) im Container aus.
+// Das aktuelle Verzeichnis wird als /work gemountet, relative Pfade in den
+// Argumenten funktionieren daher unverändert.
+//
+//	baa docker-run [--image baa:local] [--build] -- <tool> [args...]
+func runDockerRun(args []string) {
+	fs, lo := newFlagSet("docker-run")
+	image := fs.String("image", "baa:local", "Docker-Image")
+	build := fs.Bool("build", false, "Image vorher aus dem Dockerfile bauen")
+	root := fs.String("tools", ".", "Wurzel dieses Repos (Build-Kontext für --build)")
+	parseFlags(fs, lo, args)
+
+	if fs.NArg() < 1 {
+		logging.Usage("Usage: baa docker-run [--image I] [--build] -- <mttu|ttf|libyears|baa> [args...]")
+	}
+	switch fs.Arg(0) {
+	case "mttu", "ttf", "libyears", "baa":
+	default:
+		logging.Usage("unbekanntes Tool – erlaubt: mttu | ttf | libyears | baa", "tool", fs.Arg(0))
+	}
+
+	if *build {
+		ctx, err := filepath.Abs(*root)
+		if err != nil {
+			logging.Fatal("Pfad ungültig", "dir", *root, "err", err)
+		}
+		if err := docker("build", "-t", *image, ctx); err != nil {
+			logging.Fatal("docker build fehlgeschlagen", "image", *image, "err", err)
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		logging.Fatal("Arbeitsverzeichnis unbekannt", "err", err)
+	}
+	run := []string{"run", "--rm", "-v", wd + ":/work", "-w", "/work"}
+	for _, k := range dockerEnv {
+		if _, ok := os.LookupEnv(k); ok {
+			run = append(run, "-e", k) // Wert aus der Umgebung übernehmen
+		}
+	}
+	run = append(run, *image)
+	run = append(run, fs.Args()...)
+	if err := docker(run...); err != nil {
+		var ee *exec.ExitError
+		if errors.As(err, &ee) {
+			os.Exit(ee.ExitCode()) // Exit-Code des Tools (bzw. von docker) durchreichen
+		}
+		logging.Fatal("Container-Lauf fehlgeschlagen", "image", *image, "tool", fs.Arg(0), "err", err)
+	}
+}
+
+func docker(args ...string) error {
+	cmd := exec.Command("docker", args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	slog.Debug("docker", "args", args)
+	return cmd.Run()
+}
@@ -0,0 +1,393 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"baa_fs25/shared/anon"
+	"baa_fs25/shared/exitcode"
+	"baa_fs25/shared/i18n"
+	"baa_fs25/shared/logging"
+	"baa_fs25/shared/provenance"
+	"baa_fs25/shared/report"
+	"baa_fs25/shared/schema"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// baa health – kombiniert MTTU, Libyears, TTF-Exposure und Bot-Automatisierung
+// zu einem gewichteten Health-Score (0–100, höher = gesünder).
+//
+//	baa health [--eco npm] [--osv osv.json] [--weights mttu=0.3,...] [--out health.json] [--md health.md] <repo-url>
+//
+// Teil-Scores (linear, auf 0–100 begrenzt):
+//
+//	mttu      Median Tage bis Update      100 bei 0, 0 ab 365 Tagen
+//	libyears  Ø Lag je Dependency         100 bei 0, 0 ab 5 Jahren
+//	ttf       Ø Exposure Window           100 bei 0, 0 ab 180 Tagen; −10 je offenem Advisory
+//	bots      Dependabot/Renovate         50 für eine Konfiguration + 50 × Anteil Bot-Updates
+//
+// Fehlt eine Metrik, werden die übrigen Gewichte neu normiert.
+
+// healthSchemaVersion ist die Version von healthReport.
+const healthSchemaVersion = 1
+
+var defaultWeights = "mttu=0.3,libyears=0.3,ttf=0.25,bots=0.15"
+
+type healthReport struct {
+	Schema        string            `json:"schema"`
+	SchemaVersion int               `json:"schema_version"`
+	Repo          string            `json:"repo"`
+	Commit        string            `json:"commit"`
+	AnalyzedAt    time.Time         `json:"analyzed_at"`
+	Score         *float64          `json:"score"`
+	Components    []healthComponent `json:"components"`
+	Bots          botInfo           `json:"bots"`
+	Errors        map[string]string `json:"errors,omitempty"`
+	Provenance    *provenance.Info  `json:"provenance,omitempty"`
+}
+
+// healthComponent ist ein Teil-Score; Weight ist das normierte Gewicht.
+type healthComponent struct {
+	Name   string  `json:"name"`
+	Value  float64 `json:"value"`
+	Unit   string  `json:"unit"`
+	Score  float64 `json:"score"`
+	Weight float64 `json:"weight"`
+	Detail string  `json:"detail,omitempty"`
+}
+
+// botInfo beschreibt die Update-Automatisierung des Repos.
+type botInfo struct {
+	Config     []string `json:"config"`      // gefundene Konfigurationsdateien
+	Updates    int      `json:"updates"`     // Dependency-Updates laut mttu
+	BotUpdates int      `json:"bot_updates"` // davon von Bots committet
+}
+
+// botConfigs sind die Konfigurationsdateien von Dependabot und Renovate.
+var botConfigs = []string{
+	".github/dependabot.yml", ".github/dependabot.yaml",
+	"renovate.json", "renovate.json5", ".renovaterc", ".renovaterc.json",
+	".github/renovate.json", ".github/renovate.json5", ".gitlab/renovate.json",
+}
+
+func runHealth(args []string) {
+	fs, lo := newFlagSet("health")
+	var r studyRepo
+	fs.StringVar(&r.Eco, "eco", "", "npm | go | py – für mttu und libyears")
+	fs.StringVar(&r.OSV, "osv", "", "OSV-JSON für ttf")
+	fs.StringVar(&r.Slug, "slug", "", "owner/repo für ttf (Default: aus GitHub-URL)")
+	fs.StringVar(&r.Plat, "plat", "", "libraries.io-Plattform für ttf")
+	fs.StringVar(&r.Pkg, "pkg", "", "Paketname für ttf")
+	weights := fs.String("weights", defaultWeights, "Gewichte der Teil-Scores")
+	out := fs.String("out", "", "Report als JSON schreiben (\"-\" = stdout)")
+	md := fs.String("md", "", "Report als Markdown schreiben (Default: stdout)")
+	var cfg studyConfig
+	fs.StringVar(&cfg.out, "work", "results/health", "Verzeichnis für Tool-Logs")
+	fs.StringVar(&cfg.clones, "clones", "clones", "Verzeichnis für die Checkouts")
+	fs.StringVar(&cfg.root, "tools", ".", "Wurzel dieses Repos (dort liegen die Tool-Module)")
+	fs.IntVar(&cfg.days, "days", 365, "mttu: Historie X Tage zurück")
+	parseFlags(fs, lo, args)
+	if fs.NArg() != 1 {
+		logging.Usage("Usage: baa health [flags] <repo-url>")
+	}
+	r.URL = fs.Arg(0)
+	if r.Slug == "" {
+		r.Slug = githubSlug(r.URL)
+	}
+	w, err := parseWeights(*weights)
+	if err != nil {
+		logging.Usage("--weights ungültig", "err", err)
+	}
+	for _, dir := range []*string{&cfg.out, &cfg.clones, &cfg.root} {
+		if *dir, err = filepath.Abs(*dir); err != nil {
+			logging.Fatal("Pfad ungültig", "dir", *dir, "err", err)
+		}
+	}
+	if r.OSV != "" {
+		if r.OSV, err = filepath.Abs(r.OSV); err != nil {
+			logging.Fatal("Pfad ungültig", "osv", r.OSV, "err", err)
+		}
+	}
+	for _, dir := range []string{cfg.out, cfg.clones} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			logging.Fatal("Verzeichnis nicht anlegbar", "dir", dir, "err", err)
+		}
+	}
+
+	checkout, commit, release, err := cloneOnce(cfg.clones, r.URL)
+	if err != nil {
+		logging.Fatal("Klonen fehlgeschlagen", "repo", r.URL, "err", err)
+	}
+	defer release()
+	rep := healthReport{
+		Schema: schema.ID("baa-health"), SchemaVersion: healthSchemaVersion, Repo: anon.Repo(r.URL), Commit: anon.Commit(commit),
+		AnalyzedAt: time.Now().UTC(), Errors: map[string]string{},
+	}
+
+	// Der Report enthält nur Aggregate; die Tools laufen daher ohne
+	// --anonymize, detectBots braucht die echten Commit-Hashes aus mttu.
+	os.Unsetenv("BAA_ANONYMIZE")
+	raw := map[string]json.RawMessage{}
+	for _, name := range []string{"mttu", "libyears", "ttf"} {
+		t := tools[name]
+		flags, pos, err := toolArgs(cfg, t, r, checkout)
+		if err == nil {
+			raw[name], err = runTool(cfg, t, repoName(r.URL)+".health", flags, pos)
+		}
+		if err != nil {
+			slog.Warn("Metrik fehlt im Health-Score", "metric", name, "err", err)
+			rep.Errors[name] = err.Error()
+		}
+	}
+
+	var comps []healthComponent
+	if c, err := mttuComponent(raw["mttu"]); err == nil {
+		comps = append(comps, c)
+	} else if raw["mttu"] != nil {
+		rep.Errors["mttu"] = err.Error()
+	}
+	if c, err := libyearsComponent(raw["libyears"]); err == nil {
+		comps = append(comps, c)
+	} else if raw["libyears"] != nil {
+		rep.Errors["libyears"] = err.Error()
+	}
+	if c, err := ttfComponent(raw["ttf"]); err == nil {
+		comps = append(comps, c)
+	} else if raw["ttf"] != nil {
+		rep.Errors["ttf"] = err.Error()
+	}
+	rep.Bots = detectBots(checkout, raw["mttu"])
+	comps = append(comps, botComponent(rep.Bots))
+
+	rep.Components, rep.Score = weigh(comps, w)
+	exitcode.Skipped(len(rep.Errors), len(tools)) // je Tool eine Metrik
+	if len(rep.Errors) == 0 {
+		rep.Errors = nil
+	}
+
+	if *out != "" {
+		rep.Provenance = provenance.Get()
+		if err := report.WriteJSON(*out, rep); err != nil {
+			logging.Fatal("JSON-Ausgabe fehlgeschlagen", "file", *out, "err", err)
+		}
+	}
+	text := healthMarkdown(rep)
+	if *md == "" {
+		if *out != "-" {
+			fmt.Print(text)
+		}
+		return
+	}
+	if err := os.WriteFile(*md, []byte(text), 0o644); err != nil {
+		logging.Fatal("Markdown-Ausgabe fehlgeschlagen", "file", *md, "err", err)
+	}
+}
+
+// parseWeights liest "name=gewicht,..." (nicht genannte Metriken: 0).
+func parseWeights(s string) (map[string]float64, error) {
+	w := map[string]float64{}
+	for _, part := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, fmt.Errorf("%q: erwartet name=gewicht", part)
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < 0 {
+			return nil, fmt.Errorf("%q: Gewicht ungültig", part)
+		}
+		switch k {
+		case "mttu", "libyears", "ttf", "bots":
+		default:
+			return nil, fmt.Errorf("unbekannte Metrik %q (erlaubt: mttu,libyears,ttf,bots)", k)
+		}
+		w[k] = f
+	}
+	return w, nil
+}
+
+// linearScore bildet 0 → 100 und worst → 0 ab.
+func linearScore(v, worst float64) float64 {
+	return math.Max(0, math.Min(100, 100*(1-v/worst)))
+}
+
+func mttuComponent(raw json.RawMessage) (healthComponent, error) {
+	var m struct {
+		Summary struct {
+			Updates    int     `json:"updates"`
+			MedianDays float64 `json:"median_days"`
+			LowSample  bool    `json:"low_sample"`
+		} `json:"summary"`
+	}
+	if raw == nil {
+		return healthComponent{}, fmt.Errorf("keine Daten")
+	}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return healthComponent{}, err
+	}
+	if m.Summary.Updates == 0 {
+		return healthComponent{}, fmt.Errorf("keine Updates im Zeitraum")
+	}
+	detail := fmt.Sprintf("%d Updates", m.Summary.Updates)
+	if m.Summary.LowSample {
+		detail += ", kleine Stichprobe"
+	}
+	return healthComponent{Name: "mttu", Value: m.Summary.MedianDays, Unit: "Tage (Median)",
+		Score: linearScore(m.Summary.MedianDays, 365), Detail: detail}, nil
+}
+
+func libyearsComponent(raw json.RawMessage) (healthComponent, error) {
+	var m struct {
+		Summary struct {
+			Count   int     `json:"count"`
+			MeanLag float64 `json:"mean_lag_years"`
+		} `json:"summary"`
+	}
+	if raw == nil {
+		return healthComponent{}, fmt.Errorf("keine Daten")
+	}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return healthComponent{}, err
+	}
+	if m.Summary.Count == 0 {
+		return healthComponent{}, fmt.Errorf("keine Dependencies ausgewertet")
+	}
+	return healthComponent{Name: "libyears", Value: m.Summary.MeanLag, Unit: "Jahre (Ø je Dependency)",
+		Score: linearScore(m.Summary.MeanLag, 5), Detail: fmt.Sprintf("%d Dependencies", m.Summary.Count)}, nil
+}
+
+func ttfComponent(raw json.RawMessage) (healthComponent, error) {
+	var m struct {
+		Summary struct {
+			MeanExposureDays *float64 `json:"mean_exposure_days"`
+			ExposureCount    int      `json:"exposure_count"`
+			OpenCount        int      `json:"open_count"`
+		} `json:"summary"`
+	}
+	if raw == nil {
+		return healthComponent{}, fmt.Errorf("keine Daten")
+	}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return healthComponent{}, err
+	}
+	var exp float64
+	if m.Summary.MeanExposureDays != nil {
+		exp = *m.Summary.MeanExposureDays
+	}
+	score := math.Max(0, linearScore(exp, 180)-10*float64(m.Summary.OpenCount))
+	return healthComponent{Name: "ttf", Value: exp, Unit: "Tage (Ø Exposure)", Score: score,
+		Detail: fmt.Sprintf("%d Advisories mit Exposure, %d offen", m.Summary.ExposureCount, m.Summary.OpenCount)}, nil
+}
+
+func botComponent(b botInfo) healthComponent {
+	share := 0.0
+	if b.Updates > 0 {
+		share = float64(b.BotUpdates) / float64(b.Updates)
+	}
+	score := 50 * share
+	if len(b.Config) > 0 {
+		score += 50
+	}
+	detail := "keine Bot-Konfiguration"
+	if len(b.Config) > 0 {
+		detail = strings.Join(b.Config, ", ")
+	}
+	return healthComponent{Name: "bots", Value: share * 100, Unit: "% Bot-Updates", Score: score,
+		Detail: fmt.Sprintf("%s; %d/%d Updates von Bots", detail, b.BotUpdates, b.Updates)}
+}
+
+// detectBots sucht Bot-Konfigurationen im Checkout und prüft, welche der
+// von mttu gefundenen Update-Commits von Bots stammen.
+func detectBots(checkout string, mttuRaw json.RawMessage) botInfo {
+	b := botInfo{Config: []string{}}
+	for _, f := range botConfigs {
+		if _, err := os.Stat(filepath.Join(checkout, filepath.FromSlash(f))); err == nil {
+			b.Config = append(b.Config, f)
+		}
+	}
+	var m struct {
+		Updates []struct {
+			Commit string `json:"commit"`
+		} `json:"updates"`
+	}
+	if mttuRaw == nil || json.Unmarshal(mttuRaw, &m) != nil {
+		return b
+	}
+	repo, err := git.PlainOpen(checkout)
+	if err != nil {
+		slog.Warn("Checkout nicht lesbar, Bot-Anteil unbekannt", "dir", checkout, "err", err)
+		return b
+	}
+	seen := map[string]bool{}
+	for _, u := range m.Updates {
+		if seen[u.Commit] {
+			continue
+		}
+		seen[u.Commit] = true
+		b.Updates++
+		c, err := repo.CommitObject(plumbing.NewHash(u.Commit))
+		if err == nil && isBot(c.Author.Name, c.Author.Email) {
+			b.BotUpdates++
+		}
+	}
+	return b
+}
+
+func isBot(name, email string) bool {
+	s := strings.ToLower(name + " " + email)
+	return strings.Contains(s, "[bot]") || strings.Contains(s, "dependabot") || strings.Contains(s, "renovate")
+}
+
+// weigh normiert die Gewichte auf die vorhandenen Teil-Scores und bildet
+// den gewichteten Mittelwert (nil, wenn kein Gewicht übrig bleibt).
+func weigh(comps []healthComponent, w map[string]float64) ([]healthComponent, *float64) {
+	var sum float64
+	for _, c := range comps {
+		sum += w[c.Name]
+	}
+	if sum == 0 {
+		return comps, nil
+	}
+	var score float64
+	for i := range comps {
+		comps[i].Weight = w[comps[i].Name] / sum
+		score += comps[i].Weight * comps[i].Score
+	}
+	sort.SliceStable(comps, func(i, j int) bool { return comps[i].Weight > comps[j].Weight })
+	return comps, &score
+}
+
+func healthMarkdown(rep healthReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Health: %s\n\n", rep.Repo)
+	if rep.Score != nil {
+		fmt.Fprintf(&b, "**Score: %.0f / 100**\n\n", *rep.Score)
+	} else {
+		b.WriteString("**Score: n/a**\n\n")
+	}
+	fmt.Fprint(&b, i18n.Sprintf("Commit `%s`, analysiert %s\n\n", rep.Commit, rep.AnalyzedAt.Format("2006-01-02 15:04 MST")))
+	b.WriteString(i18n.T("| Metrik | Wert | Score | Gewicht | Details |\n") + "|---|---:|---:|---:|---|\n")
+	for _, c := range rep.Components {
+		fmt.Fprintf(&b, "| %s | %.1f %s | %.0f | %.0f %% | %s |\n", c.Name, c.Value, c.Unit, c.Score, c.Weight*100, c.Detail)
+	}
+	if len(rep.Errors) > 0 {
+		b.WriteString(i18n.T("\nNicht berücksichtigt:\n\n"))
+		names := make([]string, 0, len(rep.Errors))
+		for n := range rep.Errors {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			fmt.Fprintf(&b, "- %s: %s\n", n, rep.Errors[n])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,126 @@
+// baa – gemeinsamer Einstiegspunkt für die Analyse-Tools (MTTU, TTF, Libyears)
+//
+// Usage:
+//
+//	baa study --repos repos.csv --metrics mttu,ttf,libyears --out results/
+//	baa docker-run [--build] -- <mttu|ttf|libyears|baa> [args...]
+//	baa serve [--addr 127.0.0.1:8080] [--workers 2] [--osv-dir osv] [--job-ttl 1h]
+//	baa merge [--out combined.parquet] results/*.json
+//	baa health [--eco npm] [--osv osv.json] [--out health.json] <repo-url>
+//	baa schema print [--format text|json|csv] [name ...]
+//
+// Alle Subcommands kennen --schema-version N (Format der Ausgaben pinnen,
+// wird an die Tools weitergereicht; s. shared/schema) sowie --mirror-dir und
+// --clone-quota (Bare-Mirrors mit Platz-Obergrenze, Default:
+// <UserCacheDir>/baa_fs25/mirrors; s. shared/clones) und --lang en|de
+// (Sprache der Reports, ebenfalls an die Tools weitergereicht; s.
+// shared/i18n) und --max-skipped (s. unten).
+//
+// Exit-Codes aller Subcommands und Tools (s. shared/exitcode): 0 ok,
+// 1 Analysefehler, 2 Aufruffehler, 3 Teilergebnis – mehr als --max-skipped
+// (Default 0.25) übersprungen: Metrik-Läufe bei study und health, Dateien
+// bei merge. docker-run reicht den Exit-Code des Tools durch.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"baa_fs25/shared/anon"
+	"baa_fs25/shared/clones"
+	"baa_fs25/shared/exitcode"
+	"baa_fs25/shared/httpcache"
+	"baa_fs25/shared/i18n"
+	"baa_fs25/shared/logging"
+	"baa_fs25/shared/netcfg"
+	"baa_fs25/shared/provenance"
+	"baa_fs25/shared/schema"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	cmd, args := os.Args[1], os.Args[2:]
+	switch cmd {
+	case "study":
+		runStudy(args)
+	case "docker-run":
+		runDockerRun(args)
+	case "serve":
+		runServe(args)
+	case "merge":
+		runMerge(args)
+	case "health":
+		runHealth(args)
+	case "schema":
+		runSchema(args)
+	default:
+		usage()
+	}
+	exitcode.Finish()
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s <study|docker-run|serve|merge|health|schema> [flags]\n", os.Args[0])
+	os.Exit(exitcode.Usage)
+}
+
+// netOpts, anonOpts, schemaOpts, cloneOpts, langOpts und exitOpts sind die
+// Netz-, --anonymize-, --schema-version-, Mirror-, --lang- bzw.
+// --max-skipped-Flags des aktiven Subcommands.
+var (
+	netOpts    *netcfg.Options
+	anonOpts   *anon.Options
+	schemaOpts *schema.Options
+	cloneOpts  *clones.Options
+	langOpts   *i18n.Options
+	exitOpts   *exitcode.Options
+)
+
+// newFlagSet legt das FlagSet eines Subcommands inkl. Logging- und
+// Netz-Flags an.
+func newFlagSet(name string) (*flag.FlagSet, *logging.Options) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	netOpts = netcfg.Register(fs)
+	anonOpts = anon.Register(fs)
+	schemaOpts = schema.Register(fs)
+	cloneOpts = clones.Register(fs, httpcache.DefaultDir("mirrors"))
+	langOpts = i18n.Register(fs)
+	exitOpts = exitcode.Register(fs)
+	return fs, logging.Register(fs)
+}
+
+// parseFlags parst die Argumente und installiert den Logger.
+func parseFlags(fs *flag.FlagSet, lo *logging.Options, args []string) {
+	_ = fs.Parse(args) // ExitOnError
+	provenance.Start("baa "+fs.Name(), fs)
+	if err := lo.Setup(); err != nil {
+		logging.Fatal("Logging-Setup fehlgeschlagen", "err", err)
+	}
+	if err := netOpts.Setup(); err != nil {
+		logging.Fatal("Netz-Setup fehlgeschlagen", "err", err)
+	}
+	netOpts.Export()
+	if err := anonOpts.Setup(); err != nil {
+		logging.Usage("ungültiges --anonymize", "err", err)
+	}
+	anonOpts.Export()
+	if err := schemaOpts.Setup(); err != nil {
+		logging.Usage("ungültiges --schema-version", "err", err)
+	}
+	schemaOpts.Export()
+	if err := cloneOpts.Setup(); err != nil {
+		logging.Usage("ungültige Mirror-Flags", "err", err)
+	}
+	cloneOpts.Export()
+	if err := langOpts.Setup(); err != nil {
+		logging.Usage("ungültiges --lang", "err", err)
+	}
+	langOpts.Export()
+	if err := exitOpts.Setup(); err != nil {
+		logging.Usage("ungültiges --max-skipped", "err", err)
+	}
+	exitOpts.Export()
+}
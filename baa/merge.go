@@ -0,0 +1,241 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"baa_fs25/shared/anon"
+	"baa_fs25/shared/exitcode"
+	"baa_fs25/shared/logging"
+	"baa_fs25/shared/provenance"
+	"baa_fs25/shared/report"
+	"baa_fs25/shared/schema"
+	"github.com/parquet-go/parquet-go"
+)
+
+// baa merge – führt studyRecords (<out>/<name>.json von "baa study") mehrerer
+// Läufe/Rechner zu einem flachen Datensatz zusammen.
+//
+//	baa merge [--out combined.parquet] results/*.json [more/*.json ...]
+//
+// Das Format folgt der Endung von --out: .parquet, .jsonl oder .json.
+// Zeilen mit gleichem repo+commit+metric+dep (+ Versionen/ref) werden
+// dedupliziert; es gewinnt der jüngste analyzed_at. Mit --anonymize werden
+// Repo, Commits und Workspaces auch in nicht anonymisierten Records gehasht.
+// Die Provenienz des Merges steht bei .parquet in den Key-Value-Metadaten
+// (Schlüssel "baa.provenance", dazu "baa.schema"), sonst in
+// <out>.provenance.json.
+
+// mergeSchemaVersion ist die Version von mergedRow. Bei inkompatiblen
+// Änderungen erhöhen, damit Auswertungen Altbestände erkennen.
+const mergeSchemaVersion = 1
+
+// mergedRow ist eine Zeile des zusammengeführten Datensatzes.
+//
+//	metric    dep              from           to            value
+//	mttu      dep              old_version    new_version   days (ref = Update-Commit)
+//	libyears  [workspace:]pkg  current        latest        lag_years
+//	ttf       Advisory-ID      intro_tag      fix_tag       delta_fix_days (+ exposure_days)
+type mergedRow struct {
+	SchemaVersion int       `parquet:"schema_version" json:"schema_version"`
+	Repo          string    `parquet:"repo" json:"repo"`
+	Commit        string    `parquet:"commit" json:"commit"`
+	AnalyzedAt    time.Time `parquet:"analyzed_at,timestamp" json:"analyzed_at"`
+	Metric        string    `parquet:"metric" json:"metric"`
+	Dep           string    `parquet:"dep" json:"dep"`
+	From          string    `parquet:"from" json:"from"`
+	To            string    `parquet:"to" json:"to"`
+	Value         *float64  `parquet:"value,optional" json:"value"`
+	ExposureDays  *float64  `parquet:"exposure_days,optional" json:"exposure_days,omitempty"`
+	Ref           string    `parquet:"ref" json:"ref,omitempty"`
+	Source        string    `parquet:"source" json:"source"`
+}
+
+func (r mergedRow) key() string {
+	return strings.Join([]string{r.Repo, r.Commit, r.Metric, r.Dep, r.From, r.To, r.Ref}, "\x00")
+}
+
+func runMerge(args []string) {
+	fs, lo := newFlagSet("merge")
+	out := fs.String("out", "combined.parquet", "Zieldatei (.parquet, .jsonl oder .json)")
+	parseFlags(fs, lo, args)
+	if fs.NArg() == 0 {
+		logging.Usage("Usage: baa merge [--out combined.parquet] results/*.json")
+	}
+
+	var files []string
+	for _, a := range fs.Args() {
+		m, err := filepath.Glob(a) // falls die Shell nicht expandiert
+		if err != nil || len(m) == 0 {
+			m = []string{a}
+		}
+		files = append(files, m...)
+	}
+
+	rows := map[string]mergedRow{}
+	invalid, dups := 0, 0
+	for _, f := range files {
+		recRows, err := recordRows(f)
+		if err != nil {
+			slog.Error("Datei ungültig, übersprungen", "file", f, "err", err)
+			invalid++
+			continue
+		}
+		for _, r := range recRows {
+			k := r.key()
+			if old, ok := rows[k]; ok {
+				dups++
+				if !r.AnalyzedAt.After(old.AnalyzedAt) {
+					continue
+				}
+			}
+			rows[k] = r
+		}
+	}
+
+	merged := make([]mergedRow, 0, len(rows))
+	for _, r := range rows {
+		merged = append(merged, r)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].key() < merged[j].key() })
+
+	if err := writeMerged(*out, merged); err != nil {
+		logging.Fatal("Ausgabe fehlgeschlagen", "file", *out, "err", err)
+	}
+	slog.Info("Merge abgeschlossen", "files", len(files), "invalid", invalid, "rows", len(merged), "duplicates", dups, "out", *out)
+	if invalid == len(files) {
+		logging.Fatal("keine gültige Eingabedatei", "files", len(files))
+	}
+	exitcode.Skipped(invalid, len(files))
+}
+
+// recordRows liest und validiert einen studyRecord und flacht ihn ab.
+func recordRows(path string) ([]mergedRow, error) {
+	var rec studyRecord
+	if err := report.ReadJSON(path, &rec); err != nil {
+		return nil, err
+	}
+	// Records vor Einführung von schema_version (0) haben dasselbe Format.
+	if rec.SchemaVersion > studySchemaVersion {
+		return nil, fmt.Errorf("schema_version %d unbekannt (unterstützt bis %d)", rec.SchemaVersion, studySchemaVersion)
+	}
+	if rec.Repo == "" || rec.Commit == "" {
+		return nil, fmt.Errorf("repo/commit fehlen – kein studyRecord?")
+	}
+
+	base := mergedRow{
+		SchemaVersion: mergeSchemaVersion, Repo: anon.Repo(rec.Repo), Commit: anon.Commit(rec.Commit),
+		AnalyzedAt: rec.AnalyzedAt, Source: filepath.Base(path),
+	}
+	if anon.Enabled() {
+		base.Source = anon.ID(base.Source)
+	}
+	var rows []mergedRow
+	for metric, raw := range rec.Metrics {
+		r := base
+		r.Metric = metric
+		switch metric {
+		case "mttu":
+			var m struct {
+				Updates []struct {
+					Dep    string  `json:"dep"`
+					OldVer string  `json:"old_version"`
+					NewVer string  `json:"new_version"`
+					Days   float64 `json:"days"`
+					Commit string  `json:"commit"`
+				} `json:"updates"`
+			}
+			if err := json.Unmarshal(raw, &m); err != nil {
+				return nil, fmt.Errorf("mttu: %w", err)
+			}
+			for _, u := range m.Updates {
+				r.Dep, r.From, r.To, r.Ref = u.Dep, u.OldVer, u.NewVer, anon.Commit(u.Commit)
+				r.Value = ptr(u.Days)
+				rows = append(rows, r)
+			}
+		case "libyears":
+			var m struct {
+				Deps []struct {
+					Package   string  `json:"package"`
+					Current   string  `json:"current"`
+					Latest    string  `json:"latest"`
+					Lag       float64 `json:"lag_years"`
+					Workspace string  `json:"workspace"`
+				} `json:"deps"`
+			}
+			if err := json.Unmarshal(raw, &m); err != nil {
+				return nil, fmt.Errorf("libyears: %w", err)
+			}
+			for _, d := range m.Deps {
+				r.Dep, r.From, r.To = d.Package, d.Current, d.Latest
+				if d.Workspace != "" {
+					r.Dep = anon.ID(d.Workspace) + ":" + d.Package
+				}
+				r.Value = ptr(d.Lag)
+				rows = append(rows, r)
+			}
+		case "ttf":
+			var m struct {
+				Advisories []struct {
+					ID       string   `json:"id"`
+					IntroTag string   `json:"intro_tag"`
+					FixTag   string   `json:"fix_tag"`
+					Fix      *float64 `json:"delta_fix_days"`
+					Exposure *float64 `json:"delta_exposure_days"`
+				} `json:"advisories"`
+			}
+			if err := json.Unmarshal(raw, &m); err != nil {
+				return nil, fmt.Errorf("ttf: %w", err)
+			}
+			for _, a := range m.Advisories {
+				r.Dep, r.From, r.To = a.ID, a.IntroTag, a.FixTag
+				r.Value, r.ExposureDays = a.Fix, a.Exposure
+				rows = append(rows, r)
+			}
+		default:
+			slog.Warn("unbekannte Metrik ignoriert", "file", path, "metric", metric)
+		}
+	}
+	return rows, nil
+}
+
+func writeMerged(path string, rows []mergedRow) error {
+	prov := provenance.Get()
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".parquet":
+		meta, err := json.Marshal(prov)
+		if err != nil {
+			return err
+		}
+		return parquet.WriteFile(path, rows,
+			parquet.KeyValueMetadata("baa.provenance", string(meta)),
+			parquet.KeyValueMetadata("baa.schema", schema.ID("baa-merge")))
+	case ".jsonl":
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		enc := json.NewEncoder(f)
+		for _, r := range rows {
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+		}
+		return report.WriteJSON(path+".provenance.json", prov)
+	case ".json":
+		if err := report.WriteJSON(path, rows); err != nil {
+			return err
+		}
+		return report.WriteJSON(path+".provenance.json", prov)
+	}
+	return fmt.Errorf("unbekanntes Format %q (erlaubt: .parquet, .jsonl, .json)", filepath.Ext(path))
+}
+
+func ptr(f float64) *float64 { return &f }
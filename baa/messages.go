@@ -0,0 +1,15 @@
+package main
+
+import "baa_fs25/shared/i18n"
+
+// Englischer Katalog für die Texte von "baa health" und "baa schema print"
+// (--lang en, s. shared/i18n).
+func init() {
+	i18n.Add(i18n.EN, map[string]string{
+		"Commit `%s`, analysiert %s\n\n":                  "Commit `%s`, analysed %s\n\n",
+		"| Metrik | Wert | Score | Gewicht | Details |\n": "| Metric | Value | Score | Weight | Details |\n",
+		"\nNicht berücksichtigt:\n\n":                     "\nNot included:\n\n",
+		"Schema-Versionen 1–%d, aktiv: %d\n":              "Schema versions 1–%d, active: %d\n",
+		"  Änderung: %s\n":                                "  Change: %s\n",
+	})
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"baa_fs25/shared/i18n"
+	"baa_fs25/shared/logging"
+	"baa_fs25/shared/schema"
+)
+
+// baa schema print – listet die Ausgabe-Schemas aller Tools (s.
+// shared/schema), damit Pipelines Brüche erkennen können.
+//
+//	baa schema print [--format text|json|csv] [--all] [--schema-version N] [name ...]
+//
+// Ohne --all wird je Ausgabe die Version gezeigt, die bei --schema-version N
+// (Default: neueste) geschrieben wird; --all zeigt alle Versionen. csv hat die
+// Spalten schema,version,field,type,doc.
+func runSchema(args []string) {
+	if len(args) == 0 || args[0] != "print" {
+		logging.Usage("Usage: baa schema print [--format text|json|csv] [--all] [name ...]")
+	}
+	fs, lo := newFlagSet("schema")
+	format := fs.String("format", "text", "Ausgabeformat: text | json | csv")
+	all := fs.Bool("all", false, "alle Versionen statt nur der aktiven")
+	parseFlags(fs, lo, args[1:])
+
+	var list []schema.Schema
+	if fs.NArg() == 0 {
+		list = schema.All()
+	}
+	for _, name := range fs.Args() {
+		s, ok := schema.Get(name)
+		if !ok {
+			logging.Usage("unbekanntes Schema", "name", name)
+		}
+		list = append(list, s)
+	}
+	if !*all {
+		for i, s := range list {
+			v, ok := s.At(schema.Requested())
+			if !ok {
+				v = s.Versions[0]
+			}
+			list[i].Versions = []schema.Revision{v}
+		}
+	}
+
+	switch *format {
+	case "text":
+		printSchemas(list)
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(map[string]any{"latest": schema.Latest(), "schemas": list}); err != nil {
+			logging.Fatal("Ausgabe fehlgeschlagen", "err", err)
+		}
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		_ = w.Write([]string{"schema", "version", "field", "type", "doc"})
+		for _, s := range list {
+			for _, v := range s.Versions {
+				for _, f := range v.Fields {
+					_ = w.Write([]string{s.Name, strconv.Itoa(v.Version), f.Name, f.Type, f.Doc})
+				}
+			}
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			logging.Fatal("Ausgabe fehlgeschlagen", "err", err)
+		}
+	default:
+		logging.Usage("ungültiges --format (erlaubt: text, json, csv)", "value", *format)
+	}
+}
+
+func printSchemas(list []schema.Schema) {
+	i18n.Printf("Schema-Versionen 1–%d, aktiv: %d\n", schema.Latest(), schema.Requested())
+	for _, s := range list {
+		for _, v := range s.Versions {
+			fmt.Printf("\n%s/%d  (%s) – %s\n", s.Name, v.Version, s.Tool, s.Doc)
+			if v.Changes != "" {
+				i18n.Printf("  Änderung: %s\n", v.Changes)
+			}
+			for _, f := range v.Fields {
+				fmt.Printf("  %-16s %-20s %s\n", f.Name, f.Type, f.Doc)
+			}
+		}
+	}
+}
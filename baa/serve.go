@@ -0,0 +1,275 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"baa_fs25/shared/logging"
+)
+
+// baa serve – REST-API, die Analysen asynchron über eine Job-Queue ausführt.
+//
+//	POST /analyze/{metric}   metric = mttu | ttf | libyears, Body = analyzeRequest
+//	                         → 202 {"id": "...", "status": "queued", "result": "/results/<id>"}
+//	GET  /results/{id}       → job (status queued | running | done | failed)
+//
+// Die Tools laufen wie bei "baa study" als Subprozesse. Checkouts liegen je
+// normalisierter Repo-URL unter --clones (s. repoName) und werden nur
+// zwischen Jobs für dasselbe Repo wiederverwendet; gleichnamige Repos
+// verschiedener Nutzer teilen sich also keinen Checkout.
+//
+// Die API hat keine Authentifizierung und lauscht daher per Default nur auf
+// 127.0.0.1. repo muss eine http(s)-URL sein (keine lokalen Verzeichnisse),
+// osv ein Pfad relativ zu --osv-dir. Beendete Jobs verfallen nach --job-ttl.
+
+// analyzeRequest ist der Body von POST /analyze/{metric}. Die Felder
+// entsprechen den Spalten von repos.csv; window ist das mttu-Stopp-Kriterium
+// (Default: 365 Tage).
+type analyzeRequest struct {
+	Repo   string `json:"repo"`
+	Eco    string `json:"eco"`
+	OSV    string `json:"osv"` // relativ zu --osv-dir
+	Slug   string `json:"slug"`
+	Plat   string `json:"plat"`
+	Pkg    string `json:"pkg"`
+	Window struct {
+		Commits int `json:"commits"`
+		Changes int `json:"changes"`
+		Days    int `json:"days"`
+	} `json:"window"`
+}
+
+type job struct {
+	ID       string          `json:"id"`
+	Metric   string          `json:"metric"`
+	Repo     string          `json:"repo"`
+	Status   string          `json:"status"`
+	Error    string          `json:"error,omitempty"`
+	Commit   string          `json:"commit,omitempty"`
+	Created  time.Time       `json:"created"`
+	Finished *time.Time      `json:"finished,omitempty"`
+	Result   json.RawMessage `json:"result,omitempty"`
+
+	req analyzeRequest
+}
+
+type server struct {
+	cfg    studyConfig
+	queue  chan *job
+	osvDir string        // Wurzel für analyzeRequest.OSV
+	ttl    time.Duration // Aufbewahrung beendeter Jobs
+
+	mu   sync.Mutex
+	jobs map[string]*job
+
+	cloneMu sync.Mutex // cloneOnce ist nicht nebenläufig sicher
+}
+
+func runServe(args []string) {
+	fs, lo := newFlagSet("serve")
+	addr := fs.String("addr", "127.0.0.1:8080", "Listen-Adresse (ohne Authentifizierung – andere Interfaces nur hinter einem Proxy freigeben)")
+	workers := fs.Int("workers", 2, "Anzahl paralleler Analysen")
+	queueLen := fs.Int("queue", 100, "maximale Anzahl wartender Jobs")
+	osvDir := fs.String("osv-dir", "osv", "Verzeichnis, relativ zu dem \"osv\" im Request aufgelöst wird")
+	ttl := fs.Duration("job-ttl", time.Hour, "beendete Jobs nach dieser Dauer verwerfen")
+	var cfg studyConfig
+	fs.StringVar(&cfg.out, "out", "results/serve", "Verzeichnis für Tool-Logs")
+	fs.StringVar(&cfg.clones, "clones", "clones", "Verzeichnis für die Checkouts")
+	fs.StringVar(&cfg.root, "tools", ".", "Wurzel dieses Repos (dort liegen die Tool-Module)")
+	parseFlags(fs, lo, args)
+
+	var err error
+	for _, dir := range []*string{&cfg.out, &cfg.clones, &cfg.root, osvDir} {
+		if *dir, err = filepath.Abs(*dir); err != nil {
+			logging.Fatal("Pfad ungültig", "dir", *dir, "err", err)
+		}
+	}
+	for _, dir := range []string{cfg.out, cfg.clones} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			logging.Fatal("Verzeichnis nicht anlegbar", "dir", dir, "err", err)
+		}
+	}
+
+	s := &server{cfg: cfg, queue: make(chan *job, *queueLen), osvDir: *osvDir, ttl: *ttl, jobs: map[string]*job{}}
+	for i := 0; i < *workers; i++ {
+		go s.worker()
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /analyze/{metric}", s.handleAnalyze)
+	mux.HandleFunc("GET /results/{id}", s.handleResult)
+	slog.Info("baa serve gestartet", "addr", *addr, "workers", *workers)
+	if err := http.ListenAndServe(*addr, mux); err != nil {
+		logging.Fatal("Server beendet", "err", err)
+	}
+}
+
+func (s *server) handleAnalyze(w http.ResponseWriter, r *http.Request) {
+	metric := r.PathValue("metric")
+	if _, ok := tools[metric]; !ok {
+		httpError(w, http.StatusNotFound, "unbekannte Metrik – erlaubt: mttu,ttf,libyears")
+		return
+	}
+	var req analyzeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpError(w, http.StatusBadRequest, "Body ungültig: "+err.Error())
+		return
+	}
+	if err := checkRepoURL(req.Repo); err != nil {
+		httpError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.OSV != "" {
+		osv, err := resolveUnder(s.osvDir, req.OSV)
+		if err != nil {
+			httpError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		req.OSV = osv
+	}
+	now := time.Now().UTC()
+	j := &job{ID: newJobID(), Metric: metric, Repo: req.Repo, Status: "queued", Created: now, req: req}
+	s.mu.Lock()
+	s.expire(now)
+	s.jobs[j.ID] = j
+	s.mu.Unlock()
+	select {
+	case s.queue <- j:
+	default:
+		s.mu.Lock()
+		delete(s.jobs, j.ID)
+		s.mu.Unlock()
+		httpError(w, http.StatusServiceUnavailable, "Queue voll")
+		return
+	}
+	slog.Info("Job angenommen", "id", j.ID, "metric", metric, "repo", req.Repo)
+
+	w.Header().Set("Location", "/results/"+j.ID)
+	writeJSON(w, http.StatusAccepted, map[string]string{"id": j.ID, "status": "queued", "result": "/results/" + j.ID})
+}
+
+func (s *server) handleResult(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.expire(time.Now().UTC())
+	j, ok := s.jobs[r.PathValue("id")]
+	var snapshot job
+	if ok {
+		snapshot = *j
+	}
+	s.mu.Unlock()
+	if !ok {
+		httpError(w, http.StatusNotFound, "Job unbekannt")
+		return
+	}
+	writeJSON(w, http.StatusOK, snapshot)
+}
+
+func (s *server) worker() {
+	for j := range s.queue {
+		s.update(j, func(j *job) { j.Status = "running" })
+		commit, raw, err := s.run(j)
+		now := time.Now().UTC()
+		s.update(j, func(j *job) {
+			j.Finished, j.Commit = &now, commit
+			if err != nil {
+				j.Status, j.Error = "failed", err.Error()
+				return
+			}
+			j.Status, j.Result = "done", raw
+		})
+		slog.Info("Job beendet", "id", j.ID, "metric", j.Metric, "err", err)
+	}
+}
+
+// expire verwirft Jobs, die seit mehr als s.ttl beendet sind; laufende und
+// wartende Jobs bleiben. Aufruf nur mit s.mu.
+func (s *server) expire(now time.Time) {
+	for id, j := range s.jobs {
+		if j.Finished != nil && now.Sub(*j.Finished) > s.ttl {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+// checkRepoURL lässt nur http(s)-URLs mit Host zu – keine lokalen
+// Verzeichnisse, file:// oder ssh.
+func checkRepoURL(repo string) error {
+	if repo == "" {
+		return fmt.Errorf("repo fehlt")
+	}
+	u, err := url.Parse(repo)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("repo muss eine http(s)-URL sein: %q", repo)
+	}
+	return nil
+}
+
+// resolveUnder löst den relativen Pfad p unter dir auf; absolute Pfade und
+// ".." aus dir hinaus sind nicht erlaubt.
+func resolveUnder(dir, p string) (string, error) {
+	p = filepath.FromSlash(p)
+	if !filepath.IsLocal(p) {
+		return "", fmt.Errorf("osv muss ein Pfad unter --osv-dir sein: %q", p)
+	}
+	return filepath.Join(dir, p), nil
+}
+
+func (s *server) update(j *job, fn func(*job)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(j)
+}
+
+// run führt einen Job wie einen Repo-Durchlauf von "baa study" aus.
+func (s *server) run(j *job) (string, json.RawMessage, error) {
+	req := j.req
+	r := studyRepo{URL: req.Repo, Eco: req.Eco, OSV: req.OSV, Slug: req.Slug, Plat: req.Plat, Pkg: req.Pkg}
+	if r.Slug == "" {
+		r.Slug = githubSlug(r.URL)
+	}
+	cfg := s.cfg
+	cfg.commits, cfg.changes, cfg.days = req.Window.Commits, req.Window.Changes, req.Window.Days
+	if cfg.commits <= 0 && cfg.changes <= 0 && cfg.days <= 0 {
+		cfg.days = 365
+	}
+
+	s.cloneMu.Lock()
+	checkout, commit, release, err := cloneOnce(cfg.clones, r.URL)
+	s.cloneMu.Unlock()
+	if err != nil {
+		return "", nil, fmt.Errorf("clone: %w", err)
+	}
+	defer release()
+	t := tools[j.Metric]
+	flags, pos, err := toolArgs(cfg, t, r, checkout)
+	if err != nil {
+		return commit, nil, err
+	}
+	raw, err := runTool(cfg, t, j.ID, flags, pos)
+	return commit, raw, err
+}
+
+func newJobID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func httpError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
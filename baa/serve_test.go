@@ -0,0 +1,97 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestHandleAnalyzeRejects(t *testing.T) {
+	s := &server{queue: make(chan *job, 1), osvDir: t.TempDir(), ttl: time.Hour, jobs: map[string]*job{}}
+	for _, body := range []string{
+		`{"repo": ""}`,
+		`{"repo": "/srv/git/private"}`,
+		`{"repo": "file:///etc"}`,
+		`{"repo": "ssh://git@example.com/x.git"}`,
+		`{"repo": "https://example.com/x.git", "osv": "/etc/passwd"}`,
+		`{"repo": "https://example.com/x.git", "osv": "../secret.json"}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/analyze/ttf", strings.NewReader(body))
+		req.SetPathValue("metric", "ttf")
+		w := httptest.NewRecorder()
+		s.handleAnalyze(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: Status %d, erwartet 400", body, w.Code)
+		}
+	}
+	if len(s.jobs) != 0 {
+		t.Errorf("%d Jobs angelegt, erwartet keine", len(s.jobs))
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/analyze/ttf", strings.NewReader(`{"repo": "https://example.com/x.git", "osv": "x/osv.json"}`))
+	req.SetPathValue("metric", "ttf")
+	w := httptest.NewRecorder()
+	s.handleAnalyze(w, req)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Status %d, erwartet 202: %s", w.Code, w.Body)
+	}
+	j := <-s.queue
+	if want := filepath.Join(s.osvDir, "x", "osv.json"); j.req.OSV != want {
+		t.Errorf("osv = %q, erwartet %q", j.req.OSV, want)
+	}
+}
+
+func TestExpire(t *testing.T) {
+	now := time.Now().UTC()
+	old, recent := now.Add(-2*time.Hour), now.Add(-time.Minute)
+	s := &server{ttl: time.Hour, jobs: map[string]*job{
+		"alt":     {Status: "done", Finished: &old},
+		"neu":     {Status: "failed", Finished: &recent},
+		"laufend": {Status: "running"},
+	}}
+	s.expire(now)
+	if _, ok := s.jobs["alt"]; ok || len(s.jobs) != 2 {
+		t.Errorf("Jobs nach expire: %v", s.jobs)
+	}
+}
+
+// TestRunSameBasename lässt zwei Jobs für gleichnamige Repos laufen; jeder
+// muss seinen eigenen Checkout bekommen.
+func TestRunSameBasename(t *testing.T) {
+	src := t.TempDir()
+	heads := map[string]string{}
+	for _, org := range []string{"org-a", "org-b"} {
+		dir := filepath.Join(src, org, "docs")
+		r, err := git.PlainInit(dir, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "README"), []byte(org), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		wt, _ := r.Worktree()
+		if _, err := wt.Add("README"); err != nil {
+			t.Fatal(err)
+		}
+		h, err := wt.Commit(org, &git.CommitOptions{Author: &object.Signature{Name: "t", When: time.Now()}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		heads[dir] = h.String()
+	}
+	s := &server{cfg: studyConfig{clones: t.TempDir()}}
+	for dir, want := range heads {
+		// ttf ohne osv scheitert erst nach dem Klonen
+		commit, _, _ := s.run(&job{Metric: "ttf", req: analyzeRequest{Repo: dir}})
+		if commit != want {
+			t.Errorf("%s: Commit %s, erwartet %s", dir, commit, want)
+		}
+	}
+}
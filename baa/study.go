@@ -0,0 +1,387 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"baa_fs25/shared/anon"
+	"baa_fs25/shared/clones"
+	"baa_fs25/shared/exitcode"
+	"baa_fs25/shared/logging"
+	"baa_fs25/shared/provenance"
+	"baa_fs25/shared/report"
+	"baa_fs25/shared/schema"
+	git "github.com/go-git/go-git/v5"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+// studyRepo ist eine Zeile aus repos.csv.
+//
+// Spalten (Header-Zeile erforderlich, Reihenfolge egal):
+//
+//	url   Git-URL (Pflicht)
+//	eco   npm | go | py          – für mttu und libyears
+//	osv   Pfad zur OSV-JSON      – für ttf
+//	slug  owner/repo             – für ttf (Default: aus GitHub-URL)
+//	plat  libraries.io-Plattform – für ttf (optional)
+//	pkg   Paketname              – für ttf (optional)
+type studyRepo struct {
+	URL, Eco, OSV, Slug, Plat, Pkg string
+}
+
+// studySchemaVersion ist die Version von studyRecord; "baa merge" lehnt
+// neuere Versionen ab.
+const studySchemaVersion = 1
+
+// studyRecord ist das kombinierte JSON pro Repo.
+type studyRecord struct {
+	Schema        string                     `json:"schema"`
+	SchemaVersion int                        `json:"schema_version"`
+	Repo          string                     `json:"repo"`
+	Commit        string                     `json:"commit"`
+	AnalyzedAt    time.Time                  `json:"analyzed_at"`
+	Metrics       map[string]json.RawMessage `json:"metrics"`
+	Errors        map[string]string          `json:"errors,omitempty"`
+	// Provenance beschreibt den Lauf von "baa study"; die Metriken tragen
+	// zusätzlich die Angaben ihres Tools.
+	Provenance *provenance.Info `json:"provenance,omitempty"`
+}
+
+type studyConfig struct {
+	root, clones, out string
+	commits, changes  int
+	days              int
+}
+
+func runStudy(args []string) {
+	fs, lo := newFlagSet("study")
+	reposCSV := fs.String("repos", "", "CSV-Datei mit Repos (Spalten: url,eco,osv,slug,plat,pkg)")
+	metrics := fs.String("metrics", "mttu,ttf,libyears", "Komma-separierte Metriken: mttu,ttf,libyears")
+	var cfg studyConfig
+	fs.StringVar(&cfg.out, "out", "results", "Ausgabeverzeichnis für die JSON-Records")
+	fs.StringVar(&cfg.clones, "clones", "clones", "Verzeichnis für die Checkouts")
+	fs.StringVar(&cfg.root, "tools", ".", "Wurzel dieses Repos (dort liegen die Tool-Module)")
+	fs.IntVar(&cfg.commits, "commits", -1, "mttu: genau N jüngste Commits")
+	fs.IntVar(&cfg.changes, "changes", -1, "mttu: Stopp nach N Datei-Änderungen")
+	fs.IntVar(&cfg.days, "days", -1, "mttu: Historie X Tage zurück (Default 365, falls nichts gesetzt)")
+	cloneJobs := fs.Int("clone-jobs", 4, "so viele Repos parallel vorausklonen (nur mit --mirror-dir)")
+	parseFlags(fs, lo, args)
+
+	if *reposCSV == "" {
+		logging.Usage("--repos fehlt")
+	}
+	if cfg.commits <= 0 && cfg.changes <= 0 && cfg.days <= 0 {
+		cfg.days = 365
+	}
+	var selected []tool
+	for _, m := range strings.Split(*metrics, ",") {
+		t, ok := tools[strings.TrimSpace(m)]
+		if !ok {
+			logging.Usage("unbekannte Metrik – erlaubt: mttu,ttf,libyears", "metric", m)
+		}
+		selected = append(selected, t)
+	}
+	repos, err := readRepos(*reposCSV)
+	if err != nil {
+		logging.Fatal("repos.csv nicht lesbar", "file", *reposCSV, "err", err)
+	}
+	for _, dir := range []*string{&cfg.out, &cfg.clones, &cfg.root} {
+		if *dir, err = filepath.Abs(*dir); err != nil {
+			logging.Fatal("Pfad ungültig", "dir", *dir, "err", err)
+		}
+	}
+	for _, dir := range []string{cfg.out, cfg.clones} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			logging.Fatal("Verzeichnis nicht anlegbar", "dir", dir, "err", err)
+		}
+	}
+
+	// Die Mirrors der nächsten Repos werden geklont, während die Tools
+	// laufen; ein Fehler beim Vorausklonen zeigt sich erneut in studyOne.
+	urls := make([]string, len(repos))
+	for i, r := range repos {
+		urls[i] = r.URL
+	}
+	pre := clones.Prefetch(urls, *cloneJobs)
+	failed, missing := 0, 0
+	for _, r := range repos {
+		_ = pre.Wait(r.URL)
+		n, err := studyOne(cfg, r, selected)
+		if err != nil {
+			slog.Error("Repo fehlgeschlagen", "repo", r.URL, "err", err)
+			failed++
+			n = len(selected)
+		}
+		missing += n
+		pre.Done(r.URL)
+	}
+	exitcode.Skipped(missing, len(repos)*len(selected))
+	slog.Info("Studie abgeschlossen", "repos", len(repos), "failed", failed, "missing_metrics", missing, "out", cfg.out)
+}
+
+func readRepos(path string) ([]studyRepo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	cr := csv.NewReader(f)
+	cr.FieldsPerRecord = -1
+	cr.Comment = '#'
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	col := map[string]int{}
+	for i, h := range header {
+		col[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	if _, ok := col["url"]; !ok {
+		return nil, fmt.Errorf("Spalte url fehlt")
+	}
+	get := func(rec []string, name string) string {
+		if i, ok := col[name]; ok && i < len(rec) {
+			return strings.TrimSpace(rec[i])
+		}
+		return ""
+	}
+	var out []studyRepo
+	names := map[string]string{} // repoName → URL
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		r := studyRepo{
+			URL: get(rec, "url"), Eco: get(rec, "eco"), OSV: get(rec, "osv"),
+			Slug: get(rec, "slug"), Plat: get(rec, "plat"), Pkg: get(rec, "pkg"),
+		}
+		if r.URL == "" {
+			continue
+		}
+		if prev, ok := names[repoName(r.URL)]; ok {
+			return nil, fmt.Errorf("Repo doppelt: %s und %s", prev, r.URL)
+		}
+		names[repoName(r.URL)] = r.URL
+		if r.Slug == "" {
+			r.Slug = githubSlug(r.URL)
+		}
+		if r.OSV != "" {
+			if r.OSV, err = filepath.Abs(r.OSV); err != nil {
+				return nil, err
+			}
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+// githubSlug leitet owner/repo aus einer GitHub-URL ab ("" sonst).
+func githubSlug(url string) string {
+	u := strings.TrimSuffix(url, ".git")
+	i := strings.Index(u, "github.com")
+	if i < 0 {
+		return ""
+	}
+	parts := strings.Split(strings.Trim(u[i+len("github.com"):], ":/"), "/")
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[0] + "/" + parts[1]
+}
+
+// repoName benennt Checkout und Ergebnisdateien eines Repos: Repo-Name plus
+// Hash der normalisierten URL (s. clones.Name), damit Forks und gleichnamige
+// Repos verschiedener Organisationen sich nicht überschreiben.
+func repoName(url string) string {
+	return clones.Name(url)
+}
+
+// cloneOnce klont url nach dir/<name>, falls noch nicht vorhanden, und
+// liefert den Checkout-Pfad sowie den HEAD-Commit. Ein vorhandener Checkout
+// muss zu url gehören. Mit --mirror-dir entsteht der Checkout aus dem
+// gemeinsamen Bare-Mirror; release schützt ihn bis zum Ende der Analyse vor
+// Verdrängung durch --clone-quota.
+func cloneOnce(dir, url string) (string, string, func(), error) {
+	path := filepath.Join(dir, repoName(url))
+	release := func() {}
+	if clones.Enabled() {
+		var err error
+		if release, err = clones.Checkout(url, path); err != nil {
+			return "", "", nil, err
+		}
+	}
+	var auth *githttp.BasicAuth
+	if token := os.Getenv("GH_TOKEN"); token != "" {
+		auth = &githttp.BasicAuth{Username: "token", Password: token}
+	}
+	r, err := git.PlainOpen(path)
+	switch err {
+	case git.ErrRepositoryNotExists:
+		slog.Info("Klonen", "url", url, "dir", path)
+		r, err = git.PlainClone(path, false, &git.CloneOptions{URL: url, Auth: auth})
+	case nil:
+		err = checkOrigin(r, url)
+	}
+	if err != nil {
+		release()
+		return "", "", nil, err
+	}
+	head, err := r.Head()
+	if err != nil {
+		release()
+		return "", "", nil, err
+	}
+	return path, head.Hash().String(), release, nil
+}
+
+// checkOrigin prüft, ob der vorhandene Checkout r ein Klon von url ist.
+func checkOrigin(r *git.Repository, url string) error {
+	rem, err := r.Remote("origin")
+	if err != nil {
+		return fmt.Errorf("Checkout ohne origin: %w", err)
+	}
+	if urls := rem.Config().URLs; len(urls) == 0 || repoName(urls[0]) != repoName(url) {
+		return fmt.Errorf("Checkout-Verzeichnis gehört zu %v, nicht zu %s", urls, url)
+	}
+	return nil
+}
+
+// studyOne wertet ein Repo aus und liefert die Zahl der Metriken, die im
+// Record fehlen (Fehler in rec.Errors).
+func studyOne(cfg studyConfig, r studyRepo, selected []tool) (int, error) {
+	checkout, commit, release, err := cloneOnce(cfg.clones, r.URL)
+	if err != nil {
+		return 0, fmt.Errorf("clone: %w", err)
+	}
+	defer release()
+	rec := studyRecord{
+		Schema:        schema.ID("baa-study"),
+		SchemaVersion: studySchemaVersion,
+		Repo:          anon.Repo(r.URL),
+		Commit:        anon.Commit(commit),
+		AnalyzedAt:    time.Now().UTC(),
+		Metrics:       map[string]json.RawMessage{},
+		Errors:        map[string]string{},
+	}
+	name := repoName(r.URL)
+	if anon.Enabled() {
+		name = anon.Repo(r.URL) // auch der Dateiname verrät das Repo
+	}
+	for _, t := range selected {
+		flags, pos, err := toolArgs(cfg, t, r, checkout)
+		if err != nil {
+			slog.Warn("Metrik übersprungen", "repo", r.URL, "metric", t.name, "err", err)
+			rec.Errors[t.name] = err.Error()
+			continue
+		}
+		raw, err := runTool(cfg, t, name, flags, pos)
+		if err != nil {
+			slog.Error("Metrik fehlgeschlagen", "repo", r.URL, "metric", t.name, "err", err)
+			rec.Errors[t.name] = err.Error()
+			continue
+		}
+		rec.Metrics[t.name] = raw
+	}
+	rec.Provenance = provenance.Get()
+	return len(rec.Errors), report.WriteJSON(filepath.Join(cfg.out, name+".json"), rec)
+}
+
+// toolArgs baut Flags und Positionsargumente für ein Tool. "--out" wird von
+// runTool zwischen beiden eingefügt, da die Flag-Auswertung beim ersten
+// Positionsargument endet.
+func toolArgs(cfg studyConfig, t tool, r studyRepo, checkout string) (flags, pos []string, err error) {
+	switch t.name {
+	case "mttu":
+		if r.Eco == "" {
+			return nil, nil, fmt.Errorf("Spalte eco fehlt")
+		}
+		flags = []string{"--eco", r.Eco}
+		switch {
+		case cfg.commits > 0:
+			flags = append(flags, "--commits", strconv.Itoa(cfg.commits))
+		case cfg.changes > 0:
+			flags = append(flags, "--changes", strconv.Itoa(cfg.changes))
+		default:
+			flags = append(flags, "--days", strconv.Itoa(cfg.days))
+		}
+		return flags, []string{checkout}, nil
+	case "ttf":
+		if r.OSV == "" || r.Slug == "" {
+			return nil, nil, fmt.Errorf("Spalten osv und slug benötigt")
+		}
+		flags = []string{"-json", r.OSV, "-repo", r.Slug}
+		if r.Plat != "" {
+			flags = append(flags, "-plat", r.Plat)
+		}
+		if r.Pkg != "" {
+			flags = append(flags, "-pkg", r.Pkg)
+		}
+		return flags, nil, nil
+	case "libyears":
+		switch r.Eco {
+		case "go":
+			return []string{"go"}, []string{checkout}, nil
+		case "npm":
+			return []string{"npm"}, []string{filepath.Join(checkout, "package.json")}, nil
+		case "py", "python":
+			return []string{"py"}, []string{filepath.Join(checkout, "requirements.txt")}, nil
+		}
+		return nil, nil, fmt.Errorf("eco %q nicht unterstützt", r.Eco)
+	}
+	return nil, nil, fmt.Errorf("unbekanntes Tool %s", t.name)
+}
+
+// runTool startet das Tool, leitet stdout/stderr in <out>/<name>.<tool>.log
+// und liefert das geschriebene JSON zurück.
+func runTool(cfg studyConfig, t tool, name string, flags, pos []string) (json.RawMessage, error) {
+	jsonPath := filepath.Join(cfg.out, name+"."+t.name+".tmp.json")
+	defer os.Remove(jsonPath)
+	logPath := filepath.Join(cfg.out, name+"."+t.name+".log")
+	logf, err := os.Create(logPath)
+	if err != nil {
+		return nil, err
+	}
+	defer logf.Close()
+
+	args := append(append(flags, "--out", jsonPath), pos...)
+	cmd := t.command(cfg.root, args...)
+	cmd.Stdout, cmd.Stderr = logf, logf
+	slog.Info("Starte Tool", "tool", t.name, "args", strings.Join(args, " "))
+	if err := cmd.Run(); err != nil {
+		// Exit-Code 3 ist ein Teilergebnis, das JSON ist geschrieben. "go run"
+		// meldet jeden Fehler als 1; ein geschriebenes JSON gilt dort ebenso
+		// als Teilergebnis.
+		var ee *exec.ExitError
+		if !errors.As(err, &ee) {
+			return nil, fmt.Errorf("%s: %w (siehe %s)", t.name, err, logPath)
+		}
+		partial := ee.ExitCode() == exitcode.Partial
+		if !partial && cmd.Dir != "" {
+			_, statErr := os.Stat(jsonPath)
+			partial = statErr == nil
+		}
+		if !partial {
+			return nil, fmt.Errorf("%s: %w (siehe %s)", t.name, err, logPath)
+		}
+		slog.Warn("Teilergebnis", "tool", t.name, "exit", ee.ExitCode(), "log", logPath)
+	}
+	raw, err := os.ReadFile(jsonPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s lieferte kein JSON: %w", t.name, err)
+	}
+	return raw, nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+)
+
+func TestRepoName(t *testing.T) {
+	a, b := repoName("https://github.com/org-a/docs.git"), repoName("https://github.com/org-b/docs")
+	if a == b {
+		t.Errorf("org-a/docs und org-b/docs ergeben denselben Namen %s", a)
+	}
+	if !strings.HasPrefix(a, "docs-") {
+		t.Errorf("Name %s, erwartet docs-<hash>", a)
+	}
+	if ssh := repoName("git@github.com:org-a/docs.git"); ssh != a {
+		t.Errorf("ssh-URL ergibt %s, erwartet %s", ssh, a)
+	}
+}
+
+func TestReadReposDuplicate(t *testing.T) {
+	dir := t.TempDir()
+	write := func(csv string) string {
+		p := filepath.Join(dir, "repos.csv")
+		if err := os.WriteFile(p, []byte(csv), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	repos, err := readRepos(write("url,eco\nhttps://github.com/org-a/docs,npm\nhttps://github.com/org-b/docs,npm\n"))
+	if err != nil || len(repos) != 2 {
+		t.Fatalf("%d Repos, %v; erwartet 2", len(repos), err)
+	}
+	if _, err := readRepos(write("url\nhttps://github.com/org-a/docs\ngit@github.com:org-a/docs.git\n")); err == nil {
+		t.Error("doppeltes Repo nicht erkannt")
+	}
+}
+
+func TestCloneOnceForeignCheckout(t *testing.T) {
+	dir := t.TempDir()
+	url := "https://example.com/org-b/docs.git"
+	r, err := git.PlainInit(filepath.Join(dir, repoName(url)), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{"https://example.com/org-a/docs.git"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := cloneOnce(dir, url); err == nil || !strings.Contains(err.Error(), "gehört zu") {
+		t.Errorf("fremder Checkout wiederverwendet: %v", err)
+	}
+}
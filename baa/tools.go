@@ -0,0 +1,32 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// tool beschreibt eines der Analyse-Tools im Repo.
+type tool struct {
+	name string // Metrik-Name (mttu | ttf | libyears)
+	dir  string // Modul-Verzeichnis relativ zur Repo-Wurzel
+	env  string // Env-Variable mit Pfad zu einem vorgebauten Binary
+}
+
+var tools = map[string]tool{
+	"mttu":     {name: "mttu", dir: "M42_mean_time_to_update", env: "BAA_MTTU_BIN"},
+	"ttf":      {name: "ttf", dir: "M17_time_to_fix", env: "BAA_TTF_BIN"},
+	"libyears": {name: "libyears", dir: "M41_libyears", env: "BAA_LIBYEARS_BIN"},
+}
+
+// command baut den Aufruf des Tools. Ist die Env-Variable gesetzt, wird das
+// Binary direkt verwendet, sonst "go run ." im Modul-Verzeichnis unter root.
+// Pfad-Argumente müssen absolut sein, da das Arbeitsverzeichnis wechselt.
+func (t tool) command(root string, args ...string) *exec.Cmd {
+	if bin := os.Getenv(t.env); bin != "" {
+		return exec.Command(bin, args...)
+	}
+	cmd := exec.Command("go", append([]string{"run", "."}, args...)...)
+	cmd.Dir = filepath.Join(root, t.dir)
+	return cmd
+}
@@ -0,0 +1,122 @@
+// Package anon pseudonymisiert Repo-Identitäten in den Ausgaben der Tools,
+// damit der Datensatz mit der Arbeit veröffentlicht werden kann.
+//
+// Alle Tools registrieren dieselben Flags:
+//
+//	--anonymize       Repo-URLs, Commit-Hashes und lokale Pfade hashen (Default: $BAA_ANONYMIZE)
+//	--anonymize-salt  geheimer Schlüssel des HMAC (Default: $BAA_ANONYMIZE_SALT)
+//
+// Gleiche Eingabe und gleicher Salt ergeben in allen Tools denselben Wert,
+// Datensätze lassen sich also weiterhin verknüpfen. Ohne den Salt sind die
+// Werte nicht per Wörterbuch (z. B. alle GitHub-Repos) umkehrbar.
+// Autorenangaben schreibt keines der Tools; Paketnamen und purls bleiben
+// lesbar, da sie öffentliche Registries betreffen.
+package anon
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Prefix kennzeichnet pseudonymisierte Werte; sie werden nicht erneut
+// gehasht (z. B. bei "baa merge" über bereits anonymisierte Records).
+const Prefix = "anon-"
+
+// Options hält die Werte der Flags.
+type Options struct {
+	Enabled bool
+	Salt    string
+}
+
+// key ist der HMAC-Schlüssel, nil solange nicht anonymisiert wird.
+var key []byte
+
+// Register fügt --anonymize und --anonymize-salt zum FlagSet hinzu.
+func Register(fs *flag.FlagSet) *Options {
+	o := &Options{}
+	on, _ := strconv.ParseBool(os.Getenv("BAA_ANONYMIZE"))
+	fs.BoolVar(&o.Enabled, "anonymize", on, "Repo-URLs, Commit-Hashes und lokale Pfade in allen Ausgaben per HMAC hashen")
+	fs.StringVar(&o.Salt, "anonymize-salt", os.Getenv("BAA_ANONYMIZE_SALT"), "Schlüssel für --anonymize (geheim halten, für alle Läufe gleich)")
+	return o
+}
+
+// Setup aktiviert die Pseudonymisierung; ein Salt ist Pflicht.
+func (o *Options) Setup() error {
+	if !o.Enabled {
+		key = nil
+		return nil
+	}
+	if o.Salt == "" {
+		return errors.New("--anonymize braucht --anonymize-salt bzw. $BAA_ANONYMIZE_SALT")
+	}
+	key = []byte(o.Salt)
+	return nil
+}
+
+// Export übernimmt die Flag-Werte in die Env-Variablen, damit Subprozesse
+// ebenfalls anonymisieren.
+func (o *Options) Export() {
+	if o.Enabled {
+		os.Setenv("BAA_ANONYMIZE", "1")
+		os.Setenv("BAA_ANONYMIZE_SALT", o.Salt)
+	}
+}
+
+// Enabled meldet, ob anonymisiert wird.
+func Enabled() bool { return key != nil }
+
+// ID liefert das Pseudonym von s bzw. s selbst, wenn nicht anonymisiert wird.
+// Leere und bereits pseudonymisierte Werte bleiben unverändert.
+func ID(s string) string {
+	if key == nil || s == "" || strings.HasPrefix(s, Prefix) {
+		return s
+	}
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(s))
+	return Prefix + hex.EncodeToString(m.Sum(nil))[:16]
+}
+
+// Repo pseudonymisiert eine Repo-URL oder einen GitHub-Slug. Schema,
+// Zugangsdaten, ".git" und Groß-/Kleinschreibung werden vorher entfernt,
+// damit "https://github.com/o/r.git", "git@github.com:o/r" und "o/r" (TTF)
+// dasselbe Pseudonym erhalten.
+func Repo(s string) string {
+	if key == nil || s == "" || strings.HasPrefix(s, Prefix) {
+		return s
+	}
+	return ID(normalizeRepo(s))
+}
+
+func normalizeRepo(s string) string {
+	r := strings.ToLower(strings.TrimSpace(s))
+	if _, rest, ok := strings.Cut(r, "://"); ok {
+		r = rest
+	} else if strings.HasPrefix(r, "git@") {
+		r = strings.Replace(strings.TrimPrefix(r, "git@"), ":", "/", 1)
+	} else if strings.Count(r, "/") == 1 && !strings.HasPrefix(r, "/") && !strings.HasPrefix(r, ".") {
+		r = "github.com/" + r // owner/repo
+	}
+	if _, host, ok := strings.Cut(r, "@"); ok && !strings.Contains(r[:strings.Index(r, "@")], "/") {
+		r = host // user:token@host/…
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(r, "/"), ".git")
+}
+
+// Commit pseudonymisiert einen Commit-Hash; gekürzte und volle Hashes
+// ergeben verschiedene Werte.
+func Commit(s string) string { return ID(s) }
+
+// Path pseudonymisiert lokale Pfade (Klon-Verzeichnisse, Manifeste), die den
+// Repo-Namen enthalten können; bloße Dateinamen bleiben lesbar.
+func Path(s string) string {
+	if key == nil || !strings.ContainsAny(s, `/\`) {
+		return s
+	}
+	return ID(s)
+}
@@ -0,0 +1,394 @@
+// Package clones verwaltet die Git-Klone für Läufe über viele Repos: Jedes
+// Repo wird einmal als Bare-Mirror abgelegt, den alle Tools wiederverwenden;
+// Checkouts teilen sich dessen Objekte (alternates). Eine Obergrenze für den
+// Platz verdrängt die am längsten unbenutzten Mirrors samt ihrer Checkouts.
+//
+// Alle Tools registrieren dieselben Flags:
+//
+//	--mirror-dir  Verzeichnis der Mirrors (Default: $BAA_MIRROR_DIR; leer = aus)
+//	--clone-quota Obergrenze für Mirrors und Checkouts, z. B. 50G
+//	              (Default: $BAA_CLONE_QUOTA; leer = unbegrenzt)
+//
+// "baa" aktiviert die Mirrors standardmäßig und gibt die Werte an seine
+// Subprozesse weiter (s. Export). Verdrängt werden nur Mirrors, die der
+// eigene Prozess gerade nicht benutzt; parallele Läufe mit demselben
+// --mirror-dir sollten daher ein großzügiges Quota haben.
+package clones
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+// Options hält die Werte der Klon-Flags.
+type Options struct {
+	Dir   string
+	Quota string
+}
+
+// Register fügt --mirror-dir und --clone-quota zum FlagSet hinzu. dir ist
+// der Default für --mirror-dir, falls $BAA_MIRROR_DIR nicht gesetzt ist.
+func Register(fs *flag.FlagSet, dir string) *Options {
+	o := &Options{}
+	if env := os.Getenv("BAA_MIRROR_DIR"); env != "" {
+		dir = env
+	}
+	fs.StringVar(&o.Dir, "mirror-dir", dir, "Bare-Mirrors hier ablegen und über Tools hinweg wiederverwenden (leer = direkt klonen)")
+	fs.StringVar(&o.Quota, "clone-quota", os.Getenv("BAA_CLONE_QUOTA"), "Obergrenze für Mirrors und Checkouts, z. B. 50G; verdrängt die am längsten unbenutzten (leer = unbegrenzt)")
+	return o
+}
+
+// Setup prüft das Quota und legt das Mirror-Verzeichnis an. o.Dir wird
+// absolut, damit Subprozesse mit anderem Arbeitsverzeichnis dieselben
+// Mirrors finden (s. Export).
+func (o *Options) Setup() error {
+	q, err := ParseSize(o.Quota)
+	if err != nil {
+		return fmt.Errorf("--clone-quota: %w", err)
+	}
+	if o.Dir == "" {
+		return nil
+	}
+	d, err := filepath.Abs(o.Dir)
+	if err == nil {
+		err = os.MkdirAll(d, 0o755)
+	}
+	if err != nil {
+		return fmt.Errorf("--mirror-dir: %w", err)
+	}
+	o.Dir = d
+	mu.Lock()
+	root, quota = d, q
+	mu.Unlock()
+	return nil
+}
+
+// Export übernimmt die Flag-Werte in die Env-Variablen, damit Subprozesse
+// dieselben Mirrors verwenden.
+func (o *Options) Export() {
+	if o.Dir != "" {
+		os.Setenv("BAA_MIRROR_DIR", o.Dir)
+	}
+	if o.Quota != "" {
+		os.Setenv("BAA_CLONE_QUOTA", o.Quota)
+	}
+}
+
+// ParseSize liest Größen wie "500M", "50G", "1.5T" oder Bytes; "" und "0"
+// bedeuten unbegrenzt.
+func ParseSize(size string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(size))
+	if s == "" {
+		return 0, nil
+	}
+	mult := int64(1)
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "B"), "I") // 50GB, 50GiB
+	for i, unit := range "KMGT" {
+		if strings.HasSuffix(s, string(unit)) {
+			mult = int64(1) << (10 * (i + 1))
+			s = strings.TrimSuffix(s, string(unit))
+			break
+		}
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("ungültige Größe %q (z. B. 500M, 50G)", size)
+	}
+	return int64(f * float64(mult)), nil
+}
+
+// Marker-Dateien im Mirror: Zeitpunkt der letzten Nutzung (mtime) und die
+// Checkouts, die per alternates auf seine Objekte zeigen.
+const (
+	usedFile      = "baa-last-used"
+	checkoutsFile = "baa-checkouts"
+)
+
+var (
+	mu      sync.Mutex
+	root    string // "" = Mirrors aus
+	quota   int64  // 0 = unbegrenzt
+	pins    = map[string]int{}
+	fetched = map[string]*fetchState{}
+	sizes   = map[string]int64{} // Mirror-Verzeichnis → Größe inkl. Checkouts
+)
+
+type fetchState struct {
+	once sync.Once
+	err  error
+}
+
+// Enabled meldet, ob --mirror-dir gesetzt ist.
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return root != ""
+}
+
+// Mirror liefert den Bare-Mirror von url: beim ersten Aufruf im Prozess
+// geklont bzw. aktualisiert, danach unverändert. Bis release aufgerufen wird,
+// ist der Mirror vor Verdrängung geschützt.
+func Mirror(url string) (dir string, release func(), err error) {
+	mu.Lock()
+	if root == "" {
+		mu.Unlock()
+		return "", nil, errors.New("clones: --mirror-dir nicht gesetzt")
+	}
+	dir = filepath.Join(root, mirrorName(url))
+	pins[dir]++
+	st := fetched[dir]
+	if st == nil {
+		st = &fetchState{}
+		fetched[dir] = st
+	}
+	mu.Unlock()
+	release = sync.OnceFunc(func() {
+		mu.Lock()
+		pins[dir]--
+		mu.Unlock()
+	})
+
+	st.once.Do(func() { st.err = update(url, dir) })
+	if st.err != nil {
+		release()
+		return "", nil, st.err
+	}
+	touch(dir)
+	return dir, release, nil
+}
+
+// Checkout legt unter dst einen Checkout von url an, der die Objekte des
+// Mirrors mitbenutzt; ein vorhandener dst bleibt unverändert. Wird der
+// Mirror verdrängt, wird dst mitgelöscht – release schützt beide.
+func Checkout(url, dst string) (release func(), err error) {
+	dir, release, err := Mirror(url)
+	if err != nil {
+		return nil, err
+	}
+	if dst, err = filepath.Abs(dst); err != nil {
+		release()
+		return nil, err
+	}
+	if _, err := git.PlainOpen(dst); err == git.ErrRepositoryNotExists {
+		slog.Info("Checkout aus Mirror", "mirror", dir, "dir", dst)
+		r, err := git.PlainClone(dst, false, &git.CloneOptions{URL: dir, Shared: true})
+		if err == nil {
+			// origin zeigt auf das Original, nicht auf den Mirror
+			err = r.DeleteRemote("origin")
+		}
+		if err == nil {
+			_, err = r.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{url}})
+		}
+		if err != nil {
+			os.RemoveAll(dst)
+			release()
+			return nil, err
+		}
+	} else if err != nil {
+		release()
+		return nil, err
+	}
+	if err := addCheckout(dir, dst); err != nil {
+		slog.Warn("Checkout nicht im Mirror vermerkt", "mirror", dir, "err", err)
+	}
+	mu.Lock()
+	delete(sizes, dir)
+	mu.Unlock()
+	enforce()
+	return release, nil
+}
+
+// update klont url als Mirror nach dir bzw. holt alle Refs neu. Neue Klone
+// entstehen in einem Temp-Verzeichnis, damit abgebrochene Läufe keine
+// halben Mirrors hinterlassen.
+func update(url, dir string) error {
+	var auth *githttp.BasicAuth
+	if token := os.Getenv("GH_TOKEN"); token != "" {
+		auth = &githttp.BasicAuth{Username: "token", Password: token}
+	}
+	if r, err := git.PlainOpen(dir); err == nil {
+		slog.Info("Mirror aktualisieren", "url", url, "dir", dir)
+		err = r.Fetch(&git.FetchOptions{
+			RefSpecs: []config.RefSpec{"+refs/*:refs/*"},
+			Auth:     auth,
+			Force:    true,
+		})
+		if err != nil && err != git.NoErrAlreadyUpToDate {
+			return fmt.Errorf("fetch %s: %w", dir, err)
+		}
+	} else {
+		slog.Info("Mirror klonen", "url", url, "dir", dir)
+		tmp, err := os.MkdirTemp(filepath.Dir(dir), ".tmp-")
+		if err != nil {
+			return err
+		}
+		_, err = git.PlainClone(tmp, true, &git.CloneOptions{URL: url, Auth: auth, Mirror: true})
+		if err == nil {
+			err = os.Rename(tmp, dir)
+		}
+		if err != nil {
+			os.RemoveAll(tmp)
+			return err
+		}
+	}
+	mu.Lock()
+	delete(sizes, dir)
+	mu.Unlock()
+	touch(dir)
+	enforce()
+	return nil
+}
+
+// mirrorName ist der Verzeichnisname des Mirrors.
+func mirrorName(url string) string {
+	return Name(url) + ".git"
+}
+
+// Name ist ein eindeutiger, lesbarer Name für url: Repo-Name plus Hash der
+// normalisierten URL. https- und ssh-URLs desselben Repos ergeben denselben
+// Namen, Forks und gleichnamige Repos verschiedener Organisationen nicht.
+func Name(url string) string {
+	n := normalize(url)
+	sum := sha256.Sum256([]byte(n))
+	return filepath.Base(n) + "-" + hex.EncodeToString(sum[:])[:12]
+}
+
+func normalize(url string) string {
+	u := strings.ToLower(strings.TrimSpace(url))
+	if _, rest, ok := strings.Cut(u, "://"); ok {
+		u = rest
+	} else if strings.HasPrefix(u, "git@") {
+		u = strings.Replace(strings.TrimPrefix(u, "git@"), ":", "/", 1)
+	}
+	if i := strings.Index(u, "@"); i >= 0 && !strings.Contains(u[:i], "/") {
+		u = u[i+1:] // user:token@host/…
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(u, "/"), ".git")
+}
+
+func touch(dir string) {
+	now := time.Now()
+	p := filepath.Join(dir, usedFile)
+	if err := os.Chtimes(p, now, now); err != nil {
+		_ = os.WriteFile(p, nil, 0o644)
+	}
+}
+
+func addCheckout(dir, dst string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	list := checkouts(dir)
+	if slices.Contains(list, dst) {
+		return nil
+	}
+	f, err := os.OpenFile(filepath.Join(dir, checkoutsFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = fmt.Fprintln(f, dst)
+	return err
+}
+
+func checkouts(dir string) []string {
+	b, err := os.ReadFile(filepath.Join(dir, checkoutsFile))
+	if err != nil {
+		return nil
+	}
+	return strings.Fields(string(b))
+}
+
+// enforce verdrängt die am längsten unbenutzten, nicht gepinnten Mirrors
+// samt Checkouts, bis die Summe unter dem Quota liegt.
+func enforce() {
+	mu.Lock()
+	defer mu.Unlock()
+	if quota <= 0 {
+		return
+	}
+	type entry struct {
+		dir  string
+		used time.Time
+		size int64
+	}
+	ents, err := os.ReadDir(root)
+	if err != nil {
+		slog.Warn("Mirror-Verzeichnis nicht lesbar", "dir", root, "err", err)
+		return
+	}
+	var list []entry
+	var total int64
+	for _, e := range ents {
+		if !e.IsDir() || !strings.HasSuffix(e.Name(), ".git") {
+			continue
+		}
+		dir := filepath.Join(root, e.Name())
+		en := entry{dir: dir}
+		if fi, err := os.Stat(filepath.Join(dir, usedFile)); err == nil {
+			en.used = fi.ModTime()
+		}
+		size, ok := sizes[dir]
+		if !ok {
+			size = dirSize(dir)
+			for _, c := range checkouts(dir) {
+				size += dirSize(c)
+			}
+			sizes[dir] = size
+		}
+		en.size = size
+		total += size
+		list = append(list, en)
+	}
+	slices.SortFunc(list, func(a, b entry) int { return a.used.Compare(b.used) })
+	for _, en := range list {
+		if total <= quota {
+			return
+		}
+		if pins[en.dir] > 0 {
+			continue
+		}
+		slog.Info("Mirror verdrängt (--clone-quota)", "dir", en.dir, "bytes", en.size)
+		for _, c := range checkouts(en.dir) {
+			os.RemoveAll(c)
+		}
+		if err := os.RemoveAll(en.dir); err != nil {
+			slog.Warn("Mirror nicht löschbar", "dir", en.dir, "err", err)
+			continue
+		}
+		delete(sizes, en.dir)
+		delete(fetched, en.dir)
+		total -= en.size
+	}
+	if total > quota {
+		slog.Warn("--clone-quota überschritten, alle Mirrors in Benutzung", "bytes", total, "quota", quota)
+	}
+}
+
+func dirSize(dir string) int64 {
+	var n int64
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err == nil && d.Type().IsRegular() {
+			if fi, err := d.Info(); err == nil {
+				n += fi.Size()
+			}
+		}
+		return nil
+	})
+	return n
+}
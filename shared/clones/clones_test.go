@@ -0,0 +1,37 @@
+package clones
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestExportAbs prüft, dass ein relatives --mirror-dir absolut an
+// Subprozesse geht, die in einem anderen Verzeichnis laufen.
+func TestExportAbs(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = os.Chdir(wd)
+		mu.Lock()
+		root = ""
+		mu.Unlock()
+	})
+	t.Setenv("BAA_MIRROR_DIR", "")
+
+	o := &Options{Dir: "mirrors"}
+	if err := o.Setup(); err != nil {
+		t.Fatal(err)
+	}
+	o.Export()
+	got := os.Getenv("BAA_MIRROR_DIR")
+	if fi, err := os.Stat(got); !filepath.IsAbs(got) || err != nil || !fi.IsDir() {
+		t.Errorf("BAA_MIRROR_DIR = %q, erwartet absoluten Pfad von %s/mirrors", got, dir)
+	}
+}
@@ -0,0 +1,90 @@
+package clones
+
+import (
+	"log/slog"
+	"sync"
+)
+
+// Prefetcher holt die Mirrors einer Repo-Liste mit jobs parallelen Klonen
+// voraus, während der Aufrufer die Repos der Reihe nach auswertet. Höchstens
+// jobs Mirrors sind dabei vorgeholt, aber noch nicht mit Done freigegeben,
+// damit das Quota nicht durch Vorratsklone gesprengt wird.
+type Prefetcher struct {
+	mu    sync.Mutex
+	items map[string]*prefetchItem
+	slots chan struct{}
+}
+
+type prefetchItem struct {
+	url     string
+	ready   chan struct{}
+	err     error
+	release func()
+}
+
+// Prefetch startet das Vorholen. Ohne --mirror-dir ist das Ergebnis nil;
+// Wait und Done sind dann wirkungslos.
+func Prefetch(urls []string, jobs int) *Prefetcher {
+	if !Enabled() {
+		return nil
+	}
+	p := &Prefetcher{items: map[string]*prefetchItem{}, slots: make(chan struct{}, max(jobs, 1))}
+	// Die Goroutine arbeitet auf order statt auf items, das Done parallel
+	// verkleinert.
+	var order []*prefetchItem
+	for _, u := range urls {
+		if p.items[u] == nil {
+			it := &prefetchItem{url: u, ready: make(chan struct{})}
+			p.items[u] = it
+			order = append(order, it)
+		}
+	}
+	go func() {
+		for _, it := range order {
+			p.slots <- struct{}{}
+			go func() {
+				_, rel, err := Mirror(it.url)
+				if err != nil {
+					slog.Warn("Vorausklonen fehlgeschlagen", "url", it.url, "err", err)
+				}
+				it.err, it.release = err, rel
+				close(it.ready)
+			}()
+		}
+	}()
+	return p
+}
+
+// Wait wartet, bis der Mirror von url bereitliegt.
+func (p *Prefetcher) Wait(url string) error {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	it := p.items[url]
+	p.mu.Unlock()
+	if it != nil {
+		<-it.ready
+		return it.err
+	}
+	return nil
+}
+
+// Done gibt den Mirror von url frei und lässt den nächsten Klon starten.
+func (p *Prefetcher) Done(url string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	it := p.items[url]
+	delete(p.items, url)
+	p.mu.Unlock()
+	if it == nil {
+		return
+	}
+	<-it.ready
+	if it.release != nil {
+		it.release()
+	}
+	<-p.slots
+}
@@ -0,0 +1,56 @@
+package clones
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// TestPrefetchDone gibt Mirrors mit Done frei, während die Goroutine von
+// Prefetch noch weitere startet (go test -race).
+func TestPrefetchDone(t *testing.T) {
+	src := t.TempDir()
+	var urls []string
+	for i := range 6 {
+		dir := filepath.Join(src, fmt.Sprintf("repo%d", i))
+		r, err := git.PlainInit(dir, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "README"), []byte(dir), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		wt, _ := r.Worktree()
+		if _, err := wt.Add("README"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := wt.Commit("init", &git.CommitOptions{Author: &object.Signature{Name: "t", When: time.Now()}}); err != nil {
+			t.Fatal(err)
+		}
+		urls = append(urls, dir)
+	}
+	mu.Lock()
+	root, quota = t.TempDir(), 0
+	mu.Unlock()
+	t.Cleanup(func() {
+		mu.Lock()
+		root = ""
+		mu.Unlock()
+	})
+
+	p := Prefetch(urls, 2)
+	for _, u := range urls {
+		if err := p.Wait(u); err != nil {
+			t.Errorf("%s: %v", u, err)
+		}
+		p.Done(u)
+	}
+	if len(p.items) != 0 {
+		t.Errorf("%d Einträge nach Done", len(p.items))
+	}
+}
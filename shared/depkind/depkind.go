@@ -0,0 +1,38 @@
+// Package depkind ordnet Requirements-Dateien einer Art zu (runtime, dev,
+// optional), damit mttu (req_scope) und libyears (--exclude-kind) dasselbe
+// Repo gleich aufteilen.
+package depkind
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Die Arten einer Dependency.
+const (
+	Runtime  = "runtime"
+	Dev      = "dev"
+	Optional = "optional"
+)
+
+var (
+	devRx      = regexp.MustCompile(`(?i)(^|[-_/.])(dev|develop|test|tests|testing|lint|docs?|ci|tox|local)([-_/.]|$)`)
+	optionalRx = regexp.MustCompile(`(?i)(^|[-_/.])(optional|extras?)([-_/.]|$)`)
+)
+
+// Requirements leitet die Art aus den Wörtern der Verzeichnis- und
+// Dateinamen von path ab (requirements-dev.txt, tests/requirements.txt,
+// requirements/tox.txt, requirements-optional.txt …); dev geht vor optional.
+// path ist relativ zur Repo-Wurzel, damit Verzeichnisse außerhalb des Repos
+// nicht mitzählen.
+func Requirements(path string) string {
+	p := filepath.ToSlash(strings.TrimSuffix(path, filepath.Ext(path)))
+	switch {
+	case devRx.MatchString(p):
+		return Dev
+	case optionalRx.MatchString(p):
+		return Optional
+	}
+	return Runtime
+}
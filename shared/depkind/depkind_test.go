@@ -0,0 +1,24 @@
+package depkind
+
+import "testing"
+
+func TestRequirements(t *testing.T) {
+	for path, want := range map[string]string{
+		"requirements.txt":           Runtime,
+		"requirements-dev.txt":       Dev,
+		"test-requirements.txt":      Dev,
+		"requirements_tox.txt":       Dev,
+		"requirements/local.txt":     Dev,
+		"tests/requirements.txt":     Dev,
+		"docs/requirements.in":       Dev,
+		"requirements-optional.txt":  Optional,
+		"requirements/extras.txt":    Optional,
+		"requirements-dev-extra.txt": Dev,
+		"latest-requirements.txt":    Runtime, // "test" nur als ganzes Wort
+		"environment.yml":            Runtime,
+	} {
+		if got := Requirements(path); got != want {
+			t.Errorf("Requirements(%q) = %s, erwartet %s", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Package exitcode legt die Exit-Codes fest, mit denen alle Tools und "baa"
+// enden, damit CI und Pipelines darauf reagieren können:
+//
+//	0  OK       Analyse vollständig
+//	1  Error    Analysefehler (Repo, Registry oder Ausgabe nicht verfügbar)
+//	2  Usage    Aufruffehler (ungültige Flags oder Argumente)
+//	3  Partial  Ergebnis geschrieben, aber der Anteil übersprungener
+//	            Einheiten liegt über --max-skipped
+//
+// Alle Tools registrieren dazu dasselbe Flag:
+//
+//	--max-skipped F  Anteil übersprungener Einheiten (0–1), ab dem Exit-Code 3 gilt (Default: $BAA_MAX_SKIPPED, sonst 0.25)
+//
+// Was eine Einheit ist, bestimmt das Tool: Dependencies bei libyears und
+// mttu, Advisories bei ttf, Projekte bzw. Metrik-Läufe bei "baa". Fehler
+// enden über logging.Fatal (1) bzw. logging.Usage (2); Bibliothekscode
+// liefert Fehler zurück, statt das Programm zu beenden.
+package exitcode
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// Die Exit-Codes.
+const (
+	OK      = 0
+	Error   = 1
+	Usage   = 2
+	Partial = 3
+)
+
+// DefaultMaxSkipped ist die Schwelle ohne --max-skipped und $BAA_MAX_SKIPPED.
+const DefaultMaxSkipped = 0.25
+
+// Options hält den Wert von --max-skipped.
+type Options struct {
+	MaxSkipped float64
+}
+
+var (
+	mu         sync.Mutex
+	maxSkipped = DefaultMaxSkipped
+	skipped    int
+	total      int
+)
+
+// Register fügt --max-skipped zum FlagSet hinzu.
+func Register(fs *flag.FlagSet) *Options {
+	o := &Options{}
+	def := DefaultMaxSkipped
+	if v, err := strconv.ParseFloat(os.Getenv("BAA_MAX_SKIPPED"), 64); err == nil {
+		def = v
+	}
+	fs.Float64Var(&o.MaxSkipped, "max-skipped", def, "Anteil übersprungener Einheiten (0–1), ab dem der Lauf mit Exit-Code 3 (Teilergebnis) endet")
+	return o
+}
+
+// Setup prüft --max-skipped und übernimmt die Schwelle.
+func (o *Options) Setup() error {
+	if o.MaxSkipped < 0 || o.MaxSkipped > 1 {
+		return fmt.Errorf("ungültiges --max-skipped %v – erlaubt: 0 bis 1", o.MaxSkipped)
+	}
+	mu.Lock()
+	maxSkipped = o.MaxSkipped
+	mu.Unlock()
+	return nil
+}
+
+// Export übernimmt --max-skipped in $BAA_MAX_SKIPPED, damit Subprozesse
+// dieselbe Schwelle verwenden.
+func (o *Options) Export() {
+	os.Setenv("BAA_MAX_SKIPPED", strconv.FormatFloat(o.MaxSkipped, 'g', -1, 64))
+}
+
+// Skipped vermerkt n übersprungene von insgesamt of Einheiten; mehrere
+// Aufrufe (etwa je Ökosystem) werden aufsummiert.
+func Skipped(n, of int) {
+	mu.Lock()
+	defer mu.Unlock()
+	skipped += n
+	total += of
+}
+
+// Reset verwirft die vermerkten Einheiten, etwa zu Beginn jeder Runde eines
+// wiederholten Laufs (libyears --watch).
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	skipped, total = 0, 0
+}
+
+// Code liefert Partial, wenn der Anteil übersprungener Einheiten über der
+// Schwelle liegt, sonst OK.
+func Code() int {
+	mu.Lock()
+	defer mu.Unlock()
+	if total > 0 && float64(skipped)/float64(total) > maxSkipped {
+		return Partial
+	}
+	return OK
+}
+
+// Finish beendet das Programm mit Partial, wenn zu viel übersprungen wurde,
+// und kehrt sonst zurück. Es gehört ans Ende von main, nachdem alle
+// Ausgaben geschrieben sind.
+func Finish() {
+	if Code() != Partial {
+		return
+	}
+	mu.Lock()
+	n, of, limit := skipped, total, maxSkipped
+	mu.Unlock()
+	slog.Warn("Teilergebnis: zu viele Einheiten übersprungen", "skipped", n, "total", of, "max_skipped", limit, "exit", Partial)
+	os.Exit(Partial)
+}
@@ -0,0 +1,16 @@
+package exitcode
+
+import "testing"
+
+func TestReset(t *testing.T) {
+	t.Cleanup(Reset)
+	Skipped(3, 4)
+	if Code() != Partial {
+		t.Fatalf("Code %d bei 3/4 übersprungen, erwartet %d", Code(), Partial)
+	}
+	Reset()
+	Skipped(0, 4)
+	if Code() != OK {
+		t.Errorf("Code %d nach Reset, erwartet %d", Code(), OK)
+	}
+}
@@ -0,0 +1,53 @@
+package gitwalk
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Check ist der Smoke-Test für das CLI-Backend: Es prüft, ob git im PATH
+// liegt und sich aufrufen lässt, und liefert die Version (z. B. "git version
+// 2.45.1.windows.1"). Die Fehler nennen, was zu tun ist.
+func Check() (string, error) {
+	bin, err := exec.LookPath("git")
+	if err != nil {
+		return "", fmt.Errorf("git nicht im PATH gefunden (%w) – Git installieren (Windows: Git for Windows, Option \"Git from the command line\") oder --git go-git verwenden", err)
+	}
+	out, err := exec.Command(bin, "--version").CombinedOutput()
+	if err != nil {
+		return "", gitError(bin+" --version", err, string(out))
+	}
+	v := strings.TrimSpace(string(out))
+	if !strings.HasPrefix(v, "git version ") {
+		return "", fmt.Errorf("%s liefert keine git-Version: %q", bin, v)
+	}
+	return v, nil
+}
+
+// CheckRepo prüft, ob git das Repo in dir lesen kann (Checkout oder Bare).
+func CheckRepo(dir string) error {
+	cmd := exec.Command("git", "rev-parse", "--git-dir")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return gitError("git rev-parse in "+dir, err, string(out))
+	}
+	return nil
+}
+
+// gitError verpackt einen fehlgeschlagenen git-Aufruf mit dessen stderr und
+// ergänzt für bekannte Ursachen einen Hinweis.
+func gitError(what string, err error, stderr string) error {
+	msg := strings.TrimSpace(stderr)
+	switch {
+	case errors.Is(err, exec.ErrNotFound):
+		return fmt.Errorf("%s: %w – git installieren oder --git go-git verwenden", what, err)
+	case strings.Contains(msg, "dubious ownership"):
+		// häufig unter Windows bei Checkouts anderer Benutzer oder auf Netzlaufwerken
+		return fmt.Errorf("%s: %w: %s – Verzeichnis mit 'git config --global --add safe.directory <dir>' freigeben", what, err, msg)
+	case strings.Contains(msg, "not a git repository"):
+		return fmt.Errorf("%s: %w: %s – Pfad zeigt auf kein Git-Repo", what, err, msg)
+	}
+	return fmt.Errorf("%s: %w: %s", what, err, msg)
+}
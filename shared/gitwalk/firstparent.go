@@ -0,0 +1,174 @@
+package gitwalk
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// FirstParent entspricht CLI ('git log --first-parent -- <paths>'), kommt
+// aber ohne git-Binary aus: ab HEAD wird nur dem ersten Parent gefolgt und
+// jeder Commit mit diesem verglichen. since/until beziehen sich wie bei git
+// auf das Committer-Datum.
+//
+// Wie bei CLI liegt die Liste der Treffer nie vollständig im Speicher: ein
+// erster Durchgang merkt sich nur jeden bufferSize-ten Commit der Kette als
+// Marke, der zweite arbeitet die Abschnitte zwischen den Marken ältester
+// zuerst ab und hält dabei höchstens bufferSize Hashes.
+type FirstParent struct {
+	Repo    *git.Repository
+	Exclude []string
+}
+
+// ForEach implementiert Source.
+func (s FirstParent) ForEach(paths []string, since, until *time.Time, fn func(*object.Commit) error) error {
+	head, err := s.Repo.Head()
+	if err != nil {
+		return err
+	}
+	marks, err := s.marks(head.Hash(), since)
+	if err != nil {
+		return err
+	}
+	for i := len(marks) - 1; i >= 0; i-- {
+		hits, err := s.segment(marks[i], paths, since, until)
+		if err != nil {
+			return err
+		}
+		// ältester Commit zuerst, wie 'git log --reverse'
+		for j := len(hits) - 1; j >= 0; j-- {
+			c, err := s.Repo.CommitObject(hits[j])
+			if err != nil {
+				return err
+			}
+			if err := fn(c); err != nil {
+				if err == storer.ErrStop {
+					return nil
+				}
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// marks liefert ab h jeden bufferSize-ten Commit der First-Parent-Kette bis
+// vor since, jüngster zuerst.
+func (s FirstParent) marks(h plumbing.Hash, since *time.Time) ([]plumbing.Hash, error) {
+	var marks []plumbing.Hash
+	for n := 0; ; n++ {
+		c, err := s.Repo.CommitObject(h)
+		if err != nil {
+			return nil, err
+		}
+		if since != nil && c.Committer.When.Before(*since) {
+			return marks, nil
+		}
+		if n%bufferSize == 0 {
+			marks = append(marks, h)
+		}
+		if c.NumParents() == 0 {
+			return marks, nil
+		}
+		h = c.ParentHashes[0]
+	}
+}
+
+// segment liefert die Treffer unter den höchstens bufferSize Commits ab der
+// Marke h, jüngster zuerst.
+func (s FirstParent) segment(h plumbing.Hash, paths []string, since, until *time.Time) ([]plumbing.Hash, error) {
+	var hits []plumbing.Hash
+	for n := 0; n < bufferSize; n++ {
+		c, err := s.Repo.CommitObject(h)
+		if err != nil {
+			return nil, err
+		}
+		when := c.Committer.When
+		if since != nil && when.Before(*since) {
+			break
+		}
+		if until == nil || !when.After(*until) {
+			ok, err := s.touches(c, paths)
+			if err != nil {
+				return nil, fmt.Errorf("commit %s: %w", c.Hash, err)
+			}
+			if ok {
+				hits = append(hits, c.Hash)
+			}
+		}
+		if c.NumParents() == 0 {
+			break
+		}
+		h = c.ParentHashes[0]
+	}
+	return hits, nil
+}
+
+// touches prüft, ob c gegenüber seinem ersten Parent eine nicht
+// ausgeschlossene Datei unter paths ändert.
+func (s FirstParent) touches(c *object.Commit, paths []string) (bool, error) {
+	files, err := Changed(c, paths, s.Exclude)
+	return len(files) > 0, err
+}
+
+// Changed liefert die Dateien unter paths (ohne exclude), die c gegenüber
+// seinem ersten Parent ändert, sortiert und ohne Duplikate.
+func Changed(c *object.Commit, paths, exclude []string) ([]string, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return nil, err
+	}
+	var parent *object.Tree
+	if c.NumParents() > 0 {
+		p, err := c.Parent(0)
+		if err != nil {
+			return nil, err
+		}
+		if parent, err = p.Tree(); err != nil {
+			return nil, err
+		}
+	}
+	changes, err := object.DiffTree(parent, tree)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	var files []string
+	for _, ch := range changes {
+		for _, p := range []string{ch.From.Name, ch.To.Name} {
+			if p != "" && !seen[p] && Match(p, paths) && !Excluded(p, exclude) {
+				seen[p] = true
+				files = append(files, p)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Backends sind die Werte für New bzw. die --git-Flags der Tools.
+const Backends = "go-git | cli"
+
+// New wählt die Implementierung: "go-git" (FirstParent, kein git-Binary
+// nötig) oder "cli" (CLI, schneller bei sehr großen Historien). Besteht git
+// den Smoke-Test (Check) nicht, fällt "cli" mit einer Warnung auf go-git
+// zurück.
+func New(backend, dir string, repo *git.Repository, exclude []string) (Source, error) {
+	switch backend {
+	case "", "go-git":
+		return FirstParent{Repo: repo, Exclude: exclude}, nil
+	case "cli":
+		if _, err := Check(); err != nil {
+			slog.Warn("--git cli nicht verfügbar, verwende go-git", "err", err)
+			return FirstParent{Repo: repo, Exclude: exclude}, nil
+		}
+		return CLI{Dir: dir, Repo: repo, Exclude: exclude}, nil
+	}
+	return nil, fmt.Errorf("unbekanntes git-Backend %q (%s)", backend, Backends)
+}
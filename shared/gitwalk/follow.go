@@ -0,0 +1,114 @@
+package gitwalk
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Follow verfolgt eine Datei wie 'git log --follow --first-parent' rückwärts
+// ab HEAD und liefert je Commit den dort gültigen Pfad. Existiert name in
+// HEAD nicht (Manifest inzwischen verschoben), beginnt die Suche bei der
+// flachsten Datei gleichen Namens außerhalb von exclude. paths enthält alle
+// Pfade, unter denen die Datei in der Historie lag (für Source.ForEach).
+func Follow(repo *git.Repository, name string, exclude []string) (at map[plumbing.Hash]string, paths []string, err error) {
+	head, err := repo.Head()
+	if err != nil {
+		return nil, nil, err
+	}
+	c, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, nil, err
+	}
+	cur, err := locate(c, name, exclude)
+	if err != nil {
+		return nil, nil, err
+	}
+	at = map[plumbing.Hash]string{}
+	seen := map[string]bool{cur: true}
+	paths = []string{cur}
+	for {
+		at[c.Hash] = cur
+		if c.NumParents() == 0 {
+			break
+		}
+		p, err := c.Parent(0)
+		if err != nil {
+			return nil, nil, err
+		}
+		if from, ok, err := renamedFrom(p, c, cur); err != nil {
+			return nil, nil, err
+		} else if ok {
+			cur = from
+			if !seen[cur] {
+				seen[cur] = true
+				paths = append(paths, cur)
+			}
+		}
+		c = p
+	}
+	return at, paths, nil
+}
+
+// locate liefert name, falls vorhanden, sonst die flachste Datei mit
+// demselben Basisnamen.
+func locate(c *object.Commit, name string, exclude []string) (string, error) {
+	if _, err := c.File(name); err == nil {
+		return name, nil
+	}
+	tree, err := c.Tree()
+	if err != nil {
+		return "", err
+	}
+	var hits []string
+	base := path.Base(name)
+	err = tree.Files().ForEach(func(f *object.File) error {
+		if path.Base(f.Name) == base && !Excluded(f.Name, exclude) {
+			hits = append(hits, f.Name)
+		}
+		return nil
+	})
+	if err != nil || len(hits) == 0 {
+		return name, err
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		di, dj := strings.Count(hits[i], "/"), strings.Count(hits[j], "/")
+		if di != dj {
+			return di < dj
+		}
+		return hits[i] < hits[j]
+	})
+	return hits[0], nil
+}
+
+// renamedFrom prüft per Rename-Erkennung, ob c die Datei cur aus einem
+// anderen Pfad des Parents p verschoben hat.
+func renamedFrom(p, c *object.Commit, cur string) (string, bool, error) {
+	pt, err := p.Tree()
+	if err != nil {
+		return "", false, err
+	}
+	ct, err := c.Tree()
+	if err != nil {
+		return "", false, err
+	}
+	// lag die Datei schon im Parent an dieser Stelle, ist es kein Rename
+	if _, err := pt.FindEntry(cur); err == nil {
+		return "", false, nil
+	}
+	changes, err := object.DiffTreeWithOptions(context.Background(), pt, ct, object.DefaultDiffTreeOptions)
+	if err != nil {
+		return "", false, err
+	}
+	for _, ch := range changes {
+		if ch.To.Name == cur && ch.From.Name != "" && ch.From.Name != cur {
+			return ch.From.Name, true, nil
+		}
+	}
+	return "", false, nil
+}
@@ -0,0 +1,206 @@
+// Package gitwalk liefert die Commits eines Repos, die bestimmte Dateien
+// berühren – wahlweise über die git-CLI oder rein über go-git.
+package gitwalk
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// Source iteriert über die Commits, die einen der paths (Pathspecs, s. Match)
+// berühren, ältester Commit zuerst. Gibt fn storer.ErrStop zurück, endet die Iteration ohne Fehler.
+type Source interface {
+	ForEach(paths []string, since, until *time.Time, fn func(*object.Commit) error) error
+}
+
+// CLI ruft 'git log --first-parent' im Checkout Dir auf und lädt die Commits
+// anschließend aus Repo. Commits, die nur Pfade aus Exclude (Globs mit "**",
+// siehe Excluded) berühren, werden übersprungen. Die Hashes werden gestreamt:
+// eine Goroutine liest die Ausgabe von git und reicht sie über einen Kanal
+// mit höchstens bufferSize Einträgen weiter, auch bei 100k+ Commits liegt
+// die Liste also nie vollständig im Speicher.
+type CLI struct {
+	Dir     string
+	Repo    *git.Repository
+	Exclude []string
+}
+
+// bufferSize begrenzt die gelesenen, aber noch nicht verarbeiteten Hashes.
+const bufferSize = 256
+
+// ForEach implementiert Source.
+func (s CLI) ForEach(paths []string, since, until *time.Time, fn func(*object.Commit) error) error {
+	cmd := commitsTouchingFiles(s.Dir, paths, s.Exclude, since, until)
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	hashes := make(chan plumbing.Hash, bufferSize)
+	done := make(chan struct{})
+	go func() {
+		defer close(hashes)
+		scan := bufio.NewScanner(out)
+		for scan.Scan() {
+			select {
+			case hashes <- plumbing.NewHash(strings.TrimSpace(scan.Text())):
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	err = func() error {
+		// go-git-Objekte werden nur hier gelesen; der Objekt-Speicher ist
+		// nicht für parallele Zugriffe ausgelegt.
+		for h := range hashes {
+			c, err := s.Repo.CommitObject(h)
+			if err != nil {
+				continue
+			}
+			if err := fn(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}()
+	close(done)
+	if err != nil {
+		cmd.Process.Kill() // vorzeitig beendet: Rest der Ausgabe verwerfen
+		for range hashes {
+		}
+		cmd.Wait()
+		if err == storer.ErrStop {
+			return nil
+		}
+		return err
+	}
+	if err := cmd.Wait(); err != nil {
+		return gitError("git log", err, stderr.String())
+	}
+	return nil
+}
+
+// commitsTouchingFiles baut den Aufruf 'git log --pretty=%H -- <pfad>', der
+// die Hashes zeilenweise ausgibt (jüngster Commit zuletzt). since/until gehen
+// als Unix-Zeit ("@1700000000") an git: ohne Leerzeichen, Zeitzone und
+// Gebietsschema, damit auch git unter Windows sie gleich auslegt. Pfade sind
+// Pathspecs und daher immer mit "/" getrennt.
+func commitsTouchingFiles(repoDir string, paths, exclude []string, since, until *time.Time) *exec.Cmd {
+	args := []string{"log", "--first-parent", "--reverse", "--pretty=%H"}
+	if since != nil {
+		args = append(args, fmt.Sprintf("--since=@%d", since.Unix()))
+	}
+	if until != nil {
+		args = append(args, fmt.Sprintf("--until=@%d", until.Unix()))
+	}
+	args = append(args, "--")
+	for _, p := range paths {
+		args = append(args, filepath.ToSlash(p))
+	}
+	for _, ex := range exclude {
+		args = append(args, ":(exclude,glob)"+filepath.ToSlash(ex))
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoDir
+	return cmd
+}
+
+// Match meldet, ob der Pfad p unter paths fällt. paths gelten wie
+// git-Pathspecs ohne Magic – dieselben Regeln wie 'git log -- <paths>' beim
+// CLI-Backend: exakte Pfade bzw. Verzeichnis-Präfixe; "*" und "?" passen
+// dabei auch auf "/" ("*.tf" trifft also auch "modules/x/main.tf").
+func Match(p string, paths []string) bool {
+	for _, want := range paths {
+		if p == want || strings.HasPrefix(p, strings.TrimSuffix(want, "/")+"/") {
+			return true
+		}
+		if strings.ContainsAny(want, "*?[") && pathspecRx(want).MatchString(p) {
+			return true
+		}
+	}
+	return false
+}
+
+func pathspecRx(want string) *regexp.Regexp {
+	key := "pathspec:" + want
+	if rx, ok := globCache.Load(key); ok {
+		return rx.(*regexp.Regexp)
+	}
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range want {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	rx := regexp.MustCompile(b.String())
+	globCache.Store(key, rx)
+	return rx
+}
+
+// Excluded prüft, ob p auf eines der Globs passt. Neben "*" und "?" (ohne "/")
+// steht "**" für beliebig viele Verzeichnisebenen, z. B. "**/vendor/**" –
+// dieselbe Semantik wie die glob-Pathspecs von git.
+func Excluded(p string, globs []string) bool {
+	for _, g := range globs {
+		if globRx(g).MatchString(p) {
+			return true
+		}
+	}
+	return false
+}
+
+var globCache sync.Map // string → *regexp.Regexp
+
+func globRx(g string) *regexp.Regexp {
+	if rx, ok := globCache.Load(g); ok {
+		return rx.(*regexp.Regexp)
+	}
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(g); i++ {
+		switch {
+		case strings.HasPrefix(g[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(g[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case g[i] == '*':
+			b.WriteString("[^/]*")
+		case g[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(g[i : i+1]))
+		}
+	}
+	b.WriteString("$")
+	rx := regexp.MustCompile(b.String())
+	globCache.Store(g, rx)
+	return rx
+}
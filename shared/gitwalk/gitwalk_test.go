@@ -0,0 +1,113 @@
+package gitwalk
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+func TestMatch(t *testing.T) {
+	cases := []struct {
+		p     string
+		paths []string
+		want  bool
+	}{
+		{"go.mod", []string{"go.mod"}, true},
+		{"sub/go.mod", []string{"go.mod"}, false},
+		{".github/workflows/ci.yml", []string{".github/workflows"}, true},
+		{".github/workflows-old/ci.yml", []string{".github/workflows"}, false},
+		{"main.tf", []string{"*.tf"}, true},
+		{"modules/net/main.tf", []string{"*.tf"}, true}, // "*" kreuzt "/" wie bei git
+		{"requirements/dev.txt", []string{"requirements*.txt"}, true},
+		{"requirements.txt.bak", []string{"requirements*.txt"}, false},
+		{"a.b", []string{"a?b"}, true},
+	}
+	for _, tc := range cases {
+		if got := Match(tc.p, tc.paths); got != tc.want {
+			t.Errorf("Match(%q, %q) = %v, erwartet %v", tc.p, tc.paths, got, tc.want)
+		}
+	}
+}
+
+func TestExcluded(t *testing.T) {
+	globs := []string{"**/vendor/**", "docs/*.md"}
+	for p, want := range map[string]bool{
+		"vendor/x/go.mod":     true,
+		"a/b/vendor/go.mod":   true,
+		"docs/readme.md":      true,
+		"docs/sub/readme.md":  false,
+		"src/vendorized/x.go": false,
+	} {
+		if got := Excluded(p, globs); got != want {
+			t.Errorf("Excluded(%q) = %v, erwartet %v", p, got, want)
+		}
+	}
+}
+
+// linear baut eine Historie aus n Commits im Tagesabstand; jeder dritte
+// ändert go.mod, die übrigen nur README.
+func linear(t *testing.T, n int) (*git.Repository, []plumbing.Hash) {
+	t.Helper()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var commits []SynthCommit
+	var mods []plumbing.Hash
+	gomod, readme := "", ""
+	for i := 0; i < n; i++ {
+		if i%3 == 0 {
+			gomod = fmt.Sprintf("module x // %d\n", i)
+		} else {
+			readme = fmt.Sprintf("rev %d\n", i)
+		}
+		h := plumbing.NewHash(fmt.Sprintf("%040x", i+1))
+		sig := object.Signature{Name: "t", Email: "t@example.com", When: start.AddDate(0, 0, i)}
+		commits = append(commits, SynthCommit{Hash: h, Author: sig, Committer: sig,
+			Files: map[string][]byte{"go.mod": []byte(gomod), "README": []byte(readme)}})
+		if i%3 == 0 {
+			mods = append(mods, h)
+		}
+	}
+	r, err := Synthesize(commits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r, mods
+}
+
+// Über mehrere Abschnitte (> bufferSize Commits) hinweg muss FirstParent
+// genau die Treffer liefern, ältester zuerst.
+func TestFirstParentSegments(t *testing.T) {
+	r, want := linear(t, 2*bufferSize+17)
+	var got []plumbing.Hash
+	if err := (FirstParent{Repo: r}).ForEach([]string{"go.mod"}, nil, nil, func(c *object.Commit) error {
+		got = append(got, c.Hash)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("%d Commits, erwartet %d; erster %v", len(got), len(want), got[:min(1, len(got))])
+	}
+
+	// since/until und vorzeitiges Ende
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, 300)
+	until := since.AddDate(0, 0, 30)
+	got = got[:0]
+	if err := (FirstParent{Repo: r}).ForEach([]string{"go.mod"}, &since, &until, func(c *object.Commit) error {
+		got = append(got, c.Hash)
+		if len(got) == 5 {
+			return storer.ErrStop
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, want[100:105]) {
+		t.Errorf("since/until: %v, erwartet %v", got, want[100:105])
+	}
+}
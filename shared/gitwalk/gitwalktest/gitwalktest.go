@@ -0,0 +1,66 @@
+// Package gitwalktest baut kleine In-Memory-Repos für Tests von Analyzern,
+// ohne Netz und ohne Checkout auf der Platte:
+//
+//	f := gitwalktest.New()
+//	f.Commit(day(0), map[string]string{"go.mod": "..."})
+//	f.Commit(day(30), map[string]string{"go.mod": "..."})
+//	f.Commit(day(40), nil, "go.mod") // löscht go.mod
+//	src, err := f.Source()
+package gitwalktest
+
+import (
+	"fmt"
+	"maps"
+	"time"
+
+	"baa_fs25/shared/gitwalk"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Fixture sammelt Commits mit linearer Historie; Repo und Source bauen
+// daraus per gitwalk.Synthesize ein Repo. Die Hashes sind fortlaufend
+// vergeben, nicht aus dem Inhalt berechnet.
+type Fixture struct {
+	commits []gitwalk.SynthCommit
+	files   map[string][]byte // Stand nach dem letzten Commit
+}
+
+// New legt ein leeres Fixture an.
+func New() *Fixture {
+	return &Fixture{files: map[string][]byte{}}
+}
+
+// Commit schreibt files (Pfad → Inhalt, leerer Inhalt = leere Datei),
+// löscht die Pfade in del und committet mit Autor- und Committer-Datum when.
+func (f *Fixture) Commit(when time.Time, files map[string]string, del ...string) plumbing.Hash {
+	for p, content := range files {
+		f.files[p] = []byte(content)
+	}
+	for _, p := range del {
+		delete(f.files, p)
+	}
+	h := plumbing.NewHash(fmt.Sprintf("%040x", len(f.commits)+1))
+	sig := object.Signature{Name: "fixture", Email: "fixture@example.com", When: when}
+	f.commits = append(f.commits, gitwalk.SynthCommit{
+		Hash: h, Author: sig, Committer: sig,
+		Message: "fixture " + when.Format(time.RFC3339),
+		Files:   maps.Clone(f.files),
+	})
+	return h
+}
+
+// Repo baut das Repo; HEAD zeigt auf den letzten Commit.
+func (f *Fixture) Repo() (*git.Repository, error) {
+	return gitwalk.Synthesize(f.commits)
+}
+
+// Source liefert eine go-git-basierte Source für das Fixture-Repo.
+func (f *Fixture) Source() (gitwalk.Source, error) {
+	r, err := f.Repo()
+	if err != nil {
+		return nil, err
+	}
+	return gitwalk.FirstParent{Repo: r}, nil
+}
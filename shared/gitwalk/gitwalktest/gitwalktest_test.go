@@ -0,0 +1,51 @@
+package gitwalktest
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"baa_fs25/shared/gitwalk"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestFixture(t *testing.T) {
+	day := func(n int) time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, n) }
+	f := New()
+	c1 := f.Commit(day(0), map[string]string{"go.mod": "module x\n", "a/empty.txt": ""})
+	f.Commit(day(1), map[string]string{"README": "hi"})
+	c3 := f.Commit(day(2), nil, "go.mod")
+	src, err := f.Source()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []plumbing.Hash
+	var files [][]string
+	if err := src.ForEach([]string{"go.mod", "a"}, nil, nil, func(c *object.Commit) error {
+		got = append(got, c.Hash)
+		ch, err := gitwalk.Changed(c, []string{"go.mod", "a"}, nil)
+		files = append(files, ch)
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []plumbing.Hash{c1, c3}; !slices.Equal(got, want) {
+		t.Fatalf("Commits %v, erwartet %v", got, want)
+	}
+	if !slices.Equal(files[0], []string{"a/empty.txt", "go.mod"}) || !slices.Equal(files[1], []string{"go.mod"}) {
+		t.Errorf("geänderte Dateien %v", files)
+	}
+
+	r, _ := f.Repo()
+	head, _ := r.Head()
+	c, _ := r.CommitObject(head.Hash())
+	if _, err := c.File("go.mod"); err == nil {
+		t.Error("go.mod nach dem Löschen noch vorhanden")
+	}
+	empty, err := c.File("a/empty.txt")
+	if err != nil || empty.Size != 0 {
+		t.Errorf("leere Datei: %v, %v", empty, err)
+	}
+}
@@ -0,0 +1,129 @@
+package gitwalk
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// SynthCommit ist ein Commit, dessen Dateien bereits anderweitig gelesen
+// wurden (z. B. über die GitHub-API). Files enthält den vollständigen Stand
+// der relevanten Dateien in diesem Commit.
+type SynthCommit struct {
+	Hash      plumbing.Hash
+	Author    object.Signature
+	Committer object.Signature
+	Message   string
+	Files     map[string][]byte
+}
+
+// Synthesize baut aus commits (ältester zuerst) ein In-Memory-Repo mit
+// linearer Historie: Parent ist jeweils der vorige Eintrag, die Trees
+// enthalten nur Files. Die Commits behalten ihren ursprünglichen Hash, damit
+// Ausgaben auf das echte Repo verweisen; die Hashes sind daher nicht
+// nachprüfbar. Als Source eignet sich FirstParent.
+func Synthesize(commits []SynthCommit) (*git.Repository, error) {
+	st := memory.NewStorage()
+	r, err := git.Init(st, nil)
+	if err != nil {
+		return nil, err
+	}
+	var parent plumbing.Hash
+	for _, sc := range commits {
+		tree, err := writeTree(st, sc.Files)
+		if err != nil {
+			return nil, err
+		}
+		c := &object.Commit{
+			Author:    sc.Author,
+			Committer: sc.Committer,
+			Message:   sc.Message,
+			TreeHash:  tree,
+		}
+		if !parent.IsZero() {
+			c.ParentHashes = []plumbing.Hash{parent}
+		}
+		obj := st.NewEncodedObject()
+		if err := c.Encode(obj); err != nil {
+			return nil, err
+		}
+		if _, err := st.SetEncodedObject(fixedHash{obj, sc.Hash}); err != nil {
+			return nil, err
+		}
+		parent = sc.Hash
+	}
+	if !parent.IsZero() {
+		if err := st.SetReference(plumbing.NewHashReference(plumbing.Master, parent)); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// fixedHash gibt einem Objekt einen vorgegebenen Hash.
+type fixedHash struct {
+	plumbing.EncodedObject
+	h plumbing.Hash
+}
+
+func (o fixedHash) Hash() plumbing.Hash { return o.h }
+
+// writeTree schreibt files (Pfad → Inhalt) als verschachtelte Trees.
+func writeTree(st storer.EncodedObjectStorer, files map[string][]byte) (plumbing.Hash, error) {
+	var entries []object.TreeEntry
+	sub := map[string]map[string][]byte{}
+	for p, content := range files {
+		dir, rest, nested := strings.Cut(p, "/")
+		if nested {
+			if sub[dir] == nil {
+				sub[dir] = map[string][]byte{}
+			}
+			sub[dir][rest] = content
+			continue
+		}
+		blob := st.NewEncodedObject()
+		blob.SetType(plumbing.BlobObject)
+		w, err := blob.Writer()
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		if _, err := w.Write(content); err != nil {
+			return plumbing.ZeroHash, err
+		}
+		if err := w.Close(); err != nil {
+			return plumbing.ZeroHash, err
+		}
+		h, err := st.SetEncodedObject(blob)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		entries = append(entries, object.TreeEntry{Name: path.Base(p), Mode: filemode.Regular, Hash: h})
+	}
+	for dir, fs := range sub {
+		h, err := writeTree(st, fs)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		entries = append(entries, object.TreeEntry{Name: dir, Mode: filemode.Dir, Hash: h})
+	}
+	// git sortiert Verzeichnisse, als endeten sie auf "/"
+	sortKey := func(e object.TreeEntry) string {
+		if e.Mode == filemode.Dir {
+			return e.Name + "/"
+		}
+		return e.Name
+	}
+	sort.Slice(entries, func(i, j int) bool { return sortKey(entries[i]) < sortKey(entries[j]) })
+	obj := st.NewEncodedObject()
+	if err := (&object.Tree{Entries: entries}).Encode(obj); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return st.SetEncodedObject(obj)
+}
@@ -0,0 +1,180 @@
+// Package httpcache ist ein Festplatten-Cache für HTTP-Antworten, der bei
+// jedem Zugriff per ETag bzw. Last-Modified revalidiert. Unveränderte
+// Antworten (304) kosten bei GitHub kein Rate-Limit.
+package httpcache
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+)
+
+// Transport cached erfolgreiche (200) Antworten auf GET- und POST-Anfragen
+// in Dir. Der Schlüssel ist Methode + URL + Body (+ Authorization-Header als
+// Hash, damit Antworten mit und ohne Token getrennt bleiben).
+type Transport struct {
+	Dir  string
+	Base http.RoundTripper // nil = http.DefaultTransport
+}
+
+// hits und misses zählen die cachebaren Anfragen aller Transports.
+var hits, misses atomic.Int64
+
+// Stats liefert die Zahl der aus dem Cache beantworteten (304) und der neu
+// geladenen Anfragen.
+func Stats() (hit, miss int64) { return hits.Load(), misses.Load() }
+
+// Install hängt einen Cache in dir vor den Transport von hc.
+func Install(hc *http.Client, dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	hc.Transport = &Transport{Dir: dir, Base: hc.Transport}
+	return nil
+}
+
+// DefaultDir ist <UserCacheDir>/baa_fs25/<tool>.
+func DefaultDir(tool string) string {
+	d, err := os.UserCacheDir()
+	if err != nil {
+		d = os.TempDir()
+	}
+	return filepath.Join(d, "baa_fs25", tool)
+}
+
+// RoundTrip implementiert http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if req.Method != http.MethodGet && req.Method != http.MethodPost {
+		return base.RoundTrip(req)
+	}
+
+	// Der Body wird auf einer Kopie der Anfrage gelesen und ersetzt; req
+	// selbst bleibt unverändert (http.RoundTripper), nur sein Body wird wie
+	// vorgeschrieben geschlossen.
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	out := req.Clone(req.Context())
+	if body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+		out.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+		out.ContentLength = int64(len(body))
+	}
+	path := filepath.Join(t.Dir, key(out, body))
+
+	cached := load(path, req)
+	if cached != nil {
+		if et := cached.Header.Get("ETag"); et != "" {
+			out.Header.Set("If-None-Match", et)
+		}
+		if lm := cached.Header.Get("Last-Modified"); lm != "" {
+			out.Header.Set("If-Modified-Since", lm)
+		}
+	}
+	resp, err := base.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		resp.Body.Close()
+		slog.Debug("HTTP-Cache: unverändert", "url", req.URL.Redacted())
+		hits.Add(1)
+		return cached, nil
+	case resp.StatusCode == http.StatusOK:
+		misses.Add(1)
+		if err := store(path, resp); err != nil {
+			slog.Warn("HTTP-Cache: nicht schreibbar", "file", path, "err", err)
+		}
+	}
+	return resp, nil
+}
+
+// readBody liefert den Body von req, bevorzugt über GetBody, damit req.Body
+// nicht gelesen werden muss; nil ohne Body.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	rc := req.Body
+	if req.GetBody != nil {
+		if b, err := req.GetBody(); err == nil {
+			req.Body.Close()
+			rc = b
+		}
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+func key(req *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, req.Method+" "+req.URL.String()+"\n")
+	io.WriteString(h, req.Header.Get("Authorization")+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// load liest eine gespeicherte Antwort; nil, wenn es keine gibt.
+func load(path string, req *http.Request) *http.Response {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(b)), req)
+	if err != nil {
+		slog.Debug("HTTP-Cache: Eintrag ungültig", "file", path, "err", err)
+		return nil
+	}
+	return resp
+}
+
+// store schreibt resp nach path und ersetzt den Body durch eine Kopie.
+func store(path string, resp *http.Response) error {
+	if resp.Header.Get("ETag") == "" && resp.Header.Get("Last-Modified") == "" {
+		return nil // nicht revalidierbar
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	cp := *resp
+	cp.Body = io.NopCloser(bytes.NewReader(body))
+	cp.ContentLength = int64(len(body))
+	cp.TransferEncoding = nil
+	dump, err := httputil.DumpResponse(&cp, true)
+	if err != nil {
+		return err
+	}
+	// eigener Temp-Name, da mehrere Prozesse denselben Cache teilen können
+	// (libyears batch)
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(dump); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,50 @@
+package httpcache
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Der Transport darf die Anfrage des Aufrufers nicht verändern, muss aber
+// beim zweiten Aufruf mit ETag revalidieren und den gecachten Body liefern.
+func TestRoundTripKeepsRequestAndRevalidates(t *testing.T) {
+	var posts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		posts = append(posts, string(b))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		io.WriteString(w, "antwort")
+	}))
+	defer srv.Close()
+	tr := &Transport{Dir: t.TempDir()}
+
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(`{"q":1}`))
+		body := req.Body
+		resp, err := tr.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if string(got) != "antwort" {
+			t.Errorf("Lauf %d: Body = %q", i, got)
+		}
+		if req.Body != body || req.Header.Get("If-None-Match") != "" {
+			t.Errorf("Lauf %d: Anfrage des Aufrufers verändert", i)
+		}
+	}
+	if len(posts) != 2 || posts[1] != `{"q":1}` {
+		t.Errorf("Server sah %q, erwartet zweimal den Body", posts)
+	}
+	if hit, miss := Stats(); hit != 1 || miss != 1 {
+		t.Errorf("Stats = %d/%d, erwartet 1/1", hit, miss)
+	}
+}
@@ -0,0 +1,111 @@
+// Package i18n übersetzt die Konsolen-Reports der Tools, damit sie nicht
+// mehr Deutsch und Englisch mischen.
+//
+// Alle Tools registrieren dasselbe Flag:
+//
+//	--lang en | de   Sprache der Reports (Default: $BAA_LANG, sonst en)
+//
+// Die Texte bleiben im Code, wie sie geschrieben sind, und dienen als
+// Schlüssel (wie msgid bei gettext). Jedes Tool hinterlegt mit Add je
+// Sprache einen Katalog Originaltext → Übersetzung; Texte ohne Eintrag
+// erscheinen unverändert. Logs, Fehlermeldungen und JSON-Ausgaben werden
+// nicht übersetzt.
+package i18n
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Sprachen der Reports.
+const (
+	EN = "en"
+	DE = "de"
+)
+
+// Options hält den Wert von --lang.
+type Options struct {
+	Lang string
+}
+
+var (
+	mu       sync.RWMutex
+	lang     = EN
+	catalogs = map[string]map[string]string{}
+)
+
+// Register fügt --lang zum FlagSet hinzu.
+func Register(fs *flag.FlagSet) *Options {
+	o := &Options{}
+	def := os.Getenv("BAA_LANG")
+	if def == "" {
+		def = EN
+	}
+	fs.StringVar(&o.Lang, "lang", def, "Sprache der Reports: en | de")
+	return o
+}
+
+// Setup prüft --lang und stellt die Sprache ein.
+func (o *Options) Setup() error {
+	switch o.Lang {
+	case EN, DE:
+	default:
+		return fmt.Errorf("ungültiges --lang %q – erlaubt: en | de", o.Lang)
+	}
+	mu.Lock()
+	lang = o.Lang
+	mu.Unlock()
+	return nil
+}
+
+// Export übernimmt --lang in $BAA_LANG, damit Subprozesse dieselbe Sprache
+// verwenden.
+func (o *Options) Export() {
+	os.Setenv("BAA_LANG", o.Lang)
+}
+
+// Lang liefert die eingestellte Sprache.
+func Lang() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return lang
+}
+
+// Add ergänzt den Katalog einer Sprache (Originaltext → Übersetzung).
+func Add(l string, msgs map[string]string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if catalogs[l] == nil {
+		catalogs[l] = map[string]string{}
+	}
+	for k, v := range msgs {
+		catalogs[l][k] = v
+	}
+}
+
+// T übersetzt msg in die eingestellte Sprache.
+func T(msg string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	if t, ok := catalogs[lang][msg]; ok {
+		return t
+	}
+	return msg
+}
+
+// Sprintf formatiert mit dem übersetzten Format.
+func Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// Printf gibt mit dem übersetzten Format auf stdout aus.
+func Printf(format string, args ...any) {
+	fmt.Printf(T(format), args...)
+}
+
+// Println gibt msg übersetzt auf stdout aus.
+func Println(msg string) {
+	fmt.Println(T(msg))
+}
@@ -0,0 +1,68 @@
+// Package logging stellt den gemeinsamen slog-Logger der Analyse-Tools bereit.
+//
+// Alle Tools registrieren dieselben Flags:
+//
+//	--log-level  debug | info | warn | error   (Default: info)
+//	--log-format text | json                   (Default: text)
+//
+// Logs gehen immer nach stderr, damit stdout für Reports frei bleibt.
+package logging
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"baa_fs25/shared/exitcode"
+)
+
+// Options hält die Werte der Logging-Flags.
+type Options struct {
+	Level  string
+	Format string
+}
+
+// Register fügt --log-level und --log-format zum FlagSet hinzu.
+func Register(fs *flag.FlagSet) *Options {
+	o := &Options{}
+	fs.StringVar(&o.Level, "log-level", "info", "Log-Level: debug | info | warn | error")
+	fs.StringVar(&o.Format, "log-format", "text", "Log-Format: text | json")
+	return o
+}
+
+// Setup installiert den konfigurierten Logger als slog-Default.
+func (o *Options) Setup() error {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(o.Level)); err != nil {
+		return fmt.Errorf("ungültiges --log-level %q: %w", o.Level, err)
+	}
+	hopts := &slog.HandlerOptions{Level: lvl}
+
+	var h slog.Handler
+	switch strings.ToLower(o.Format) {
+	case "text", "":
+		h = slog.NewTextHandler(os.Stderr, hopts)
+	case "json":
+		h = slog.NewJSONHandler(os.Stderr, hopts)
+	default:
+		return fmt.Errorf("ungültiges --log-format %q – erlaubt: text | json", o.Format)
+	}
+	slog.SetDefault(slog.New(h))
+	return nil
+}
+
+// Fatal loggt msg auf Error-Level und beendet das Programm mit Exit-Code 1
+// (Analysefehler, s. shared/exitcode).
+func Fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(exitcode.Error)
+}
+
+// Usage loggt msg auf Error-Level und beendet das Programm mit Exit-Code 2
+// (ungültige Flags oder Argumente).
+func Usage(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(exitcode.Usage)
+}
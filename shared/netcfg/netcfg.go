@@ -0,0 +1,122 @@
+// Package netcfg konfiguriert den HTTP-Zugriff aller Tools für Firmennetze:
+// Proxy aus HTTPS_PROXY/HTTP_PROXY/NO_PROXY, zusätzliche CA-Zertifikate und
+// optional abgeschaltete Zertifikatsprüfung.
+//
+// Alle Tools registrieren dieselben Flags:
+//
+//	--ca-bundle            PEM-Datei mit zusätzlichen CAs (Default: $BAA_CA_BUNDLE)
+//	--insecure-skip-verify TLS-Zertifikate nicht prüfen (Default: $BAA_INSECURE_SKIP_VERIFY)
+//
+// Die Env-Variablen gelten auch für Tools, die "baa" als Subprozess startet
+// (s. Export).
+package netcfg
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+// Options hält die Werte der Netz-Flags.
+type Options struct {
+	CABundle string
+	Insecure bool
+}
+
+// Register fügt --ca-bundle und --insecure-skip-verify zum FlagSet hinzu.
+func Register(fs *flag.FlagSet) *Options {
+	o := &Options{}
+	insecure, _ := strconv.ParseBool(os.Getenv("BAA_INSECURE_SKIP_VERIFY"))
+	fs.StringVar(&o.CABundle, "ca-bundle", os.Getenv("BAA_CA_BUNDLE"), "PEM-Datei mit zusätzlichen CA-Zertifikaten (z. B. Firmen-Proxy)")
+	fs.BoolVar(&o.Insecure, "insecure-skip-verify", insecure, "TLS-Zertifikate nicht prüfen (nur für Tests)")
+	return o
+}
+
+// Setup richtet http.DefaultTransport ein, den alle Registry- und API-Clients
+// (auch http.DefaultClient) verwenden, und lässt go-git denselben Transport
+// für HTTPS-Klone nutzen. o.CABundle wird absolut, damit Subprozesse mit
+// anderem Arbeitsverzeichnis das Bundle finden (s. Export).
+func (o *Options) Setup() error {
+	tr, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return fmt.Errorf("http.DefaultTransport ist kein *http.Transport")
+	}
+	// Proxy wird für jede Anfrage aufgerufen und merkt sich nebenbei den Host
+	// für die Provenienz-Angaben der Ausgabe.
+	tr.Proxy = func(req *http.Request) (*url.URL, error) {
+		hosts.Lock()
+		hosts.seen[req.URL.Host] = true
+		hosts.Unlock()
+		return http.ProxyFromEnvironment(req)
+	}
+	if o.CABundle != "" || o.Insecure {
+		cfg := &tls.Config{InsecureSkipVerify: o.Insecure}
+		if o.CABundle != "" {
+			abs, err := filepath.Abs(o.CABundle)
+			if err != nil {
+				return fmt.Errorf("CA-Bundle: %w", err)
+			}
+			o.CABundle = abs
+			pem, err := os.ReadFile(o.CABundle)
+			if err != nil {
+				return fmt.Errorf("CA-Bundle: %w", err)
+			}
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return fmt.Errorf("CA-Bundle %s enthält keine Zertifikate", o.CABundle)
+			}
+			cfg.RootCAs = pool
+		}
+		if o.Insecure {
+			slog.Warn("TLS-Zertifikatsprüfung abgeschaltet")
+		}
+		tr.TLSClientConfig = cfg
+	}
+	hc := githttp.NewClient(&http.Client{Transport: tr})
+	client.InstallProtocol("https", hc)
+	client.InstallProtocol("http", hc)
+	return nil
+}
+
+var hosts = struct {
+	sync.Mutex
+	seen map[string]bool
+}{seen: map[string]bool{}}
+
+// Hosts liefert die seit Setup angefragten Hosts, sortiert.
+func Hosts() []string {
+	hosts.Lock()
+	defer hosts.Unlock()
+	out := make([]string, 0, len(hosts.seen))
+	for h := range hosts.seen {
+		out = append(out, h)
+	}
+	slices.Sort(out)
+	return out
+}
+
+// Export übernimmt die Flag-Werte in die Env-Variablen, damit Subprozesse
+// dieselbe Konfiguration erhalten.
+func (o *Options) Export() {
+	if o.CABundle != "" {
+		os.Setenv("BAA_CA_BUNDLE", o.CABundle)
+	}
+	if o.Insecure {
+		os.Setenv("BAA_INSECURE_SKIP_VERIFY", "1")
+	}
+}
@@ -0,0 +1,53 @@
+package netcfg
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestExportAbs prüft, dass ein relatives --ca-bundle absolut an
+// Subprozesse geht, die in einem anderen Verzeichnis laufen.
+func TestExportAbs(t *testing.T) {
+	dir := t.TempDir()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "Test-CA"},
+		NotBefore: time.Now(), NotAfter: time.Now().Add(time.Hour), IsCA: true, BasicConstraintsValid: true}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ca.pem"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+	t.Setenv("BAA_CA_BUNDLE", "")
+
+	o := &Options{CABundle: "ca.pem"}
+	if err := o.Setup(); err != nil {
+		t.Fatal(err)
+	}
+	o.Export()
+	got := os.Getenv("BAA_CA_BUNDLE")
+	if _, err := os.Stat(got); !filepath.IsAbs(got) || err != nil {
+		t.Errorf("BAA_CA_BUNDLE = %q, erwartet absoluten Pfad von %s/ca.pem", got, dir)
+	}
+}
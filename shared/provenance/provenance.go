@@ -0,0 +1,103 @@
+// Package provenance beschreibt, wie eine Ausgabe entstanden ist: Version und
+// Commit des Tools, gesetzte Flags, Zeitpunkt, kontaktierte Endpunkte und
+// Trefferquote des HTTP-Caches. Die Tools betten Info als "provenance" in
+// ihre JSON-Ausgaben ein, damit sich Läufe nachvollziehen und wiederholen
+// lassen.
+package provenance
+
+import (
+	"flag"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"baa_fs25/shared/anon"
+	"baa_fs25/shared/httpcache"
+	"baa_fs25/shared/netcfg"
+)
+
+// Info ist der Metadatenblock einer Ausgabe.
+type Info struct {
+	Tool      string            `json:"tool"`
+	Version   string            `json:"version"`
+	Commit    string            `json:"commit,omitempty"`   // vcs.revision des Builds
+	Modified  bool              `json:"modified,omitempty"` // Build aus geändertem Arbeitsverzeichnis
+	GoVersion string            `json:"go_version"`
+	Flags     map[string]string `json:"flags"` // explizit gesetzte Flags
+	Args      []string          `json:"args,omitempty"`
+	StartedAt time.Time         `json:"started_at"`
+	WrittenAt time.Time         `json:"written_at"`
+	Endpoints []string          `json:"endpoints"` // angefragte Hosts (Registries, APIs, Klone)
+	Cache     *CacheStats       `json:"cache,omitempty"`
+}
+
+// CacheStats sind die Zugriffe auf den HTTP-Cache (nur bei --cache-dir).
+type CacheStats struct {
+	Hits     int64   `json:"hits"`
+	Misses   int64   `json:"misses"`
+	HitRatio float64 `json:"hit_ratio"`
+}
+
+var run struct {
+	tool  string
+	fs    *flag.FlagSet
+	start time.Time
+}
+
+// Start merkt sich Tool und geparste Flags; Aufruf direkt nach fs.Parse.
+func Start(tool string, fs *flag.FlagSet) {
+	run.tool, run.fs, run.start = tool, fs, time.Now().UTC()
+}
+
+// Get liefert den Block zum Zeitpunkt der Ausgabe; nil ohne Start.
+func Get() *Info {
+	if run.fs == nil {
+		return nil
+	}
+	info := &Info{
+		Tool:      run.tool,
+		Version:   "(devel)",
+		Flags:     map[string]string{},
+		StartedAt: run.start,
+		WrittenAt: time.Now().UTC(),
+		Endpoints: netcfg.Hosts(),
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		info.GoVersion = bi.GoVersion
+		if bi.Main.Version != "" {
+			info.Version = bi.Main.Version
+		}
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				info.Commit = s.Value
+			case "vcs.modified":
+				info.Modified = s.Value == "true"
+			}
+		}
+	}
+	run.fs.Visit(func(f *flag.Flag) {
+		info.Flags[f.Name] = value(f.Name, f.Value.String())
+	})
+	for _, a := range run.fs.Args() {
+		info.Args = append(info.Args, value("", a))
+	}
+	if hits, misses := httpcache.Stats(); hits+misses > 0 {
+		info.Cache = &CacheStats{Hits: hits, Misses: misses, HitRatio: float64(hits) / float64(hits+misses)}
+	}
+	return info
+}
+
+// secret sind Namensteile von Flags, deren Wert nicht in die Ausgabe gehört.
+var secret = []string{"salt", "token", "secret", "password", "webhook"}
+
+// value schwärzt Geheimnisse und pseudonymisiert bei --anonymize Pfade und
+// URLs, die das Repo verraten würden.
+func value(name, v string) string {
+	for _, s := range secret {
+		if strings.Contains(name, s) && v != "" {
+			return "***"
+		}
+	}
+	return anon.Path(v)
+}
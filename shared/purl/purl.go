@@ -0,0 +1,135 @@
+// Package purl bildet Dependencies auf Package-URLs ab
+// (https://github.com/package-url/purl-spec), z. B. pkg:npm/lodash@4.17.21.
+// Alle Tools schreiben sie in ihre Ausgabe, damit sich Datensätze über
+// Tools und Ökosysteme hinweg zuverlässig verknüpfen lassen.
+package purl
+
+import (
+	"sort"
+	"strings"
+)
+
+// types ordnet die Ökosystem-Namen der Tools (mttu --eco, libyears-
+// Subcommands, OSV-Ökosysteme) dem purl-Typ zu.
+var types = map[string]string{
+	"npm":       "npm",
+	"go":        "golang",
+	"Go":        "golang",
+	"py":        "pypi",
+	"pypi":      "pypi",
+	"PyPI":      "pypi",
+	"gha":       "githubactions",
+	"docker":    "docker",
+	"helm":      "helm",
+	"cocoapods": "cocoapods",
+	"swiftpm":   "swift",
+	"terraform": "terraform",
+	"submodule": "generic",
+	"Maven":     "maven",
+	"crates.io": "cargo",
+}
+
+// For liefert die purl von name in der Version version ("" = ohne Version)
+// im Ökosystem eco; "" für unbekannte Ökosysteme oder leere Namen.
+func For(eco, name, version string) string {
+	typ, ok := types[eco]
+	if !ok || name == "" {
+		return ""
+	}
+	var ns []string
+	var subpath string
+	quals := map[string]string{}
+	switch typ {
+	case "npm": // @scope/name
+		if scope, n, ok := strings.Cut(name, "/"); ok && strings.HasPrefix(scope, "@") {
+			ns, name = []string{scope}, n
+		}
+	case "pypi": // PEP 503, wie von der Spezifikation verlangt
+		name = strings.ReplaceAll(strings.ToLower(name), "_", "-")
+	case "golang", "terraform":
+		ns, name = splitLast(name)
+	case "githubactions": // owner/repo[/pfad]
+		parts := strings.SplitN(name, "/", 3)
+		if len(parts) >= 2 {
+			ns, name = parts[:1], parts[1]
+		}
+		if len(parts) == 3 {
+			subpath = parts[2]
+		}
+	case "swift": // owner/repo auf GitHub
+		if !strings.Contains(name, ".") || strings.Count(name, "/") == 1 {
+			name = "github.com/" + name
+		}
+		ns, name = splitLast(name)
+	case "cocoapods": // Pod/Subspec
+		name, subpath, _ = strings.Cut(name, "/")
+	case "helm": // "<repo-url>/<chart>", sonst ein Image aus values.yaml
+		if i := strings.LastIndex(name, "/"); i > 0 && strings.Contains(name, "://") {
+			quals["repository_url"] = name[:i]
+			name = name[i+1:]
+			break
+		}
+		typ = "docker"
+		fallthrough
+	case "docker":
+		ns, name = splitLast(name)
+	case "maven": // group:artifact
+		if g, a, ok := strings.Cut(name, ":"); ok {
+			ns, name = []string{g}, a
+		}
+	case "generic": // Submodul-URL
+		quals["vcs_url"] = "git+" + name
+		name = name[strings.LastIndex(name, "/")+1:]
+	}
+
+	var b strings.Builder
+	b.WriteString("pkg:" + typ + "/")
+	for _, s := range ns {
+		b.WriteString(escape(s) + "/")
+	}
+	b.WriteString(escape(name))
+	if version != "" {
+		b.WriteString("@" + escape(version))
+	}
+	if len(quals) > 0 {
+		keys := make([]string, 0, len(quals))
+		for k := range quals {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for i, k := range keys {
+			sep := "&"
+			if i == 0 {
+				sep = "?"
+			}
+			b.WriteString(sep + k + "=" + escape(quals[k]))
+		}
+	}
+	if subpath != "" {
+		b.WriteString("#" + subpath)
+	}
+	return b.String()
+}
+
+// splitLast trennt "a/b/c" in die Namespace-Segmente [a b] und den Namen c.
+func splitLast(name string) ([]string, string) {
+	parts := strings.Split(name, "/")
+	return parts[:len(parts)-1], parts[len(parts)-1]
+}
+
+// escape kodiert alles außer den unreservierten Zeichen (RFC 3986).
+func escape(s string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte("-._~", c) >= 0 {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&15])
+	}
+	return b.String()
+}
@@ -0,0 +1,61 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// CocoaPods fragt trunk.cocoapods.org/api/v1/pods/<name> ab.
+type CocoaPods struct {
+	HTTP  *http.Client
+	cache cache
+}
+
+type podResp struct {
+	Versions []struct {
+		Name      string `json:"name"`
+		CreatedAt string `json:"created_at"`
+	} `json:"versions"`
+}
+
+// ReleaseTime implementiert Client.
+func (c *CocoaPods) ReleaseTime(pod, ver string) (time.Time, error) {
+	if c.cache == nil {
+		c.cache = cache{}
+	}
+	if m, ok := c.cache[pod]; ok {
+		if t, ok2 := m[ver]; ok2 {
+			return t, nil
+		}
+		return time.Time{}, fmt.Errorf("kein Datum für %s %s", pod, ver)
+	}
+	body, resp, err := fetch(c.HTTP, "https://trunk.cocoapods.org/api/v1/pods/"+url.PathEscape(pod))
+	if err != nil {
+		return time.Time{}, err
+	}
+	if resp.StatusCode != 200 {
+		return time.Time{}, fmt.Errorf("cocoapods trunk %s", resp.Status)
+	}
+	var pr podResp
+	if err := json.Unmarshal(body, &pr); err != nil {
+		return time.Time{}, err
+	}
+	c.cache[pod] = map[string]time.Time{}
+	for _, v := range pr.Versions {
+		// Trunk liefert "2015-01-05 17:11:39 UTC"
+		t, err := time.Parse("2006-01-02 15:04:05 MST", v.CreatedAt)
+		if err != nil {
+			if t, err = time.Parse(time.RFC3339, v.CreatedAt); err != nil {
+				continue
+			}
+		}
+		c.cache.put(pod, v.Name, t)
+	}
+	if t, ok := c.cache.get(pod, ver); ok {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("kein Datum für %s %s", pod, ver)
+}
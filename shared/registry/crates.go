@@ -0,0 +1,79 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ---------- crates.io ---------------------------------------------------------
+
+// Crates liest crates.io/api/v1/crates/<name>. crates.io verlangt einen
+// aussagekräftigen User-Agent.
+type Crates struct {
+	HTTP  *http.Client
+	cache cache
+}
+
+type cratesResp struct {
+	Versions []struct {
+		Num       string    `json:"num"`
+		CreatedAt time.Time `json:"created_at"`
+	} `json:"versions"`
+}
+
+// ReleaseTime implementiert Client.
+func (c *Crates) ReleaseTime(pkg, ver string) (time.Time, error) {
+	m, err := c.load(pkg)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if t, ok := m[ver]; ok {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("kein Datum für %s %s", pkg, ver)
+}
+
+// Versions implementiert Lister (ohne zurückgezogene Versionen zu filtern).
+func (c *Crates) Versions(pkg string) ([]string, error) {
+	m, err := c.load(pkg)
+	return keys(m), err
+}
+
+func (c *Crates) load(pkg string) (map[string]time.Time, error) {
+	if c.cache == nil {
+		c.cache = cache{}
+	}
+	if m, ok := c.cache[pkg]; ok {
+		return m, nil
+	}
+	req, err := http.NewRequest("GET", "https://crates.io/api/v1/crates/"+url.PathEscape(pkg), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "baa_fs25 (https://github.com/mauricexmaier/baa_fs25)")
+	hc := c.HTTP
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("crates.io %s", resp.Status)
+	}
+	var cr cratesResp
+	if err := json.NewDecoder(resp.Body).Decode(&cr); err != nil {
+		return nil, err
+	}
+	m := map[string]time.Time{}
+	for _, v := range cr.Versions {
+		m[v.Num] = v.CreatedAt
+	}
+	c.cache[pkg] = m
+	return m, nil
+}
@@ -0,0 +1,279 @@
+package registry
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrRateLimited meldet ein erschöpftes GitHub-Rate-Limit (403/429).
+var ErrRateLimited = errors.New("GitHub-Rate-Limit erreicht")
+
+// ErrNotFound meldet 404 (z. B. Datei existiert in diesem Commit nicht).
+var ErrNotFound = errors.New("nicht gefunden")
+
+// GitHub löst Tag-Daten über die GitHub-REST-API auf. pkg ist "owner/repo",
+// ver der Versions-String; probiert werden die Tags ver und "v"+ver.
+// Bei annotierten Tags zählt das Tag-Datum, sonst das Commit-Datum.
+type GitHub struct {
+	HTTP  *http.Client
+	Token string // optional, erhöht das Rate-Limit
+	cache cache
+}
+
+// ReleaseTime implementiert Client.
+func (c *GitHub) ReleaseTime(slug, ver string) (time.Time, error) {
+	if c.cache == nil {
+		c.cache = cache{}
+	}
+	if t, ok := c.cache.get(slug, ver); ok {
+		return t, nil
+	}
+	for _, tag := range []string{ver, "v" + ver} {
+		t, err := c.TagTime(slug, tag)
+		if err == nil {
+			c.cache.put(slug, ver, t)
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("kein Tag %s für %s", ver, slug)
+}
+
+// TagTime liefert das Datum eines konkreten Tags.
+func (c *GitHub) TagTime(slug, tag string) (time.Time, error) {
+	var ref struct {
+		Object struct {
+			Type string `json:"type"`
+			SHA  string `json:"sha"`
+		} `json:"object"`
+	}
+	if err := c.get(fmt.Sprintf("repos/%s/git/ref/tags/%s", slug, url.PathEscape(tag)), &ref); err != nil {
+		return time.Time{}, err
+	}
+	switch ref.Object.Type {
+	case "tag":
+		var t struct {
+			Tagger struct {
+				Date time.Time `json:"date"`
+			} `json:"tagger"`
+		}
+		if err := c.get(fmt.Sprintf("repos/%s/git/tags/%s", slug, ref.Object.SHA), &t); err != nil {
+			return time.Time{}, err
+		}
+		return t.Tagger.Date, nil
+	case "commit":
+		return c.CommitTime(slug, ref.Object.SHA)
+	}
+	return time.Time{}, fmt.Errorf("unerwarteter Ref-Typ %q", ref.Object.Type)
+}
+
+// CommitTime liefert das Committer-Datum eines Commits.
+func (c *GitHub) CommitTime(slug, sha string) (time.Time, error) {
+	var cm struct {
+		Commit struct {
+			Committer struct {
+				Date time.Time `json:"date"`
+			} `json:"committer"`
+		} `json:"commit"`
+	}
+	if err := c.get(fmt.Sprintf("repos/%s/commits/%s", slug, sha), &cm); err != nil {
+		return time.Time{}, err
+	}
+	return cm.Commit.Committer.Date, nil
+}
+
+func (c *GitHub) get(path string, v any) error {
+	resp, err := c.do(path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// do führt GET path aus; Antworten außer 200 werden zu Fehlern.
+func (c *GitHub) do(path string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", "https://api.github.com/"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	hc := c.HTTP
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		err = fmt.Errorf("github %s: %w", path, ErrNotFound)
+	case resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0":
+		err = fmt.Errorf("github %s: %s: %w", path, resp.Status, ErrRateLimited)
+	case resp.StatusCode != 200:
+		err = fmt.Errorf("github %s: %s", path, resp.Status)
+	}
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Commit ist ein Eintrag der Commit-Liste.
+type Commit struct {
+	SHA    string `json:"sha"`
+	Commit struct {
+		Author    Signature `json:"author"`
+		Committer Signature `json:"committer"`
+		Message   string    `json:"message"`
+	} `json:"commit"`
+}
+
+// Signature ist Autor bzw. Committer eines Commits.
+type Signature struct {
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	Date  time.Time `json:"date"`
+}
+
+// Commits listet die Commits des Default-Branches, die file berühren
+// (jüngster zuerst, alle Seiten). since ist optional.
+func (c *GitHub) Commits(slug, file string, since *time.Time) ([]Commit, error) {
+	q := url.Values{"path": {file}, "per_page": {"100"}}
+	if since != nil {
+		q.Set("since", since.UTC().Format(time.RFC3339))
+	}
+	var all []Commit
+	for page := 1; ; page++ {
+		q.Set("page", fmt.Sprint(page))
+		var cs []Commit
+		if err := c.get(fmt.Sprintf("repos/%s/commits?%s", slug, q.Encode()), &cs); err != nil {
+			return nil, err
+		}
+		all = append(all, cs...)
+		if len(cs) < 100 {
+			return all, nil
+		}
+	}
+}
+
+// Files listet alle Dateipfade im Tree von ref (rekursiv). truncated meldet,
+// dass GitHub die Liste bei sehr großen Repos gekürzt hat.
+func (c *GitHub) Files(slug, ref string) (files []string, truncated bool, err error) {
+	var t struct {
+		Tree []struct {
+			Path string `json:"path"`
+			Type string `json:"type"`
+		} `json:"tree"`
+		Truncated bool `json:"truncated"`
+	}
+	if err := c.get(fmt.Sprintf("repos/%s/git/trees/%s?recursive=1", slug, url.PathEscape(ref)), &t); err != nil {
+		return nil, false, err
+	}
+	for _, e := range t.Tree {
+		if e.Type == "blob" {
+			files = append(files, e.Path)
+		}
+	}
+	return files, t.Truncated, nil
+}
+
+// Contents liest file im Stand von ref. Existiert die Datei dort nicht,
+// ist der Fehler ErrNotFound.
+func (c *GitHub) Contents(slug, file, ref string) ([]byte, error) {
+	var f struct {
+		Encoding string `json:"encoding"`
+		Content  string `json:"content"`
+	}
+	p := fmt.Sprintf("repos/%s/contents/%s?ref=%s", slug, escapePath(file), url.QueryEscape(ref))
+	if err := c.get(p, &f); err != nil {
+		return nil, err
+	}
+	if f.Encoding != "base64" {
+		return nil, fmt.Errorf("github %s: Encoding %q nicht unterstützt (Datei > 1 MB?)", p, f.Encoding)
+	}
+	return base64.StdEncoding.DecodeString(strings.ReplaceAll(f.Content, "\n", ""))
+}
+
+func escapePath(p string) string {
+	parts := strings.Split(p, "/")
+	for i, s := range parts {
+		parts[i] = url.PathEscape(s)
+	}
+	return strings.Join(parts, "/")
+}
+
+// PullRequest ist der Ausschnitt eines Pull Requests, den die Tools brauchen.
+type PullRequest struct {
+	Number int `json:"number"`
+	User   struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	CreatedAt time.Time  `json:"created_at"`
+	MergedAt  *time.Time `json:"merged_at"`
+}
+
+// PullRequest liefert PR number.
+func (c *GitHub) PullRequest(slug string, number int) (PullRequest, error) {
+	var pr PullRequest
+	err := c.get(fmt.Sprintf("repos/%s/pulls/%d", slug, number), &pr)
+	return pr, err
+}
+
+// CommitPulls liefert die PRs, zu denen ein Commit gehört (z. B. den
+// Squash-Merge eines Bot-PRs ohne Nummer in der Commit-Message).
+func (c *GitHub) CommitPulls(slug, sha string) ([]PullRequest, error) {
+	var prs []PullRequest
+	err := c.get(fmt.Sprintf("repos/%s/commits/%s/pulls", slug, sha), &prs)
+	return prs, err
+}
+
+// Repo sind die Stammdaten eines Repositorys.
+type Repo struct {
+	Stars         int       `json:"stargazers_count"`
+	Language      string    `json:"language"`
+	CreatedAt     time.Time `json:"created_at"`
+	DefaultBranch string    `json:"default_branch"`
+	Fork          bool      `json:"fork"`
+	Archived      bool      `json:"archived"`
+}
+
+// Repo liefert die Stammdaten von slug.
+func (c *GitHub) Repo(slug string) (Repo, error) {
+	var r Repo
+	err := c.get("repos/"+slug, &r)
+	return r, err
+}
+
+var lastPageRx = regexp.MustCompile(`[?&]page=(\d+)>; rel="last"`)
+
+// Contributors zählt die Contributors von slug (inkl. anonymer). Bei einer
+// Seite pro Eintrag ergibt die letzte Seite im Link-Header die Anzahl.
+func (c *GitHub) Contributors(slug string) (int, error) {
+	resp, err := c.do("repos/" + slug + "/contributors?per_page=1&anon=1")
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if m := lastPageRx.FindStringSubmatch(resp.Header.Get("Link")); m != nil {
+		return strconv.Atoi(m[1])
+	}
+	var list []json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return 0, err
+	}
+	return len(list), nil
+}
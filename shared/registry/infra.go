@@ -0,0 +1,159 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ---------- Docker Hub --------------------------------------------------------
+
+// DockerHub fragt hub.docker.com/v2/repositories/<ns>/<name>/tags/<tag> ab.
+// pkg ist eine Image-Referenz ohne Tag ("nginx", "bitnami/redis",
+// "docker.io/library/nginx"); andere Registries werden nicht unterstützt.
+// Achtung: Docker Hub kennt nur den letzten Push eines Tags.
+type DockerHub struct {
+	HTTP  *http.Client
+	cache cache
+}
+
+// DockerHubRepo normalisiert eine Image-Referenz auf "<ns>/<name>".
+func DockerHubRepo(image string) (string, error) {
+	parts := strings.Split(image, "/")
+	if len(parts) > 1 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		if parts[0] != "docker.io" && parts[0] != "index.docker.io" {
+			return "", fmt.Errorf("Registry %s nicht unterstützt", parts[0])
+		}
+		parts = parts[1:]
+	}
+	if len(parts) == 1 {
+		parts = append([]string{"library"}, parts...)
+	}
+	return strings.Join(parts, "/"), nil
+}
+
+// ReleaseTime implementiert Client.
+func (c *DockerHub) ReleaseTime(image, tag string) (time.Time, error) {
+	if c.cache == nil {
+		c.cache = cache{}
+	}
+	repo, err := DockerHubRepo(image)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if t, ok := c.cache.get(repo, tag); ok {
+		return t, nil
+	}
+	body, resp, err := fetch(c.HTTP, fmt.Sprintf("https://hub.docker.com/v2/repositories/%s/tags/%s", repo, url.PathEscape(tag)))
+	if err != nil {
+		return time.Time{}, err
+	}
+	if resp.StatusCode != 200 {
+		return time.Time{}, fmt.Errorf("docker hub %s", resp.Status)
+	}
+	var tr struct {
+		LastUpdated   time.Time `json:"last_updated"`
+		TagLastPushed time.Time `json:"tag_last_pushed"`
+	}
+	if err := json.Unmarshal(body, &tr); err != nil {
+		return time.Time{}, err
+	}
+	t := tr.TagLastPushed
+	if t.IsZero() {
+		t = tr.LastUpdated
+	}
+	if t.IsZero() {
+		return time.Time{}, fmt.Errorf("kein Datum für %s:%s", image, tag)
+	}
+	c.cache.put(repo, tag, t)
+	return t, nil
+}
+
+// ---------- Artifact Hub ------------------------------------------------------
+
+// ArtifactHub löst Helm-Chart-Versionen über artifacthub.io auf. pkg hat die
+// Form "<repository-url>/<chart>", wie in Chart.yaml angegeben. Der Artifact-
+// Hub-Name des Repos wird über die Paketsuche anhand der URL ermittelt.
+type ArtifactHub struct {
+	HTTP  *http.Client
+	repos map[string]string // "<url>/<chart>" → AH-Repo-Name
+	cache cache
+}
+
+// ReleaseTime implementiert Client.
+func (c *ArtifactHub) ReleaseTime(pkg, ver string) (time.Time, error) {
+	if c.cache == nil {
+		c.cache = cache{}
+		c.repos = map[string]string{}
+	}
+	if t, ok := c.cache.get(pkg, ver); ok {
+		return t, nil
+	}
+	i := strings.LastIndex(pkg, "/")
+	if i < 0 {
+		return time.Time{}, fmt.Errorf("Chart ohne Repository: %s", pkg)
+	}
+	repoURL, chart := pkg[:i], pkg[i+1:]
+	ahRepo, err := c.repoName(repoURL, chart)
+	if err != nil {
+		return time.Time{}, err
+	}
+	body, resp, err := fetch(c.HTTP, fmt.Sprintf("https://artifacthub.io/api/v1/packages/helm/%s/%s/%s",
+		url.PathEscape(ahRepo), url.PathEscape(chart), url.PathEscape(ver)))
+	if err != nil {
+		return time.Time{}, err
+	}
+	if resp.StatusCode != 200 {
+		return time.Time{}, fmt.Errorf("artifact hub %s", resp.Status)
+	}
+	var p struct {
+		TS int64 `json:"ts"`
+	}
+	if err := json.Unmarshal(body, &p); err != nil {
+		return time.Time{}, err
+	}
+	t := time.Unix(p.TS, 0).UTC()
+	c.cache.put(pkg, ver, t)
+	return t, nil
+}
+
+func (c *ArtifactHub) repoName(repoURL, chart string) (string, error) {
+	key := repoURL + "/" + chart
+	if n, ok := c.repos[key]; ok {
+		if n == "" {
+			return "", fmt.Errorf("kein Artifact-Hub-Repo für %s", key)
+		}
+		return n, nil
+	}
+	body, resp, err := fetch(c.HTTP, "https://artifacthub.io/api/v1/packages/search?kind=0&limit=60&ts_query_web="+url.QueryEscape(chart))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("artifact hub %s", resp.Status)
+	}
+	var sr struct {
+		Packages []struct {
+			Name       string `json:"name"`
+			Repository struct {
+				Name string `json:"name"`
+				URL  string `json:"url"`
+			} `json:"repository"`
+		} `json:"packages"`
+	}
+	if err := json.Unmarshal(body, &sr); err != nil {
+		return "", err
+	}
+	want := strings.TrimSuffix(repoURL, "/")
+	c.repos[key] = ""
+	for _, p := range sr.Packages {
+		if p.Name == chart && strings.TrimSuffix(p.Repository.URL, "/") == want {
+			c.repos[key] = p.Repository.Name
+			return p.Repository.Name, nil
+		}
+	}
+	return "", fmt.Errorf("kein Artifact-Hub-Repo für %s", key)
+}
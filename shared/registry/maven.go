@@ -0,0 +1,93 @@
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ---------- Maven Central -----------------------------------------------------
+
+// Maven fragt die Suche von Maven Central (search.maven.org, Core "gav") ab;
+// pkg hat die OSV-Form "groupId:artifactId". Der Zeitstempel ist der
+// Zeitpunkt, zu dem die Version in Central indiziert wurde.
+type Maven struct {
+	HTTP  *http.Client
+	cache cache
+}
+
+type mavenResp struct {
+	Response struct {
+		NumFound int `json:"numFound"`
+		Docs     []struct {
+			V         string `json:"v"`
+			Timestamp int64  `json:"timestamp"` // Millisekunden
+		} `json:"docs"`
+	} `json:"response"`
+}
+
+// mavenRows ist die Seitengröße der Suche (Maximum von search.maven.org).
+const mavenRows = 200
+
+// ReleaseTime implementiert Client.
+func (c *Maven) ReleaseTime(pkg, ver string) (time.Time, error) {
+	m, err := c.load(pkg)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if t, ok := m[ver]; ok {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("kein Datum für %s:%s", pkg, ver)
+}
+
+// Versions implementiert Lister.
+func (c *Maven) Versions(pkg string) ([]string, error) {
+	m, err := c.load(pkg)
+	return keys(m), err
+}
+
+func (c *Maven) load(pkg string) (map[string]time.Time, error) {
+	if c.cache == nil {
+		c.cache = cache{}
+	}
+	if m, ok := c.cache[pkg]; ok {
+		return m, nil
+	}
+	group, artifact, ok := strings.Cut(pkg, ":")
+	if !ok {
+		return nil, fmt.Errorf("Maven-Paket ohne groupId: %s", pkg)
+	}
+	q := fmt.Sprintf(`g:"%s" AND a:"%s"`, group, artifact)
+	m := map[string]time.Time{}
+	for start := 0; ; start += mavenRows {
+		u := fmt.Sprintf("https://search.maven.org/solrsearch/select?core=gav&wt=json&rows=%d&start=%d&q=%s",
+			mavenRows, start, url.QueryEscape(q))
+		body, resp, err := fetch(c.HTTP, u)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != 200 {
+			return nil, fmt.Errorf("search.maven.org %s", resp.Status)
+		}
+		var mr mavenResp
+		if err := json.Unmarshal(body, &mr); err != nil {
+			return nil, err
+		}
+		for _, d := range mr.Response.Docs {
+			m[d.V] = time.UnixMilli(d.Timestamp).UTC()
+		}
+		if len(mr.Response.Docs) < mavenRows || start+mavenRows >= mr.Response.NumFound {
+			break
+		}
+	}
+	if len(m) == 0 {
+		return nil, errors.New("nicht in Maven Central")
+	}
+	c.cache[pkg] = m
+	return m, nil
+}
@@ -0,0 +1,412 @@
+// Package registry löst Release-Zeitpunkte von Paketversionen über die
+// Registries der Ökosysteme auf (npm, proxy.golang.org, PyPI, anaconda.org,
+// CocoaPods trunk, Docker Hub, Artifact Hub, Terraform Registry, Maven
+// Central, crates.io) bzw. über GitHub-Tags.
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Client liefert den Veröffentlichungszeitpunkt einer Paketversion.
+type Client interface {
+	ReleaseTime(pkg, ver string) (time.Time, error)
+}
+
+// Lister ist optional: Registries, die alle veröffentlichten Versionen eines
+// Pakets kennen (für "erste verfügbare Version" und übersprungene Releases).
+type Lister interface {
+	Versions(pkg string) ([]string, error)
+}
+
+// Deprecator ist optional: Registries, die einzelne Versionen als veraltet
+// kennzeichnen (npm "deprecated", PyPI yanked bzw. Classifier "7 - Inactive").
+// Ohne Hinweis ist die Meldung "".
+type Deprecator interface {
+	Deprecated(pkg, ver string) (string, error)
+}
+
+// LatestReporter ist optional: Registries, die selbst ausweisen, welche
+// Version eines Pakets aktuell ist (PyPI info.version).
+type LatestReporter interface {
+	Latest(pkg string) (string, error)
+}
+
+// StatusError ist eine Registry-Antwort mit einem anderen Status als 200.
+type StatusError struct {
+	API    string // Präfix der Meldung, z. B. "pypi"
+	Code   int
+	Status string
+}
+
+func (e *StatusError) Error() string { return e.API + " " + e.Status }
+
+func statusError(api string, resp *http.Response) error {
+	return &StatusError{API: api, Code: resp.StatusCode, Status: resp.Status}
+}
+
+// cache hält bereits aufgelöste Zeitpunkte je Paket und Version.
+type cache map[string]map[string]time.Time
+
+func (c cache) get(pkg, ver string) (time.Time, bool) {
+	t, ok := c[pkg][ver]
+	return t, ok
+}
+
+func (c cache) put(pkg, ver string, t time.Time) {
+	if _, ok := c[pkg]; !ok {
+		c[pkg] = map[string]time.Time{}
+	}
+	c[pkg][ver] = t
+}
+
+func fetch(hc *http.Client, url string) ([]byte, *http.Response, error) {
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Get(url)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	return body, resp, err
+}
+
+// ---------- npm ---------------------------------------------------------------
+
+// NPM liest registry.npmjs.org/<pkg> und cached alle Zeitpunkte und
+// Deprecation-Hinweise eines Pakets.
+type NPM struct {
+	HTTP  *http.Client
+	cache cache
+	notes map[string]map[string]string
+}
+
+type npmMeta struct {
+	Time     map[string]string `json:"time"`
+	Versions map[string]struct {
+		Deprecated json.RawMessage `json:"deprecated"` // Hinweis oder false
+	} `json:"versions"`
+}
+
+// ReleaseTime implementiert Client.
+func (c *NPM) ReleaseTime(pkg, ver string) (time.Time, error) {
+	m, err := c.load(pkg)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if t, ok := m[ver]; ok {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("kein Datum für %s@%s", pkg, ver)
+}
+
+// Versions implementiert Lister.
+func (c *NPM) Versions(pkg string) ([]string, error) {
+	m, err := c.load(pkg)
+	return keys(m), err
+}
+
+// Deprecated implementiert Deprecator.
+func (c *NPM) Deprecated(pkg, ver string) (string, error) {
+	if _, err := c.load(pkg); err != nil {
+		return "", err
+	}
+	return c.notes[pkg][ver], nil
+}
+
+// load holt alle Zeitpunkte eines Pakets (ohne "created"/"modified").
+func (c *NPM) load(pkg string) (map[string]time.Time, error) {
+	if c.cache == nil {
+		c.cache, c.notes = cache{}, map[string]map[string]string{}
+	}
+	if m, ok := c.cache[pkg]; ok {
+		return m, nil
+	}
+	body, resp, err := fetch(c.HTTP, fmt.Sprintf("https://registry.npmjs.org/%s", pkg))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		return nil, statusError("npm api status", resp)
+	}
+	var meta npmMeta
+	if err := json.Unmarshal(body, &meta); err != nil {
+		return nil, err
+	}
+	c.cache[pkg] = map[string]time.Time{}
+	for v, raw := range meta.Time {
+		if v == "created" || v == "modified" {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			c.cache.put(pkg, v, t)
+		}
+	}
+	c.notes[pkg] = map[string]string{}
+	for v, info := range meta.Versions {
+		var msg string
+		if json.Unmarshal(info.Deprecated, &msg) == nil && msg != "" {
+			c.notes[pkg][v] = msg
+		}
+	}
+	return c.cache[pkg], nil
+}
+
+// ---------- Go ----------------------------------------------------------------
+
+// GoProxy fragt proxy.golang.org/<module>/@v/<ver>.info ab.
+type GoProxy struct {
+	HTTP  *http.Client
+	cache cache
+	lists map[string][]string
+}
+
+type goInfo struct {
+	Time time.Time `json:"Time"`
+}
+
+// ReleaseTime implementiert Client.
+func (c *GoProxy) ReleaseTime(module, ver string) (time.Time, error) {
+	if c.cache == nil {
+		c.cache = cache{}
+	}
+	if t, ok := c.cache.get(module, ver); ok {
+		return t, nil
+	}
+	body, resp, err := fetch(c.HTTP, fmt.Sprintf("https://proxy.golang.org/%s/@v/%s.info", module, ver))
+	if err != nil {
+		return time.Time{}, err
+	}
+	if resp.StatusCode != 200 {
+		return time.Time{}, fmt.Errorf("proxy %s", resp.Status)
+	}
+	var info goInfo
+	if err := json.Unmarshal(body, &info); err != nil {
+		return time.Time{}, err
+	}
+	c.cache.put(module, ver, info.Time)
+	return info.Time, nil
+}
+
+// Versions implementiert Lister über <module>/@v/list (nur getaggte
+// Versionen, keine Pseudo-Versionen).
+func (c *GoProxy) Versions(module string) ([]string, error) {
+	if l, ok := c.lists[module]; ok {
+		return l, nil
+	}
+	body, resp, err := fetch(c.HTTP, fmt.Sprintf("https://proxy.golang.org/%s/@v/list", module))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("proxy %s", resp.Status)
+	}
+	if c.lists == nil {
+		c.lists = map[string][]string{}
+	}
+	c.lists[module] = strings.Fields(string(body))
+	return c.lists[module], nil
+}
+
+// ---------- PyPI --------------------------------------------------------------
+
+// PyPI liest pypi.org/pypi/<pkg>/json; Paketnamen werden kleingeschrieben.
+type PyPI struct {
+	HTTP   *http.Client
+	cache  cache
+	latest map[string]string
+	notes  map[string]map[string]string
+}
+
+type pypiResp struct {
+	Info struct {
+		Version     string   `json:"version"`
+		Classifiers []string `json:"classifiers"`
+	} `json:"info"`
+	Releases map[string][]struct {
+		UploadTimeISO8601 string `json:"upload_time_iso_8601"`
+		Yanked            bool   `json:"yanked"`
+		YankedReason      string `json:"yanked_reason"`
+	} `json:"releases"`
+}
+
+// ReleaseTime implementiert Client.
+func (c *PyPI) ReleaseTime(pkg, ver string) (time.Time, error) {
+	m, err := c.load(pkg)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if t, ok := m[ver]; ok {
+		return t, nil
+	}
+	return time.Time{}, errors.New("keine uploads")
+}
+
+// Versions implementiert Lister (nur Releases mit Uploads).
+func (c *PyPI) Versions(pkg string) ([]string, error) {
+	m, err := c.load(pkg)
+	return keys(m), err
+}
+
+// Latest implementiert LatestReporter.
+func (c *PyPI) Latest(pkg string) (string, error) {
+	if _, err := c.load(pkg); err != nil {
+		return "", err
+	}
+	return c.latest[strings.ToLower(pkg)], nil
+}
+
+// Deprecated implementiert Deprecator: gelöschte (yanked) Version mit Grund,
+// sonst "inactive" für Projekte mit Classifier "7 - Inactive".
+func (c *PyPI) Deprecated(pkg, ver string) (string, error) {
+	if _, err := c.load(pkg); err != nil {
+		return "", err
+	}
+	return c.notes[strings.ToLower(pkg)][ver], nil
+}
+
+// load holt die Zeitpunkte aller Releases (erster Upload je Release).
+func (c *PyPI) load(pkg string) (map[string]time.Time, error) {
+	if c.cache == nil {
+		c.cache, c.latest, c.notes = cache{}, map[string]string{}, map[string]map[string]string{}
+	}
+	pkg = strings.ToLower(pkg)
+	if m, ok := c.cache[pkg]; ok {
+		return m, nil
+	}
+	body, resp, err := fetch(c.HTTP, fmt.Sprintf("https://pypi.org/pypi/%s/json", pkg))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		return nil, statusError("pypi", resp)
+	}
+	var pr pypiResp
+	if err := json.Unmarshal(body, &pr); err != nil {
+		return nil, err
+	}
+	c.cache[pkg] = map[string]time.Time{}
+	c.latest[pkg] = pr.Info.Version
+	c.notes[pkg] = map[string]string{}
+	inactive := slices.Contains(pr.Info.Classifiers, "Development Status :: 7 - Inactive")
+	for v, uploads := range pr.Releases {
+		if len(uploads) == 0 {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339, uploads[0].UploadTimeISO8601); err == nil {
+			c.cache.put(pkg, v, t)
+		}
+		switch {
+		case uploads[0].Yanked:
+			c.notes[pkg][v] = strings.TrimSpace("yanked " + uploads[0].YankedReason)
+		case inactive:
+			c.notes[pkg][v] = "inactive"
+		}
+	}
+	return c.cache[pkg], nil
+}
+
+// ---------- Conda -------------------------------------------------------------
+
+// Conda fragt api.anaconda.org/package/<kanal>/<name> ab. pkg hat die
+// conda-übliche Form "kanal::name". Da jede Version mehrere Builds hat, gilt
+// der früheste Upload einer Version als Release-Zeitpunkt.
+type Conda struct {
+	HTTP  *http.Client
+	cache cache
+}
+
+type condaResp struct {
+	Files []struct {
+		Version    string `json:"version"`
+		UploadTime string `json:"upload_time"`
+	} `json:"files"`
+}
+
+var condaTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999-07:00",
+	"2006-01-02 15:04:05-07:00",
+	time.RFC3339,
+}
+
+// ReleaseTime implementiert Client.
+func (c *Conda) ReleaseTime(pkg, ver string) (time.Time, error) {
+	if c.cache == nil {
+		c.cache = cache{}
+	}
+	channel, name, ok := strings.Cut(pkg, "::")
+	if !ok {
+		return time.Time{}, fmt.Errorf("conda-Paket ohne Kanal: %s", pkg)
+	}
+	if m, ok := c.cache[pkg]; ok {
+		if t, ok2 := m[ver]; ok2 {
+			return t, nil
+		}
+		return time.Time{}, fmt.Errorf("kein Datum für %s=%s", pkg, ver)
+	}
+	body, resp, err := fetch(c.HTTP, fmt.Sprintf("https://api.anaconda.org/package/%s/%s", channel, name))
+	if err != nil {
+		return time.Time{}, err
+	}
+	if resp.StatusCode != 200 {
+		return time.Time{}, fmt.Errorf("anaconda.org %s", resp.Status)
+	}
+	var cr condaResp
+	if err := json.Unmarshal(body, &cr); err != nil {
+		return time.Time{}, err
+	}
+	c.cache[pkg] = map[string]time.Time{}
+	for _, f := range cr.Files {
+		t, ok := parseCondaTime(f.UploadTime)
+		if !ok {
+			continue
+		}
+		if old, seen := c.cache.get(pkg, f.Version); !seen || t.Before(old) {
+			c.cache.put(pkg, f.Version, t)
+		}
+	}
+	if t, ok := c.cache.get(pkg, ver); ok {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("kein Datum für %s=%s", pkg, ver)
+}
+
+// Versions implementiert Lister.
+func (c *Conda) Versions(pkg string) ([]string, error) {
+	if _, ok := c.cache[pkg]; !ok {
+		// lädt die Versionsliste; ein fehlendes "" ist kein Fehler
+		if _, err := c.ReleaseTime(pkg, ""); err != nil && c.cache[pkg] == nil {
+			return nil, err
+		}
+	}
+	return keys(c.cache[pkg]), nil
+}
+
+func parseCondaTime(s string) (time.Time, bool) {
+	for _, l := range condaTimeLayouts {
+		if t, err := time.Parse(l, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// keys liefert die Versionen einer Zeitpunkt-Map, sortiert.
+func keys(m map[string]time.Time) []string {
+	out := make([]string, 0, len(m))
+	for v := range m {
+		out = append(out, v)
+	}
+	sort.Strings(out)
+	return out
+}
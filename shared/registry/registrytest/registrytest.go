@@ -0,0 +1,29 @@
+// Package registrytest stellt einen festen registry.Client für Tests bereit.
+package registrytest
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Static ist ein fester Client (Paket → Version → Zeitpunkt).
+type Static map[string]map[string]time.Time
+
+// ReleaseTime implementiert registry.Client.
+func (s Static) ReleaseTime(pkg, ver string) (time.Time, error) {
+	if t, ok := s[pkg][ver]; ok {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("kein Datum für %s@%s", pkg, ver)
+}
+
+// Versions implementiert registry.Lister.
+func (s Static) Versions(pkg string) ([]string, error) {
+	out := make([]string, 0, len(s[pkg]))
+	for v := range s[pkg] {
+		out = append(out, v)
+	}
+	sort.Strings(out)
+	return out, nil
+}
@@ -0,0 +1,59 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Terraform fragt die Terraform Registry ab. pkg ist entweder eine
+// Provider-Adresse "namespace/type" oder eine Modul-Adresse
+// "namespace/name/provider", jeweils optional mit Host davor
+// (Default registry.terraform.io).
+type Terraform struct {
+	HTTP  *http.Client
+	cache cache
+}
+
+// ReleaseTime implementiert Client.
+func (c *Terraform) ReleaseTime(addr, ver string) (time.Time, error) {
+	if c.cache == nil {
+		c.cache = cache{}
+	}
+	if t, ok := c.cache.get(addr, ver); ok {
+		return t, nil
+	}
+	host, parts := "registry.terraform.io", strings.Split(addr, "/")
+	if len(parts) > 0 && strings.Contains(parts[0], ".") {
+		host, parts = parts[0], parts[1:]
+	}
+	var u string
+	switch len(parts) {
+	case 2:
+		u = fmt.Sprintf("https://%s/v1/providers/%s/%s/%s", host, parts[0], parts[1], ver)
+	case 3:
+		u = fmt.Sprintf("https://%s/v1/modules/%s/%s/%s/%s", host, parts[0], parts[1], parts[2], ver)
+	default:
+		return time.Time{}, fmt.Errorf("unbekannte Terraform-Adresse %q", addr)
+	}
+	body, resp, err := fetch(c.HTTP, u)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if resp.StatusCode != 200 {
+		return time.Time{}, fmt.Errorf("terraform registry %s", resp.Status)
+	}
+	var r struct {
+		PublishedAt time.Time `json:"published_at"`
+	}
+	if err := json.Unmarshal(body, &r); err != nil {
+		return time.Time{}, err
+	}
+	if r.PublishedAt.IsZero() {
+		return time.Time{}, fmt.Errorf("kein Datum für %s %s", addr, ver)
+	}
+	c.cache.put(addr, ver, r.PublishedAt)
+	return r.PublishedAt, nil
+}
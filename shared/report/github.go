@@ -0,0 +1,108 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// PRComment beschreibt einen Kommentar, den ein Tool im CI an einen Pull
+// Request hängt. Marker (ein HTML-Kommentar im Body) identifiziert den
+// Kommentar, damit spätere Läufe ihn aktualisieren statt neue anzulegen.
+type PRComment struct {
+	Repo   string // owner/repo; leer = $GITHUB_REPOSITORY
+	PR     int
+	Marker string
+	Body   string // Markdown ohne Marker
+	HTTP   *http.Client
+}
+
+// Post legt den Kommentar an oder aktualisiert ihn. Benötigt GITHUB_TOKEN.
+func (c PRComment) Post() error {
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		return errors.New("GITHUB_TOKEN nicht gesetzt")
+	}
+	if c.Repo == "" {
+		c.Repo = os.Getenv("GITHUB_REPOSITORY")
+	}
+	if c.Repo == "" || c.PR <= 0 {
+		return fmt.Errorf("Repo (%q) und PR-Nummer (%d) benötigt", c.Repo, c.PR)
+	}
+	hc := c.HTTP
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	tag := "<!-- " + c.Marker + " -->"
+	body := tag + "\n" + c.Body
+
+	api := "https://api.github.com/repos/" + c.Repo + "/issues"
+	for page := 1; ; page++ {
+		var comments []struct {
+			ID   int64  `json:"id"`
+			Body string `json:"body"`
+		}
+		url := fmt.Sprintf("%s/%d/comments?per_page=100&page=%d", api, c.PR, page)
+		if err := ghDo(hc, token, "GET", url, nil, &comments); err != nil {
+			return err
+		}
+		for _, cm := range comments {
+			if strings.HasPrefix(cm.Body, tag) {
+				return ghDo(hc, token, "PATCH", fmt.Sprintf("%s/comments/%d", api, cm.ID), map[string]string{"body": body}, nil)
+			}
+		}
+		if len(comments) < 100 {
+			break
+		}
+	}
+	return ghDo(hc, token, "POST", fmt.Sprintf("%s/%d/comments", api, c.PR), map[string]string{"body": body}, nil)
+}
+
+func ghDo(hc *http.Client, token, method, url string, in, out any) error {
+	var rd io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		rd = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, url, rd)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%s %s: %s", method, url, resp.Status)
+	}
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
+
+// ReadJSON liest ein mit WriteJSON geschriebenes Dokument, z. B. das Ergebnis
+// des Basis-Branches für einen Vergleich.
+func ReadJSON(path string, v any) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// Delta formatiert die Differenz zweier Werte mit Vorzeichen.
+func Delta(base, cur float64, prec int) string {
+	return fmt.Sprintf("%+.*f", prec, cur-base)
+}
@@ -0,0 +1,24 @@
+// Package report enthält Hilfen für maschinenlesbare Ausgaben der Tools.
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+)
+
+// WriteJSON schreibt v eingerückt als JSON nach path. "-" steht für stdout.
+func WriteJSON(path string, v any) error {
+	var w io.Writer = os.Stdout
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,38 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Webhook postet Nachrichten an einen Incoming Webhook. Der Payload
+// {"text": ...} wird von Slack und Microsoft Teams gleichermaßen
+// verstanden; Markdown wird von beiden in Grundzügen gerendert.
+type Webhook struct {
+	URL  string
+	HTTP *http.Client
+}
+
+// Post sendet text als Nachricht.
+func (w Webhook) Post(text string) error {
+	hc := w.HTTP
+	if hc == nil {
+		hc = &http.Client{Timeout: 15 * time.Second}
+	}
+	b, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+	resp, err := hc.Post(w.URL, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("Webhook: %s", resp.Status)
+	}
+	return nil
+}
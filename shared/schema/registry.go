@@ -0,0 +1,158 @@
+package schema
+
+// provenance und schemaID stehen in fast jeder Ausgabe.
+var (
+	schemaID   = Field{"schema", "string", "Bezeichner <name>/<version> dieser Ausgabe"}
+	provenance = Field{"provenance", "object", "Tool-Version, Flags, Endpunkte und Cache-Quote des Laufs (shared/provenance)"}
+)
+
+// registry listet alle Ausgaben. Neue Version: Eintrag mit Latest()+1 und
+// Changes anhängen, den Schreiber der Ausgabe die alte Form weiter
+// beherrschen lassen (s. Version).
+var registry = []Schema{
+	{
+		Name: "mttu-result", Tool: "mttu",
+		Doc: "--out-JSON von mttu; bei --format jsonl je Update eine Zeile (record=update, Felder wie updates[]) und am Ende record=summary mit diesem Dokument; .gz/.zst komprimiert, mit --chunk-size auf <name>-00001.json, … verteilt",
+		Versions: []Revision{{Version: 1, Fields: []Field{
+			schemaID,
+			{"repo", "string", "Git-URL oder Verzeichnis (--anonymize: gehasht)"},
+			{"repo_meta", "object?", "Repo-Metadaten (nur --repo-meta)"},
+			{"sample", "object?", "Stichprobe (nur --sample)"},
+			{"eco", "string", "Ökosystem(e) (--eco, mehrere kommagetrennt)"},
+			{"tz", "string", "--tz-Policy der Zeitstempel"},
+			{"date", "string", "--date: author | committer"},
+			{"scope", "object", "commits | changes | days"},
+			{"summary", "object", "Mittelwert, Median, Perzentile und Gruppen der Verzögerung in Tagen; major_lag: Major-Versionen zurück mit Verlauf"},
+			{"updates", "array<object>", "ein Eintrag je erkanntem Versionssprung, mit eco; ci_change: Commit ändert auch CI-Konfiguration"},
+			{"skipped_specs", "array<object>?", "Dependencies ohne Registry-Version je Ökosystem mit Grund (npm: git, file, …; alle: unpublished-version)"},
+			{"chunk", "object?", "index und of dieser Teildatei (nur --chunk-size)"},
+			provenance,
+		}}},
+	},
+	{
+		Name: "mttu-dry-run", Tool: "mttu",
+		Doc: "--out-JSON von mttu --dry-run",
+		Versions: []Revision{{Version: 1, Fields: []Field{
+			schemaID,
+			{"repo", "string", "Git-URL oder Verzeichnis"},
+			{"eco", "string", "Ökosystem(e) (--eco, mehrere kommagetrennt)"},
+			{"scope", "object", "commits | changes | days"},
+			{"commits", "array<object>", "geplante Commits mit eco, Datum, Manifesten und Upgrades"},
+			{"from", "time?", "ältester Commit"},
+			{"to", "time?", "jüngster Commit"},
+			{"manifests", "map<string,int>", "Manifest-Pfad → Anzahl Commits"},
+			{"upgrades", "int", "erkannte Versionssprünge"},
+			{"sample", "object?", "Stichprobe (nur --sample)"},
+			provenance,
+		}}},
+	},
+	{
+		Name: "ttf-result", Tool: "ttf",
+		Doc: "-out-JSON von ttf",
+		Versions: []Revision{{Version: 1, Fields: []Field{
+			schemaID,
+			{"repo", "string", "owner/repo bzw. Paket"},
+			{"source", "string", "Herkunft der Advisories (Dateien, govulndb, OSV)"},
+			{"advisories", "array<object>", "Advisories mit Fix: Tags, Daten, ΔFix/ΔExposure/ΔDisclosure, unfixed_branches, disclosure (silent-fix | fixed-after-disclosure), Downloads der letzten Woche (-popularity)"},
+			{"open", "array<object>?", "Advisories ohne Fix-Version"},
+			{"summary", "object", "Mittelwerte und Zähler der Deltas in Tagen; stille Fixes (vor der Veröffentlichung) getrennt mit Median-Vorlauf; mit -popularity ΔExposure gewichtet nach Downloads"},
+			{"normalized", "object?", "Advisories je KLOC und Dependency (nur -normalize)"},
+			provenance,
+		}}},
+	},
+	{
+		Name: "ttf-osv-enriched", Tool: "ttf",
+		Doc: "-emit-osv: OSV-Records mit database_specific.ttf, wieder mit -json lesbar",
+		Versions: []Revision{{Version: 1, Fields: []Field{
+			schemaID,
+			{"repo", "string", "owner/repo bzw. Paket"},
+			{"source", "string", "Herkunft der Advisories"},
+			{"vulns", "array<object>", "OSV-Records, database_specific.ttf wie advisories[] in ttf-result"},
+			provenance,
+		}}},
+	},
+	{
+		Name: "libyears-result", Tool: "libyears",
+		Doc: "--out-JSON von libyears go|npm|py; bei --format jsonl je Dependency eine Zeile (record=dep bzw. skipped) und am Ende record=summary ohne deps/skipped",
+		Versions: []Revision{{Version: 1, Fields: []Field{
+			schemaID,
+			{"eco", "string", "go | npm | py"},
+			{"source", "array<string>", "ausgewertete Manifeste"},
+			{"deps", "array<object>", "package, current, latest, lag_years, ..."},
+			{"summary", "object", "Summe, Mittel, Median, P90, Max und Anzahl über --threshold"},
+			{"workspaces", "array<object>?", "npm-Workspaces"},
+			{"conflicts", "array<object>?", "py: widersprüchliche Pins"},
+			{"duplicates", "array<object>?", "npm --duplicates: Pakete in mehreren Versionen laut package-lock.json, Lag je Version"},
+			{"exceptions", "array<object>?", "--exceptions: Ausnahmen mit approved_by, expires, state (active | expired | unused) und Treffern; deps[].excepted zählt nicht in summary"},
+			{"groups", "array<object>?", "--group-by scope"},
+			{"kinds", "array<object>?", "npm/py: Zwischensummen je Art"},
+			{"runtime", "array<object>?", "Lag der go-/toolchain-Direktive bzw. --runtime"},
+			{"skipped", "array<object>?", "nicht ausgewertete Dependencies mit Grund"},
+			{"coverage", "float", "ausgewerteter Anteil"},
+			{"as_of", "time?", "Stichtag (--as-of)"},
+			provenance,
+		}}},
+	},
+	{
+		Name: "libyears-batch", Tool: "libyears",
+		Doc: "--out-JSON von libyears batch",
+		Versions: []Revision{
+			{Version: 1, Fields: []Field{
+				{"[]", "array<object>", "eine Zeile je Projekt (project, eco, count, total_lag_years, mean_lag_years, worst, coverage, error, source, provenance)"},
+			}},
+			{Version: 2, Changes: "Dokument statt Array: Zeilen unter projects, dazu schema und die Provenienz des Batch-Laufs", Fields: []Field{
+				schemaID,
+				{"projects", "array<object>", "eine Zeile je Projekt wie in Version 1"},
+				provenance,
+			}},
+		},
+	},
+	{
+		Name: "baa-study", Tool: "baa study",
+		Doc: "ein JSON-Record je Repo in --out",
+		Versions: []Revision{{Version: 1, Fields: []Field{
+			schemaID,
+			{"schema_version", "int", "Version als Zahl (für baa merge)"},
+			{"repo", "string", "Git-URL (--anonymize: gehasht)"},
+			{"commit", "string", "analysierter HEAD"},
+			{"analyzed_at", "time", "Zeitpunkt der Analyse"},
+			{"metrics", "map<string,object>", "Ausgabe je Tool: mttu-result, ttf-result, libyears-result"},
+			{"errors", "map<string,string>?", "Fehler je Tool"},
+			provenance,
+		}}},
+	},
+	{
+		Name: "baa-health", Tool: "baa health",
+		Doc: "--out-JSON von baa health",
+		Versions: []Revision{{Version: 1, Fields: []Field{
+			schemaID,
+			{"schema_version", "int", "Version als Zahl"},
+			{"repo", "string", "Git-URL (--anonymize: gehasht)"},
+			{"commit", "string", "analysierter HEAD"},
+			{"analyzed_at", "time", "Zeitpunkt der Analyse"},
+			{"score", "float?", "Gesamt-Score 0–100"},
+			{"components", "array<object>", "Teil-Scores mit Wert, Einheit und Gewicht"},
+			{"bots", "object", "Dependabot/Renovate-Konfiguration und Bot-Anteil"},
+			{"errors", "map<string,string>?", "Fehler je Tool"},
+			provenance,
+		}}},
+	},
+	{
+		Name: "baa-merge", Tool: "baa merge",
+		Doc: "eine Zeile je Messwert (.parquet, .jsonl, .json); jede Zeile trägt schema_version, Parquet zusätzlich den Bezeichner in den Metadaten (baa.schema)",
+		Versions: []Revision{{Version: 1, Fields: []Field{
+			{"schema_version", "int", "Version als Zahl"},
+			{"repo", "string", "Repo (gehasht)"},
+			{"commit", "string", "Commit (gehasht)"},
+			{"analyzed_at", "timestamp", "Zeitpunkt der Analyse"},
+			{"metric", "string", "mttu | libyears | ttf"},
+			{"dep", "string", "Dependency bzw. Advisory-ID"},
+			{"from", "string", "alte bzw. aktuelle Version, intro_tag"},
+			{"to", "string", "neue bzw. neueste Version, fix_tag"},
+			{"value", "float?", "Tage, lag_years bzw. delta_fix_days"},
+			{"exposure_days", "float?", "ttf: delta_exposure_days"},
+			{"ref", "string", "mttu: Update-Commit (gehasht)"},
+			{"source", "string", "Quelldatei des Records"},
+		}}},
+	},
+}
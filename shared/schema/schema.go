@@ -0,0 +1,147 @@
+// Package schema versioniert die maschinenlesbaren Ausgaben aller Tools.
+//
+// Jede Ausgabe (--out-JSON, JSON Lines, Parquet, ...) trägt im Feld
+// "schema" ihren Bezeichner "<name>/<version>", z. B. "mttu-result/1".
+// Die Versionsnummern sind über alle Tools hinweg eine gemeinsame Folge:
+// Eine inkompatible Änderung an irgendeiner Ausgabe erhöht Latest, und nur
+// diese Ausgabe bekommt die neue Nummer (Lücken je Ausgabe sind also normal).
+//
+// Alle Tools registrieren dasselbe Flag:
+//
+//	--schema-version N  jede Ausgabe in ihrer neuesten Version ≤ N schreiben
+//	                    (Default: $BAA_SCHEMA_VERSION, sonst Latest)
+//
+// Eine Auswertung, die mit Version N gebaut wurde, pinnt N und bekommt auch
+// von neueren Tool-Ständen dieselben Formate. "baa schema print" listet die
+// Felder aller Versionen.
+package schema
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+)
+
+// Options hält den Wert von --schema-version.
+type Options struct {
+	Version int
+}
+
+// requested ist die gewählte Version, 0 = Latest.
+var requested int
+
+// Register fügt --schema-version zum FlagSet hinzu.
+func Register(fs *flag.FlagSet) *Options {
+	o := &Options{}
+	v, _ := strconv.Atoi(os.Getenv("BAA_SCHEMA_VERSION"))
+	fs.IntVar(&o.Version, "schema-version", v, fmt.Sprintf("Ausgaben in der neuesten Schema-Version ≤ N schreiben (1–%d, 0 = neueste; s. \"baa schema print\")", Latest()))
+	return o
+}
+
+// Setup prüft und aktiviert die gewählte Version.
+func (o *Options) Setup() error {
+	if o.Version < 0 || o.Version > Latest() {
+		return fmt.Errorf("--schema-version %d unbekannt (1–%d)", o.Version, Latest())
+	}
+	requested = o.Version
+	return nil
+}
+
+// Export übernimmt den Flag-Wert in $BAA_SCHEMA_VERSION, damit Subprozesse
+// dieselben Versionen schreiben.
+func (o *Options) Export() {
+	if o.Version > 0 {
+		os.Setenv("BAA_SCHEMA_VERSION", strconv.Itoa(o.Version))
+	}
+}
+
+// Requested liefert die gewählte Version bzw. Latest.
+func Requested() int {
+	if requested == 0 {
+		return Latest()
+	}
+	return requested
+}
+
+// Version liefert die Version, in der die Ausgabe name geschrieben wird:
+// die neueste ≤ Requested. Unbekannte Namen sind ein Programmierfehler; er
+// wird geloggt und Requested geliefert, statt das Tool abzubrechen.
+func Version(name string) int {
+	s, ok := Get(name)
+	if !ok {
+		slog.Error("schema: unbekannte Ausgabe", "name", name)
+		return Requested()
+	}
+	v, ok := s.At(Requested())
+	if !ok {
+		v = s.Versions[0] // Ausgabe gab es damals noch nicht
+	}
+	return v.Version
+}
+
+// ID liefert den Bezeichner "<name>/<version>" für das Feld "schema".
+func ID(name string) string {
+	return name + "/" + strconv.Itoa(Version(name))
+}
+
+// Latest ist die höchste Version über alle Ausgaben.
+func Latest() int {
+	n := 0
+	for _, s := range registry {
+		for _, v := range s.Versions {
+			n = max(n, v.Version)
+		}
+	}
+	return n
+}
+
+// Field ist ein Feld der obersten Ebene bzw. eine Spalte.
+type Field struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Doc  string `json:"doc"`
+}
+
+// Revision ist ein Stand einer Ausgabe. Changes beschreibt den Bruch zur
+// Vorversion.
+type Revision struct {
+	Version int     `json:"version"`
+	Changes string  `json:"changes,omitempty"`
+	Fields  []Field `json:"fields"`
+}
+
+// Schema ist eine Ausgabe mit allen Versionen, älteste zuerst.
+type Schema struct {
+	Name     string     `json:"name"`
+	Tool     string     `json:"tool"`
+	Doc      string     `json:"doc"`
+	Versions []Revision `json:"versions"`
+}
+
+// At liefert die neueste Version ≤ n; ok ist false, wenn es die Ausgabe in
+// Version n noch nicht gab.
+func (s Schema) At(n int) (Revision, bool) {
+	var out Revision
+	ok := false
+	for _, v := range s.Versions {
+		if v.Version <= n {
+			out, ok = v, true
+		}
+	}
+	return out, ok
+}
+
+// All liefert alle Ausgaben in der Reihenfolge der Registry.
+func All() []Schema { return registry }
+
+// Get sucht eine Ausgabe nach Namen.
+func Get(name string) (Schema, bool) {
+	for _, s := range registry {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Schema{}, false
+}